@@ -6,7 +6,7 @@ set -euo pipefail
 # On PR events, only modules with changed files are marked.
 #
 # Args: EVENT_NAME
-# Outputs (to GITHUB_OUTPUT): optargs, goarg, pflag
+# Outputs (to GITHUB_OUTPUT): optargs, goarg, pflag, example
 
 EVENT_NAME="$1"
 
@@ -14,6 +14,7 @@ if [ "$EVENT_NAME" = "push" ]; then
 	echo "optargs=true"
 	echo "goarg=true"
 	echo "pflag=true"
+	echo "example=true"
 else
 	CHANGED=$(git diff --name-only HEAD~1 2>/dev/null || echo "")
 
@@ -34,4 +35,10 @@ else
 	else
 		echo "pflag=false"
 	fi
+
+	if echo "$CHANGED" | grep -qE '^(example/|[^/]+\.(go|mod|sum)$)'; then
+		echo "example=true"
+	else
+		echo "example=false"
+	fi
 fi
@@ -236,7 +236,8 @@ func TestPropertyAbbrev4_EqualsSplitArgExtraction(t *testing.T) {
 }
 
 // TestPropertyAbbrev5_NoArgumentRejectsInlineArg verifies that NoArgument
-// flags return UnexpectedArgumentError when given --{name}={value}.
+// flags return UnexpectedArgumentError, carrying the value, when given
+// --{name}={value}.
 //
 // **Validates: Requirements 5.3**
 func TestPropertyAbbrev5_NoArgumentRejectsInlineArg(t *testing.T) {
@@ -265,7 +266,7 @@ func TestPropertyAbbrev5_NoArgumentRejectsInlineArg(t *testing.T) {
 			}
 		}
 		var unexpErr *UnexpectedArgumentError
-		return errors.As(gotErr, &unexpErr)
+		return errors.As(gotErr, &unexpErr) && unexpErr.Value == value
 	}
 	if err := quick.Check(f, cfg); err != nil {
 		t.Error(err)
@@ -0,0 +1,157 @@
+package optargs
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newAliasParser(t *testing.T, config ParserConfig, args []string) *Parser {
+	t.Helper()
+	p, err := NewParser(config, nil, map[string]*Flag{
+		"color": {Name: "color", HasArg: OptionalArgument, ArgName: "WHEN", Aliases: []string{"colour"}},
+		"file":  {Name: "file", HasArg: RequiredArgument},
+	}, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLongAliases(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ParserConfig
+		args   []string
+		want   []Option
+	}{
+		{
+			name: "exact alias",
+			args: []string{"--colour=never"},
+			want: []Option{{Name: "color", HasArg: true, Arg: "never"}},
+		},
+		{
+			name: "canonical name",
+			args: []string{"--color"},
+			want: []Option{{Name: "color"}},
+		},
+		{
+			name: "prefix of both spellings",
+			args: []string{"--col"},
+			want: []Option{{Name: "color"}},
+		},
+		{
+			name: "prefix of alias only",
+			args: []string{"--colou=auto"},
+			want: []Option{{Name: "color", HasArg: true, Arg: "auto"}},
+		},
+		{
+			name:   "case-insensitive alias",
+			config: ParserConfig{longCaseIgnore: true},
+			args:   []string{"--COLOUR"},
+			want:   []Option{{Name: "color"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newAliasParser(t, tt.config, tt.args)
+			assertOptions(t, requireParsedOptions(t, p), tt.want)
+		})
+	}
+}
+
+func TestLongAliasHandlerAndInheritance(t *testing.T) {
+	root := newAliasParser(t, ParserConfig{}, []string{"sub", "--colour=always"})
+	var got []string
+	if err := root.SetLongHandler("colour", func(name, arg string) error {
+		got = append(got, name+"="+arg)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sub, err := NewParser(ParserConfig{}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("sub", sub)
+
+	requireParsedOptions(t, root)
+	for _, child := range root.Dispatches() {
+		requireParsedOptions(t, child)
+	}
+	if want := []string{"color=always"}; !slices.Equal(got, want) {
+		t.Errorf("handler calls = %q, want %q", got, want)
+	}
+}
+
+func TestLongAliasErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		longOpts map[string]*Flag
+		want     string
+	}{
+		{
+			name: "conflicts with option",
+			longOpts: map[string]*Flag{
+				"color":  {Name: "color", Aliases: []string{"colour"}},
+				"colour": {Name: "colour"},
+			},
+			want: "long option alias conflicts with option: colour",
+		},
+		{
+			name:     "invalid",
+			longOpts: map[string]*Flag{"color": {Name: "color", Aliases: []string{"col our"}}},
+			want:     "invalid long option alias: col our",
+		},
+		{
+			name: "shared",
+			longOpts: map[string]*Flag{
+				"color": {Name: "color", Aliases: []string{"c"}},
+				"count": {Name: "count", Aliases: []string{"c"}},
+			},
+			want: "long option alias c is shared by",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser(ParserConfig{errorMode: ErrorSilent}, nil, tt.longOpts, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewParser error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLongAliasGenerated verifies that aliases appear in usage text,
+// completion candidates, and the serialized spec.
+func TestLongAliasGenerated(t *testing.T) {
+	p := newAliasParser(t, ParserConfig{}, nil)
+	p.Name = "prog"
+
+	var b strings.Builder
+	if err := p.WriteUsage(&b); err != nil {
+		t.Fatal(err)
+	}
+	if want := "      --color, --colour[=WHEN]\n"; !strings.Contains(b.String(), want) {
+		t.Errorf("usage missing %q:\n%s", want, b.String())
+	}
+
+	if got, want := p.Complete("prog --col", 10), []string{"--color", "--colour"}; !slices.Equal(got, want) {
+		t.Errorf("Complete = %q, want %q", got, want)
+	}
+
+	data, err := json.Marshal(p.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := NewParserFromSpec(&spec, []string{"--colour"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, requireParsedOptions(t, decoded), []Option{{Name: "color"}})
+}
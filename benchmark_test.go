@@ -2,6 +2,7 @@ package optargs
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"testing"
 )
@@ -104,6 +105,12 @@ func BenchmarkGetOptLongOnly(b *testing.B) {
 			args:      []string{"prog", "-verbose", "-v", "-output", "file.txt"},
 			optstring: "",
 		},
+		{
+			// Each group is probed as a long option first.
+			name:      "GroupedShortFallback",
+			args:      []string{"prog", "-vqf", "-xyz"},
+			optstring: "vqfxyz",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -205,3 +212,117 @@ func BenchmarkIteratorEfficiency(b *testing.B) {
 		}
 	})
 }
+
+// Per-phase micro-benchmarks. Baselines live in testdata/bench-baseline.txt;
+// regenerate with `make bench-baseline` and compare with benchstat.
+
+// BenchmarkPhaseTokenize measures classifying words and collecting
+// operands with no option lookups.
+func BenchmarkPhaseTokenize(b *testing.B) {
+	args := make([]string, 64)
+	for i := range args {
+		args[i] = "operand" + strconv.Itoa(i)
+	}
+	b.ReportAllocs()
+	for range b.N {
+		p, err := NewParser(ParserConfig{}, nil, nil, slices.Clone(args))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, err := range p.Options() {
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkPhaseLongLookup measures findLongOpt against 101 registered
+// long options for each resolution path.
+func BenchmarkPhaseLongLookup(b *testing.B) {
+	longOpts := make(map[string]*Flag, 100)
+	for i := range 100 {
+		name := fmt.Sprintf("option-%03d", i)
+		longOpts[name] = &Flag{Name: name, HasArg: OptionalArgument}
+	}
+	longOpts["verbose"] = &Flag{Name: "verbose"}
+	p, err := NewParser(ParserConfig{errorMode: ErrorSilent}, nil, longOpts, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, tc := range []struct{ name, word string }{
+		{"Exact", "option-050"},
+		{"ExactInline", "option-050=value"},
+		{"Prefix", "verb"},
+		{"Ambiguous", "option-05"},
+		{"Unknown", "missing"},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				_, _, _, _ = p.findLongOpt(tc.word, nil)
+			}
+		})
+	}
+}
+
+// BenchmarkPhaseCompaction measures resolving a cluster of short options.
+func BenchmarkPhaseCompaction(b *testing.B) {
+	p, err := GetOpt(nil, "abcdefgho:")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, tc := range []struct{ name, word string }{
+		{"Flags8", "abcdefgh"},
+		{"AttachedArg", "abcoVALUE"},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				word := tc.word
+				for len(word) > 0 {
+					var err error
+					if _, word, _, _, err = p.findShortOpt(word[0], word[1:], nil); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkPhaseParentChain measures resolving root options from
+// subcommands nested at increasing depth.
+func BenchmarkPhaseParentChain(b *testing.B) {
+	root, err := GetOptLong(nil, "v", []Flag{{Name: "verbose"}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	leaf := root
+	for _, depth := range []int{1, 4, 16} {
+		for leafDepth(leaf) < depth {
+			child, err := GetOptLong(nil, "", []Flag{{Name: "local"}})
+			if err != nil {
+				b.Fatal(err)
+			}
+			leaf.AddCmd("sub", child)
+			leaf = child
+		}
+		b.Run("Depth"+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				if leaf.exactMatch("verbose").flag == nil || leaf.resolveShort('v') == nil {
+					b.Fatal("root option not found")
+				}
+			}
+		})
+	}
+}
+
+func leafDepth(p *Parser) int {
+	n := 0
+	for ; p.parent != nil; p = p.parent {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,146 @@
+package optargs
+
+import (
+	"encoding"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bind attaches a handler to each option named in targets that stores the
+// option's argument into the variable the pointer refers to, converting
+// it to the variable's type, so small programs get struct-tag-like
+// ergonomics from a plain [GetOpt] or [GetOptLong] parser:
+//
+//	var verbose bool
+//	var file string
+//	err := optargs.Bind(p, map[string]any{"verbose": &verbose, "file": &file})
+//	opts, err := p.Collect() // bound options are handled, not yielded
+//
+// A name is looked up as a long option first (aliases included), then as
+// a single-character short option; a linked Flag.Peer is bound as well.
+// Supported targets are pointers to string, bool, the int, uint, and
+// float types, time.Duration, []string, []int, []int64, []float64,
+// []time.Duration, and map[string]string, plus any
+// [encoding.TextUnmarshaler]. A *bool is set to true when its option is
+// given without an argument, or to false for a +x toggle (see
+// [ParserConfig.SetPlusOptions]), and a *int bound to a no-argument option
+// counts its occurrences. Slices and maps accumulate repeated options.
+// Variables keep their current values until their option is parsed.
+//
+// A conversion failure surfaces through the Options iterator as an
+// [*InvalidArgumentError]. Bind itself fails, binding nothing, if a name
+// is not an option of p or a target has an unsupported type. Like
+// [Parser.SetHandler], Bind only modifies options registered on p.
+func Bind(p *Parser, targets map[string]any) error {
+	flags := make(map[*Flag]TypedValue, len(targets))
+	for name, target := range targets {
+		flag := p.bindFlag(name)
+		if flag == nil {
+			return fmt.Errorf("bind: unknown option: %s", name)
+		}
+		tv, err := bindValue(target, flag)
+		if err != nil {
+			return fmt.Errorf("bind: option %s: %w", name, err)
+		}
+		flags[flag] = tv
+	}
+	for flag, tv := range flags {
+		flag.Handle, flag.HandleCtx = bindHandler(tv), nil
+		if peer := flag.Peer; peer != nil && peer != flag {
+			peer.Handle, peer.HandleCtx = flag.Handle, nil
+		}
+	}
+	return nil
+}
+
+// bindFlag returns the flag registered on p that Bind should attach name
+// to, or nil.
+func (p *Parser) bindFlag(name string) *Flag {
+	if registered, ok := p.longAliases[name]; ok {
+		name = registered
+	}
+	if flag := p.longOpts[name]; flag != nil {
+		return flag
+	}
+	if len(name) == 1 {
+		return p.shortOpts[name[0]]
+	}
+	if r, ok := runeName(name); ok {
+		return p.runeOpts[r]
+	}
+	return nil
+}
+
+// bindHandler returns a Flag.Handle that sets tv from the option argument.
+func bindHandler(tv TypedValue) func(name, arg string) error {
+	return func(name, arg string) error {
+		if arg == "" && IsBool(tv) {
+			arg = boolTrueStr
+			if strings.HasPrefix(name, "+") {
+				arg = "false"
+			}
+		}
+		if err := tv.Set(arg); err != nil {
+			return &InvalidArgumentError{Name: name, Arg: arg, Type: tv.Type(), Err: err}
+		}
+		return nil
+	}
+}
+
+// bindValue wraps target in the TypedValue Bind stores through.
+//
+//nolint:gocyclo,cyclop // one case per supported target type
+func bindValue(target any, flag *Flag) (TypedValue, error) {
+	switch t := target.(type) {
+	case *string:
+		return NewStringValue(*t, t), nil
+	case *bool:
+		return NewBoolValue(*t, t), nil
+	case *int:
+		if flag.HasArg == NoArgument {
+			return NewCountValue(*t, t), nil
+		}
+		return NewIntValue(*t, t), nil
+	case *int8:
+		return NewInt8Value(*t, t), nil
+	case *int16:
+		return NewInt16Value(*t, t), nil
+	case *int32:
+		return NewInt32Value(*t, t), nil
+	case *int64:
+		return NewInt64Value(*t, t), nil
+	case *uint:
+		return NewUintValue(*t, t), nil
+	case *uint8:
+		return NewUint8Value(*t, t), nil
+	case *uint16:
+		return NewUint16Value(*t, t), nil
+	case *uint32:
+		return NewUint32Value(*t, t), nil
+	case *uint64:
+		return NewUint64Value(*t, t), nil
+	case *float32:
+		return NewFloat32Value(*t, t), nil
+	case *float64:
+		return NewFloat64Value(*t, t), nil
+	case *time.Duration:
+		return NewDurationValue(*t, t), nil
+	case *[]string:
+		return NewStringArrayValue(*t, t), nil
+	case *[]int:
+		return NewIntSliceValue(*t, t), nil
+	case *[]int64:
+		return NewInt64SliceValue(*t, t), nil
+	case *[]float64:
+		return NewFloat64SliceValue(*t, t), nil
+	case *[]time.Duration:
+		return NewDurationSliceValue(*t, t), nil
+	case *map[string]string:
+		return NewStringToStringValue(*t, t), nil
+	case encoding.TextUnmarshaler:
+		m, _ := target.(encoding.TextMarshaler) //nolint:errcheck // nil when not implemented
+		return NewTextValue(m, t), nil
+	}
+	return nil, fmt.Errorf("unsupported target type %T", target)
+}
@@ -0,0 +1,134 @@
+package optargs
+
+import (
+	"errors"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBind(t *testing.T) {
+	p, err := GetOptLong(
+		[]string{"-vv", "--file=a.txt", "--color", "--timeout", "1m", "-I", "x", "-Iy", "--level=3", "--addr", "10.0.0.1", "-k", "op"},
+		"vI:kl:", []Flag{
+			{Name: "file", HasArg: RequiredArgument},
+			{Name: "color", HasArg: OptionalArgument},
+			{Name: "timeout", HasArg: RequiredArgument},
+			{Name: "level", HasArg: RequiredArgument},
+			{Name: "addr", HasArg: RequiredArgument},
+			{Name: "ratio", HasArg: RequiredArgument},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		verbose  int
+		file     string
+		color    bool
+		timeout  time.Duration
+		includes []string
+		level    uint8
+		addr     netip.Addr
+		ratio    = 0.5
+	)
+	err = Bind(p, map[string]any{
+		"v": &verbose, "file": &file, "color": &color, "timeout": &timeout,
+		"I": &includes, "level": &level, "addr": &addr, "ratio": &ratio,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := p.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, opts, []Option{{Name: "k"}})
+	assertArgs(t, p.Args, []string{"op"})
+
+	if verbose != 2 || file != "a.txt" || !color || timeout != time.Minute ||
+		!slices.Equal(includes, []string{"x", "y"}) || level != 3 ||
+		addr != netip.MustParseAddr("10.0.0.1") || ratio != 0.5 {
+		t.Errorf("bound values = %d %q %t %v %q %d %v %g", verbose, file, color, timeout, includes, level, addr, ratio)
+	}
+}
+
+func TestBindPeer(t *testing.T) {
+	short := &Flag{Name: "n", HasArg: RequiredArgument}
+	long := &Flag{Name: "count", HasArg: RequiredArgument, Peer: short}
+	short.Peer = long
+	p, err := NewParser(ParserConfig{}, map[byte]*Flag{'n': short}, map[string]*Flag{"count": long}, []string{"-n", "1", "--count=2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var counts []int
+	if err := Bind(p, map[string]any{"count": &counts}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Collect(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(counts, []int{1, 2}) {
+		t.Errorf("counts = %v, want [1 2]", counts)
+	}
+}
+
+// TestBindReplacesHandlerCtx verifies that Bind takes over options, and
+// their peers, that already have a context handler.
+func TestBindReplacesHandlerCtx(t *testing.T) {
+	short := &Flag{Name: "n", HasArg: RequiredArgument}
+	long := &Flag{Name: "count", HasArg: RequiredArgument, Peer: short}
+	short.Peer = long
+	p, err := NewParser(ParserConfig{}, map[byte]*Flag{'n': short}, map[string]*Flag{"count": long}, []string{"-n", "1", "--count=2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctxCalls := 0
+	ctx := func(*Parser, Option) error { ctxCalls++; return nil }
+	for _, name := range []string{"-n", "--count"} {
+		if err := p.SetHandlerCtx(name, ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var counts []int
+	if err := Bind(p, map[string]any{"count": &counts}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Collect(); err != nil {
+		t.Fatal(err)
+	}
+	if ctxCalls != 0 || !slices.Equal(counts, []int{1, 2}) {
+		t.Errorf("context handler calls = %d, counts = %v; want 0, [1 2]", ctxCalls, counts)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	newParser := func(args ...string) *Parser {
+		p, err := GetOptLong(args, ":", []Flag{{Name: "port", HasArg: RequiredArgument}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	var port int
+	if err := Bind(newParser(), map[string]any{"host": &port}); err == nil || !strings.Contains(err.Error(), "unknown option: host") {
+		t.Errorf("unknown option: err = %v", err)
+	}
+	if err := Bind(newParser(), map[string]any{"port": port}); err == nil || !strings.Contains(err.Error(), "unsupported target type int") {
+		t.Errorf("non-pointer target: err = %v", err)
+	}
+
+	p := newParser("--port", "http")
+	if err := Bind(p, map[string]any{"port": &port}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := p.Collect()
+	var invalid *InvalidArgumentError
+	if !errors.As(err, &invalid) || invalid.Name != "port" || invalid.Arg != "http" || invalid.Type != "int" {
+		t.Errorf("conversion error = %#v, want *InvalidArgumentError", err)
+	}
+}
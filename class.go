@@ -0,0 +1,43 @@
+package optargs
+
+import "slices"
+
+// AddShortClass registers flag as a class of short options: every
+// character for which flag.MatchByte reports true, such as any digit for
+// a -5 style count or the permission letters of chmod -rwx, without
+// registering each one. Classes are consulted only for characters that
+// are not a short option of the parser or of an ancestor, so an exact
+// registration always wins; among classes the parser's own come before
+// inherited ones, each in registration order.
+//
+// The matched character is the Name of the parsed Option, and the name
+// passed to the flag's handler, so one flag can tell its members apart.
+// flag.Name is used only to describe the class, in error messages and
+// traces. Like other short options, a class member can be grouped (-ef5)
+// and takes an argument according to flag.HasArg.
+func (p *Parser) AddShortClass(flag *Flag) error {
+	if flag == nil || flag.MatchByte == nil {
+		return p.optError("short option class needs a Flag with MatchByte")
+	}
+	if slices.Contains(p.shortClasses, flag) {
+		return p.optErrorf("short option class already registered: %s", flag.Name)
+	}
+	p.shortClasses = append(p.shortClasses, flag)
+	return nil
+}
+
+// matchShortClass returns the first class of p or an ancestor that
+// accepts c, searching the parent chain like the parser does.
+func (p *Parser) matchShortClass(c byte) *Flag {
+	if !isGraph(c) {
+		return nil
+	}
+	for cur := p; cur != nil; cur = cur.parent {
+		for _, flag := range cur.shortClasses {
+			if !flag.Disabled && flag.MatchByte(c) {
+				return flag
+			}
+		}
+	}
+	return nil
+}
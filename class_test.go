@@ -0,0 +1,105 @@
+package optargs
+
+import (
+	"strings"
+	"testing"
+)
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func TestAddShortClass(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     []Option
+		wantArgs []string
+	}{
+		{"member", []string{"-5"}, []Option{{Name: "5"}}, []string{}},
+		{"grouped", []string{"-e12f"}, []Option{{Name: "e"}, {Name: "1"}, {Name: "2"}, {Name: "f"}}, []string{}},
+		{"exact wins", []string{"-0x"}, []Option{{Name: "0", HasArg: true, Arg: "x"}}, []string{}},
+		{"argument", []string{"-wr", "file"}, []Option{{Name: "w", HasArg: true, Arg: "r"}}, []string{"file"}},
+		{"operands", []string{"-9", "a"}, []Option{{Name: "9"}}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetOpt(append([]string{}, tt.args...), "ef0:")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.AddShortClass(&Flag{Name: "DIGIT", MatchByte: isDigit}); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.AddShortClass(&Flag{Name: "PERM", HasArg: OptionalArgument, MatchByte: func(c byte) bool {
+				return strings.IndexByte("rwx", c) >= 0
+			}}); err != nil {
+				t.Fatal(err)
+			}
+			assertOptions(t, requireParsedOptions(t, p), tt.want)
+			assertArgs(t, p.Args, tt.wantArgs)
+		})
+	}
+}
+
+func TestAddShortClassHandlerAndInheritance(t *testing.T) {
+	var digits string
+	root, err := GetOpt(nil, ":v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := root.AddShortClass(&Flag{Name: "DIGIT", MatchByte: isDigit, Handle: func(name, _ string) error {
+		digits += name
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	child, err := GetOpt(nil, ":3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("run", child)
+
+	root.Reset([]string{"-42", "run", "-735", "-x"})
+	var got []string
+	var errs []error
+	for _, err := range root.Options() {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for opt, err := range child.Options() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		got = append(got, opt.Name)
+	}
+	if digits != "4275" {
+		t.Errorf("handled digits = %q, want %q", digits, "4275")
+	}
+	if strings.Join(got, ",") != "3" {
+		t.Errorf("child options = %q, want [3]", got)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "x") {
+		t.Errorf("errors = %v, want one for -x", errs)
+	}
+}
+
+func TestAddShortClassErrors(t *testing.T) {
+	p, err := GetOpt(nil, ":")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddShortClass(nil); err == nil {
+		t.Error("AddShortClass(nil) succeeded")
+	}
+	if err := p.AddShortClass(&Flag{Name: "x"}); err == nil {
+		t.Error("AddShortClass without MatchByte succeeded")
+	}
+	flag := &Flag{Name: "DIGIT", MatchByte: isDigit}
+	if err := p.AddShortClass(flag); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddShortClass(flag); err == nil {
+		t.Error("second AddShortClass of the same flag succeeded")
+	}
+}
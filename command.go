@@ -2,6 +2,7 @@ package optargs
 
 import (
 	"fmt"
+	"iter"
 	"strings"
 )
 
@@ -66,8 +67,7 @@ func prepareCommand(name string, parser *Parser, exists bool, args []string) (*P
 	if parser == nil {
 		return nil, fmt.Errorf("command %s has no parser", name)
 	}
-	parser.Args = args
-	parser.nonOpts = []string{}
+	parser.Reset(args)
 	return parser, nil
 }
 
@@ -102,3 +102,109 @@ func (cr CommandRegistry) GetAliases(targetParser *Parser) []string {
 func (p *Parser) ActiveCommand() (name string, parser *Parser) {
 	return p.activeCmd, p.activeCmdParser
 }
+
+// OnCommand registers fn to run when p dispatches the subcommand name, or
+// any alias of it, during Options iteration. fn receives the subcommand's
+// parser, already holding the arguments after the command name, so it can
+// iterate child.Options() itself; together with the handlers of each
+// level this turns the parser tree into a small command framework:
+//
+//	root.OnCommand("serve", func(serve *optargs.Parser) error {
+//		for _, err := range serve.Options() {
+//			if err != nil {
+//				return err
+//			}
+//		}
+//		return runServer(serve.Args)
+//	})
+//
+// fn runs after every option before the command name has been handled,
+// and after the dispatch is reported to the trace function. An error it
+// returns is yielded by p's iterator. A later call for the same command
+// replaces fn, and a nil fn removes it. OnCommand returns an error if name
+// is not a registered command of p.
+func (p *Parser) OnCommand(name string, fn func(child *Parser) error) error {
+	cmd, ok := p.GetCommand(name)
+	if !ok || cmd == nil {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+	if fn == nil {
+		delete(p.onCommand, cmd)
+		return nil
+	}
+	if p.onCommand == nil {
+		p.onCommand = make(map[*Parser]func(child *Parser) error)
+	}
+	p.onCommand[cmd] = fn
+	return nil
+}
+
+// SetDefaultCmd makes the subcommand name the one p dispatches when the
+// command line names none: the first operand that is not a registered
+// command is passed to it, together with everything after it, and when
+// there is no operand at all it is dispatched with no arguments once p's
+// options are parsed. Operands after "--" are left to p. An empty name
+// removes the default. SetDefaultCmd returns an error if name is not a
+// registered command of p.
+//
+// Usage text marks the default command, and ActiveCommand, Dispatches,
+// and OnCommand callbacks see it as if it had been typed.
+func (p *Parser) SetDefaultCmd(name string) error {
+	if name != "" {
+		if cmd, ok := p.GetCommand(name); !ok || cmd == nil {
+			return fmt.Errorf("unknown command: %s", name)
+		}
+	}
+	p.defaultCmd = name
+	return nil
+}
+
+// dispatch makes cmd, invoked as name at original position index, the
+// active subcommand of p with args, a tail of p.Args. It reports the
+// dispatch to the trace function and runs the OnCommand callback, and
+// returns the error, if any, for p's iterator to yield.
+func (p *Parser) dispatch(name string, cmd *Parser, args []string, index int) error {
+	p.activeCmd, p.activeCmdParser = name, cmd
+	if _, err := prepareCommand(name, cmd, true, args); err != nil {
+		return err
+	}
+	cmd.argBase = index + len(p.Args) - len(args)
+	cmd.dispatchedBy = p
+	if trace := p.tracer(); trace != nil {
+		trace(TraceEvent{Kind: TraceDispatch, Parser: p, Option: Option{Name: name, Index: index}})
+	}
+	if fn := p.onCommand[cmd]; fn != nil {
+		return fn(cmd)
+	}
+	return nil
+}
+
+// Dispatches returns an iterator over the chain of dispatched subcommands,
+// starting with the immediate child of p. Each step yields the command
+// name and its parser at the moment that level becomes active, so the
+// caller can react (load plugins, adjust logging) before iterating the
+// child's own Options():
+//
+//	for _, err := range root.Options() { ... }
+//	for name, child := range root.Dispatches() {
+//		for opt, err := range child.Options() { ... }
+//	}
+//
+// The walk is lazy: the next level is looked up only after the loop body
+// returns, so nested subcommands dispatched while the body iterates
+// child.Options() are picked up. Iteration ends at the first parser that
+// has not dispatched a subcommand.
+func (p *Parser) Dispatches() iter.Seq2[string, *Parser] {
+	return func(yield func(string, *Parser) bool) {
+		for current := p; current != nil; {
+			name, child := current.ActiveCommand()
+			if child == nil {
+				return
+			}
+			if !yield(name, child) {
+				return
+			}
+			current = child
+		}
+	}
+}
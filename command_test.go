@@ -1,6 +1,7 @@
 package optargs
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"testing/quick"
@@ -743,3 +744,314 @@ func TestActiveCommandNestedChain(t *testing.T) {
 		t.Errorf("migrate.ActiveCommand() = (%q, %v), want (\"\", nil)", name3, p3)
 	}
 }
+
+func TestDispatches(t *testing.T) {
+	root, _ := GetOptLong(
+		[]string{"-v", "db", "--name", "mydb", "migrate", "--steps", "3"},
+		"v", []Flag{{Name: "verbose", HasArg: NoArgument}},
+	)
+	db, _ := GetOptLong([]string{}, "n:", []Flag{{Name: "name", HasArg: RequiredArgument}})
+	root.AddCmd("db", db)
+	migrate, _ := GetOptLong([]string{}, "s:", []Flag{{Name: "steps", HasArg: RequiredArgument}})
+	db.AddCmd("migrate", migrate)
+
+	for _, err := range root.Options() {
+		if err != nil {
+			t.Fatalf("root Options(): %v", err)
+		}
+	}
+
+	var names []string
+	var args []string
+	for name, child := range root.Dispatches() {
+		names = append(names, name)
+		// The child is active but not yet iterated.
+		if n, _ := child.ActiveCommand(); n != "" {
+			t.Errorf("%s: ActiveCommand() = %q before iteration", name, n)
+		}
+		for opt, err := range child.Options() {
+			if err != nil {
+				t.Fatalf("%s Options(): %v", name, err)
+			}
+			args = append(args, opt.Name+"="+opt.Arg)
+		}
+	}
+
+	if strings.Join(names, ",") != "db,migrate" {
+		t.Errorf("dispatch names = %v, want [db migrate]", names)
+	}
+	if strings.Join(args, ",") != "name=mydb,steps=3" {
+		t.Errorf("child options = %v", args)
+	}
+}
+
+func TestDispatchesNoCommand(t *testing.T) {
+	root := newCmdRootParser(t)
+	root.AddCmd("server", newCmdServerParser(t))
+	root.Args = []string{"-v", "file"}
+	for _, err := range root.Options() {
+		if err != nil {
+			t.Fatalf("Options(): %v", err)
+		}
+	}
+	for name := range root.Dispatches() {
+		t.Errorf("unexpected dispatch %q", name)
+	}
+}
+
+func TestDispatchesBreak(t *testing.T) {
+	root, _ := GetOpt([]string{"a", "b"}, "")
+	a, _ := GetOpt([]string{}, "")
+	b, _ := GetOpt([]string{}, "")
+	root.AddCmd("a", a)
+	a.AddCmd("b", b)
+
+	count := 0
+	for range root.Options() {
+	}
+	for _, child := range root.Dispatches() {
+		count++
+		for range child.Options() {
+		}
+		break
+	}
+	if count != 1 {
+		t.Errorf("expected 1 yield before break, got %d", count)
+	}
+}
+
+func TestCommandFirst(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		args      []string
+		wantErr   string
+		wantCmd   string
+		wantNonOp []string
+	}{
+		{"command first", true, []string{"-v", "serve", "x"}, "", "serve", nil},
+		{"operand before command", true, []string{"file.txt", "serve"}, "unknown command: file.txt (expected one of: client, serve)", "serve", []string{"file.txt"}},
+		{"mistyped command", true, []string{"srve"}, "unknown command: srve (did you mean serve?)", "", []string{"srve"}},
+		{"terminator skips check", true, []string{"--", "file.txt"}, "", "", []string{"file.txt"}},
+		{"no operands", true, []string{"-v"}, "", "", nil},
+		{"disabled accepts operand first", false, []string{"file.txt", "serve"}, "", "serve", []string{"file.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newCmdRootParser(t)
+			root.SetErrorMode(ErrorSilent)
+			root.SetCommandFirst(tt.enabled)
+			root.AddCmd("serve", newCmdServerParser(t))
+			root.AddCmd("client", newCmdClientParser(t))
+			root.Args = tt.args
+
+			var errs []string
+			for _, err := range root.Options() {
+				if err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+			switch {
+			case tt.wantErr == "" && len(errs) > 0:
+				t.Errorf("unexpected errors: %v", errs)
+			case tt.wantErr != "" && (len(errs) != 1 || errs[0] != tt.wantErr):
+				t.Errorf("errors = %q, want [%q]", errs, tt.wantErr)
+			}
+			if name, _ := root.ActiveCommand(); name != tt.wantCmd {
+				t.Errorf("ActiveCommand() = %q, want %q", name, tt.wantCmd)
+			}
+			if strings.Join(root.Args, " ") != strings.Join(tt.wantNonOp, " ") {
+				t.Errorf("Args = %q, want %q", root.Args, tt.wantNonOp)
+			}
+		})
+	}
+}
+
+func TestCommandFirstWithoutCommands(t *testing.T) {
+	var config ParserConfig
+	config.SetCommandFirst(true)
+	if !config.CommandFirst() {
+		t.Fatal("CommandFirst() = false after SetCommandFirst(true)")
+	}
+	p, err := NewParser(config, nil, nil, []string{"file.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := p.Remaining()
+	if err != nil {
+		t.Fatalf("parser without commands rejected operand: %v", err)
+	}
+	if len(args) != 1 || args[0] != "file.txt" {
+		t.Errorf("Args = %q, want [file.txt]", args)
+	}
+}
+
+func TestOnCommand(t *testing.T) {
+	root := newCmdRootParser(t)
+	server := newCmdServerParser(t)
+	client := newCmdClientParser(t)
+	root.AddCmd("server", server)
+	root.AddCmd("client", client)
+	if err := root.AddAlias("srv", "server"); err != nil {
+		t.Fatal(err)
+	}
+	start := newMinimalParser(t)
+	server.AddCmd("start", start)
+
+	var events []string
+	record := func(p *Parser) error {
+		for opt, err := range p.Options() {
+			if err != nil {
+				return err
+			}
+			events = append(events, p.Name+":"+opt.Name+"="+opt.Arg)
+		}
+		events = append(events, p.Name+":"+strings.Join(p.Args, ","))
+		return nil
+	}
+	if err := root.OnCommand("server", func(child *Parser) error {
+		events = append(events, "dispatch server")
+		return record(child)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.OnCommand("start", func(child *Parser) error {
+		events = append(events, "dispatch start")
+		return record(child)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	root.Reset([]string{"-v", "srv", "-p", "80", "start", "now"})
+	for opt, err := range root.Options() {
+		if err != nil {
+			t.Fatalf("Options: %v", err)
+		}
+		events = append(events, "root:"+opt.Name)
+	}
+	want := []string{"root:v", "dispatch server", "server:p=80", "dispatch start", "start:now", "server:"}
+	if strings.Join(events, " ") != strings.Join(want, " ") {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+
+	// An error from the callback is yielded by the dispatching parser.
+	errStop := errors.New("stop")
+	if err := root.OnCommand("client", func(*Parser) error { return errStop }); err != nil {
+		t.Fatal(err)
+	}
+	root.Reset([]string{"client", "-u", "x"})
+	if _, err := root.Collect(); !errors.Is(err, errStop) {
+		t.Errorf("Collect() error = %v, want %v", err, errStop)
+	}
+	if name, cmd := root.ActiveCommand(); name != "client" || cmd != client {
+		t.Errorf("ActiveCommand() = %q, %p; want client, %p", name, cmd, client)
+	}
+
+	// A nil callback removes it.
+	if err := root.OnCommand("client", nil); err != nil {
+		t.Fatal(err)
+	}
+	root.Reset([]string{"client"})
+	if _, err := root.Collect(); err != nil {
+		t.Errorf("Collect() after removal: %v", err)
+	}
+
+	if err := root.OnCommand("missing", func(*Parser) error { return nil }); err == nil {
+		t.Error("OnCommand(missing) succeeded, want error")
+	}
+}
+
+func TestSetDefaultCmd(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantCmd   string
+		wantRoot  []string // root operands
+		wantChild []Option
+		wantArgs  []string // default command operands
+	}{
+		{
+			name: "explicit command", args: []string{"-v", "client", "-u", "x"}, wantCmd: "client",
+			wantRoot: []string{},
+		},
+		{
+			name: "operand", args: []string{"-v", "file", "-p", "80"}, wantCmd: "server",
+			wantRoot: []string{}, wantChild: []Option{{Name: "p", HasArg: true, Arg: "80", Index: 2}},
+			wantArgs: []string{"file"},
+		},
+		{
+			name: "no arguments", args: []string{"-v"}, wantCmd: "server",
+			wantRoot: []string{}, wantArgs: []string{},
+		},
+		{
+			name: "empty", args: []string{}, wantCmd: "server",
+			wantRoot: []string{}, wantArgs: []string{},
+		},
+		{
+			name: "terminator", args: []string{"-v", "--", "file"},
+			wantRoot: []string{"file"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newCmdRootParser(t)
+			server := newCmdServerParser(t)
+			root.AddCmd("server", server)
+			root.AddCmd("client", newCmdClientParser(t))
+			if err := root.SetDefaultCmd("server"); err != nil {
+				t.Fatal(err)
+			}
+			root.Reset(tt.args)
+			if _, err := root.Collect(); err != nil {
+				t.Fatalf("Collect: %v", err)
+			}
+			name, cmd := root.ActiveCommand()
+			if name != tt.wantCmd {
+				t.Fatalf("ActiveCommand() = %q, want %q", name, tt.wantCmd)
+			}
+			assertArgs(t, root.Args, tt.wantRoot)
+			if tt.wantCmd != "server" {
+				return
+			}
+			if cmd != server {
+				t.Fatalf("ActiveCommand() parser = %p, want server %p", cmd, server)
+			}
+			assertOptions(t, requireParsedOptions(t, server), tt.wantChild)
+			assertArgs(t, server.Args, tt.wantArgs)
+		})
+	}
+}
+
+func TestSetDefaultCmdUsage(t *testing.T) {
+	root := newCmdRootParser(t)
+	server := newCmdServerParser(t)
+	server.Description = "Run the server"
+	root.AddCmd("server", server)
+	root.AddCmd("client", newCmdClientParser(t))
+	if err := root.SetDefaultCmd("missing"); err == nil {
+		t.Error("SetDefaultCmd(missing) succeeded, want error")
+	}
+	if err := root.SetDefaultCmd("server"); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := root.WriteUsage(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "server  Run the server (default)\n") {
+		t.Errorf("usage does not mark the default command:\n%s", b.String())
+	}
+
+	// An empty name removes the default.
+	if err := root.SetDefaultCmd(""); err != nil {
+		t.Fatal(err)
+	}
+	root.Reset([]string{"file"})
+	if _, err := root.Collect(); err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := root.ActiveCommand(); name != "" {
+		t.Errorf("ActiveCommand() = %q after removing the default", name)
+	}
+	assertArgs(t, root.Args, []string{"file"})
+}
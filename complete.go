@@ -0,0 +1,211 @@
+package optargs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CompleteCommand is the hidden argument that makes [Parser.ServeCompletion]
+// answer a completion query instead of running the program.
+const CompleteCommand = "__complete"
+
+// Complete returns the completion candidates for the word under the
+// cursor in a command line, where point is the cursor's byte offset in
+// line (clamped to its length). The first word names the program and is
+// never completed. Candidates are subcommand names, option spellings
+// (inherited ones included), or, in an option's argument position, the
+// values returned by that option's Flag.Complete callback. Only
+// candidates starting with the word typed so far are returned, sorted.
+func (p *Parser) Complete(line string, point int) []string {
+	point = max(0, min(point, len(line)))
+	return p.completeWords(splitCompletionLine(line[:point]))
+}
+
+// completeWords completes the last of words, which holds the program name
+// followed by the words up to the cursor.
+func (p *Parser) completeWords(words []string) []string {
+	if len(words) < 2 {
+		return nil
+	}
+	cur := words[len(words)-1]
+	current := p
+	var pending *Flag // option whose argument is the next word
+	for _, word := range words[1 : len(words)-1] {
+		switch {
+		case pending != nil:
+			pending = nil
+		case word == "--":
+			return nil
+		case strings.HasPrefix(word, "--"):
+			name, _, hasArg := strings.Cut(word[2:], "=")
+			if flag := current.completionLongFlag(name); flag != nil && !hasArg && flag.HasArg == RequiredArgument {
+				pending = flag
+			}
+		case strings.HasPrefix(word, "-") && word != "-":
+			pending = current.completionShortCluster(word[1:])
+		default:
+			if cmd, ok := current.GetCommand(word); ok && cmd != nil {
+				current = cmd
+			}
+		}
+	}
+
+	var candidates []string
+	switch {
+	case pending != nil:
+		candidates = flagCandidates(pending, cur, "")
+	case strings.HasPrefix(cur, "--") && strings.Contains(cur, "="):
+		name, value, _ := strings.Cut(cur[2:], "=")
+		if flag := current.completionLongFlag(name); flag != nil && flag.HasArg != NoArgument {
+			candidates = flagCandidates(flag, value, "--"+name+"=")
+		}
+	case strings.HasPrefix(cur, "-"):
+		opts, _ := current.completionOptions()
+		candidates = filterPrefix(opts, cur)
+	default:
+		for name, cmd := range current.Commands {
+			if cmd != nil && strings.HasPrefix(name, cur) {
+				candidates = append(candidates, name)
+			}
+		}
+	}
+	slices.Sort(candidates)
+	return candidates
+}
+
+// ServeCompletion answers a completion query when args (os.Args[1:]) start
+// with [CompleteCommand] and reports whether it did. The query is
+// "__complete LINE [POINT]", or, when the COMP_LINE environment variable
+// is set, COMP_LINE and COMP_POINT; the latter lets bash call the program
+// directly:
+//
+//	complete -C 'prog __complete' prog
+//
+// Candidates are written to w one per line.
+//
+//	if root.ServeCompletion(os.Args[1:], os.Stdout) {
+//		return
+//	}
+func (p *Parser) ServeCompletion(args []string, w io.Writer) bool {
+	if len(args) == 0 || args[0] != CompleteCommand {
+		return false
+	}
+	var line, point string
+	if env, ok := os.LookupEnv("COMP_LINE"); ok {
+		line, point = env, os.Getenv("COMP_POINT")
+	} else if len(args) > 1 {
+		line = args[1]
+		if len(args) > 2 {
+			point = args[2]
+		}
+	}
+	at, err := strconv.Atoi(point)
+	if err != nil {
+		at = len(line)
+	}
+	for _, c := range p.Complete(line, at) {
+		fmt.Fprintln(w, c)
+	}
+	return true
+}
+
+// completionLongFlag resolves a long option name typed on the command line
+// the way the parser would: exact match first, then a unique prefix.
+func (p *Parser) completionLongFlag(name string) *Flag {
+	if m := p.exactMatch(name); m.flag != nil {
+		return m.flag
+	}
+	if matches := p.prefixMatches(name); len(matches) == 1 {
+		return matches[0].flag
+	}
+	return nil
+}
+
+// completionShortCluster walks a short-option cluster (without its dash)
+// and returns the option that takes the next word as its argument, if any.
+func (p *Parser) completionShortCluster(cluster string) *Flag {
+	for i := 0; i < len(cluster); {
+		n := 1
+		if _, size := utf8.DecodeRuneInString(cluster[i:]); size > 1 && p.resolveShort(cluster[i]) == nil {
+			n = size
+		}
+		_, flag := p.resolveShortName(cluster[i : i+n])
+		i += n
+		if flag == nil || flag.HasArg == NoArgument {
+			continue
+		}
+		if i == len(cluster) && flag.HasArg == RequiredArgument {
+			return flag
+		}
+		return nil // the rest of the cluster is the argument
+	}
+	return nil
+}
+
+// flagCandidates returns flag's completion values that start with value,
+// each prefixed with prefix.
+func flagCandidates(flag *Flag, value, prefix string) []string {
+	if flag.Complete == nil {
+		return nil
+	}
+	var out []string
+	for _, c := range flag.Complete(value) {
+		if strings.HasPrefix(c, value) {
+			out = append(out, prefix+c)
+		}
+	}
+	return out
+}
+
+func filterPrefix(words []string, prefix string) []string {
+	var out []string
+	for _, w := range words {
+		if strings.HasPrefix(w, prefix) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// splitCompletionLine splits a partial command line into words, honoring
+// single quotes, double quotes, and backslash escapes. A trailing blank
+// yields an empty final word, the word being started at the cursor.
+func splitCompletionLine(line string) []string {
+	var words []string
+	var b strings.Builder
+	inWord := false
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == '\'':
+			b.WriteByte(c)
+		case c == '\\' && i+1 < len(line):
+			i++
+			b.WriteByte(line[i])
+			inWord = true
+		case quote == '"':
+			b.WriteByte(c)
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, b.String())
+				b.Reset()
+				inWord = false
+			}
+		default:
+			b.WriteByte(c)
+			inWord = true
+		}
+	}
+	return append(words, b.String())
+}
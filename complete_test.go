@@ -0,0 +1,111 @@
+package optargs
+
+import (
+	"strings"
+	"testing"
+)
+
+func newDynamicCompletionTree(t *testing.T) *Parser {
+	t.Helper()
+	root := newCompletionTree(t)
+	files := func(string) []string { return []string{"a.txt", "b.txt", "notes.md"} }
+	root.longOpts["file"].Complete = files
+	root.shortOpts['f'].Complete = files
+	serve, _ := root.GetCommand("serve")
+	serve.longOpts["port"].Complete = func(string) []string { return []string{"80", "443", "8080"} }
+	return root
+}
+
+func TestComplete(t *testing.T) {
+	root := newDynamicCompletionTree(t)
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"prog", ""},
+		{"prog ", "s serve version"},
+		{"prog se", "serve"},
+		{"prog --", "--file --verbose"},
+		{"prog -", "--file --verbose -f -v"},
+		{"prog --file ", "a.txt b.txt notes.md"},
+		{"prog --file n", "notes.md"},
+		{"prog --fi ", "a.txt b.txt notes.md"},
+		{"prog -f ", "a.txt b.txt notes.md"},
+		{"prog -vf ", "a.txt b.txt notes.md"},
+		{"prog -fx ", "s serve version"},
+		{"prog --file=", "--file=a.txt --file=b.txt --file=notes.md"},
+		{"prog --file=b", "--file=b.txt"},
+		{"prog --verbose=", ""},
+		{"prog --file x ", "s serve version"},
+		{"prog s --port 8", "80 8080"},
+		{"prog serve --", "--file --port --verbose"},
+		{"prog serve status --j", "--json"},
+		{"prog version --", "--short"},
+		{"prog -- ", ""},
+		{`prog --file "no`, "notes.md"},
+		{`prog 'se`, "serve"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := strings.Join(root.Complete(tt.line, len(tt.line)), " ")
+			if got != tt.want {
+				t.Errorf("Complete(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletePoint(t *testing.T) {
+	root := newDynamicCompletionTree(t)
+	line := "prog se --verbose"
+	if got := strings.Join(root.Complete(line, 7), " "); got != "serve" {
+		t.Errorf("Complete at 7 = %q, want serve", got)
+	}
+	if got := root.Complete(line, 1000); len(got) != 1 || got[0] != "--verbose" {
+		t.Errorf("Complete past end = %q, want [--verbose]", got)
+	}
+	if got := root.Complete(line, -1); got != nil {
+		t.Errorf("Complete at -1 = %q, want nil", got)
+	}
+}
+
+func TestServeCompletion(t *testing.T) {
+	root := newDynamicCompletionTree(t)
+	var b strings.Builder
+	if root.ServeCompletion([]string{"serve"}, &b) {
+		t.Error("ServeCompletion handled a normal command line")
+	}
+	if !root.ServeCompletion([]string{CompleteCommand, "prog --file ", "12"}, &b) {
+		t.Fatal("ServeCompletion did not handle __complete")
+	}
+	if want := "a.txt\nb.txt\nnotes.md\n"; b.String() != want {
+		t.Errorf("output = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	t.Setenv("COMP_LINE", "prog ser")
+	t.Setenv("COMP_POINT", "8")
+	root.ServeCompletion([]string{CompleteCommand, "prog", "ser", "prog"}, &b)
+	if b.String() != "serve\n" {
+		t.Errorf("COMP_LINE output = %q, want %q", b.String(), "serve\n")
+	}
+}
+
+func TestSplitCompletionLine(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{""}},
+		{"prog", []string{"prog"}},
+		{"prog  a ", []string{"prog", "a", ""}},
+		{`prog "a b" 'c d' e\ f`, []string{"prog", "a b", "c d", "e f"}},
+		{`prog "it's`, []string{"prog", "it's"}},
+	}
+	for _, tt := range tests {
+		got := splitCompletionLine(tt.in)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("splitCompletionLine(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package optargs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// GenBashCompletion writes a bash completion script for p and every
+// subcommand registered below it with [Parser.AddCmd] or
+// [Parser.AddAlias]. The script completes short options, long options,
+// and subcommand names, tracks the subcommand path typed so far, skips the
+// argument of options that require one, and falls back to file names for
+// option arguments and operands. Subcommands that inherit their parent's
+// options (see [Parser.SetStrictSubcommands]) offer those options too.
+//
+// The completed command is p.Name, or the base name of os.Args[0] when
+// p.Name is empty. Load the script with `source <(prog completion)` or
+// install it under bash-completion's completions directory.
+func (p *Parser) GenBashCompletion(w io.Writer) error {
+	prog := p.Name
+	if prog == "" {
+		prog = filepath.Base(os.Args[0])
+	}
+	fn := "_" + shellIdent(prog)
+
+	var b strings.Builder
+	b.WriteString("# bash completion for " + prog + "\n")
+	b.WriteString(fn + "() {\n")
+	b.WriteString("\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("\tlocal cmd=" + shellQuote(prog) + " word i opts cmds argopts\n\n")
+
+	b.WriteString("\t# Walk the words before the cursor to find the active subcommand.\n")
+	b.WriteString("\tfor ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("\t\tword=\"${COMP_WORDS[i]}\"\n")
+	b.WriteString("\t\tcase \"$word\" in\n")
+	b.WriteString("\t\t--) return 0 ;;\n")
+	b.WriteString("\t\t-*)\n")
+	b.WriteString("\t\t\t" + fn + "_cmd \"$cmd\"\n")
+	b.WriteString("\t\t\tcase \" $argopts \" in *\" $word \"*) ((i++)) ;; esac\n")
+	b.WriteString("\t\t\t;;\n")
+	b.WriteString("\t\t*)\n")
+	b.WriteString("\t\t\tcase \"$cmd\"$'\\t'\"$word\" in\n")
+	bashTransitions(&b, p, prog)
+	b.WriteString("\t\t\tesac\n")
+	b.WriteString("\t\t\t;;\n")
+	b.WriteString("\t\tesac\n")
+	b.WriteString("\tdone\n\n")
+
+	b.WriteString("\t" + fn + "_cmd \"$cmd\"\n")
+	b.WriteString("\tif ((COMP_CWORD > 1)); then\n")
+	b.WriteString("\t\tcase \" $argopts \" in *\" ${COMP_WORDS[COMP_CWORD-1]} \"*) COMPREPLY=(); return 0 ;; esac\n")
+	b.WriteString("\tfi\n")
+	b.WriteString("\tif [[ $cur == -* ]]; then\n")
+	b.WriteString("\t\tCOMPREPLY=($(compgen -W \"$opts\" -- \"$cur\"))\n")
+	b.WriteString("\telse\n")
+	b.WriteString("\t\tCOMPREPLY=($(compgen -W \"$cmds\" -- \"$cur\"))\n")
+	b.WriteString("\tfi\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("# " + fn + "_cmd sets opts, cmds, and argopts for the subcommand path in $1.\n")
+	b.WriteString(fn + "_cmd() {\n")
+	b.WriteString("\tcase \"$1\" in\n")
+	bashCommandTables(&b, p, prog)
+	b.WriteString("\tesac\n")
+	b.WriteString("}\n\n")
+	b.WriteString("complete -o default -o bashdefault -F " + fn + " " + shellQuote(prog) + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// bashTransitions writes one case arm per registered command name below p,
+// moving $cmd from path to the subcommand's path.
+func bashTransitions(b *strings.Builder, p *Parser, path string) {
+	_, parsers := p.commandRows()
+	for _, cmd := range parsers {
+		names := commandNames(p, cmd)
+		child := path + " " + names[0]
+		patterns := make([]string, len(names))
+		for i, name := range names {
+			patterns[i] = shellQuote(path + "\t" + name)
+		}
+		b.WriteString("\t\t\t" + strings.Join(patterns, "|") + ") cmd=" + shellQuote(child) + " ;;\n")
+		bashTransitions(b, cmd, child)
+	}
+}
+
+// bashCommandTables writes the opts/cmds/argopts assignments for p and each
+// subcommand below it.
+func bashCommandTables(b *strings.Builder, p *Parser, path string) {
+	opts, argopts := p.completionOptions()
+	var cmds []string
+	for name, cmd := range p.Commands {
+		if cmd != nil {
+			cmds = append(cmds, name)
+		}
+	}
+	slices.Sort(cmds)
+
+	b.WriteString("\t" + shellQuote(path) + ")\n")
+	b.WriteString("\t\topts=" + shellQuote(strings.Join(opts, " ")) + "\n")
+	b.WriteString("\t\tcmds=" + shellQuote(strings.Join(cmds, " ")) + "\n")
+	b.WriteString("\t\targopts=" + shellQuote(strings.Join(argopts, " ")) + "\n")
+	b.WriteString("\t\t;;\n")
+
+	_, parsers := p.commandRows()
+	for _, cmd := range parsers {
+		bashCommandTables(b, cmd, path+" "+commandNames(p, cmd)[0])
+	}
+}
+
+// completionOptions returns the option spellings accepted by p, including
+// those inherited through the parent chain, and the spellings that consume
+// the following word as their argument. Both are sorted. Hidden flags are
+// not offered but still have their argument skipped.
+func (p *Parser) completionOptions() (opts, argopts []string) {
+	for _, opt := range p.AllOptions() {
+		if !opt.Flag.Hidden {
+			opts = append(opts, opt.Name)
+		}
+		if opt.Flag.HasArg == RequiredArgument {
+			argopts = append(argopts, opt.Name)
+		}
+	}
+	return opts, argopts
+}
+
+// shellQuote quotes s for bash as a single word.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("-_./=+,:@%", r))
+	}) < 0 {
+		return s
+	}
+	return "$'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\t", `\t`, "\n", `\n`).Replace(s) + "'"
+}
+
+// shellIdent maps s onto the characters allowed in a bash function name.
+func shellIdent(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
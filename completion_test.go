@@ -0,0 +1,127 @@
+package optargs
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// newCompletionTree builds prog with -v/--verbose and --file=FILE, a serve
+// subcommand (alias s) with --port=PORT and a nested "status" command, and
+// a strict "version" subcommand that does not inherit prog's options.
+func newCompletionTree(t *testing.T) *Parser {
+	t.Helper()
+	root, err := GetOptLong(nil, "vf:", []Flag{
+		{Name: "verbose"},
+		{Name: "file", HasArg: RequiredArgument},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.Name = "prog"
+	serve, err := GetOptLong(nil, "", []Flag{{Name: "port", HasArg: RequiredArgument}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := GetOptLong(nil, "", []Flag{{Name: "json"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	version, err := GetOptLong(nil, "", []Flag{{Name: "short"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("serve", serve)
+	if err := root.AddAlias("s", "serve"); err != nil {
+		t.Fatal(err)
+	}
+	serve.AddCmd("status", status)
+	root.SetStrictSubcommands(true)
+	root.AddCmd("version", version)
+	return root
+}
+
+func TestGenBashCompletionTables(t *testing.T) {
+	var b strings.Builder
+	if err := newCompletionTree(t).GenBashCompletion(&b); err != nil {
+		t.Fatal(err)
+	}
+	script := b.String()
+	for _, want := range []string{
+		"# bash completion for prog\n_prog() {",
+		"\t\t\t$'prog\\tserve'|$'prog\\ts') cmd=$'prog serve' ;;\n",
+		"\t\t\t$'prog serve\\tstatus') cmd=$'prog serve status' ;;\n",
+		"\tprog)\n\t\topts=$'--file --verbose -f -v'\n\t\tcmds=$'s serve version'\n\t\targopts=$'--file -f'\n",
+		"\t$'prog serve')\n\t\topts=$'--file --port --verbose -f -v'\n\t\tcmds=status\n\t\targopts=$'--file --port -f'\n",
+		"\t$'prog version')\n\t\topts=--short\n\t\tcmds=''\n\t\targopts=''\n",
+		"complete -o default -o bashdefault -F _prog prog\n",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+}
+
+// TestGenBashCompletionBehavior sources the generated script in bash and
+// checks the candidates offered for a range of command lines.
+func TestGenBashCompletionBehavior(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+	var b strings.Builder
+	if err := newCompletionTree(t).GenBashCompletion(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line string // words typed; the last is being completed
+		want string
+	}{
+		{"prog ''", "s serve version"},
+		{"prog se", "serve"},
+		{"prog --", "--file --verbose"},
+		{"prog -v --f", "--file"},
+		{"prog --file ''", ""},
+		{"prog --file serve ''", "s serve version"},
+		{"prog serve --", "--file --port --verbose"},
+		{"prog s ''", "status"},
+		{"prog -v serve status --", "--file --json --port --verbose"},
+		{"prog version --", "--short"},
+		{"prog -- ''", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			script := b.String() + `
+COMP_WORDS=(` + tt.line + `)
+COMP_CWORD=$((${#COMP_WORDS[@]} - 1))
+COMPREPLY=()
+_prog
+echo "${COMPREPLY[*]}"
+`
+			out, err := exec.Command(bash, "--norc", "--noprofile", "-c", script).CombinedOutput()
+			if err != nil {
+				t.Fatalf("bash: %v\n%s", err, out)
+			}
+			if got := strings.TrimSuffix(string(out), "\n"); got != tt.want {
+				t.Errorf("completions = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"prog", "prog"},
+		{"--file", "--file"},
+		{"", "''"},
+		{"a b", "$'a b'"},
+		{"it's", `$'it\'s'`},
+		{"a\tb", `$'a\tb'`},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.in); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package optargs
+
+import "slices"
+
+// Count returns how many times the option name was parsed by p, counting
+// every occurrence whether it was yielded or dispatched to a handler, so
+// "-vvv" or a repeated "--verbose" gives 3. name is given without dashes
+// and is resolved as it would be on the command line: a single character
+// is tried as a short option first, aliases and inherited options are
+// found, and a short and a long option linked through Flag.Peer share one
+// count. It is meant to be called once [Parser.Options] iteration has
+// finished; the count restarts when p is dispatched as a subcommand again.
+func (p *Parser) Count(name string) int {
+	flag := p.resolveName(name)
+	if flag == nil {
+		return 0
+	}
+	n := p.counts[flag]
+	if flag.Peer != nil && flag.Peer != flag {
+		n += p.counts[flag.Peer]
+	}
+	return n
+}
+
+// resolveName returns the flag an option name without dashes resolves to
+// on p: a single character is tried as a short option first, then the
+// name as a long option or alias, searching the parent chain.
+func (p *Parser) resolveName(name string) *Flag {
+	if _, flag := p.resolveShortName(name); flag != nil {
+		return flag
+	}
+	return p.exactMatch(name).flag
+}
+
+// countOccurrence records one parse of flag, with the argument of option,
+// for Count and Values.
+func (p *Parser) countOccurrence(flag *Flag, option Option) {
+	if flag == nil {
+		return
+	}
+	if p.counts == nil {
+		p.counts = make(map[*Flag]int)
+	}
+	p.counts[flag]++
+	if duplicatePolicy(flag) == DuplicateLast {
+		p.occurrences = slices.DeleteFunc(p.occurrences, func(o occurrence) bool { return sameOption(o.flag, flag) })
+	}
+	p.occurrences = append(p.occurrences, occurrence{flag: flag, arg: option.Arg})
+}
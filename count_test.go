@@ -0,0 +1,63 @@
+package optargs
+
+import "testing"
+
+func TestCount(t *testing.T) {
+	verbose := &Flag{Name: "v", HasArg: NoArgument}
+	verboseLong := &Flag{Name: "verbose", HasArg: NoArgument, Peer: verbose, Aliases: []string{"chatty"}}
+	verbose.Peer = verboseLong
+	quiet := &Flag{Name: "quiet", HasArg: NoArgument, Handle: func(string, string) error { return nil }}
+
+	tests := []struct {
+		name  string
+		args  []string
+		query string
+		want  int
+	}{
+		{"compacted", []string{"-vvv"}, "v", 3},
+		{"peer by short", []string{"-v", "--verbose", "-vv"}, "v", 4},
+		{"peer by long", []string{"-v", "--verbose"}, "verbose", 2},
+		{"alias", []string{"--chatty", "--verb"}, "chatty", 2},
+		{"handler", []string{"--quiet", "--quiet"}, "quiet", 2},
+		{"absent", []string{"file"}, "verbose", 0},
+		{"unknown", []string{"-v"}, "nope", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(ParserConfig{},
+				map[byte]*Flag{'v': verbose},
+				map[string]*Flag{"verbose": verboseLong, "quiet": quiet},
+				tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requireParsedOptions(t, p)
+			if got := p.Count(tt.query); got != tt.want {
+				t.Errorf("Count(%q) = %d, want %d", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountSubcommand(t *testing.T) {
+	root, err := GetOptLong([]string{"-v", "sub", "-vv"}, "v", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := NewParser(ParserConfig{}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("sub", sub)
+
+	requireParsedOptions(t, root)
+	for _, child := range root.Dispatches() {
+		requireParsedOptions(t, child)
+	}
+	if got := root.Count("v"); got != 1 {
+		t.Errorf("root Count(v) = %d, want 1", got)
+	}
+	if got := sub.Count("v"); got != 2 {
+		t.Errorf("sub Count(v) = %d, want 2", got)
+	}
+}
@@ -0,0 +1,41 @@
+package optargs
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// SetDeprecationHandler sets the function called each time an option with
+// a non-empty Flag.Deprecated is parsed, before the option is handled or
+// yielded; parsing continues normally afterwards. name is the option as
+// spelled on the command line after resolution ("--color", "-c"). Pass
+// nil to restore the default, which in [ErrorReport] mode writes
+//
+//	option --color is deprecated: use --colour
+//
+// to the error writer (see [ParserConfig.SetErrorWriter]), or logs it with
+// slog.Warn when none is set. In [ErrorSilent] mode the default reports
+// nothing.
+func (p *Parser) SetDeprecationHandler(fn func(name string, flag *Flag)) {
+	p.config.onDeprecated = fn
+}
+
+// warnDeprecated reports the use of flag when it is deprecated.
+func (p *Parser) warnDeprecated(name string, flag *Flag) {
+	if flag == nil || flag.Deprecated == "" {
+		return
+	}
+	if p.config.onDeprecated != nil {
+		p.config.onDeprecated(name, flag)
+		return
+	}
+	if p.config.errorMode != ErrorReport {
+		return
+	}
+	msg := "option " + name + " is deprecated: " + flag.Deprecated
+	if p.config.errorWriter != nil {
+		fmt.Fprintln(p.config.errorWriter, msg)
+		return
+	}
+	slog.Warn(msg)
+}
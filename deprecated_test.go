@@ -0,0 +1,107 @@
+package optargs
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newDeprecatedParser(t *testing.T, args []string) *Parser {
+	t.Helper()
+	color := &Flag{Name: "color", HasArg: RequiredArgument, Deprecated: "use --theme"}
+	c := &Flag{Name: "c", HasArg: RequiredArgument, Deprecated: "use -t", Peer: color}
+	color.Peer = c
+	p, err := NewParser(ParserConfig{},
+		map[byte]*Flag{'c': c, 'v': {Name: "v"}},
+		map[string]*Flag{"color": color, "theme": {Name: "theme", HasArg: RequiredArgument}},
+		args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestDeprecatedOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantOpts []Option
+		wantWarn []string
+	}{
+		{
+			name:     "long",
+			args:     []string{"--color=red", "--theme", "dark"},
+			wantOpts: []Option{{Name: "color", HasArg: true, Arg: "red"}, {Name: "theme", HasArg: true, Arg: "dark"}},
+			wantWarn: []string{"--color: use --theme"},
+		},
+		{
+			name:     "long prefix",
+			args:     []string{"--col", "red"},
+			wantOpts: []Option{{Name: "color", HasArg: true, Arg: "red"}},
+			wantWarn: []string{"--color: use --theme"},
+		},
+		{
+			name:     "short in cluster",
+			args:     []string{"-vcred"},
+			wantOpts: []Option{{Name: "v"}, {Name: "c", HasArg: true, Arg: "red"}},
+			wantWarn: []string{"-c: use -t"},
+		},
+		{
+			name:     "not used",
+			args:     []string{"-v"},
+			wantOpts: []Option{{Name: "v"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newDeprecatedParser(t, tt.args)
+			var warnings []string
+			p.SetDeprecationHandler(func(name string, flag *Flag) {
+				warnings = append(warnings, name+": "+flag.Deprecated)
+			})
+			assertOptions(t, requireParsedOptions(t, p), tt.wantOpts)
+			if !slices.Equal(warnings, tt.wantWarn) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestDeprecatedDefaultWarning(t *testing.T) {
+	p := newDeprecatedParser(t, []string{"--color", "red"})
+	var b strings.Builder
+	p.config.SetErrorWriter(&b)
+	p.SetErrorMode(ErrorReport)
+	var handled []string
+	if err := p.SetHandler("--color", func(_, arg string) error {
+		handled = append(handled, arg)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	requireParsedOptions(t, p)
+	if want := "option --color is deprecated: use --theme\n"; b.String() != want {
+		t.Errorf("warning = %q, want %q", b.String(), want)
+	}
+	if !slices.Equal(handled, []string{"red"}) {
+		t.Errorf("handler saw %q, want [red]", handled)
+	}
+
+	b.Reset()
+	if err := p.WriteUsage(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "(deprecated: use -t)") {
+		t.Errorf("usage does not note the deprecation:\n%s", b.String())
+	}
+}
+
+func TestDeprecatedSilent(t *testing.T) {
+	p := newDeprecatedParser(t, []string{"--color", "red", "-c", "blue"})
+	var b strings.Builder
+	p.config.SetErrorWriter(&b)
+	requireParsedOptions(t, p)
+	if b.Len() != 0 {
+		t.Errorf("ErrorSilent parser wrote %q", b.String())
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/major0/optargs/docs/examples
 
-go 1.23
+go 1.23.4
 
 require github.com/major0/optargs v0.0.0
 
@@ -0,0 +1,48 @@
+package optargs
+
+// DuplicatePolicy selects what the parser does when an option is given
+// more than once; see Flag.DuplicatePolicy.
+type DuplicatePolicy int
+
+const (
+	// DuplicateAllow handles and yields every occurrence. It is the
+	// default, suited to options that accumulate, such as -v or --include.
+	DuplicateAllow DuplicatePolicy = iota
+	// DuplicateFirst keeps the first occurrence and silently skips later
+	// ones: they are neither handled, yielded, nor counted.
+	DuplicateFirst
+	// DuplicateLast lets each occurrence replace the previous one: every
+	// occurrence is handled or yielded in order, so the last assignment
+	// wins, and Parser.Values reports only the last argument.
+	DuplicateLast
+	// DuplicateReject yields every occurrence after the first with a
+	// *DuplicateOptionError in place of the option.
+	DuplicateReject
+)
+
+// duplicatePolicy returns the policy of flag, or of its Peer when flag
+// has none.
+func duplicatePolicy(flag *Flag) DuplicatePolicy {
+	if flag.DuplicatePolicy == DuplicateAllow && flag.Peer != nil {
+		return flag.Peer.DuplicatePolicy
+	}
+	return flag.DuplicatePolicy
+}
+
+// skipDuplicate reports whether flag is a DuplicateFirst option already
+// parsed by p or a parser on its parent chain.
+func (p *Parser) skipDuplicate(flag *Flag) bool {
+	return flag != nil && duplicatePolicy(flag) == DuplicateFirst && p.seenAbove(flag)
+}
+
+// checkDuplicate reports a *DuplicateOptionError when flag is a
+// DuplicateReject option already parsed by p or a parser on its parent
+// chain.
+func (p *Parser) checkDuplicate(flag *Flag, option Option) error {
+	if flag == nil || duplicatePolicy(flag) != DuplicateReject || !p.seenAbove(flag) {
+		return nil
+	}
+	err := &DuplicateOptionError{Name: option.Name}
+	p.report(err)
+	return err
+}
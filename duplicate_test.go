@@ -0,0 +1,102 @@
+package optargs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDuplicatePolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     DuplicatePolicy
+		args       []string
+		wantOpts   []Option
+		wantErrs   []string // names of rejected options, in order
+		wantValues []string
+	}{
+		{
+			name:       "allow",
+			policy:     DuplicateAllow,
+			args:       []string{"--output=a", "-ob", "-vo", "c"},
+			wantOpts:   []Option{{Name: "output", HasArg: true, Arg: "a"}, {Name: "o", HasArg: true, Arg: "b"}, {Name: "v"}, {Name: "o", HasArg: true, Arg: "c"}},
+			wantValues: []string{"a", "b", "c"},
+		},
+		{
+			name:       "first",
+			policy:     DuplicateFirst,
+			args:       []string{"--output=a", "-ob", "-vo", "c", "-v"},
+			wantOpts:   []Option{{Name: "output", HasArg: true, Arg: "a"}, {Name: "v"}, {Name: "v"}},
+			wantValues: []string{"a"},
+		},
+		{
+			name:       "last",
+			policy:     DuplicateLast,
+			args:       []string{"--output=a", "-ob"},
+			wantOpts:   []Option{{Name: "output", HasArg: true, Arg: "a"}, {Name: "o", HasArg: true, Arg: "b"}},
+			wantValues: []string{"b"},
+		},
+		{
+			name:       "reject",
+			policy:     DuplicateReject,
+			args:       []string{"-oa", "--output", "b", "-vo", "c"},
+			wantOpts:   []Option{{Name: "o", HasArg: true, Arg: "a"}, {Name: "v"}},
+			wantErrs:   []string{"output", "o"},
+			wantValues: []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &Flag{Name: "output", HasArg: RequiredArgument, DuplicatePolicy: tt.policy}
+			short := &Flag{Name: "o", HasArg: RequiredArgument, Peer: output}
+			output.Peer = short
+			p, err := NewParser(ParserConfig{},
+				map[byte]*Flag{'o': short, 'v': {Name: "v"}},
+				map[string]*Flag{"output": output},
+				tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var opts []Option
+			var errs []string
+			for opt, err := range p.Options() {
+				if err != nil {
+					var derr *DuplicateOptionError
+					if !errors.As(err, &derr) {
+						t.Fatalf("error %v is not a *DuplicateOptionError", err)
+					}
+					errs = append(errs, derr.Name)
+					continue
+				}
+				opts = append(opts, opt)
+			}
+			assertOptions(t, opts, tt.wantOpts)
+			if !slices.Equal(errs, tt.wantErrs) {
+				t.Errorf("rejected %v, want %v", errs, tt.wantErrs)
+			}
+			if got := p.Values("output"); !slices.Equal(got, tt.wantValues) {
+				t.Errorf("Values(output) = %q, want %q", got, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestDuplicatePolicyHandler(t *testing.T) {
+	var got []string
+	p, err := NewParser(ParserConfig{}, nil, map[string]*Flag{
+		"mode": {
+			Name: "mode", HasArg: RequiredArgument, DuplicatePolicy: DuplicateReject,
+			Handle: func(_, arg string) error { got = append(got, arg); return nil },
+		},
+	}, []string{"--mode=fast", "--mode=slow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.Collect()
+	if err == nil || err.Error() != "option mode may only be given once" {
+		t.Errorf("Collect error = %v", err)
+	}
+	if !slices.Equal(got, []string{"fast"}) {
+		t.Errorf("handled %q, want [fast]", got)
+	}
+}
@@ -1,13 +1,40 @@
 package optargs
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrIterationInProgress is returned by [Parser.Remaining] and
+// [Parser.Collect] when called from inside a range loop over the same
+// parser's [Parser.Options].
+var ErrIterationInProgress = errors.New("optargs: called during Options iteration")
+
 // UnknownOptionError is returned when the parser encounters an option
 // that is not registered in either the short or long option maps.
+//
+// For an unknown long option with an attached value, such as
+// --colr=auto, the word is split at its first '=': Name holds "colr" and
+// Value "auto". The whole word is consumed either way, so the value is
+// never left behind as an operand.
+//
+// For an unknown long option, Suggestions lists the registered long
+// option names Name is likely a misspelling of, closest first and without
+// dashes (see [Parser.SuggestOptions]). Error leaves them out so callers
+// can present them as they see fit.
 type UnknownOptionError struct {
-	Name    string // option name without dashes (e.g., "verbose", "x")
-	IsShort bool   // true if this was a short option (-x), false for long (--verbose)
+	Name        string   // option name without dashes (e.g., "verbose", "x")
+	IsShort     bool     // true if this was a short option (-x), false for long (--verbose)
+	Value       string   // value attached to a long option with '='
+	HasValue    bool     // true if a value was attached, even an empty one
+	Suggestions []string // close long option names, without dashes (may be nil)
 }
 
 func (e *UnknownOptionError) Error() string {
+	if e.HasValue {
+		return "unknown option: " + e.Name + "=" + e.Value
+	}
 	return "unknown option: " + e.Name
 }
 
@@ -23,22 +50,152 @@ func (e *MissingArgumentError) Error() string {
 }
 
 // AmbiguousOptionError is returned when a long option prefix matches
-// multiple registered options at the same length.
+// multiple registered options at the same length. Matches lists the
+// candidates so callers can suggest them:
+//
+//	ambiguous option: fil (could be file, filter)
 type AmbiguousOptionError struct {
-	Name    string   // the ambiguous input
-	Matches []string // all matching option names (may be nil if not collected)
+	Name    string   // the ambiguous input, without dashes or =value
+	Matches []string // matching option names, sorted (may be nil if not collected)
 }
 
 func (e *AmbiguousOptionError) Error() string {
-	return "ambiguous option: " + e.Name
+	if len(e.Matches) == 0 {
+		return "ambiguous option: " + e.Name
+	}
+	return "ambiguous option: " + e.Name + " (could be " + strings.Join(e.Matches, ", ") + ")"
 }
 
 // UnexpectedArgumentError is returned when a NoArgument option receives
 // a =value argument.
 type UnexpectedArgumentError struct {
-	Name string // option name without dashes
+	Name  string // option name without dashes
+	Value string // the argument attached with '=' (or ':' for /opt:value)
 }
 
 func (e *UnexpectedArgumentError) Error() string {
 	return "option does not take an argument: " + e.Name
 }
+
+// ControlCharError is returned by [RejectControlChars] when an argument
+// contains a control character.
+type ControlCharError struct {
+	Arg    string // the offending argument
+	Offset int    // byte offset of Char within Arg
+	Char   rune   // the control character
+}
+
+func (e *ControlCharError) Error() string {
+	return fmt.Sprintf("control character %U in argument: %q", e.Char, e.Arg)
+}
+
+// UnknownCommandError is returned when command-first ordering is enabled
+// (see [ParserConfig.SetCommandFirst]) and the first non-option argument
+// is not a registered command.
+type UnknownCommandError struct {
+	Name        string   // the offending argument
+	Commands    []string // registered command names, sorted
+	Suggestions []string // likely intended commands, closest first; see Parser.SuggestCommands
+}
+
+func (e *UnknownCommandError) Error() string {
+	if len(e.Suggestions) > 0 {
+		return "unknown command: " + e.Name + " (did you mean " + strings.Join(e.Suggestions, ", ") + "?)"
+	}
+	if len(e.Commands) == 0 {
+		return "unknown command: " + e.Name
+	}
+	return "unknown command: " + e.Name + " (expected one of: " + strings.Join(e.Commands, ", ") + ")"
+}
+
+// InvalidArgumentError is returned by the [Option] accessors, such as
+// [Option.Int], when the option argument cannot be converted. Err is the
+// underlying conversion error, such as a *strconv.NumError.
+type InvalidArgumentError struct {
+	Name string // option name without dashes
+	Arg  string // the offending argument
+	Type string // requested type: "int", "bool", "float", "duration"
+	Err  error
+}
+
+func (e *InvalidArgumentError) Error() string {
+	return fmt.Sprintf("invalid %s argument for option %s: %q", e.Type, e.Name, e.Arg)
+}
+
+func (e *InvalidArgumentError) Unwrap() error { return e.Err }
+
+// ValidationError is yielded by the Options iterator when a Flag.Validate
+// hook rejects an option argument. Err is the error the hook returned.
+type ValidationError struct {
+	Name string // option name without dashes
+	Arg  string // the rejected argument
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid argument for option %s: %q: %v", e.Name, e.Arg, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// RequiredOptionError is returned by [Parser.CheckRequired] when options
+// marked Flag.Required were not given:
+//
+//	missing required options: --output, -f
+type RequiredOptionError struct {
+	Names []string // missing options with dashes, sorted per parser level
+}
+
+func (e *RequiredOptionError) Error() string {
+	if len(e.Names) == 1 {
+		return "missing required option: " + e.Names[0]
+	}
+	return "missing required options: " + strings.Join(e.Names, ", ")
+}
+
+// ConflictError is yielded by the Options iterator when an option of a
+// group declared with [Parser.MutuallyExclusive] is parsed after another
+// member of the group.
+type ConflictError struct {
+	Name string // option name without dashes
+	With string // the member parsed earlier, without dashes
+}
+
+func (e *ConflictError) Error() string {
+	return "option " + e.Name + " conflicts with " + e.With
+}
+
+// DuplicateOptionError is yielded by the Options iterator when an option
+// whose Flag.DuplicatePolicy is DuplicateReject is given again.
+type DuplicateOptionError struct {
+	Name string // option name without dashes
+}
+
+func (e *DuplicateOptionError) Error() string {
+	return "option " + e.Name + " may only be given once"
+}
+
+// DependencyError is reported by [Parser.CheckRequired] when an option
+// declared with [Parser.Requires] was given without an option it requires.
+type DependencyError struct {
+	Name     string // the option given, with dashes
+	Requires string // the required option that is missing, with dashes
+}
+
+func (e *DependencyError) Error() string {
+	return "option " + e.Name + " requires " + e.Requires
+}
+
+// UnknownSubOptionError is yielded by [SubOpts] for a key that is not in
+// its token table.
+type UnknownSubOptionError struct {
+	Key    string   // the unknown key, without its value
+	Tokens []string // the accepted keys
+}
+
+func (e *UnknownSubOptionError) Error() string {
+	if len(e.Tokens) == 0 {
+		return "unknown suboption: " + e.Key
+	}
+	return "unknown suboption: " + e.Key + " (expected one of: " + strings.Join(e.Tokens, ", ") + ")"
+}
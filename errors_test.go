@@ -1,7 +1,10 @@
 package optargs
 
 import (
+	"bytes"
 	"errors"
+	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -18,6 +21,11 @@ func TestTypedErrorMessages(t *testing.T) {
 			err:  &UnknownOptionError{Name: "verbose", IsShort: false},
 			want: "unknown option: verbose",
 		},
+		{
+			name: "unknown long option with value",
+			err:  &UnknownOptionError{Name: "colr", Value: "a=b", HasValue: true},
+			want: "unknown option: colr=a=b",
+		},
 		{
 			name: "unknown short option",
 			err:  &UnknownOptionError{Name: "x", IsShort: true},
@@ -38,6 +46,11 @@ func TestTypedErrorMessages(t *testing.T) {
 			err:  &AmbiguousOptionError{Name: "verb"},
 			want: "ambiguous option: verb",
 		},
+		{
+			name: "ambiguous option with matches",
+			err:  &AmbiguousOptionError{Name: "fil", Matches: []string{"file", "filter"}},
+			want: "ambiguous option: fil (could be file, filter)",
+		},
 		{
 			name: "unexpected argument",
 			err:  &UnexpectedArgumentError{Name: "verbose"},
@@ -112,13 +125,14 @@ func TestTypedErrorsAs(t *testing.T) {
 // iterator are classifiable via errors.As() to the correct typed error.
 func TestTypedErrorsFromParser(t *testing.T) {
 	tests := []struct {
-		name      string
-		shortOpts map[byte]*Flag
-		longOpts  map[string]*Flag
-		args      []string
-		wantType  string // "unknown", "missing", "ambiguous"
-		wantShort bool
-		wantName  string
+		name        string
+		shortOpts   map[byte]*Flag
+		longOpts    map[string]*Flag
+		args        []string
+		wantType    string // "unknown", "missing", "ambiguous"
+		wantShort   bool
+		wantName    string
+		wantMatches []string
 	}{
 		{
 			name:      "unknown long option",
@@ -152,6 +166,29 @@ func TestTypedErrorsFromParser(t *testing.T) {
 			wantShort: true,
 			wantName:  "o",
 		},
+		{
+			name: "ambiguous prefix",
+			longOpts: map[string]*Flag{
+				"filter": {Name: "filter", HasArg: RequiredArgument},
+				"file":   {Name: "file", HasArg: RequiredArgument},
+				"format": {Name: "format"},
+			},
+			args:        []string{"--fil", "x"},
+			wantType:    "ambiguous",
+			wantName:    "fil",
+			wantMatches: []string{"file", "filter"},
+		},
+		{
+			name: "ambiguous prefix with inline argument",
+			longOpts: map[string]*Flag{
+				"filter": {Name: "filter", HasArg: RequiredArgument},
+				"file":   {Name: "file", HasArg: RequiredArgument},
+			},
+			args:        []string{"--fi=x"},
+			wantType:    "ambiguous",
+			wantName:    "fi",
+			wantMatches: []string{"file", "filter"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -208,7 +245,153 @@ func TestTypedErrorsFromParser(t *testing.T) {
 				if ae.Name != tt.wantName {
 					t.Errorf("Name = %q, want %q", ae.Name, tt.wantName)
 				}
+				if !slices.Equal(ae.Matches, tt.wantMatches) {
+					t.Errorf("Matches = %q, want %q", ae.Matches, tt.wantMatches)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorModeAndWriter(t *testing.T) {
+	tests := []struct {
+		name      string
+		optstring string
+		mode      *ErrorMode // override after construction
+		want      string
+	}{
+		{"getopt reports by default", "a", nil, "unknown option: x\n"},
+		{"colon prefix silences", ":a", nil, ""},
+		{"SetErrorMode overrides colon", ":a", ptrTo(ErrorReport), "unknown option: x\n"},
+		{"SetErrorMode silences", "a", ptrTo(ErrorSilent), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p, err := GetOpt([]string{"-x", "-a"}, tt.optstring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.SetErrorWriter(&buf)
+			if tt.mode != nil {
+				p.SetErrorMode(*tt.mode)
+			}
+			var errs int
+			for _, err := range p.Options() {
+				if err != nil {
+					errs++
+				}
+			}
+			if errs != 1 {
+				t.Errorf("got %d errors, want 1 regardless of mode", errs)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("diagnostics = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestUnknownLongOptionValue verifies that an unknown long option keeps
+// its attached value in the error, in both error modes, without leaving
+// the value behind as an operand.
+func TestUnknownLongOptionValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		optstring string
+		longOnly  bool
+		args      []string
+		want      UnknownOptionError
+		wantDiag  string
+	}{
+		{
+			name: "reported", optstring: "v",
+			args:     []string{"--colr=auto", "op"},
+			want:     UnknownOptionError{Name: "colr", Value: "auto", HasValue: true},
+			wantDiag: "unknown option: colr=auto\n",
+		},
+		{
+			name: "silent", optstring: ":v",
+			args: []string{"--colr=auto", "op"},
+			want: UnknownOptionError{Name: "colr", Value: "auto", HasValue: true},
+		},
+		{
+			name: "empty value", optstring: ":v",
+			args: []string{"--colr=", "op"},
+			want: UnknownOptionError{Name: "colr", HasValue: true},
+		},
+		{
+			name: "value containing equals", optstring: ":v",
+			args: []string{"--colr=a=b", "op"},
+			want: UnknownOptionError{Name: "colr", Value: "a=b", HasValue: true},
+		},
+		{
+			name: "no value", optstring: ":v",
+			args: []string{"--colr", "op"},
+			want: UnknownOptionError{Name: "colr"},
+		},
+		{
+			name: "long-only without short fallback", optstring: ":", longOnly: true,
+			args: []string{"-colr=auto", "op"},
+			want: UnknownOptionError{Name: "colr", Value: "auto", HasValue: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			longOpts := []Flag{{Name: "color", HasArg: OptionalArgument}, {Name: "colour"}}
+			newParser := GetOptLong
+			if tt.longOnly {
+				newParser = GetOptLongOnly
+			}
+			p, err := newParser(tt.args, tt.optstring, longOpts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			p.SetErrorWriter(&buf)
+
+			var errs []error
+			for _, err := range p.Options() {
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+			var unknown *UnknownOptionError
+			if len(errs) != 1 || !errors.As(errs[0], &unknown) {
+				t.Fatalf("errors = %v, want one *UnknownOptionError", errs)
+			}
+			// Every case misspells --color.
+			tt.want.Suggestions = []string{"color"}
+			if !reflect.DeepEqual(*unknown, tt.want) {
+				t.Errorf("error = %+v, want %+v", *unknown, tt.want)
+			}
+			if buf.String() != tt.wantDiag {
+				t.Errorf("diagnostics = %q, want %q", buf.String(), tt.wantDiag)
+			}
+			if !slices.Equal(p.Args, []string{"op"}) {
+				t.Errorf("Args = %q, want [op]", p.Args)
 			}
 		})
 	}
 }
+
+func TestParserConfigErrorMode(t *testing.T) {
+	var buf bytes.Buffer
+	var config ParserConfig
+	if config.ErrorMode() != ErrorSilent {
+		t.Errorf("zero ParserConfig mode = %v, want ErrorSilent", config.ErrorMode())
+	}
+	config.SetErrorMode(ErrorReport)
+	config.SetErrorWriter(&buf)
+	p, err := NewParser(config, nil, map[string]*Flag{"alpha": {Name: "alpha"}}, []string{"--beta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range p.Options() {
+	}
+	if want := "unknown option: beta\n"; buf.String() != want {
+		t.Errorf("diagnostics = %q, want %q", buf.String(), want)
+	}
+}
+
+func ptrTo[T any](v T) *T { return &v }
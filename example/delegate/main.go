@@ -0,0 +1,77 @@
+// Command delegate demonstrates forwarding everything after "--" to a
+// child process, the way wrappers such as env(1), nice(1), or timeout(1)
+// do.
+//
+// Usage:
+//
+//	go run ./delegate -v -C /tmp -- ls -l
+//
+// The wrapper's own options come before "--"; the command to run and its
+// arguments come after. Because the split is taken from the parser, a
+// "--" consumed as an option argument (as in "-C --") is not mistaken for
+// the terminator.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/major0/optargs"
+)
+
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 {
+		// Demo args when run without arguments
+		args = []string{"-v", "-C", os.TempDir(), "--", "ls", "-l"}
+	}
+
+	p, err := optargs.GetOptLong(args, "vC:", []optargs.Flag{
+		{Name: "verbose", HasArg: optargs.NoArgument},
+		{Name: "chdir", HasArg: optargs.RequiredArgument},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var verbose bool
+	var dir string
+	for opt, err := range p.Options() {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		switch opt.Name {
+		case "v", "verbose":
+			verbose = true
+		case "C", "chdir":
+			dir = opt.Arg
+		}
+	}
+
+	operands, rest := p.SplitArgs()
+	if len(operands) > 0 {
+		fmt.Fprintf(os.Stderr, "error: unexpected arguments before --: %q\n", operands)
+		os.Exit(1)
+	}
+	if len(rest) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: delegate [-v] [-C dir] -- command [args...]")
+		os.Exit(1)
+	}
+
+	cmd := exec.Command(rest[0], rest[1:]...) //nolint:gosec // running the user-supplied command is the point
+	cmd.Dir = dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if verbose {
+		fmt.Fprintf(os.Stderr, "running %q in %q\n", rest, dir)
+	}
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok { //nolint:errorlint // Run returns *exec.ExitError unwrapped
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,404 @@
+// Command minigit is a tutorial-sized imitation of git(1) built on the
+// native parser API. It parses a realistic command tree and prints what it
+// would do instead of touching a repository, so every feature it shows can
+// be exercised from main_test.go:
+//
+//   - a parser tree built with NewParser and AddCmd, two levels deep
+//     (minigit remote add), with a command alias (ci for commit);
+//   - root options inherited by every subcommand (-C, -v, -h);
+//   - options stored by Flag.Handle, and a context handler
+//     (Flag.HandleCtx) that knows which command's help was asked for;
+//   - required options, argument validation, and typed errors;
+//   - usage text from WriteUsage and shell completion from
+//     GenBashCompletion and ServeCompletion.
+//
+// Usage:
+//
+//	go run ./minigit -v commit -am 'first commit'
+//	go run ./minigit remote add -f origin https://example.com/repo.git
+//	go run ./minigit log --help
+//	source <(go run ./minigit completion)
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/major0/optargs"
+)
+
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 {
+		// Demo args when run without arguments
+		args = []string{"-v", "commit", "-am", "first commit"}
+	}
+	os.Exit(run(args, os.Stdout, os.Stderr))
+}
+
+// Exit statuses, as git uses them.
+const (
+	exitOK    = 0
+	exitError = 1 // the command failed
+	exitUsage = 2 // the command line was wrong
+)
+
+// logFormats are the values --format accepts, offered by completion.
+var logFormats = []string{"full", "oneline", "short"}
+
+// errHelp is returned by the -h handler to stop parsing; the parser whose
+// usage is wanted is recorded in app.help.
+var errHelp = errors.New("help requested")
+
+// app holds the settings gathered while parsing. Handlers write to it;
+// nothing reads the Option values yielded by the iterator except operands.
+type app struct {
+	stdout, stderr io.Writer
+
+	// root options, inherited by every subcommand
+	dir     string
+	verbose int
+	help    *optargs.Parser
+
+	// subcommand options
+	quiet, bare   bool // init
+	dryRun, force bool // add
+	message       string
+	all, amend    bool // commit
+	maxCount      int  // log; 0 is unlimited
+	oneline       bool
+	format        string
+	fetch         bool // remote add
+}
+
+// flagSet accumulates the option tables handed to optargs.NewParser.
+type flagSet struct {
+	short map[byte]*optargs.Flag
+	long  map[string]*optargs.Flag
+}
+
+func newFlagSet() *flagSet {
+	return &flagSet{short: map[byte]*optargs.Flag{}, long: map[string]*optargs.Flag{}}
+}
+
+// add registers f under its long name and, when short is non-zero, under
+// the short name as well. The two spellings are separate Flags linked
+// through Peer, so usage prints them on one row and either one satisfies
+// Required.
+func (fs *flagSet) add(short byte, f optargs.Flag) {
+	long := &f
+	if f.Name != "" {
+		fs.long[f.Name] = long
+	}
+	if short != 0 {
+		s := f
+		s.Name = string(short)
+		s.Peer, long.Peer = long, &s
+		fs.short[short] = &s
+	}
+}
+
+// set returns a handler that stores true in b.
+func set(b *bool) func(string, string) error {
+	return func(string, string) error {
+		*b = true
+		return nil
+	}
+}
+
+// store returns a handler that stores the option argument in s.
+func store(s *string) func(string, string) error {
+	return func(_, arg string) error {
+		*s = arg
+		return nil
+	}
+}
+
+// command builds one parser of the tree from its option table.
+func command(name, description string, fs *flagSet, args []string) *optargs.Parser {
+	p, err := optargs.NewParser(optargs.ParserConfig{}, fs.short, fs.long, args)
+	if err != nil {
+		// The option tables are fixed; an error here is a programming bug.
+		panic(err)
+	}
+	p.Name = name
+	p.Description = description
+	return p
+}
+
+// newParser builds the minigit command tree over args.
+func (a *app) newParser(args []string) *optargs.Parser {
+	fs := newFlagSet()
+	fs.add('C', optargs.Flag{
+		Name: "chdir", HasArg: optargs.RequiredArgument, ArgName: "PATH",
+		Help: "run as if started in PATH", Handle: store(&a.dir),
+	})
+	fs.add('v', optargs.Flag{
+		Name: "verbose", HasArg: optargs.NoArgument,
+		Help: "be more verbose; repeat for more detail",
+		Handle: func(string, string) error {
+			a.verbose++
+			return nil
+		},
+	})
+	fs.add('h', optargs.Flag{
+		Name: "help", HasArg: optargs.NoArgument,
+		Help: "show help for the command and exit",
+		// HandleCtx is given the parser being iterated, which is the
+		// subcommand when -h follows its name, so "minigit log -h"
+		// shows the help of log.
+		HandleCtx: func(p *optargs.Parser, _ optargs.Option) error {
+			a.help = p
+			return errHelp
+		},
+	})
+	root := command("minigit", "A tiny version control front end.", fs, args)
+	root.SetCommandFirst(true)
+
+	fs = newFlagSet()
+	fs.add('q', optargs.Flag{Name: "quiet", HasArg: optargs.NoArgument, Help: "only print errors", Handle: set(&a.quiet)})
+	fs.add(0, optargs.Flag{Name: "bare", HasArg: optargs.NoArgument, Help: "create a bare repository", Handle: set(&a.bare)})
+	root.AddCmd("init", command("init", "Create an empty repository.", fs, nil))
+
+	fs = newFlagSet()
+	fs.add('n', optargs.Flag{Name: "dry-run", HasArg: optargs.NoArgument, Help: "do not add, only show", Handle: set(&a.dryRun)})
+	fs.add('f', optargs.Flag{Name: "force", HasArg: optargs.NoArgument, Help: "allow adding ignored files", Handle: set(&a.force)})
+	root.AddCmd("add", command("add", "Add file contents to the index.", fs, nil))
+
+	fs = newFlagSet()
+	fs.add('m', optargs.Flag{
+		Name: "message", HasArg: optargs.RequiredArgument, ArgName: "MSG",
+		Help: "use MSG as the commit message", Required: true, Handle: store(&a.message),
+		Validate: func(arg string) error {
+			if strings.TrimSpace(arg) == "" {
+				return errors.New("empty commit message")
+			}
+			return nil
+		},
+	})
+	fs.add('a', optargs.Flag{Name: "all", HasArg: optargs.NoArgument, Help: "commit all changed files", Handle: set(&a.all)})
+	fs.add(0, optargs.Flag{Name: "amend", HasArg: optargs.NoArgument, Help: "replace the tip of the branch", Handle: set(&a.amend)})
+	root.AddCmd("commit", command("commit", "Record changes to the repository.", fs, nil))
+	if err := root.AddAlias("ci", "commit"); err != nil {
+		panic(err)
+	}
+
+	fs = newFlagSet()
+	fs.add('n', optargs.Flag{
+		Name: "max-count", HasArg: optargs.RequiredArgument, ArgName: "N",
+		Help: "show at most N commits",
+		Validate: func(arg string) error {
+			if n, err := strconv.Atoi(arg); err != nil || n < 0 {
+				return fmt.Errorf("not a non-negative number: %s", arg)
+			}
+			return nil
+		},
+		Handle: func(_, arg string) error {
+			a.maxCount, _ = strconv.Atoi(arg) // checked by Validate
+			return nil
+		},
+	})
+	fs.add(0, optargs.Flag{Name: "oneline", HasArg: optargs.NoArgument, Help: "same as --format=oneline", Handle: set(&a.oneline)})
+	fs.add(0, optargs.Flag{
+		Name: "format", HasArg: optargs.RequiredArgument, ArgName: "FORMAT",
+		Help: "pretty-print as " + strings.Join(logFormats, ", "), DefaultValue: "full",
+		Handle: store(&a.format),
+		Validate: func(arg string) error {
+			if !slices.Contains(logFormats, arg) {
+				return fmt.Errorf("unknown format: %s", arg)
+			}
+			return nil
+		},
+		Complete: func(string) []string { return logFormats },
+	})
+	root.AddCmd("log", command("log", "Show commit logs.", fs, nil))
+
+	remote := command("remote", "Manage tracked repositories.", newFlagSet(), nil)
+	remote.SetCommandFirst(true)
+	root.AddCmd("remote", remote)
+
+	fs = newFlagSet()
+	fs.add('f', optargs.Flag{Name: "fetch", HasArg: optargs.NoArgument, Help: "fetch the remote after adding it", Handle: set(&a.fetch)})
+	remote.AddCmd("add", command("add", "Add a remote named NAME for URL.", fs, nil))
+	remote.AddCmd("remove", command("remove", "Remove the remote named NAME.", newFlagSet(), nil))
+	if err := remote.AddAlias("rm", "remove"); err != nil {
+		panic(err)
+	}
+
+	root.AddCmd("completion", command("completion", "Print a bash completion script.", newFlagSet(), nil))
+	return root
+}
+
+// run parses args, carries out the command, and returns the exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	a := &app{stdout: stdout, stderr: stderr, format: "full"}
+	root := a.newParser(args)
+
+	// Dynamic completion: bash runs "minigit __complete" with COMP_LINE
+	// and COMP_POINT set; see the completion script.
+	if root.ServeCompletion(args, stdout) {
+		return exitOK
+	}
+
+	// Parse the root, then each dispatched subcommand in turn. Options are
+	// handled by their Flag handlers, so only errors matter here. Each
+	// level's operands are left in its Args.
+	path := []string{root.Name}
+	leaf := root
+	err := drain(root)
+	for name, cmd := range root.Dispatches() {
+		if err != nil {
+			break
+		}
+		path = append(path, name)
+		leaf = cmd
+		err = drain(cmd)
+	}
+	switch {
+	case errors.Is(err, errHelp):
+		if err := a.help.WriteUsage(stdout); err != nil {
+			return exitError
+		}
+		return exitOK
+	case err == nil:
+		err = root.CheckRequired()
+	}
+	if err != nil {
+		return a.usageError(leaf, path, err)
+	}
+
+	if a.dir != "" {
+		a.logf(1, "working in %s\n", a.dir)
+	}
+	return a.dispatch(root, strings.Join(path[1:], " "), leaf.Args)
+}
+
+// drain runs one parser's Options iteration, returning the first error.
+func drain(p *optargs.Parser) error {
+	for _, err := range p.Options() {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usageError reports a command-line error in the command p, with a
+// pointer to its help. An unknown option that differs from a known one
+// only in case, such as -c for -C, gets a suggestion.
+func (a *app) usageError(p *optargs.Parser, path []string, err error) int {
+	fmt.Fprintf(a.stderr, "%s: %v\n", path[0], err)
+
+	var unknown *optargs.UnknownOptionError
+	if errors.As(err, &unknown) {
+		typed := "--" + unknown.Name
+		if unknown.IsShort {
+			typed = "-" + unknown.Name
+		}
+		for _, opt := range p.VisibleOptions() {
+			if strings.EqualFold(opt.Name, typed) {
+				fmt.Fprintf(a.stderr, "Did you mean %s?\n", opt.Name)
+			}
+		}
+	}
+	fmt.Fprintf(a.stderr, "Try '%s --help'.\n", strings.Join(path, " "))
+	return exitUsage
+}
+
+// dispatch carries out the command named by path, such as "remote add",
+// with its operands.
+func (a *app) dispatch(root *optargs.Parser, path string, operands []string) int {
+	fail := func(format string, args ...any) int {
+		fmt.Fprintf(a.stderr, "minigit %s: "+format+"\n", append([]any{path}, args...)...)
+		return exitUsage
+	}
+
+	switch path {
+	case "":
+		if err := root.WriteUsage(a.stdout); err != nil {
+			return exitError
+		}
+		return exitUsage
+	case "init":
+		if len(operands) > 1 {
+			return fail("too many arguments: %q", operands[1:])
+		}
+		dir := "."
+		if len(operands) == 1 {
+			dir = operands[0]
+		}
+		kind := "repository"
+		if a.bare {
+			kind = "bare repository"
+		}
+		if !a.quiet {
+			fmt.Fprintf(a.stdout, "Initialized empty %s in %s\n", kind, dir)
+		}
+	case "add":
+		if len(operands) == 0 {
+			return fail("nothing specified, nothing added")
+		}
+		for _, file := range operands {
+			if a.dryRun || a.verbose > 0 {
+				fmt.Fprintf(a.stdout, "add '%s'\n", file)
+			}
+		}
+	case "commit", "ci":
+		if len(operands) > 0 && !a.all {
+			a.logf(1, "committing only %s\n", strings.Join(operands, ", "))
+		}
+		verb := "commit"
+		if a.amend {
+			verb = "amend"
+		}
+		subject, _, _ := strings.Cut(a.message, "\n")
+		fmt.Fprintf(a.stdout, "[main] %s: %s\n", verb, subject)
+	case "log":
+		format := a.format
+		if a.oneline {
+			format = "oneline"
+		}
+		limit := "all"
+		if a.maxCount > 0 {
+			limit = strconv.Itoa(a.maxCount)
+		}
+		fmt.Fprintf(a.stdout, "log format=%s limit=%s paths=%q\n", format, limit, operands)
+	case "remote":
+		if len(operands) > 0 {
+			return fail("unexpected argument: %s", operands[0])
+		}
+		fmt.Fprintln(a.stdout, "origin")
+		a.logf(1, "origin\thttps://example.com/repo.git\n")
+	case "remote add":
+		if len(operands) != 2 {
+			return fail("usage: minigit remote add [-f] NAME URL")
+		}
+		fmt.Fprintf(a.stdout, "added remote %s → %s\n", operands[0], operands[1])
+		if a.fetch {
+			fmt.Fprintf(a.stdout, "fetching %s\n", operands[0])
+		}
+	case "remote remove", "remote rm":
+		if len(operands) != 1 {
+			return fail("usage: minigit remote remove NAME")
+		}
+		fmt.Fprintf(a.stdout, "removed remote %s\n", operands[0])
+	case "completion":
+		if err := root.GenBashCompletion(a.stdout); err != nil {
+			fmt.Fprintf(a.stderr, "minigit: %v\n", err)
+			return exitError
+		}
+	}
+	return exitOK
+}
+
+// logf prints to stderr when -v was given at least level times.
+func (a *app) logf(level int, format string, args ...any) {
+	if a.verbose >= level {
+		fmt.Fprintf(a.stderr, format, args...)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMinigit(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string // prefix of the output, unless empty
+		stderr string // substring of the diagnostics, unless empty
+	}{
+		{name: "commit", args: []string{"commit", "-am", "first"}, stdout: "[main] commit: first\n"},
+		{name: "alias", args: []string{"ci", "--amend", "--message=fix\nbody"}, stdout: "[main] amend: fix\n"},
+		{
+			name: "inherited after command", args: []string{"init", "-v", "-C", "/src", "-q"},
+			stderr: "working in /src",
+		},
+		{name: "init", args: []string{"init", "--bare", "repo"}, stdout: "Initialized empty bare repository in repo\n"},
+		{name: "add dry run", args: []string{"add", "-n", "a.go", "b.go"}, stdout: "add 'a.go'\nadd 'b.go'\n"},
+		{
+			name: "log", args: []string{"log", "--format", "short", "-n3", "--", "-weird"},
+			stdout: "log format=short limit=3 paths=[\"-weird\"]\n",
+		},
+		{name: "nested", args: []string{"remote", "add", "-vf", "origin", "u"}, stdout: "added remote origin → u\nfetching origin\n"},
+		{name: "nested alias", args: []string{"remote", "rm", "origin"}, stdout: "removed remote origin\n"},
+		{name: "command help", args: []string{"log", "-h", "--bogus"}, stdout: "Usage: minigit log [OPTIONS]\n"},
+		{name: "nested help", args: []string{"remote", "add", "--help"}, stdout: "Usage: minigit remote add [OPTIONS]\n"},
+		{name: "completion script", args: []string{"completion"}, stdout: "# bash completion for minigit\n"},
+		{name: "complete values", args: []string{"__complete", "minigit log --format o"}, stdout: "oneline\n"},
+		{name: "complete commands", args: []string{"__complete", "minigit remote r"}, stdout: "remove\nrm\n"},
+
+		{name: "no command", args: nil, code: exitUsage, stdout: "Usage: minigit [OPTIONS] [COMMAND]\n"},
+		{name: "required", args: []string{"commit", "-a"}, code: exitUsage, stderr: "missing required option: --message"},
+		{name: "validate", args: []string{"log", "-n", "-1"}, code: exitUsage, stderr: "not a non-negative number: -1"},
+		{name: "bad value", args: []string{"log", "--format=long"}, code: exitUsage, stderr: "unknown format: long"},
+		{name: "unknown command", args: []string{"push"}, code: exitUsage, stderr: "unknown command: push"},
+		{name: "unknown subcommand", args: []string{"remote", "show"}, code: exitUsage, stderr: "Try 'minigit remote --help'."},
+		{name: "case hint", args: []string{"-c", "/src", "init"}, code: exitUsage, stderr: "Did you mean -C?"},
+		{name: "operands", args: []string{"remote", "add", "origin"}, code: exitUsage, stderr: "usage: minigit remote add"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			code := run(tt.args, &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("exit status = %d, want %d (stderr %q)", code, tt.code, stderr.String())
+			}
+			if tt.stdout != "" && !strings.HasPrefix(stdout.String(), tt.stdout) {
+				t.Errorf("stdout = %q, want prefix %q", stdout.String(), tt.stdout)
+			}
+			if tt.stderr != "" && !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
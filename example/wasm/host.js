@@ -0,0 +1,15 @@
+// Minimal Node.js host for the wasm example: loads tool.wasm and prints
+// what parseCommand makes of the command-line arguments.
+//
+//   cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .   # misc/wasm before Go 1.24
+//   GOOS=js GOARCH=wasm go build -o tool.wasm .
+//   node host.js -v build --output=out main.go
+require("./wasm_exec.js");
+const fs = require("fs");
+
+const go = new Go();
+WebAssembly.instantiate(fs.readFileSync(__dirname + "/tool.wasm"), go.importObject).then((result) => {
+  go.run(result.instance);
+  console.log(JSON.stringify(parseCommand(process.argv.slice(2))));
+  process.exit(0);
+});
@@ -0,0 +1,84 @@
+//go:build js && wasm
+
+// Command wasm demonstrates command parsing inside a WebAssembly host. It
+// registers a global parseCommand function that takes an array of
+// argument strings and returns the dispatched command, the options seen,
+// and the operands, or the first parse error. Nothing is read from
+// os.Args and nothing calls os.Exit, so the instance stays alive between
+// calls.
+//
+// Usage, from this directory (see host.js):
+//
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .
+//	GOOS=js GOARCH=wasm go build -o tool.wasm .
+//	node host.js -v build -o out main.go
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/major0/optargs"
+)
+
+func main() {
+	js.Global().Set("parseCommand", js.FuncOf(func(_ js.Value, args []js.Value) any {
+		if len(args) != 1 || args[0].Type() != js.TypeObject {
+			return map[string]any{"error": "parseCommand takes an array of strings"}
+		}
+		argv := make([]string, args[0].Length())
+		for i := range argv {
+			argv[i] = args[0].Index(i).String()
+		}
+		return parse(argv)
+	}))
+	select {}
+}
+
+// parse builds a fresh parser tree for argv and reports what it found in a
+// form js.ValueOf accepts. The ':' optstring prefix keeps errors out of the
+// host console; they are returned instead.
+func parse(argv []string) map[string]any {
+	root, err := optargs.GetOptLong(argv, ":v", []optargs.Flag{
+		{Name: "verbose", HasArg: optargs.NoArgument},
+	})
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	build, err := optargs.GetOptLong(nil, ":o:", []optargs.Flag{
+		{Name: "output", HasArg: optargs.RequiredArgument},
+	})
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	root.AddCmd("build", build)
+
+	var options []any
+	collect := func(p *optargs.Parser) error {
+		for opt, err := range p.Options() {
+			if err != nil {
+				return err
+			}
+			options = append(options, map[string]any{"name": opt.Name, "arg": opt.Arg})
+		}
+		return nil
+	}
+	if err := collect(root); err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	result := map[string]any{}
+	operands := root.Args
+	if name, cmd := root.ActiveCommand(); cmd != nil {
+		if err := collect(cmd); err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		result["command"] = name
+		operands = cmd.Args
+	}
+	list := make([]any, len(operands))
+	for i, arg := range operands {
+		list[i] = arg
+	}
+	result["options"] = options
+	result["operands"] = list
+	return result
+}
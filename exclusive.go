@@ -0,0 +1,89 @@
+package optargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MutuallyExclusive declares that at most one of the named options may be
+// given. Each name is spelled with or without dashes ("--json", "json",
+// "-j") and is resolved like [Parser.Count], so aliases and inherited
+// options are accepted and a short and a long option linked through
+// Flag.Peer are one member. When a second member is parsed, the Options
+// iterator yields it with a *ConflictError instead of handling or
+// yielding it; repeating the same member is not a conflict. Groups
+// declared on a parent apply to its subcommands as well.
+//
+// It fails, declaring nothing, if a name is not an option of p.
+func (p *Parser) MutuallyExclusive(names ...string) error {
+	group := make([]*Flag, 0, len(names))
+	for _, name := range names {
+		flag := p.resolveName(strings.TrimLeft(name, "-"))
+		if flag == nil {
+			return fmt.Errorf("mutually exclusive: unknown option: %s", name)
+		}
+		group = append(group, flag)
+	}
+	p.exclusive = append(p.exclusive, group)
+	return nil
+}
+
+// checkExclusive reports a *ConflictError when flag belongs to an
+// exclusive group of p or an ancestor with another member already parsed.
+func (p *Parser) checkExclusive(flag *Flag, option Option) error {
+	if flag == nil {
+		return nil
+	}
+	for owner := p; owner != nil; owner = owner.parent {
+		for _, group := range owner.exclusive {
+			if !inGroup(group, flag) {
+				continue
+			}
+			for _, member := range group {
+				if sameOption(member, flag) || !p.seenAbove(member) {
+					continue
+				}
+				err := &ConflictError{Name: option.Name, With: optionName(member)}
+				p.report(err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// inGroup reports whether flag, or its Peer, is a member of group.
+func inGroup(group []*Flag, flag *Flag) bool {
+	for _, member := range group {
+		if sameOption(member, flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameOption reports whether a and b are one option: the same flag or a
+// Peer pair.
+func sameOption(a, b *Flag) bool {
+	return a == b || a.Peer == b || b.Peer == a
+}
+
+// seenAbove reports whether flag, or its Peer, was parsed by p or by a
+// parser on its parent chain.
+func (p *Parser) seenAbove(flag *Flag) bool {
+	for cur := p; cur != nil; cur = cur.parent {
+		if cur.counts[flag] > 0 || flag.Peer != nil && cur.counts[flag.Peer] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// optionName returns the name flag is reported under, preferring the long
+// name of a Peer pair.
+func optionName(flag *Flag) string {
+	if len(flag.Name) == 1 && flag.Peer != nil {
+		return flag.Peer.Name
+	}
+	return flag.Name
+}
@@ -0,0 +1,107 @@
+package optargs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMutuallyExclusive(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantOpts  []Option
+		conflicts []ConflictError
+	}{
+		{
+			name:     "one member",
+			args:     []string{"--json", "--json", "-v"},
+			wantOpts: []Option{{Name: "json"}, {Name: "json"}, {Name: "v"}},
+		},
+		{
+			name:      "two members",
+			args:      []string{"--json", "--yaml"},
+			wantOpts:  []Option{{Name: "json"}},
+			conflicts: []ConflictError{{Name: "yaml", With: "json"}},
+		},
+		{
+			name:      "peer spelling",
+			args:      []string{"-vj", "--yaml", "--json"},
+			wantOpts:  []Option{{Name: "v"}, {Name: "j"}, {Name: "json"}},
+			conflicts: []ConflictError{{Name: "yaml", With: "json"}},
+		},
+		{
+			name:      "inherited by subcommand",
+			args:      []string{"--yaml", "sub", "-j"},
+			wantOpts:  []Option{{Name: "yaml"}},
+			conflicts: []ConflictError{{Name: "j", With: "yaml"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := &Flag{Name: "json"}
+			j := &Flag{Name: "j", Peer: json}
+			json.Peer = j
+			root, err := NewParser(ParserConfig{},
+				map[byte]*Flag{'j': j, 'v': {Name: "v"}},
+				map[string]*Flag{"json": json, "yaml": {Name: "yaml"}},
+				tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := root.MutuallyExclusive("--json", "yaml"); err != nil {
+				t.Fatal(err)
+			}
+			sub, err := NewParser(ParserConfig{}, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.AddCmd("sub", sub)
+
+			var opts []Option
+			var conflicts []ConflictError
+			collect := func(p *Parser) {
+				for opt, err := range p.Options() {
+					var cerr *ConflictError
+					switch {
+					case errors.As(err, &cerr):
+						conflicts = append(conflicts, *cerr)
+					case err != nil:
+						t.Fatal(err)
+					default:
+						opts = append(opts, opt)
+					}
+				}
+			}
+			collect(root)
+			for _, child := range root.Dispatches() {
+				collect(child)
+			}
+			assertOptions(t, opts, tt.wantOpts)
+			if len(conflicts) != len(tt.conflicts) {
+				t.Fatalf("conflicts = %v, want %v", conflicts, tt.conflicts)
+			}
+			for i := range conflicts {
+				if conflicts[i] != tt.conflicts[i] {
+					t.Errorf("conflict %d = %v, want %v", i, conflicts[i], tt.conflicts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMutuallyExclusiveErrors(t *testing.T) {
+	p, err := GetOptLong(nil, "", []Flag{{Name: "json"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.MutuallyExclusive("json", "--toml"); err == nil || !strings.Contains(err.Error(), "unknown option: --toml") {
+		t.Errorf("MutuallyExclusive error = %v", err)
+	}
+	if len(p.exclusive) != 0 {
+		t.Error("failed declaration left a group behind")
+	}
+	if got := (&ConflictError{Name: "yaml", With: "json"}).Error(); got != "option yaml conflicts with json" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -21,6 +21,7 @@
 //   - short-options of any character that is a valid `isgraph()`
 //     character; with the exception of `-`, `:` and `;`. This means that
 //     the following options are valid: -=, -+, -{, -}, -^, -!, -@, etc.
+//     Multibyte UTF-8 characters, such as -ä, are short options too.
 //   - short-option compaction: `-abc` is the equivalent of `-a -b -c`
 //   - short-option compaction with optional args: `-abarg` is the
 //     equivalent of `-a -b arg`
@@ -102,9 +103,9 @@
 package optargs
 
 import (
-	"errors"
 	"log/slog"
 	"os"
+	"unicode/utf8"
 )
 
 // ArgType specifies whether a flag takes no argument, a required argument,
@@ -129,20 +130,104 @@ type Flag struct {
 	HasArg ArgType
 	Handle func(name string, arg string) error
 
+	// HandleCtx, when non-nil, is invoked instead of Handle and instead
+	// of yielding, with the parser being iterated and the Option as it
+	// would have been yielded. The parser is the subcommand for an
+	// inherited option, and its Args holds the arguments not yet
+	// examined, which the handler should treat as read-only.
+	HandleCtx func(p *Parser, opt Option) error
+
 	// Metadata for help generation — set at registration time
 	Help         string // human-readable help text
 	ArgName      string // placeholder name (e.g., "FILE", "COUNT")
 	DefaultValue string // display representation of default
 	Peer         *Flag  // bidirectional short↔long link
+
+	// Hidden flags are parsed normally but left out of usage text,
+	// completion candidates, and Parser.VisibleOptions.
+	Hidden bool
+
+	// Disabled flags are treated as not registered: the option is
+	// unknown to the parser and its subcommands, and a parent option of
+	// the same name is not inherited past it. See Parser.RemoveFlag.
+	Disabled bool
+
+	// Deprecated, when non-empty, marks the option for removal; the text
+	// (e.g., "use --colour instead") is reported when the option is
+	// parsed. See Parser.SetDeprecationHandler.
+	Deprecated string
+
+	// Aliases are additional long option names that resolve to this flag.
+	// A match through an alias is reported under the flag's registered
+	// long name. Aliases are read when the parser is constructed.
+	Aliases []string
+
+	// Complete, when non-nil, supplies candidate arguments for dynamic
+	// completion (see Parser.Complete). It receives the argument typed so
+	// far; candidates not starting with it are discarded.
+	Complete func(prefix string) []string
+
+	// Required marks an option that must be given; see
+	// Parser.CheckRequired.
+	Required bool
+
+	// KeyValue requires the option's argument to have the form key=value
+	// with a non-empty key, as in --label env=prod; any other argument is
+	// yielded as an *InvalidArgumentError in place of the option. Use
+	// Option.KeyValue to split it. A flag uses its Peer's setting when
+	// either is set.
+	KeyValue bool
+
+	// DuplicatePolicy selects how repeated occurrences of the option are
+	// treated; the zero value, DuplicateAllow, accepts them all. A flag
+	// with DuplicateAllow uses its Peer's policy, so -o and --output count
+	// as one option.
+	DuplicatePolicy DuplicatePolicy
+
+	// Validate, when non-nil, checks the option's argument before the
+	// option is handled or yielded. An error is yielded from the Options
+	// iterator as a *ValidationError in place of the option. It is not
+	// called when the option has no argument. A flag without Validate
+	// uses its Peer's.
+	Validate func(arg string) error
+
+	// MatchByte, when non-nil, makes a flag registered with
+	// Parser.AddShortClass stand for every short option character it
+	// accepts. It is ignored on flags registered any other way.
+	MatchByte func(c byte) bool
 }
 
+// OperandName is the Name of the Option yielded for a non-option argument
+// when operands are returned in order: by a parser in [ParseNonOpts] mode,
+// selected by a leading '-' in the optstring.
+const OperandName = "\x01"
+
+// NumericName is the Name of the Option yielded for a numeric option such
+// as -5 in head -5, whose digits are held in Arg; see
+// [ParserConfig.SetNumericOptions].
+const NumericName = "\x02"
+
 // Option represents a parsed option yielded by the iterator.
 // Name is the option name, HasArg indicates whether an argument was
 // consumed, and Arg holds the argument value if present.
+//
+// Index is the position in the parser's original argument list of the
+// word the option was found in; for a subcommand it counts from the root
+// parser's list, so it indexes the same slice throughout. Offset is the
+// byte offset of the option character within that word for short options
+// ("-abc" gives 1, 2, and 3), and 0 for long options. An argument taken
+// from the following word is not reflected in either field. Both are also
+// set on the Option yielded with a parse error.
+//
+// Enabled is only set by a parser with [ParserConfig.SetPlusOptions]:
+// it is false for a toggle given with '+', as in +x, and true otherwise.
 type Option struct {
-	Name   string
-	HasArg bool
-	Arg    string
+	Name    string
+	HasArg  bool
+	Arg     string
+	Index   int
+	Offset  int
+	Enabled bool
 }
 
 // GetOpt creates a parser implementing POSIX [getopt(3)] behavior.
@@ -179,7 +264,7 @@ func getOpt(args []string, optstring string, longopts []Flag, longOnly bool) (*P
 		shortCaseIgnore: false,
 		longCaseIgnore:  true,
 		longOptsOnly:    longOnly,
-		enableErrors:    true,
+		errorMode:       ErrorReport,
 		gnuWords:        false,
 		parseMode:       ParseDefault,
 	}
@@ -215,7 +300,7 @@ optPrefix:
 		}
 		switch optstring[0] {
 		case ':':
-			config.enableErrors = false
+			config.errorMode = ErrorSilent
 		case '+':
 			config.parseMode = ParsePosixlyCorrect
 			config.strictSubcommands = true
@@ -230,60 +315,33 @@ optPrefix:
 	// Iterate over optstring parsing it according to the libc
 	// getopt() spec. Note, the spec fully allows definitions to
 	// overwrite previous definitions. The code will not treat this as
-	// an error as this allows for the most flexibility.
-	for len(optstring) > 0 {
-		if debug {
-			slog.Debug("GetOpt", "optstring", optstring, "len", len(optstring))
-		}
-
-		c := optstring[0]
-		optstring = optstring[1:]
-		if !isGraph(c) {
-			return nil, errors.New("invalid short option: " + byteString(c))
-		}
-
-		if debug {
-			slog.Debug("GetOpt", "c", byteString(c), "optstring", optstring, "len", len(optstring))
-		}
-		switch c {
-		case ':', '-', ';': // Disallowed by the spec
-			return nil, errors.New("prohibited short option: " + byteString(c))
+	// an error as this allows for the most flexibility. A multibyte
+	// UTF-8 character is one short option, such as 'ä'.
+	specs, gnuWords, err := scanOptString(optstring)
+	if err != nil {
+		return nil, err
+	}
+	config.gnuWords = gnuWords
+	runeOpts := make(map[rune]*Flag)
+	for _, s := range specs {
+		if s.Name >= utf8.RuneSelf {
+			runeOpts[s.Name] = &Flag{Name: string(s.Name), HasArg: s.HasArg}
+			continue
 		}
-
-		// look ahead to see if c is followed by ":" or "::"
-		var hasArg ArgType
-		switch {
-		case len(optstring) > 1 && optstring[0] == ':' && optstring[1] == ':':
-			if debug {
-				slog.Debug("GetOpt", "c", byteString(c), "hasArg", "optional")
-			}
-			hasArg = OptionalArgument
-			optstring = optstring[2:]
-		case len(optstring) > 0 && optstring[0] == ':':
-			if debug {
-				slog.Debug("GetOpt", "c", byteString(c), "hasArg", "required")
-			}
-			hasArg = RequiredArgument
-			optstring = optstring[1:]
-		case c == 'W' && len(optstring) > 0 && optstring[0] == ';':
-			if debug {
-				slog.Debug("GetOpt", "c", byteString(c), "gnuWords", true)
-			}
-			config.gnuWords = true
-			hasArg = RequiredArgument
-			optstring = optstring[1:]
-		default:
-			if debug {
-				slog.Debug("GetOpt", "c", byteString(c), "hasArg", "none")
-			}
-			hasArg = NoArgument
+		shortOpts[byte(s.Name)] = &Flag{
+			Name:   string(s.Name),
+			HasArg: s.HasArg,
 		}
+	}
 
-		shortOpts[c] = &Flag{
-			Name:   byteString(c),
-			HasArg: hasArg,
+	p, err := NewParser(config, shortOpts, longOpts, args)
+	if err != nil {
+		return nil, err
+	}
+	for r, flag := range runeOpts {
+		if err := p.addShortRune(r, flag); err != nil {
+			return nil, err
 		}
 	}
-
-	return NewParser(config, shortOpts, longOpts, args)
+	return p, nil
 }
@@ -121,8 +121,8 @@ func TestShortOptsDisableErrors(t *testing.T) {
 			t.Errorf("unexpected error: %s", err)
 		}
 
-		if getopt.config.enableErrors {
-			t.Errorf("Expected enableErrors to be false, got true")
+		if getopt.config.errorMode != ErrorSilent {
+			t.Errorf("Expected errorMode ErrorSilent, got %v", getopt.config.errorMode)
 		}
 	}
 }
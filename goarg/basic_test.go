@@ -1,8 +1,10 @@
 package goarg
 
 import (
+	"errors"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"testing/quick"
 )
@@ -388,3 +390,32 @@ func TestPropertyParsingEquivalence(t *testing.T) {
 		}
 	})
 }
+
+func TestParseAs(t *testing.T) {
+	type options = struct {
+		Verbose bool     `arg:"-v"`
+		Output  string   `arg:"-o" default:"out.txt"`
+		Files   []string `arg:"positional"`
+	}
+
+	opts, err := ParseAs[options]([]string{"-v", "a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.Verbose || opts.Output != "out.txt" || len(opts.Files) != 2 {
+		t.Errorf("ParseAs = %+v", opts)
+	}
+	if _, ok := metadataCache.Load(metadataKey{t: reflect.TypeFor[*options]()}); !ok {
+		t.Error("metadata for the parsed type was not cached")
+	}
+
+	if _, err := ParseAs[options]([]string{"--nope"}); err == nil || !strings.Contains(err.Error(), "--nope") {
+		t.Errorf("unknown option: err = %v", err)
+	}
+	if _, err := ParseAs[options]([]string{"--help"}); !errors.Is(err, ErrHelp) {
+		t.Errorf("--help: err = %v, want ErrHelp", err)
+	}
+	if _, err := ParseAs[int]([]string{}); err == nil {
+		t.Error("ParseAs[int] succeeded")
+	}
+}
@@ -1,6 +1,12 @@
 package goarg
 
-import "testing"
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/major0/optargs"
+)
 
 // BenchmarkParseSimple benchmarks parsing a simple struct with a few options.
 func BenchmarkParseSimple(b *testing.B) {
@@ -83,3 +89,81 @@ func BenchmarkNewParser(b *testing.B) {
 		_, _ = NewParser(Config{Program: "bench"}, &a)
 	}
 }
+
+// largeStruct returns the type of a struct with n string options
+// --f0 ... --f<n-1>, and arguments setting every fourth one.
+func largeStruct(n int) (reflect.Type, []string) {
+	fields := make([]reflect.StructField, n)
+	var args []string
+	for i := range fields {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: reflect.TypeFor[string](),
+			Tag:  reflect.StructTag(fmt.Sprintf(`arg:"--f%d"`, i)),
+		}
+		if i%4 == 0 {
+			args = append(args, fmt.Sprintf("--f%d=v%d", i, i))
+		}
+	}
+	return reflect.StructOf(fields), args
+}
+
+// BenchmarkParseLargeStruct compares goarg's single pass, where each
+// option's Flag.Handle stores into its field as the option is parsed,
+// with a two-pass flow over the same metadata, handlers and post-parse
+// processing that collects the parsed options first and runs each
+// option's handler afterwards.
+func BenchmarkParseLargeStruct(b *testing.B) {
+	t, args := largeStruct(1000)
+	if err := Precompile(reflect.New(t).Interface()); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("single-pass", func(b *testing.B) {
+		for range b.N {
+			if err := ParseArgs(reflect.New(t).Interface(), args); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("two-pass", func(b *testing.B) {
+		for range b.N {
+			dest := reflect.New(t)
+			p, err := NewParser(Config{}, dest.Interface())
+			if err != nil {
+				b.Fatal(err)
+			}
+			destValue := dest.Elem()
+			fb := &FlagBuilder{metadata: p.metadata, config: p.config}
+			shortOpts, longOpts, err := fb.Build(destValue)
+			if err != nil {
+				b.Fatal(err)
+			}
+			bind := make(map[string]func(string, string) error, len(longOpts))
+			for name, flag := range longOpts {
+				bind[name], flag.Handle = flag.Handle, nil
+			}
+			core, err := optargs.NewParser(optargs.ParserConfig{}, shortOpts, longOpts, args)
+			if err != nil {
+				b.Fatal(err)
+			}
+			options, err := core.Collect()
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, opt := range options {
+				if err := bind[opt.Name](opt.Name, opt.Arg); err != nil {
+					b.Fatal(err)
+				}
+			}
+			ci := &CoreIntegration{metadata: p.metadata, config: p.config, setFields: fb.SetFieldNames()}
+			if err := ci.PostParse(core, destValue); err != nil {
+				b.Fatal(err)
+			}
+			if got := destValue.Field(0).String(); got != "v0" {
+				b.Fatalf("F0 = %q, want v0", got)
+			}
+		}
+	})
+}
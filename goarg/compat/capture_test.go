@@ -267,3 +267,47 @@ func TestUpstreamInterspersedArgs(t *testing.T) {
 		t.Errorf("rest = %v, want [pos1 pos2]", a.Rest)
 	}
 }
+
+// TestUpstreamShortOptionMatrix pins the upstream column of the matrix in
+// goarg's TestShortOptionGroupingMatrix: upstream splits a dash argument
+// at its first '=' and looks up the rest as a whole option name, so only
+// -o value and -o=value spellings parse.
+func TestUpstreamShortOptionMatrix(t *testing.T) {
+	type Args struct {
+		Verbose bool   `arg:"-v,--verbose"`
+		Quiet   bool   `arg:"-q"`
+		Out     string `arg:"-o,--out"`
+		Count   int    `arg:"-c"`
+	}
+	tests := []struct {
+		args    []string
+		wantOut string
+		wantErr bool
+	}{
+		{[]string{"-o", "out.txt"}, "out.txt", false},
+		{[]string{"-o=out.txt"}, "out.txt", false},
+		{[]string{"-o==x"}, "=x", false},
+		{[]string{"--out=-x"}, "-x", false},
+		{[]string{"-oout.txt"}, "", true},
+		{[]string{"-vq"}, "", true},
+		{[]string{"-vvo", "out.txt"}, "", true},
+		{[]string{"-vo=x"}, "", true},
+		{[]string{"-o", "-v"}, "", true},
+		{[]string{"--out", "-x"}, "", true},
+	}
+	for _, tt := range tests {
+		var a Args
+		p, err := arg.NewParser(arg.Config{Program: "test"}, &a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.Parse(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("upstream Parse(%q) err = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if err == nil && a.Out != tt.wantOut {
+			t.Errorf("upstream Parse(%q) Out = %q, want %q", tt.args, a.Out, tt.wantOut)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package goarg
+
+import "slices"
+
+// SupportStatus describes how far a feature of the compatibility matrix
+// is supported.
+type SupportStatus int
+
+const (
+	// Unsupported means the feature is not available.
+	Unsupported SupportStatus = iota
+	// Supported means the feature behaves as documented.
+	Supported
+	// Partial means the feature is available with documented differences.
+	Partial
+	// Extension means goarg supports a feature upstream go-arg lacks.
+	Extension
+)
+
+func (s SupportStatus) String() string {
+	switch s {
+	case Supported:
+		return "supported"
+	case Partial:
+		return "partial"
+	case Extension:
+		return "extension"
+	}
+	return "unsupported"
+}
+
+// CompatFeature is one row of the compatibility matrix.
+type CompatFeature struct {
+	Feature  string        // feature name, as in the README table
+	Upstream SupportStatus // support in upstream alexflint/go-arg
+	Goarg    SupportStatus // support in this package
+	Note     string        // how a Partial feature differs, if it does
+	Test     string        // TestTable_ function that verifies the row
+}
+
+// compatibilityMatrix lists the rows of the README Feature Comparison
+// table, in table order. The statuses are maintained by hand. Every row
+// names the test that verifies it against this package, which
+// compat_matrix_test.go runs, failing when a feature claimed as available
+// fails or skips it; compat/table_validation_test.go verifies the same
+// rows against upstream.
+var compatibilityMatrix = []CompatFeature{
+	{Feature: "Struct tag parsing", Upstream: Supported, Goarg: Supported, Test: "TestTable_StructTagParsing"},
+	{
+		Feature: "Short/long options", Upstream: Partial, Goarg: Supported, Test: "TestTable_ShortLongOptions",
+		Note: "upstream parses every option in getopt_long_only(3) mode",
+	},
+	{Feature: "Positional arguments", Upstream: Supported, Goarg: Supported, Test: "TestTable_PositionalArguments"},
+	{Feature: "Subcommands", Upstream: Supported, Goarg: Supported, Test: "TestTable_Subcommands"},
+	{Feature: "Environment variable fallback", Upstream: Supported, Goarg: Supported, Test: "TestTable_EnvironmentVariableFallback"},
+	{Feature: "Env-only fields", Upstream: Supported, Goarg: Supported, Test: "TestTable_EnvOnlyFields"},
+	{Feature: "Default values", Upstream: Supported, Goarg: Supported, Test: "TestTable_DefaultValues"},
+	{
+		Feature: "Map types", Upstream: Supported, Goarg: Partial, Test: "TestTable_MapTypes",
+		Note: "repeated options merge entries instead of resetting the map",
+	},
+	{
+		Feature: "Slice types (repeated flags)", Upstream: Supported, Goarg: Partial, Test: "TestTable_SliceTypes",
+		Note: "repeated options append instead of resetting the slice",
+	},
+	{Feature: "Embedded struct inheritance", Upstream: Supported, Goarg: Supported, Test: "TestTable_EmbeddedStructInheritance"},
+	{Feature: "Versioned/Described/Epilogued", Upstream: Supported, Goarg: Supported, Test: "TestTable_VersionedDescribedEpilogued"},
+	{Feature: "ErrHelp/ErrVersion sentinels", Upstream: Supported, Goarg: Supported, Test: "TestTable_ErrHelpErrVersionSentinels"},
+	{Feature: "Builtin help/version flags", Upstream: Supported, Goarg: Supported, Test: "TestTable_BuiltinHelpVersionFlags"},
+	{Feature: "Subcommand()/SubcommandNames()", Upstream: Supported, Goarg: Supported, Test: "TestTable_SubcommandQuery"},
+	{Feature: "POSIX short-option compaction (-abc)", Upstream: Unsupported, Goarg: Extension, Test: "TestTable_POSIXCompaction"},
+	{
+		Feature: "Abbreviation matching (--verb → --verbose, ambiguity detection)", Upstream: Unsupported, Goarg: Extension,
+		Test: "TestTable_AbbreviationMatching",
+	},
+	{Feature: "Arbitrary option names (=, :, [] in names)", Upstream: Unsupported, Goarg: Extension, Test: "TestTable_ArbitraryOptionNames"},
+	{Feature: "Boolean negation (--no-flag)", Upstream: Unsupported, Goarg: Extension, Test: "TestTable_BooleanNegation"},
+	{Feature: "Boolean prefix pairs (--enable/--disable)", Upstream: Unsupported, Goarg: Extension, Test: "TestTable_BooleanPrefixPairs"},
+	{Feature: "-- termination", Upstream: Supported, Goarg: Supported, Test: "TestTable_DoubleHyphenTermination"},
+	{Feature: "Parent flag inheritance across subcommands", Upstream: Supported, Goarg: Supported, Test: "TestTable_ParentFlagInheritance"},
+	{Feature: "Case-insensitive subcommand matching", Upstream: Unsupported, Goarg: Extension, Test: "TestTable_CaseInsensitiveSubcommand"},
+	{Feature: "Interspersed argument handling", Upstream: Supported, Goarg: Supported, Test: "TestTable_InterspersedArguments"},
+	{
+		Feature: "getopt_long_only mode", Upstream: Partial, Goarg: Supported, Test: "TestTable_GetoptLongOnly",
+		Note: "upstream is always in long-only mode and cannot leave it",
+	},
+}
+
+// CompatibilityMatrix returns the upstream go-arg features and how each
+// is supported by upstream and by this package, in the order of the
+// README Feature Comparison table. The statuses are not derived from the
+// tests at run time: they are kept by hand, and this package's tests fail
+// when a feature listed as available does not pass its test, or when the
+// README table disagrees. The caller owns the returned slice.
+func CompatibilityMatrix() []CompatFeature {
+	return slices.Clone(compatibilityMatrix)
+}
@@ -0,0 +1,116 @@
+package goarg
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// tableTests maps the test names of the matrix rows to the tests of
+// table_validation_test.go, so each row's test can be run against its
+// claim.
+var tableTests = map[string]func(*testing.T){
+	"TestTable_StructTagParsing":            TestTable_StructTagParsing,
+	"TestTable_ShortLongOptions":            TestTable_ShortLongOptions,
+	"TestTable_PositionalArguments":         TestTable_PositionalArguments,
+	"TestTable_Subcommands":                 TestTable_Subcommands,
+	"TestTable_EnvironmentVariableFallback": TestTable_EnvironmentVariableFallback,
+	"TestTable_EnvOnlyFields":               TestTable_EnvOnlyFields,
+	"TestTable_DefaultValues":               TestTable_DefaultValues,
+	"TestTable_MapTypes":                    TestTable_MapTypes,
+	"TestTable_SliceTypes":                  TestTable_SliceTypes,
+	"TestTable_EmbeddedStructInheritance":   TestTable_EmbeddedStructInheritance,
+	"TestTable_VersionedDescribedEpilogued": TestTable_VersionedDescribedEpilogued,
+	"TestTable_ErrHelpErrVersionSentinels":  TestTable_ErrHelpErrVersionSentinels,
+	"TestTable_BuiltinHelpVersionFlags":     TestTable_BuiltinHelpVersionFlags,
+	"TestTable_SubcommandQuery":             TestTable_SubcommandQuery,
+	"TestTable_POSIXCompaction":             TestTable_POSIXCompaction,
+	"TestTable_AbbreviationMatching":        TestTable_AbbreviationMatching,
+	"TestTable_ArbitraryOptionNames":        TestTable_ArbitraryOptionNames,
+	"TestTable_BooleanNegation":             TestTable_BooleanNegation,
+	"TestTable_BooleanPrefixPairs":          TestTable_BooleanPrefixPairs,
+	"TestTable_DoubleHyphenTermination":     TestTable_DoubleHyphenTermination,
+	"TestTable_ParentFlagInheritance":       TestTable_ParentFlagInheritance,
+	"TestTable_CaseInsensitiveSubcommand":   TestTable_CaseInsensitiveSubcommand,
+	"TestTable_InterspersedArguments":       TestTable_InterspersedArguments,
+	"TestTable_GetoptLongOnly":              TestTable_GetoptLongOnly,
+}
+
+// TestCompatibilityMatrixTests runs the test behind every matrix row and
+// fails when a feature claimed as available fails its test or skips it.
+func TestCompatibilityMatrixTests(t *testing.T) {
+	for _, f := range CompatibilityMatrix() {
+		if (f.Upstream == Partial || f.Goarg == Partial) != (f.Note != "") {
+			t.Errorf("%s: Note must be set exactly for partial support", f.Feature)
+		}
+		test := tableTests[f.Test]
+		if test == nil {
+			t.Errorf("%s: test %q not found in table_validation_test.go", f.Feature, f.Test)
+			continue
+		}
+		if f.Goarg == Unsupported {
+			continue
+		}
+		var skipped bool
+		passed := t.Run(f.Test, func(t *testing.T) {
+			defer func() { skipped = t.Skipped() }()
+			test(t)
+		})
+		if !passed || skipped {
+			t.Errorf("%s: claimed %s, but %s did not pass", f.Feature, f.Goarg, f.Test)
+		}
+	}
+}
+
+// readmeRow matches a Feature Comparison row: feature, upstream, goarg.
+var readmeRow = regexp.MustCompile(`^\| (.+?) \| (\S+) \| (\S+) \|$`)
+
+// TestCompatibilityMatrixREADME verifies the README Feature Comparison
+// table lists the matrix rows, in order, with matching symbols.
+func TestCompatibilityMatrixREADME(t *testing.T) {
+	data, err := os.ReadFile("README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, table, ok := strings.Cut(string(data), "## Feature Comparison")
+	if !ok {
+		t.Fatal("README has no Feature Comparison section")
+	}
+	var rows [][]string
+	for _, line := range strings.Split(table, "\n") {
+		if m := readmeRow.FindStringSubmatch(line); m != nil && m[1] != "Feature" && !strings.HasPrefix(m[1], "-") {
+			rows = append(rows, m[1:])
+		}
+		if strings.HasPrefix(line, "## ") {
+			break
+		}
+	}
+
+	matrix := CompatibilityMatrix()
+	if len(rows) != len(matrix) {
+		t.Fatalf("README lists %d features, matrix has %d", len(rows), len(matrix))
+	}
+	for i, f := range matrix {
+		feature := strings.ReplaceAll(rows[i][0], "`", "")
+		if !strings.Contains(feature, f.Feature) {
+			t.Errorf("row %d: README feature %q, matrix %q", i, rows[i][0], f.Feature)
+		}
+		if got, want := strings.TrimRight(rows[i][1], "¹²³⁴"), readmeSymbol(f.Upstream); got != want {
+			t.Errorf("%s: README upstream %s, matrix %s", f.Feature, got, want)
+		}
+		if got, want := strings.TrimRight(rows[i][2], "¹²³⁴"), readmeSymbol(f.Goarg); got != want {
+			t.Errorf("%s: README goarg %s, matrix %s", f.Feature, got, want)
+		}
+	}
+}
+
+func readmeSymbol(s SupportStatus) string {
+	switch s {
+	case Supported, Extension:
+		return "✅"
+	case Partial:
+		return "⚠️"
+	}
+	return "❌"
+}
@@ -13,8 +13,12 @@ import (
 type CoreIntegration struct {
 	metadata    *StructMetadata
 	config      Config
-	setFields   map[int]bool // tracks field indices explicitly set during parsing
+	setFields   map[string]bool // names of fields explicitly set during parsing
 	flagBuilder *FlagBuilder
+
+	// children holds the integration of each registered subcommand, so
+	// its setFields survive until the subcommand is post-processed.
+	children map[*StructMetadata]*CoreIntegration
 }
 
 // fieldByMeta returns the reflect.Value for a field using the cached index
@@ -43,7 +47,7 @@ func (ci *CoreIntegration) CreateParserWithHandlers(args []string, destValue ref
 	if err != nil {
 		return nil, fmt.Errorf("failed to build flags: %w", err)
 	}
-	ci.setFields = ci.flagBuilder.SetFields()
+	ci.setFields = ci.flagBuilder.SetFieldNames()
 
 	// Register builtin -h/--help flag (returns ErrHelp when parsed).
 	helpFlag := &optargs.Flag{
@@ -67,6 +71,16 @@ func (ci *CoreIntegration) CreateParserWithHandlers(args []string, destValue ref
 		longOpts["help"] = helpLong
 	}
 
+	// Register builtin --help-all flag when help can be condensed.
+	if ci.config.MaxHelpLines > 0 && longOpts["help-all"] == nil {
+		longOpts["help-all"] = &optargs.Flag{
+			Name:   "help-all",
+			HasArg: optargs.NoArgument,
+			Help:   "show help for every option and exit",
+			Handle: func(_, _ string) error { return &HelpRequest{Format: HelpFormatText, All: true} },
+		}
+	}
+
 	// Register builtin --version flag if version is configured.
 	if ci.config.Version != "" {
 		if longOpts["version"] == nil {
@@ -74,7 +88,26 @@ func (ci *CoreIntegration) CreateParserWithHandlers(args []string, destValue ref
 				Name:   "version",
 				HasArg: optargs.NoArgument,
 				Help:   "display version and exit",
-				Handle: func(_, _ string) error { return ErrVersion },
+				Handle: func(_, _ string) error { return &VersionRequest{Version: ci.config.Version} },
+			}
+		}
+	}
+
+	// Register builtin documentation generation flags if enabled.
+	for name, format := range generateFlags {
+		enabled := ci.config.GenerateMan
+		if format == HelpFormatMarkdown {
+			enabled = ci.config.GenerateDocs
+		}
+		if enabled && longOpts[name] == nil {
+			longOpts[name] = &optargs.Flag{
+				Name:    name,
+				HasArg:  optargs.RequiredArgument,
+				ArgName: "DIR",
+				Help:    "write " + string(format) + " documentation to DIR and exit",
+				Handle: func(_, dir string) error {
+					return &GenerateRequest{Format: format, Dir: dir}
+				},
 			}
 		}
 	}
@@ -82,6 +115,7 @@ func (ci *CoreIntegration) CreateParserWithHandlers(args []string, destValue ref
 	config := optargs.ParserConfig{}
 	config.SetLongOnly(ci.config.LongOnly)
 	config.SetCommandCaseIgnore(!ci.config.CaseSensitiveCommands)
+	config.SetShortOptEquals(true) // upstream accepts -o=value
 
 	parser, err := optargs.NewParser(config, shortOpts, longOpts, args)
 	if err != nil {
@@ -0,0 +1,139 @@
+package goarg
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// FieldDiff describes one field whose value differs between two
+// configurations compared by Diff.
+type FieldDiff struct {
+	Field string // Go field path, such as "Verbose" or "Push.Force"
+	Flag  string // command-line spelling, such as "--verbose", "push --force", or "INPUT"
+	Old   any    // value in the first configuration
+	New   any    // value in the second configuration
+}
+
+// String formats the difference as "FLAG: OLD -> NEW", dereferencing
+// pointer values.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Flag, diffValue(d.Old), diffValue(d.New))
+}
+
+// Diff compares two configurations of the same struct type, such as two
+// parsed job invocations, and returns the fields whose values differ in
+// declaration order, each labelled with the spelling a user would type:
+// the long option, else the short option, else the positional's uppercase
+// name, else the environment variable.
+//
+// Subcommands are compared after the fields of their parent, in name
+// order. A subcommand selected in only one configuration is reported as
+// a change from false to true (or back), and its fields are compared
+// against their zero values.
+//
+// a and b must be non-nil pointers to structs of the same type; Diff
+// panics otherwise, or when the struct tags are invalid or a subcommand
+// struct contains itself.
+func Diff(a, b any) []FieldDiff {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() != reflect.Ptr || va.IsNil() || va.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("goarg: Diff: %T is not a pointer to a struct", a))
+	}
+	if va.Type() != vb.Type() || vb.IsNil() {
+		panic(fmt.Sprintf("goarg: Diff: cannot compare %T with %T", a, b))
+	}
+	metadata, _, err := loadMetadata(a, 0, true)
+	if err != nil {
+		panic("goarg: Diff: " + err.Error())
+	}
+	var diffs []FieldDiff
+	diffStruct(&diffs, metadata, va.Elem(), vb.Elem(), "", "")
+	return diffs
+}
+
+// diffStruct appends the differences between the struct values a and b,
+// described by metadata, prefixing field paths with field and spellings
+// with command.
+func diffStruct(diffs *[]FieldDiff, metadata *StructMetadata, a, b reflect.Value, field, command string) {
+	for i := range metadata.Fields {
+		meta := &metadata.Fields[i]
+		fa, fb := fieldByMeta(a, meta), fieldByMeta(b, meta)
+		if !fa.IsValid() || !fb.IsValid() || reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			continue
+		}
+		*diffs = append(*diffs, FieldDiff{
+			Field: field + meta.Name,
+			Flag:  command + diffFlag(meta),
+			Old:   fa.Interface(),
+			New:   fb.Interface(),
+		})
+	}
+
+	names := make([]string, 0, len(metadata.Subcommands))
+	for name := range metadata.Subcommands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fieldName := metadata.SubcommandFields[name]
+		sa, sb := a.FieldByName(fieldName), b.FieldByName(fieldName)
+		if !sa.IsValid() || !sb.IsValid() || sa.Kind() != reflect.Ptr || sa.IsNil() && sb.IsNil() {
+			continue
+		}
+		if sa.IsNil() != sb.IsNil() {
+			*diffs = append(*diffs, FieldDiff{
+				Field: field + fieldName,
+				Flag:  command + name,
+				Old:   !sa.IsNil(),
+				New:   !sb.IsNil(),
+			})
+		}
+		diffStruct(diffs, metadata.Subcommands[name], diffElem(sa), diffElem(sb),
+			field+fieldName+".", command+name+" ")
+	}
+}
+
+// diffElem returns the struct a subcommand pointer refers to, or a zero
+// struct when it is nil.
+func diffElem(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return reflect.New(v.Type().Elem()).Elem()
+	}
+	return v.Elem()
+}
+
+// diffFlag returns the spelling that identifies field on the command line
+// or in the environment.
+func diffFlag(field *FieldMetadata) string {
+	switch {
+	case field.Long != "":
+		return "--" + field.Long
+	case field.Short != "":
+		return "-" + field.Short
+	case field.Positional:
+		return strings.ToUpper(field.Name)
+	case field.Env != "":
+		return "$" + field.Env
+	}
+	return field.Name
+}
+
+// diffValue formats v for FieldDiff.String.
+func diffValue(v any) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "<nil>"
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.String {
+		return fmt.Sprintf("%q", rv.String())
+	}
+	if !rv.IsValid() {
+		return "<nil>"
+	}
+	return fmt.Sprint(rv.Interface())
+}
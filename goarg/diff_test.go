@@ -0,0 +1,97 @@
+package goarg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	type push struct {
+		Force  bool   `arg:"-f,--force"`
+		Remote string `arg:"positional"`
+	}
+	type pull struct {
+		Rebase bool `arg:"--rebase"`
+	}
+	type args struct {
+		Verbose bool     `arg:"-v"`
+		Level   int      `arg:"--level"`
+		Tags    []string `arg:"--tag"`
+		Token   string   `arg:"env:DIFF_TEST_TOKEN"`
+		Push    *push    `arg:"subcommand:push"`
+		Pull    *pull    `arg:"subcommand:pull"`
+	}
+
+	var a, b args
+	if err := ParseArgs(&a, []string{"--level=1", "--tag=x", "push", "origin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ParseArgs(&b, []string{"-v", "--level=1", "--tag=x", "--tag=y", "push", "-f", "upstream"}); err != nil {
+		t.Fatal(err)
+	}
+	b.Token = "secret"
+
+	got := Diff(&a, &b)
+	want := []string{
+		"-v: false -> true",
+		"--tag: [x] -> [x y]",
+		`$DIFF_TEST_TOKEN: "" -> "secret"`,
+		"push --force: false -> true",
+		`push REMOTE: "origin" -> "upstream"`,
+	}
+	var lines []string
+	for _, d := range got {
+		lines = append(lines, d.String())
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("Diff =\n%q\nwant\n%q", lines, want)
+	}
+	if got[3].Field != "Push.Force" {
+		t.Errorf("Field = %q, want Push.Force", got[3].Field)
+	}
+
+	if d := Diff(&a, &a); len(d) != 0 {
+		t.Errorf("Diff(a, a) = %v, want none", d)
+	}
+
+	var c args
+	if err := ParseArgs(&c, []string{"--level=1", "--tag=x", "pull", "--rebase"}); err != nil {
+		t.Fatal(err)
+	}
+	lines = nil
+	for _, d := range Diff(&a, &c) {
+		lines = append(lines, d.String())
+	}
+	want = []string{
+		"pull: false -> true",
+		"pull --rebase: false -> true",
+		"push: true -> false",
+		`push REMOTE: "origin" -> ""`,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("Diff =\n%q\nwant\n%q", lines, want)
+	}
+}
+
+func TestDiffPanics(t *testing.T) {
+	type x struct{ A int }
+	type y struct{ A int }
+	tests := []struct {
+		name string
+		a, b any
+	}{
+		{name: "not a pointer", a: x{}, b: x{}},
+		{name: "different types", a: &x{}, b: &y{}},
+		{name: "nil", a: &x{}, b: (*x)(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Diff did not panic")
+				}
+			}()
+			Diff(tt.a, tt.b)
+		})
+	}
+}
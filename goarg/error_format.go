@@ -0,0 +1,72 @@
+package goarg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrorFormat selects how MustParse, Fail, and FailSubcommand render
+// parse failures.
+type ErrorFormat int
+
+const (
+	// ErrorFormatPlain prints the error message followed by usage
+	// (upstream go-arg behavior).
+	ErrorFormatPlain ErrorFormat = iota
+	// ErrorFormatColor prints the error message with ANSI highlighting
+	// followed by usage.
+	ErrorFormatColor
+	// ErrorFormatJSON prints a single JSON object per failure and omits
+	// usage, for consumption by automation.
+	ErrorFormatJSON
+)
+
+// ANSI escape sequences used by ErrorFormatColor.
+const (
+	ansiBoldRed = "\x1b[1;31m"
+	ansiBold    = "\x1b[1m"
+	ansiReset   = "\x1b[0m"
+)
+
+// errorRecord is the ErrorFormatJSON wire shape.
+type errorRecord struct {
+	Error       string   `json:"error"`
+	Flag        string   `json:"flag,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// writeError renders err to w according to Config.ErrorFormat. The usage
+// callback writes the usage synopsis for the relevant (sub)command; it is
+// skipped in JSON mode.
+func (p *Parser) writeError(w io.Writer, err error, usage func(io.Writer)) {
+	var pe *ParseError
+	errors.As(err, &pe)
+
+	switch p.config.ErrorFormat {
+	case ErrorFormatJSON:
+		rec := errorRecord{Error: err.Error()}
+		if pe != nil {
+			rec.Flag = pe.Flag
+			rec.Suggestions = pe.Suggestions
+		}
+		json.NewEncoder(w).Encode(rec) //nolint:errcheck,gosec // best-effort diagnostic output
+		return
+
+	case ErrorFormatColor:
+		msg := err.Error()
+		if pe != nil && pe.Flag != "" {
+			msg = strings.Replace(msg, pe.Flag, ansiBold+pe.Flag+ansiReset, 1)
+		}
+		fmt.Fprintf(w, "%serror:%s %s\n", ansiBoldRed, ansiReset, msg)
+
+	default:
+		fmt.Fprintln(w, err)
+	}
+
+	if usage != nil {
+		usage(w)
+	}
+}
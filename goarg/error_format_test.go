@@ -0,0 +1,117 @@
+package goarg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/major0/optargs"
+)
+
+type errorFormatArgs struct {
+	Format string `arg:"-f,--format"`
+}
+
+// newErrorFormatParser returns a parser writing to buf with a no-op Exit.
+func newErrorFormatParser(t *testing.T, format ErrorFormat, buf *bytes.Buffer) *Parser {
+	t.Helper()
+	var a errorFormatArgs
+	p, err := NewParser(Config{
+		Program:     "prog",
+		ErrorFormat: format,
+		Out:         buf,
+		Exit:        func(int) {},
+	}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestErrorFormatPlain(t *testing.T) {
+	var buf bytes.Buffer
+	p := newErrorFormatParser(t, ErrorFormatPlain, &buf)
+	p.MustParse([]string{"--foo"})
+
+	want := "unrecognized argument: --foo\nUsage: prog [OPTIONS]\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestErrorFormatColor(t *testing.T) {
+	var buf bytes.Buffer
+	p := newErrorFormatParser(t, ErrorFormatColor, &buf)
+	p.MustParse([]string{"--foo"})
+
+	out := buf.String()
+	if !strings.HasPrefix(out, ansiBoldRed+"error:"+ansiReset) {
+		t.Errorf("missing colored prefix: %q", out)
+	}
+	if !strings.Contains(out, ansiBold+"--foo"+ansiReset) {
+		t.Errorf("flag not highlighted: %q", out)
+	}
+	if !strings.Contains(out, "Usage: prog") {
+		t.Errorf("usage missing: %q", out)
+	}
+}
+
+func TestErrorFormatJSON(t *testing.T) {
+	var buf bytes.Buffer
+	p := newErrorFormatParser(t, ErrorFormatJSON, &buf)
+	p.MustParse([]string{"--foo"})
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("output is not JSON: %v: %q", err, buf.String())
+	}
+	if rec["error"] != "unrecognized argument: --foo" {
+		t.Errorf("error = %v", rec["error"])
+	}
+	if rec["flag"] != "--foo" {
+		t.Errorf("flag = %v", rec["flag"])
+	}
+	if strings.Contains(buf.String(), "Usage:") {
+		t.Errorf("JSON output must not include usage: %q", buf.String())
+	}
+}
+
+func TestErrorFormatJSONSuggestions(t *testing.T) {
+	var buf bytes.Buffer
+	p := newErrorFormatParser(t, ErrorFormatJSON, &buf)
+	p.MustParse([]string{"--fromat=json"})
+
+	want := `{"error":"unrecognized argument: --fromat=json","flag":"--fromat","suggestions":["--format"]}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestErrorFormatJSONFail(t *testing.T) {
+	var buf bytes.Buffer
+	p := newErrorFormatParser(t, ErrorFormatJSON, &buf)
+	p.Fail("something broke")
+
+	if got := strings.TrimSpace(buf.String()); got != `{"error":"something broke"}` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestParseErrorUnwrapsCoreError(t *testing.T) {
+	var a errorFormatArgs
+	err := ParseArgs(&a, []string{"-f"})
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ParseError, got %T", err)
+	}
+	if pe.Flag != "-f" {
+		t.Errorf("Flag = %q, want -f", pe.Flag)
+	}
+	var missing *optargs.MissingArgumentError
+	if !errors.As(err, &missing) {
+		t.Error("core MissingArgumentError not reachable via errors.As")
+	}
+}
@@ -8,8 +8,22 @@ var ErrHelp = errors.New("help requested by user")
 // ErrVersion indicates that the builtin --version flag was provided.
 var ErrVersion = errors.New("version requested by user")
 
+// VersionRequest is returned by Parse when --version is given. Version is
+// the version of the command it was given to: the subcommand's own when
+// it implements Versioned, otherwise the nearest ancestor's. It satisfies
+// errors.Is(err, ErrVersion).
+type VersionRequest struct {
+	Version string
+}
+
+func (e *VersionRequest) Error() string { return ErrVersion.Error() }
+
+// Is reports whether target is ErrVersion.
+func (e *VersionRequest) Is(target error) bool { return target == ErrVersion }
+
 // Versioned is implemented by destination structs that provide a version string.
 // When implemented, --version is registered and the version appears in help output.
+// Subcommand structs may implement it too, giving that command its own version.
 type Versioned interface {
 	Version() string
 }
@@ -25,3 +39,19 @@ type Described interface {
 type Epilogued interface {
 	Epilogue() string
 }
+
+// ParseError is a translated command-line parse failure. Message holds the
+// go-arg compatible error text; Flag holds the offending option as typed
+// by the user (e.g. "--foo", "-x") when one is known. The underlying core
+// error remains reachable via errors.As.
+type ParseError struct {
+	Message     string
+	Flag        string
+	Suggestions []string
+	err         error
+}
+
+func (e *ParseError) Error() string { return e.Message }
+
+// Unwrap returns the underlying OptArgs Core error.
+func (e *ParseError) Unwrap() error { return e.err }
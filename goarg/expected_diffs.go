@@ -49,6 +49,25 @@ var expectedDiffs = []ExpectedDiff{
 			"one value, merging into the map (--header a=b --header c=d → {a:b, c:d}). " +
 			"Same root cause as the slice_option divergence.",
 	},
+	{
+		Scenario:         "short_cluster.values",
+		UpstreamBehavior: "unknown argument -vvo",
+		OurBehavior:      "&{Verbose:true Out:out.txt}",
+		Rationale: "Upstream looks up each dash argument as a whole option name, " +
+			"so clustered short flags (-vq, -vvo out.txt) and attached values " +
+			"(-ofile) are unknown arguments. Our core implements getopt(3) " +
+			"clustering; every spelling upstream accepts parses identically. " +
+			"See TestShortOptionGroupingMatrix.",
+	},
+	{
+		Scenario:         "dash_value.values",
+		UpstreamBehavior: "missing value for -o",
+		OurBehavior:      "&{Out:-v}",
+		Rationale: "Upstream refuses an option value that starts with '-' " +
+			"unless it is a negative number (-o -v, --out -x). getopt(3) " +
+			"takes the next argument as a required value unconditionally, " +
+			"so values like -x need no --out=-x spelling.",
+	},
 }
 
 // HelpUsageDiffRationale explains the systematic help/usage formatting
@@ -0,0 +1,60 @@
+package goarg
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// factories holds the constructors registered by RegisterFactory.
+var factories sync.Map // reflect.Type → func(string) (any, error)
+
+// RegisterFactory registers factory as the constructor for fields of the
+// interface type T. A field declared as T, such as Output io.Writer, is
+// populated by calling factory with the option's argument, so one factory
+// can map "stdout", "stderr", or a path onto different implementations.
+// Defaults and environment variables go through the factory as well.
+//
+// Registering a factory for T again replaces the previous one. It panics
+// when T is not an interface type.
+func RegisterFactory[T any](factory func(value string) (T, error)) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("goarg: RegisterFactory: %s is not an interface type", t))
+	}
+	factories.Store(t, func(value string) (any, error) { return factory(value) })
+}
+
+// lookupFactory returns the factory registered for t, or nil.
+func lookupFactory(t reflect.Type) func(string) (any, error) {
+	if fn, ok := factories.Load(t); ok {
+		return fn.(func(string) (any, error)) //nolint:errcheck // only RegisterFactory stores entries
+	}
+	return nil
+}
+
+// factoryValue is a TypedValue for an interface field built by a
+// registered factory.
+type factoryValue struct {
+	fieldValue reflect.Value
+	factory    func(string) (any, error)
+	raw        string
+}
+
+func (v *factoryValue) Set(s string) error {
+	val, err := v.factory(s)
+	if err != nil {
+		return err
+	}
+	if val == nil {
+		v.fieldValue.SetZero()
+	} else {
+		v.fieldValue.Set(reflect.ValueOf(val))
+	}
+	v.raw = s
+	return nil
+}
+
+func (v *factoryValue) String() string { return v.raw }
+
+func (v *factoryValue) Type() string { return v.fieldValue.Type().String() }
@@ -0,0 +1,121 @@
+package goarg
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// namedBuffer is an io.Writer produced by the test factory for any value
+// other than stdout and stderr.
+type namedBuffer struct {
+	bytes.Buffer
+	name string
+}
+
+func registerWriterFactory(t *testing.T) {
+	t.Helper()
+	RegisterFactory(func(value string) (io.Writer, error) {
+		switch value {
+		case "stdout":
+			return os.Stdout, nil
+		case "stderr":
+			return os.Stderr, nil
+		case "":
+			return nil, errors.New("empty output name")
+		}
+		return &namedBuffer{name: value}, nil
+	})
+	t.Cleanup(func() { factories.Delete(reflectTypeWriter) })
+}
+
+var reflectTypeWriter = reflect.TypeFor[io.Writer]()
+
+func TestFactoryField(t *testing.T) {
+	registerWriterFactory(t)
+	type args struct {
+		Output io.Writer `arg:"--output" default:"stderr"`
+		Log    io.Writer `arg:"--log,env:FACTORY_TEST_LOG"`
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		wantOut io.Writer
+		wantLog string
+	}{
+		{name: "default", args: []string{}, wantOut: os.Stderr},
+		{name: "flag", args: []string{"--output", "stdout"}, wantOut: os.Stdout},
+		{name: "env", args: []string{}, env: "app.log", wantOut: os.Stderr, wantLog: "app.log"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("FACTORY_TEST_LOG", tt.env)
+			}
+			var a args
+			p, err := NewParser(Config{}, &a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if a.Output != tt.wantOut {
+				t.Errorf("Output = %v, want %v", a.Output, tt.wantOut)
+			}
+			if tt.wantLog == "" {
+				if a.Log != nil {
+					t.Errorf("Log = %v, want nil", a.Log)
+				}
+			} else if nb, ok := a.Log.(*namedBuffer); !ok || nb.name != tt.wantLog {
+				t.Errorf("Log = %#v, want *namedBuffer %q", a.Log, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestFactoryFieldErrors(t *testing.T) {
+	t.Run("factory error", func(t *testing.T) {
+		registerWriterFactory(t)
+		var a struct {
+			Output io.Writer `arg:"--output"`
+		}
+		p, err := NewParser(Config{}, &a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.Parse([]string{"--output="})
+		if err == nil || !strings.Contains(err.Error(), "empty output name") {
+			t.Errorf("Parse error = %v, want factory error", err)
+		}
+	})
+
+	t.Run("unregistered", func(t *testing.T) {
+		var a struct {
+			Input io.Reader `arg:"--input"`
+		}
+		p, err := NewParser(Config{}, &a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.Parse([]string{"--input", "-"})
+		if err == nil || !strings.Contains(err.Error(), "no factory registered for interface type io.Reader (field Input)") {
+			t.Errorf("Parse error = %v, want unregistered factory error", err)
+		}
+	})
+
+	t.Run("not an interface", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("RegisterFactory did not panic for a non-interface type")
+			}
+		}()
+		RegisterFactory(func(string) (int, error) { return 0, nil })
+	})
+}
@@ -0,0 +1,76 @@
+package goarg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/major0/optargs"
+)
+
+// FieldValue returns the value through which goarg stores arguments in
+// the field of the struct dest points to, where field is metadata that
+// TagParser.ParseStruct returned for dest. Set converts its argument to
+// the field's type as Parse does, and String reports the field's current
+// value. Other front ends use it to bind struct fields to their own
+// parsers, as the pflag package's FlagSet.BindStruct does.
+func FieldValue(dest any, field *FieldMetadata) (optargs.TypedValue, error) {
+	fieldValue, err := settableField(dest, field)
+	if err != nil {
+		return nil, err
+	}
+	return typedValueForField(fieldValue, field)
+}
+
+// SetFieldFallback stores the value Parse gives the field of the struct
+// dest points to when its option is not given: the value of its `env`
+// variable when that is set, otherwise its `default` tag. A field that
+// already has a non-zero value is left alone, and the `onset` method is
+// not called. A default with ${...} references needs the rest of the
+// parse and is an error here.
+func SetFieldFallback(dest any, field *FieldMetadata) error {
+	fieldValue, err := settableField(dest, field)
+	if err != nil {
+		return err
+	}
+	if !isZeroValue(fieldValue) {
+		return nil
+	}
+	if field.Env != "" {
+		if envValue, ok := os.LookupEnv(field.Env); ok {
+			tv, err := typedValueForField(fieldValue, field)
+			if err != nil {
+				return err
+			}
+			if err := tv.Set(envValue); err != nil {
+				return fmt.Errorf("failed to set environment variable %s for field %s: %w", field.Env, field.Name, err)
+			}
+			return nil
+		}
+	}
+	if !field.HasDefault {
+		return nil
+	}
+	if isInterpolated(field.DefaultTag) {
+		return fmt.Errorf("default for field %s: interpolation is only supported by Parse", field.Name)
+	}
+	return setDefault(fieldValue, field, field.DefaultTag)
+}
+
+// settableField returns the field of the struct dest points to that field
+// describes.
+func settableField(dest any, field *FieldMetadata) (reflect.Value, error) {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || destValue.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, errors.New("destination must be a pointer to a struct")
+	}
+	if field.FieldIndex >= destValue.Elem().NumField() {
+		return reflect.Value{}, fmt.Errorf("cannot set field %s", field.Name)
+	}
+	fieldValue := fieldByMeta(destValue.Elem(), field)
+	if !fieldValue.IsValid() || !fieldValue.CanSet() || fieldValue.Type() != field.Type {
+		return reflect.Value{}, fmt.Errorf("cannot set field %s", field.Name)
+	}
+	return fieldValue, nil
+}
@@ -0,0 +1,70 @@
+package goarg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFieldValue(t *testing.T) {
+	type Embedded struct {
+		Level int `arg:"--level" default:"3"`
+	}
+	var dest struct {
+		Embedded
+		Name  string   `arg:"-n,--name" default:"anon"`
+		Tags  []string `arg:"--tag" default:"a,'b,c'"`
+		Port  *int     `arg:"--port"`
+		Set   string   `arg:"--set" default:"keep"`
+		Env   string   `arg:"--env" env:"GOARG_TEST_FIELD_ENV" default:"unused"`
+		Later string   `arg:"--later" default:"${field:Name}"`
+	}
+	dest.Set = "given"
+	t.Setenv("GOARG_TEST_FIELD_ENV", "from-env")
+
+	tp := &TagParser{}
+	metadata, err := tp.ParseStruct(&dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := make(map[string]*FieldMetadata)
+	for i := range metadata.Options {
+		fields[metadata.Options[i].Name] = &metadata.Options[i]
+	}
+
+	for _, name := range []string{"Level", "Name", "Tags", "Port", "Set", "Env"} {
+		if err := SetFieldFallback(&dest, fields[name]); err != nil {
+			t.Fatalf("SetFieldFallback(%s): %v", name, err)
+		}
+	}
+	if dest.Level != 3 || dest.Name != "anon" || !reflect.DeepEqual(dest.Tags, []string{"a", "b,c"}) || dest.Port != nil || dest.Set != "given" || dest.Env != "from-env" {
+		t.Fatalf("defaults = %+v", dest)
+	}
+	if err := SetFieldFallback(&dest, fields["Later"]); err == nil || !strings.Contains(err.Error(), "interpolation") {
+		t.Errorf("interpolated default: err = %v", err)
+	}
+
+	for name, arg := range map[string]string{"Level": "7", "Name": "bob", "Port": "8080"} {
+		tv, err := FieldValue(&dest, fields[name])
+		if err != nil {
+			t.Fatalf("FieldValue(%s): %v", name, err)
+		}
+		if err := tv.Set(arg); err != nil {
+			t.Fatalf("Set(%s): %v", name, err)
+		}
+		if tv.String() != arg {
+			t.Errorf("%s: String() = %q, want %q", name, tv.String(), arg)
+		}
+	}
+	if dest.Level != 7 || dest.Name != "bob" || dest.Port == nil || *dest.Port != 8080 {
+		t.Errorf("values = %+v", dest)
+	}
+
+	if _, err := FieldValue(dest, fields["Name"]); err == nil {
+		t.Error("FieldValue accepted a struct value")
+	}
+	var other struct{ Name int }
+	if _, err := FieldValue(&other, fields["Name"]); err == nil {
+		t.Error("FieldValue accepted a field of another type")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,16 +15,31 @@ import (
 type FlagBuilder struct {
 	metadata  *StructMetadata
 	config    Config
-	setFields map[int]bool // tracks field indices explicitly set during parsing
+	setFields map[string]bool // names of fields explicitly set during parsing
 }
 
-// SetFields returns the set-fields tracker, populated during parsing
-// via handler callbacks. The PostProcessor uses this to skip fields
-// that were explicitly set.
-func (fb *FlagBuilder) SetFields() map[int]bool {
+// SetFieldNames returns the set-fields tracker, keyed by field name and
+// populated during parsing via handler callbacks. The PostProcessor uses
+// this to skip fields that were explicitly set.
+func (fb *FlagBuilder) SetFieldNames() map[string]bool {
 	return fb.setFields
 }
 
+// SetFields returns the fields set so far, keyed by FieldMetadata.FieldIndex.
+// Fields inherited from embedded structs all share index -1.
+//
+// Deprecated: use SetFieldNames, which tells embedded fields apart. The
+// map returned here is a snapshot, not updated by later parsing.
+func (fb *FlagBuilder) SetFields() map[int]bool {
+	fields := make(map[int]bool, len(fb.setFields))
+	for i := range fb.metadata.Options {
+		if field := &fb.metadata.Options[i]; fb.setFields[field.Name] {
+			fields[field.FieldIndex] = true
+		}
+	}
+	return fields
+}
+
 // Cached reflect.Type for time.Duration and TextUnmarshaler interface.
 var (
 	durationType         = reflect.TypeFor[time.Duration]()
@@ -63,6 +79,15 @@ func typedValueForField(fieldValue reflect.Value, field *FieldMetadata) (optargs
 		return optargs.NewDurationValue(*p, p), nil
 	}
 
+	// Interface types are built by a factory registered for the type.
+	if ft.Kind() == reflect.Interface {
+		factory := lookupFactory(ft)
+		if factory == nil {
+			return nil, fmt.Errorf("no factory registered for interface type %s (field %s)", ft, field.Name)
+		}
+		return &factoryValue{fieldValue: fieldValue, factory: factory}, nil
+	}
+
 	// Scalar types.
 	switch ft.Kind() {
 	case reflect.String:
@@ -233,22 +258,26 @@ func (fb *FlagBuilder) makeHandler(field *FieldMetadata, destValue reflect.Value
 	if err != nil {
 		return nil, err
 	}
-	idx := field.FieldIndex
 	return func(_, arg string) error {
+		if field.Stdin && arg == stdinArg {
+			value, err := fb.config.readStdin(field)
+			if err != nil {
+				return err
+			}
+			arg = value
+		}
 		if arg == "" {
 			if _, ok := tv.(optargs.BoolValuer); ok {
 				if err := tv.Set("true"); err != nil {
 					return err
 				}
-				fb.setFields[idx] = true
-				return nil
+				return fb.fieldParsed(destValue, field, "true")
 			}
 		}
 		if err := tv.Set(arg); err != nil {
 			return err
 		}
-		fb.setFields[idx] = true
-		return nil
+		return fb.fieldParsed(destValue, field, arg)
 	}, nil
 }
 
@@ -257,8 +286,7 @@ func (fb *FlagBuilder) makeBoolPrefixHandler(field *FieldMetadata, destValue ref
 	return func(_, _ string) error {
 		fv := fieldByMeta(destValue, field)
 		fv.SetBool(val)
-		fb.setFields[field.FieldIndex] = true
-		return nil
+		return fb.fieldParsed(destValue, field, strconv.FormatBool(val))
 	}
 }
 
@@ -267,14 +295,13 @@ func (fb *FlagBuilder) makeNegatableHandler(field *FieldMetadata, destValue refl
 	return func(_, _ string) error {
 		fv := fieldByMeta(destValue, field)
 		fv.Set(reflect.Zero(fv.Type()))
-		fb.setFields[field.FieldIndex] = true
-		return nil
+		return fb.fieldParsed(destValue, field, "")
 	}
 }
 
 // Build produces the short and long option maps for optargs.NewParser.
 func (fb *FlagBuilder) Build(destValue reflect.Value) (map[byte]*optargs.Flag, map[string]*optargs.Flag, error) {
-	fb.setFields = make(map[int]bool)
+	fb.setFields = make(map[string]bool)
 	nOpts := len(fb.metadata.Options)
 	shortOpts := make(map[byte]*optargs.Flag, nOpts)
 	longOpts := make(map[string]*optargs.Flag, nOpts)
@@ -87,6 +87,40 @@ func TestFlagBuilderSetFieldsTracking(t *testing.T) {
 	}
 }
 
+// TestFlagBuilderSetFieldAccessors verifies that SetFieldNames and the
+// deprecated, index-keyed SetFields report the same parsed fields.
+func TestFlagBuilderSetFieldAccessors(t *testing.T) {
+	type Common struct {
+		Debug bool `arg:"--debug"`
+	}
+	type Args struct {
+		Common
+		Name  string `arg:"--name"`
+		Count int    `arg:"--count"`
+	}
+	meta, err := (&TagParser{}).ParseStruct(&Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb := &FlagBuilder{metadata: meta, config: Config{}}
+	var a Args
+	_, longOpts, err := fb.Build(reflect.ValueOf(&a).Elem())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"debug", "count"} {
+		if err := longOpts[name].Handle(name, "1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := fb.SetFieldNames(), map[string]bool{"Debug": true, "Count": true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SetFieldNames() = %v, want %v", got, want)
+	}
+	if got, want := fb.SetFields(), map[int]bool{-1: true, 2: true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SetFields() = %v, want %v", got, want)
+	}
+}
+
 // TestFlagBuilderPrefixPairs verifies prefix pair registration.
 func TestFlagBuilderPrefixPairs(t *testing.T) {
 	type Args struct {
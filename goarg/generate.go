@@ -0,0 +1,129 @@
+package goarg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GenerateRequest is returned by Parse when a documentation generation
+// flag enabled by Config.GenerateMan or Config.GenerateDocs is given.
+// MustParse handles it by calling GenerateDocs and exiting.
+type GenerateRequest struct {
+	Format HelpFormat // HelpFormatMan or HelpFormatMarkdown
+	Dir    string     // output directory from the flag argument
+}
+
+func (e *GenerateRequest) Error() string {
+	return fmt.Sprintf("%s documentation requested for %s", e.Format, e.Dir)
+}
+
+// generateFlags maps the builtin generation flags to their formats.
+var generateFlags = map[string]HelpFormat{
+	"generate-man":  HelpFormatMan,
+	"generate-docs": HelpFormatMarkdown,
+}
+
+// GenerateDocs writes one page per command in the command tree to dir,
+// creating dir if needed. Man pages are named after the command path
+// joined by "-" with a ".1" suffix (prog.1, prog-sub.1); Markdown pages
+// join with "_" and end in ".md". Each page links its parent and
+// subcommands. It returns the paths written.
+func (p *Parser) GenerateDocs(dir string, format HelpFormat) ([]string, error) {
+	var sep, ext string
+	switch format {
+	case HelpFormatMan:
+		sep, ext = "-", ".1"
+	case HelpFormatMarkdown:
+		sep, ext = "_", ".md"
+	default:
+		return nil, fmt.Errorf("cannot generate %s documentation", format)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // documentation is world-readable
+		return nil, err
+	}
+	page := func(doc *HelpDoc) string { return strings.ReplaceAll(doc.Name, " ", sep) + ext }
+
+	var written []string
+	var walk func(doc, parent *HelpDoc) error
+	walk = func(doc, parent *HelpDoc) error {
+		var related []*HelpDoc
+		if parent != nil {
+			related = append(related, parent)
+		}
+		for i := range doc.Commands {
+			related = append(related, &doc.Commands[i])
+		}
+		node := *doc
+		node.Commands = nil
+
+		path := filepath.Join(dir, page(doc))
+		f, err := os.Create(path) //nolint:gosec // path is built from command names under the caller's dir
+		if err != nil {
+			return err
+		}
+		if format == HelpFormatMan {
+			err = writeManPage(f, &node, related)
+		} else {
+			err = writeMarkdownPage(f, &node, related, page)
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		written = append(written, path)
+		for i := range doc.Commands {
+			if err := walk(&doc.Commands[i], doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return written, walk(p.HelpDoc(), nil)
+}
+
+// writeManPage renders doc as a man page followed by a SEE ALSO section
+// naming the related commands.
+func writeManPage(w io.Writer, doc *HelpDoc, related []*HelpDoc) error {
+	if err := writeMan(w, doc); err != nil {
+		return err
+	}
+	if len(related) == 0 {
+		return nil
+	}
+	refs := make([]string, len(related))
+	for i, r := range related {
+		refs[i] = `\fB` + roffEscape(strings.ReplaceAll(r.Name, " ", "-")) + `\fR(1)`
+	}
+	_, err := io.WriteString(w, ".SH SEE ALSO\n"+strings.Join(refs, ",\n")+"\n")
+	return err
+}
+
+// writeMarkdownPage renders doc as Markdown followed by a "See also" list
+// linking the pages of the related commands.
+func writeMarkdownPage(w io.Writer, doc *HelpDoc, related []*HelpDoc, page func(*HelpDoc) string) error {
+	if err := writeMarkdown(w, doc, 1); err != nil {
+		return err
+	}
+	if len(related) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("## See also\n\n")
+	for _, r := range related {
+		fmt.Fprintf(&b, "- [%s](%s)\n", r.Name, page(r))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// isGenerateRequest reports whether err is a *GenerateRequest.
+func isGenerateRequest(err error) bool {
+	var req *GenerateRequest
+	return errors.As(err, &req)
+}
@@ -0,0 +1,128 @@
+package goarg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type generateArgs struct {
+	Verbose bool            `arg:"-v" help:"verbose output"`
+	Remote  *generateRemote `arg:"subcommand:remote" help:"manage remotes"`
+	Status  *helpBuildArgs  `arg:"subcommand:status" help:"show status"`
+}
+
+type generateRemote struct {
+	Add *helpBuildArgs `arg:"subcommand:add" help:"add a remote"`
+}
+
+func readPage(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestGenerateDocs(t *testing.T) {
+	p, err := NewParser(Config{Program: "git"}, &generateArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format    HelpFormat
+		files     []string
+		remote    string
+		wantLinks []string
+	}{
+		{HelpFormatMan, []string{"git.1", "git-remote.1", "git-remote-add.1", "git-status.1"}, "git-remote.1",
+			[]string{".SH SEE ALSO", `\fBgit\fR(1)`, `\fBgit\-remote\-add\fR(1)`}},
+		{HelpFormatMarkdown, []string{"git.md", "git_remote.md", "git_remote_add.md", "git_status.md"}, "git_remote.md",
+			[]string{"## See also", "- [git](git.md)", "- [git remote add](git_remote_add.md)"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "out")
+			written, err := p.GenerateDocs(dir, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, path := range written {
+				names = append(names, filepath.Base(path))
+			}
+			if !slices.Equal(names, tt.files) {
+				t.Errorf("written = %q, want %q", names, tt.files)
+			}
+			page := readPage(t, filepath.Join(dir, tt.remote))
+			for _, want := range tt.wantLinks {
+				if !strings.Contains(page, want) {
+					t.Errorf("%s missing %q:\n%s", tt.remote, want, page)
+				}
+			}
+			// Subcommand details live on their own pages, not the parent's.
+			if strings.Contains(page, "parallel jobs") {
+				t.Errorf("%s includes subcommand options:\n%s", tt.remote, page)
+			}
+		})
+	}
+
+	if _, err := p.GenerateDocs(t.TempDir(), HelpFormatJSON); err == nil {
+		t.Error("expected error for json format")
+	}
+}
+
+func TestGenerateFlags(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		config Config
+		args   []string
+		want   *GenerateRequest
+	}{
+		{"man", Config{GenerateMan: true}, []string{"--generate-man", dir}, &GenerateRequest{HelpFormatMan, dir}},
+		{"docs", Config{GenerateDocs: true}, []string{"--generate-docs=" + dir}, &GenerateRequest{HelpFormatMarkdown, dir}},
+		{"from subcommand", Config{GenerateDocs: true}, []string{"status", "--generate-docs", dir}, &GenerateRequest{HelpFormatMarkdown, dir}},
+		{"disabled", Config{GenerateDocs: true}, []string{"--generate-man", dir}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(tt.config, &generateArgs{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = p.Parse(tt.args)
+			var req *GenerateRequest
+			if tt.want == nil {
+				if err == nil || errors.As(err, &req) {
+					t.Errorf("Parse() = %v, want unrecognized argument", err)
+				}
+				return
+			}
+			if !errors.As(err, &req) || *req != *tt.want {
+				t.Errorf("Parse() = %v, want %+v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustParseGenerate(t *testing.T) {
+	dir := t.TempDir()
+	code := -1
+	p, err := NewParser(Config{Program: "git", GenerateMan: true, Exit: func(c int) { code = c }}, &generateArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.MustParse([]string{"--generate-man", dir})
+	if code != 0 {
+		t.Errorf("exit code = %d, want 0", code)
+	}
+	if page := readPage(t, filepath.Join(dir, "git.1")); !strings.Contains(page, ".TH GIT 1") {
+		t.Errorf("unexpected man page:\n%s", page)
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"io"
 	"os"
 	"reflect"
-	"strings"
+	"time"
 
 	"github.com/major0/optargs"
 )
@@ -41,6 +41,8 @@ type Parser struct {
 	// Active subcommand chain, populated during Parse
 	subcommandNames []string
 	subcommandDest  any
+
+	stats ParseStats
 }
 
 // Config matches alexflint/go-arg configuration options exactly.
@@ -57,6 +59,58 @@ type Config struct {
 	EnvPrefix             string
 	Exit                  func(int)
 	Out                   io.Writer
+	ErrorFormat           ErrorFormat // rendering of parse failures in MustParse/Fail (default: plain)
+
+	// GenerateMan and GenerateDocs register the maintenance flags
+	// --generate-man=DIR and --generate-docs=DIR, which make Parse return
+	// a *GenerateRequest and MustParse write man pages or Markdown for
+	// the whole command tree into DIR; see Parser.GenerateDocs.
+	GenerateMan  bool
+	GenerateDocs bool
+
+	// HelpSort selects the order of options and subcommands in help
+	// text, and HelpColumns its column layout; the zero values keep the
+	// go-arg layout.
+	HelpSort    HelpSort
+	HelpColumns HelpColumns
+
+	// MaxHelpLines, when positive, caps the length of the help text:
+	// help that would run longer lists only the option names, and the
+	// builtin --help-all flag prints the complete listing.
+	MaxHelpLines int
+
+	// OnFieldParsed, when set, is called each time a command-line option
+	// stores a value into a field, with the raw argument ("true" for bare
+	// booleans). Intended for progress reporting and instrumentation. For
+	// a field tagged `secret` the value is always empty.
+	OnFieldParsed func(field *FieldMetadata, value string)
+
+	// OnFlagParsed, when set, is called each time a field is set, with
+	// the spelling that identifies it ("--verbose", "-v" for short-only
+	// options, the uppercase name of a positional, or "$VAR" for an
+	// env-only field) and where the value came from. It never receives
+	// the value, so products can count which options are used without
+	// collecting what users type.
+	OnFlagParsed func(name string, source FlagSource)
+
+	// MaxSubcommandDepth allows a subcommand struct to contain itself,
+	// directly or through other subcommands, as in an expression
+	// evaluator's "neg" command taking another expression. The struct may
+	// recur this many times below its first use; deeper, the recursive
+	// subcommand is not offered. Zero makes such a cycle an error from
+	// NewParser. Every level is registered up front, so keep it small.
+	MaxSubcommandDepth int
+
+	// Stdin is read by a field tagged `stdin` when its option is given
+	// the value "-"; nil means os.Stdin.
+	Stdin io.Reader
+
+	// Phases selects the stages of Parse to run, for embedders that do
+	// some of the work themselves: a GUI that supplies positionals but
+	// wants option parsing (PhaseAll &^ PhasePositionals), or a check of
+	// a struct populated elsewhere (PhaseValidation). Zero runs them all.
+	// ParseValues honors the env, defaults, and validation phases.
+	Phases Phase
 }
 
 // Parse parses command line arguments into the destination struct(s).
@@ -80,6 +134,27 @@ func ParseArgs(dest any, args []string) error {
 	return parser.Parse(args)
 }
 
+// ParseAs parses args into a new value of the struct type T and returns
+// it, so a small tool can declare its options inline:
+//
+//	opts, err := goarg.ParseAs[struct {
+//		Verbose bool   `arg:"-v"`
+//		Output  string `arg:"-o" default:"out.txt"`
+//	}](os.Args[1:])
+//
+// T may be an anonymous struct type. The metadata built for T is cached,
+// as by [Precompile], so repeated calls skip reflection. Errors, including
+// [ErrHelp] and [ErrVersion], are returned as by [Parser.Parse], together
+// with the value as populated so far.
+func ParseAs[T any](args []string) (T, error) {
+	var v T
+	if _, _, err := loadMetadata(&v, 0, true); err != nil {
+		return v, err
+	}
+	err := ParseArgs(&v, args)
+	return v, err
+}
+
 // MustParse parses command line arguments, prints help/version on the
 // corresponding sentinel errors, and exits on any error. Returns the
 // parser on success so callers can inspect subcommand state.
@@ -117,11 +192,15 @@ func NewParser(config Config, dest any) (*Parser, error) {
 		return nil, fmt.Errorf("destination must be a pointer to a struct, got pointer to %s", destElem.Kind())
 	}
 
-	// Parse struct metadata
-	tagParser := &TagParser{}
-	metadata, err := tagParser.ParseStruct(dest)
+	// Parse struct metadata, or reuse it from Precompile
+	start := time.Now()
+	metadata, cached, err := loadMetadata(dest, config.MaxSubcommandDepth, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse struct: %w", err)
+		return nil, err
+	}
+	stats := ParseStats{Options: len(metadata.Options), Cached: cached}
+	if !cached {
+		stats.Metadata = time.Since(start)
 	}
 
 	// Detect Versioned/Described/Epilogued interfaces on dest struct
@@ -145,6 +224,7 @@ func NewParser(config Config, dest any) (*Parser, error) {
 		dest:            dest,
 		metadata:        metadata,
 		errorTranslator: &ErrorTranslator{},
+		stats:           stats,
 	}, nil
 }
 
@@ -154,11 +234,15 @@ func (p *Parser) Parse(args []string) error {
 		args = os.Args[1:]
 	}
 
+	parseOptions := p.config.runs(PhaseOptions)
+	dispatch := parseOptions && p.config.runs(PhaseSubcommands)
+
 	ci := &CoreIntegration{
 		metadata: p.metadata,
 		config:   p.config,
 	}
 	destValue := reflect.ValueOf(p.dest).Elem()
+	start := time.Now()
 
 	// Build parser with Handle callbacks
 	coreParser, err := ci.CreateParserWithHandlers(args, destValue)
@@ -167,27 +251,34 @@ func (p *Parser) Parse(args []string) error {
 	}
 
 	// Register subcommands
-	if err := ci.RegisterSubcommands(coreParser, destValue); err != nil {
-		return p.translateError(err, "")
+	if dispatch {
+		if err := ci.RegisterSubcommands(coreParser, destValue); err != nil {
+			return p.translateError(err, "")
+		}
 	}
 
 	p.coreParser = coreParser
+	p.stats.Build = time.Since(start)
+	start = time.Now()
 
-	// Iterate — Handle callbacks fire automatically
-	for _, err := range coreParser.Options() {
-		if err != nil {
-			// Sentinel errors pass through without translation
-			if errors.Is(err, ErrHelp) || errors.Is(err, ErrVersion) {
-				return err
+	// Iterate — Handle callbacks fire automatically. Without
+	// PhaseOptions the arguments stay in coreParser.Args as operands.
+	if parseOptions {
+		for _, err := range coreParser.Options() {
+			if err != nil {
+				// Sentinel errors pass through without translation
+				if errors.Is(err, ErrHelp) || errors.Is(err, ErrVersion) || isGenerateRequest(err) {
+					return err
+				}
+				return p.translateError(err, "")
 			}
-			return p.translateError(err, "")
 		}
 	}
 
 	// Subcommand dispatch: use core's ActiveCommand() to detect which
 	// subcommand was dispatched, iterate its Options(), run PostParse,
 	// and walk recursively for nested subcommands.
-	if len(p.metadata.Subcommands) > 0 { //nolint:nestif // subcommand dispatch requires conditional walk + recursive parse
+	if dispatch && len(p.metadata.Subcommands) > 0 { //nolint:nestif // subcommand dispatch requires conditional walk + recursive parse
 		invokedName, childParser := coreParser.ActiveCommand()
 
 		if invokedName != "" && childParser != nil {
@@ -197,24 +288,17 @@ func (p *Parser) Parse(args []string) error {
 			p.recordSubcommandChain(destValue, ci)
 		}
 
-		// Nil out non-invoked subcommand fields so callers can detect
-		// which subcommand was selected.
-		for name := range p.metadata.Subcommands {
-			if strings.EqualFold(name, invokedName) {
-				continue
-			}
-			fv, _, err := ci.findSubcommandField(destValue, name)
-			if err != nil {
-				continue
-			}
-			if fv.Kind() == reflect.Ptr {
-				fv.Set(reflect.Zero(fv.Type()))
-			}
-		}
+		ci.clearSubcommands(destValue, invokedName)
 	}
 
+	p.stats.Iterate = time.Since(start)
+	start = time.Now()
+
 	// Post-parse: positionals, env vars, defaults, required validation
-	return p.translateError(ci.PostParse(coreParser, destValue), "")
+	err = ci.PostParse(coreParser, destValue)
+	p.stats.PostParse = time.Since(start)
+	err = p.requiredByCommand(err, coreParser, NewHelpGenerator(p.metadata, p.config).programName())
+	return p.translateError(err, "")
 }
 
 // WriteHelp writes help text to the provided writer.
@@ -223,7 +307,13 @@ func (p *Parser) WriteHelp(w io.Writer) {
 	helpGenerator.WriteHelp(w) //nolint:errcheck,gosec // matches upstream go-arg API (no error return)
 }
 
-// WriteUsage writes usage text to the provided writer.
+// WriteHelpAll writes the complete help text, ignoring Config.MaxHelpLines.
+func (p *Parser) WriteHelpAll(w io.Writer) {
+	helpGenerator := NewHelpGenerator(p.metadata, p.config)
+	helpGenerator.WriteHelpAll(w) //nolint:errcheck,gosec // mirrors WriteHelp
+}
+
+// WriteUsage writes the one-line usage synopsis to the provided writer.
 func (p *Parser) WriteUsage(w io.Writer) {
 	helpGenerator := NewHelpGenerator(p.metadata, p.config)
 	helpGenerator.WriteUsage(w) //nolint:errcheck,gosec // matches upstream go-arg API (no error return)
@@ -231,8 +321,7 @@ func (p *Parser) WriteUsage(w io.Writer) {
 
 // Fail prints an error message and exits.
 func (p *Parser) Fail(msg string) {
-	fmt.Fprintln(p.output(), msg)
-	p.WriteUsage(p.output())
+	p.writeError(p.output(), errors.New(msg), p.WriteUsage)
 	p.config.Exit(1)
 }
 
@@ -245,6 +334,7 @@ func (p *Parser) MustParse(args []string) {
 
 // handleMustParseError handles the result of Parse for MustParse callers.
 // ErrHelp prints help and exits 0, ErrVersion prints version and exits 0,
+// a *GenerateRequest writes documentation and exits 0 (1 on failure), and
 // any other error prints the error with usage and exits 1.
 func (p *Parser) handleMustParseError(err error) {
 	if err == nil {
@@ -253,14 +343,36 @@ func (p *Parser) handleMustParseError(err error) {
 	out := p.output()
 	switch {
 	case errors.Is(err, ErrHelp):
-		p.WriteHelp(out)
+		var req *HelpRequest
+		errors.As(err, &req)
+		switch {
+		case req == nil:
+			p.WriteHelp(out)
+		case req.All:
+			p.WriteHelpAll(out)
+		default:
+			p.WriteHelpFormat(out, req.Format) //nolint:errcheck,gosec // format validated by Parse
+		}
 		p.config.Exit(0)
 	case errors.Is(err, ErrVersion):
-		fmt.Fprintln(out, p.config.Version)
+		version := p.config.Version
+		var req *VersionRequest
+		if errors.As(err, &req) {
+			version = req.Version
+		}
+		fmt.Fprintln(out, version)
+		p.config.Exit(0)
+	case isGenerateRequest(err):
+		var req *GenerateRequest
+		errors.As(err, &req)
+		if _, err := p.GenerateDocs(req.Dir, req.Format); err != nil {
+			p.writeError(out, err, nil)
+			p.config.Exit(1)
+			return
+		}
 		p.config.Exit(0)
 	default:
-		fmt.Fprintln(out, err)
-		p.WriteUsage(out)
+		p.writeError(out, err, p.WriteUsage)
 		p.config.Exit(1)
 	}
 }
@@ -271,6 +383,10 @@ func (p *Parser) translateError(err error, fieldName string) error {
 		return nil
 	}
 
+	if req := p.helpFormatRequest(err); req != nil {
+		return req
+	}
+
 	context := ParseContext{
 		StructType: reflect.TypeOf(p.dest).Elem(),
 		FieldName:  fieldName,
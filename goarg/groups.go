@@ -0,0 +1,105 @@
+package goarg
+
+import (
+	"reflect"
+	"strings"
+)
+
+// XorGroup is a set of mutually exclusive options declared by a shared
+// `xor:"name"` struct tag. When any member is tagged `required`, the group
+// as a whole is required: exactly one member must be given.
+type XorGroup struct {
+	Name     string
+	Fields   []*FieldMetadata
+	Required bool
+}
+
+// XorGroups returns the mutual-exclusion groups declared on the struct's
+// options, ordered by the declaration of each group's first member.
+func (m *StructMetadata) XorGroups() []XorGroup {
+	var groups []XorGroup
+	index := make(map[string]int)
+	for i := range m.Options {
+		field := &m.Options[i]
+		if field.Group == "" {
+			continue
+		}
+		gi, ok := index[field.Group]
+		if !ok {
+			gi = len(groups)
+			index[field.Group] = gi
+			groups = append(groups, XorGroup{Name: field.Group})
+		}
+		groups[gi].Fields = append(groups[gi].Fields, field)
+		if field.Required {
+			groups[gi].Required = true
+		}
+	}
+	return groups
+}
+
+// optionSpelling returns the preferred command-line spelling of an option
+// field: --long when available, otherwise -s.
+func optionSpelling(field *FieldMetadata) string {
+	if field.Long != "" {
+		return "--" + field.Long
+	}
+	if field.Short != "" {
+		return "-" + field.Short
+	}
+	return field.Name
+}
+
+// spellings returns the option spellings of every group member.
+func (g *XorGroup) spellings() []string {
+	names := make([]string, len(g.Fields))
+	for i, field := range g.Fields {
+		names[i] = optionSpelling(field)
+	}
+	return names
+}
+
+// validateXorGroups reports an error when more than one member of a
+// mutual-exclusion group was set from the command line, as recorded in
+// setFields. Values already in the destination, or from env vars and
+// defaults, do not count; an explicit zero value such as --count=0 does.
+func validateXorGroups(setFields map[string]bool, metadata *StructMetadata) error {
+	for _, g := range metadata.XorGroups() {
+		var given []string
+		for _, field := range g.Fields {
+			if setFields[field.Name] {
+				given = append(given, optionSpelling(field))
+			}
+		}
+		if len(given) > 1 {
+			return &ParseError{
+				Message: "options " + strings.Join(given, " and ") + " are mutually exclusive",
+				Flag:    given[1],
+			}
+		}
+	}
+	return nil
+}
+
+// validateXorRequired reports an error when a required group has no
+// member set: none given on the command line, even as an explicit zero
+// value, and none holding a value from env vars, defaults, or the
+// destination, as for other required fields.
+func validateXorRequired(destValue reflect.Value, setFields map[string]bool, metadata *StructMetadata) error {
+	for _, g := range metadata.XorGroups() {
+		if !g.Required {
+			continue
+		}
+		found := false
+		for _, field := range g.Fields {
+			if setFields[field.Name] || !isZeroValue(fieldByMeta(destValue, field)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &ParseError{Message: "one of " + strings.Join(g.spellings(), ", ") + " is required"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package goarg
+
+import (
+	"bytes"
+	"testing"
+)
+
+type xorArgs struct {
+	JSON bool   `arg:"--json" xor:"format" help:"emit JSON"`
+	YAML bool   `arg:"--yaml" xor:"format" help:"emit YAML"`
+	Out  string `arg:"-o,--out" help:"output file"`
+}
+
+type xorCountArgs struct {
+	Count int  `arg:"--count" xor:"limit"`
+	All   bool `arg:"--all" xor:"limit"`
+}
+
+type xorRequiredArgs struct {
+	File string `arg:"--file,required" xor:"source"`
+	URL  string `arg:"--url" xor:"source"`
+}
+
+func TestXorGroupsMetadata(t *testing.T) {
+	p, err := NewParser(Config{}, &xorArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := p.metadata.XorGroups()
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	g := groups[0]
+	if g.Name != "format" || len(g.Fields) != 2 || g.Required {
+		t.Errorf("unexpected group %+v", g)
+	}
+}
+
+func TestXorGroupsSynopsis(t *testing.T) {
+	tests := []struct {
+		name string
+		dest any
+		want string
+	}{
+		{"optional", &xorArgs{}, "Usage: prog [--json | --yaml] [OPTIONS]\n"},
+		{"required", &xorRequiredArgs{}, "Usage: prog (--file FILE | --url URL)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(Config{Program: "prog"}, tt.dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			p.WriteUsage(&buf)
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestXorGroupsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		dest    any
+		args    []string
+		wantErr string
+	}{
+		{"none", &xorArgs{}, []string{}, ""},
+		{"one", &xorArgs{}, []string{"--json"}, ""},
+		{"both", &xorArgs{}, []string{"--json", "--yaml"}, "options --json and --yaml are mutually exclusive"},
+		{"required missing", &xorRequiredArgs{}, []string{}, "one of --file, --url is required"},
+		{"required satisfied by non-required member", &xorRequiredArgs{}, []string{"--url", "x"}, ""},
+		{"required both", &xorRequiredArgs{}, []string{"--url", "x", "--file", "y"}, "options --file and --url are mutually exclusive"},
+		{"pre-populated destination", &xorArgs{JSON: true, YAML: true}, []string{}, ""},
+		{"pre-populated member and flag", &xorArgs{JSON: true}, []string{"--yaml"}, ""},
+		{"explicit zero value", &xorCountArgs{}, []string{"--count=0", "--all"}, "options --count and --all are mutually exclusive"},
+		{"required satisfied by explicit zero value", &xorRequiredArgs{}, []string{"--url="}, ""},
+		{"required satisfied by pre-populated member", &xorRequiredArgs{URL: "x"}, []string{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseArgs(tt.dest, tt.args)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestXorTagOnPositionalRejected(t *testing.T) {
+	type Args struct {
+		Src string `arg:"positional" xor:"x"`
+	}
+	if _, err := NewParser(Config{}, &Args{}); err == nil {
+		t.Error("expected error for xor tag on positional field")
+	}
+}
@@ -1,6 +1,7 @@
 package goarg
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -33,41 +34,40 @@ func (hg *HelpGenerator) programName() string {
 	return os.Args[0]
 }
 
-// WriteHelp writes help text to the provided writer.
+// WriteHelp writes help text to the provided writer. When
+// Config.MaxHelpLines is set and the complete help would be longer, the
+// options are condensed to their names; see WriteHelpAll.
+func (hg *HelpGenerator) WriteHelp(w io.Writer) error {
+	if hg.config.MaxHelpLines > 0 && hg.metadata != nil {
+		var full bytes.Buffer
+		hg.writeHelp(&full, false) //nolint:errcheck,gosec // bytes.Buffer writes do not fail
+		if bytes.Count(full.Bytes(), []byte("\n")) > hg.config.MaxHelpLines {
+			return hg.writeHelp(w, true)
+		}
+		_, err := w.Write(full.Bytes())
+		return err
+	}
+	return hg.writeHelp(w, false)
+}
+
+// WriteHelpAll writes the complete help text regardless of
+// Config.MaxHelpLines, as requested with --help-all.
+func (hg *HelpGenerator) WriteHelpAll(w io.Writer) error {
+	return hg.writeHelp(w, false)
+}
+
+// writeHelp writes the help text, with the options section reduced to
+// option names when condensed is set.
 //
 //nolint:gocognit,gocyclo,cyclop,funlen // help text generation requires conditional formatting for each field type
-func (hg *HelpGenerator) WriteHelp(w io.Writer) error {
+func (hg *HelpGenerator) writeHelp(w io.Writer, condensed bool) error {
 	if hg.metadata == nil {
 		fmt.Fprintln(w, "No help available")
 		return nil
 	}
 
-	program := hg.programName()
-
 	// Usage line
-	fmt.Fprintf(w, "Usage: %s", program)
-
-	// Add subcommands if available
-	if len(hg.metadata.Subcommands) > 0 {
-		fmt.Fprint(w, " COMMAND")
-	}
-
-	// Add options placeholder if we have options
-	if len(hg.metadata.Options) > 0 {
-		fmt.Fprint(w, " [OPTIONS]")
-	}
-
-	// Add positional arguments
-	for i := range hg.metadata.Positionals {
-		field := &hg.metadata.Positionals[i]
-		if field.Required {
-			fmt.Fprintf(w, " %s", strings.ToUpper(field.Name))
-		} else {
-			fmt.Fprintf(w, " [%s]", strings.ToUpper(field.Name))
-		}
-	}
-
-	fmt.Fprintln(w)
+	hg.writeSynopsis(w)
 
 	// Add description if available
 	if hg.config.Description != "" {
@@ -81,82 +81,42 @@ func (hg *HelpGenerator) WriteHelp(w io.Writer) error {
 		fmt.Fprintln(w, "Positional arguments:")
 		for i := range hg.metadata.Positionals {
 			field := &hg.metadata.Positionals[i]
-			name := strings.ToUpper(field.Name)
-			if field.Help != "" {
-				fmt.Fprintf(w, "  %-20s %s\n", name, field.Help)
-			} else {
-				fmt.Fprintf(w, "  %s\n", name)
-			}
+			hg.writeRow(w, "  "+strings.ToUpper(field.Name), field.Help, "", 22)
 		}
 	}
 
 	// Add options section
-	if len(hg.metadata.Options) > 0 {
+	if condensed {
+		hg.writeCondensedOptions(w)
+	} else if len(hg.metadata.Options) > 0 {
+		options, groups := hg.helpSections()
 		fmt.Fprintln(w)
 		fmt.Fprintln(w, "Options:")
+		for _, field := range options {
+			hg.writeOption(w, field)
+		}
 
-		for i := range hg.metadata.Options {
-			field := &hg.metadata.Options[i]
-			var optStr string
-			switch {
-			case field.Short != "" && field.Long != "":
-				optStr = fmt.Sprintf("  -%s, --%s", field.Short, field.Long)
-			case field.Short != "":
-				optStr = fmt.Sprintf("  -%s", field.Short)
-			case field.Long != "":
-				optStr = fmt.Sprintf("      --%s", field.Long)
-			}
-
-			// Add argument placeholder for options that take arguments
-			if field.ArgType != 0 { // NoArgument is 0
-				argName := strings.ToUpper(field.Name)
-				optStr += fmt.Sprintf(" %s", argName)
-			}
-
-			// Append prefix pair forms
-			var optStrSb110 strings.Builder
-			for _, pp := range field.Prefixes {
-				fmt.Fprintf(&optStrSb110, ", --%s-%s, --%s-%s", pp.True, field.Long, pp.False, field.Long)
-			}
-			optStr += optStrSb110.String()
-			// Append negatable form
-			if field.Negatable {
-				optStr += fmt.Sprintf(", --no-%s", field.Long)
-			}
-
-			if field.Help != "" {
-				fmt.Fprintf(w, "%-30s %s", optStr, field.Help)
-			} else {
-				fmt.Fprint(w, optStr)
-			}
-
-			// Add default value if available
-			if field.Default != nil && field.Default != "" {
-				fmt.Fprintf(w, " (default: %v)", field.Default)
-			}
+		// Add help option
+		hg.writeRow(w, "  -h, --help", "show this help message and exit", "", 30)
+		if hg.config.MaxHelpLines > 0 {
+			hg.writeRow(w, "      --help-all", "show help for every option and exit", "", 30)
+		}
 
+		for _, group := range groups {
 			fmt.Fprintln(w)
+			fmt.Fprintf(w, "%s options (mutually exclusive):\n", group.Name)
+			for _, field := range group.Fields {
+				hg.writeOption(w, field)
+			}
 		}
-
-		// Add help option
-		fmt.Fprintf(w, "%-30s %s\n", "  -h, --help", "show this help message and exit")
 	}
 
 	// Add subcommands section
 	if len(hg.metadata.Subcommands) > 0 {
 		fmt.Fprintln(w)
 		fmt.Fprintln(w, "Commands:")
-		for cmdName := range hg.metadata.Subcommands {
-			help := ""
-			// Get help text from the SubcommandHelp map
-			if hg.metadata.SubcommandHelp != nil {
-				help = hg.metadata.SubcommandHelp[cmdName]
-			}
-			if help != "" {
-				fmt.Fprintf(w, "  %-20s %s\n", cmdName, help)
-			} else {
-				fmt.Fprintf(w, "  %s\n", cmdName)
-			}
+		for _, cmdName := range hg.commandNames() {
+			hg.writeRow(w, "  "+cmdName, hg.metadata.SubcommandHelp[cmdName], "", 22)
 		}
 	}
 
@@ -172,19 +132,14 @@ func (hg *HelpGenerator) WriteHelp(w io.Writer) error {
 		fmt.Fprintln(w, "Environment variables:")
 		for i := range hg.metadata.EnvOnly {
 			field := &hg.metadata.EnvOnly[i]
-			label := fmt.Sprintf("  %s", field.Env)
-			if field.Help != "" {
-				fmt.Fprintf(w, "%-30s %s", label, field.Help)
-			} else {
-				fmt.Fprint(w, label)
-			}
+			var suffix string
 			if field.Required {
-				fmt.Fprint(w, " (required)")
+				suffix += " (required)"
 			}
 			if field.Default != nil && field.Default != "" {
-				fmt.Fprintf(w, " (default: %v)", field.Default)
+				suffix += fmt.Sprintf(" (default: %v)", field.Default)
 			}
-			fmt.Fprintln(w)
+			hg.writeRow(w, "  "+field.Env, field.Help, suffix, 30)
 		}
 	}
 
@@ -197,38 +152,139 @@ func (hg *HelpGenerator) WriteHelp(w io.Writer) error {
 	return nil
 }
 
+// writeOption writes the help row of one option.
+func (hg *HelpGenerator) writeOption(w io.Writer, field *FieldMetadata) {
+	var optStr string
+	switch {
+	case field.Short != "" && field.Long != "":
+		optStr = fmt.Sprintf("  -%s, --%s", field.Short, field.Long)
+	case field.Short != "":
+		optStr = fmt.Sprintf("  -%s", field.Short)
+	case field.Long != "":
+		optStr = fmt.Sprintf("      --%s", field.Long)
+	}
+
+	// Add argument placeholder for options that take arguments
+	if field.ArgType != 0 { // NoArgument is 0
+		argName := strings.ToUpper(field.Name)
+		optStr += fmt.Sprintf(" %s", argName)
+	}
+
+	// Append prefix pair forms
+	var optStrSb110 strings.Builder
+	for _, pp := range field.Prefixes {
+		fmt.Fprintf(&optStrSb110, ", --%s-%s, --%s-%s", pp.True, field.Long, pp.False, field.Long)
+	}
+	optStr += optStrSb110.String()
+	// Append negatable form
+	if field.Negatable {
+		optStr += fmt.Sprintf(", --no-%s", field.Long)
+	}
+
+	// Add default value if available
+	var suffix string
+	if field.Default != nil && field.Default != "" {
+		suffix = fmt.Sprintf(" (default: %v)", field.Default)
+	}
+	hg.writeRow(w, optStr, field.Help, suffix, 30)
+}
+
+// writeCondensedOptions lists the option spellings, --help included, packed
+// onto lines of at most condensedWidth columns, followed by a pointer to
+// --help-all.
+func (hg *HelpGenerator) writeCondensedOptions(w io.Writer) {
+	options, groups := hg.helpSections()
+	for _, group := range groups {
+		options = append(options, group.Fields...)
+	}
+	names := make([]string, 0, len(options)+1)
+	for _, field := range options {
+		name := optionSpelling(field)
+		if field.ArgType != optargs.NoArgument {
+			name += " " + strings.ToUpper(field.Name)
+		}
+		names = append(names, name)
+	}
+	names = append(names, "--help")
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Options:")
+	line := " "
+	for i, name := range names {
+		if i < len(names)-1 {
+			name += ","
+		}
+		if len(line)+1+len(name) > condensedWidth && line != " " {
+			fmt.Fprintln(w, line)
+			line = " "
+		}
+		line += " " + name
+	}
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run with --help-all to describe every option.")
+}
+
+// condensedWidth is the line width condensed option listings wrap at.
+const condensedWidth = 79
+
 // WriteUsage writes usage text to the provided writer.
 //
 
 func (hg *HelpGenerator) WriteUsage(w io.Writer) error {
-	program := hg.programName()
+	hg.writeSynopsis(w)
+	return nil
+}
+
+// writeSynopsis writes the one-line "Usage:" synopsis. Mutually exclusive
+// option groups are spelled out as (--a | --b) when required and
+// [--a | --b] otherwise; remaining options collapse to [OPTIONS].
+func (hg *HelpGenerator) writeSynopsis(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s", hg.programName())
 
-	fmt.Fprintf(w, "Usage: %s", program)
+	if hg.metadata == nil {
+		fmt.Fprintln(w)
+		return
+	}
 
 	// Add subcommands if available
-	if hg.metadata != nil && len(hg.metadata.Subcommands) > 0 {
+	if len(hg.metadata.Subcommands) > 0 {
 		fmt.Fprint(w, " COMMAND")
 	}
 
-	if hg.metadata != nil {
-		// Add options placeholder if we have options
-		if len(hg.metadata.Options) > 0 {
-			fmt.Fprint(w, " [OPTIONS]")
+	// Add mutually exclusive groups, then an options placeholder for
+	// any options not covered by a group.
+	grouped := 0
+	for _, g := range hg.metadata.XorGroups() {
+		alts := make([]string, len(g.Fields))
+		for i, field := range g.Fields {
+			alts[i] = optionSpelling(field)
+			if field.ArgType != optargs.NoArgument {
+				alts[i] += " " + strings.ToUpper(field.Name)
+			}
 		}
+		open, closing := "[", "]"
+		if g.Required {
+			open, closing = "(", ")"
+		}
+		fmt.Fprintf(w, " %s%s%s", open, strings.Join(alts, " | "), closing)
+		grouped += len(g.Fields)
+	}
+	if len(hg.metadata.Options) > grouped {
+		fmt.Fprint(w, " [OPTIONS]")
+	}
 
-		// Add positional arguments
-		for i := range hg.metadata.Positionals {
-			field := &hg.metadata.Positionals[i]
-			if field.Required {
-				fmt.Fprintf(w, " %s", strings.ToUpper(field.Name))
-			} else {
-				fmt.Fprintf(w, " [%s]", strings.ToUpper(field.Name))
-			}
+	// Add positional arguments
+	for i := range hg.metadata.Positionals {
+		field := &hg.metadata.Positionals[i]
+		if field.Required {
+			fmt.Fprintf(w, " %s", strings.ToUpper(field.Name))
+		} else {
+			fmt.Fprintf(w, " [%s]", strings.ToUpper(field.Name))
 		}
 	}
 
 	fmt.Fprintln(w)
-	return nil
 }
 
 // ErrorTranslator translates OptArgs Core errors to go-arg format.
@@ -242,6 +298,16 @@ func (et *ErrorTranslator) TranslateError(err error, context ParseContext) error
 		return nil
 	}
 
+	// Builtin flag requests and errors already translated by goarg pass
+	// through unchanged.
+	if errors.Is(err, ErrHelp) || errors.Is(err, ErrVersion) || isGenerateRequest(err) {
+		return err
+	}
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr
+	}
+
 	// Typed error classification — use errors.As() for core parser errors.
 	var unknownErr *optargs.UnknownOptionError
 	if errors.As(err, &unknownErr) {
@@ -251,7 +317,17 @@ func (et *ErrorTranslator) TranslateError(err error, context ParseContext) error
 		} else {
 			option = "--" + option
 		}
-		return fmt.Errorf("unrecognized argument: %s", option)
+		spelled := option
+		if unknownErr.HasValue {
+			spelled += "=" + unknownErr.Value
+		}
+		var suggestions []string
+		for _, name := range unknownErr.Suggestions {
+			suggestions = append(suggestions, "--"+name)
+		}
+		return &ParseError{
+			Message: "unrecognized argument: " + spelled, Flag: option, Suggestions: suggestions, err: err,
+		}
 	}
 
 	var missingErr *optargs.MissingArgumentError
@@ -262,12 +338,13 @@ func (et *ErrorTranslator) TranslateError(err error, context ParseContext) error
 		} else {
 			option = "--" + option
 		}
-		return fmt.Errorf("option requires an argument: %s", option)
+		return &ParseError{Message: "option requires an argument: " + option, Flag: option, err: err}
 	}
 
 	var unexpectedErr *optargs.UnexpectedArgumentError
 	if errors.As(err, &unexpectedErr) {
-		return fmt.Errorf("option does not take an argument: --%s", unexpectedErr.Name)
+		option := "--" + unexpectedErr.Name
+		return &ParseError{Message: "option does not take an argument: " + option, Flag: option, err: err}
 	}
 
 	errMsg := err.Error()
@@ -249,6 +249,14 @@ func TestErrorMessageFormatAndContent(t *testing.T) {
 			args:          []string{"--unknown"},
 			expectedError: "unrecognized argument: --unknown",
 		},
+		{
+			name: "unknown long option with value",
+			testStruct: &struct {
+				Verbose bool `arg:"-v,--verbose"`
+			}{},
+			args:          []string{"--unknown=value"},
+			expectedError: "unrecognized argument: --unknown=value",
+		},
 		{
 			name: "unknown short option",
 			testStruct: &struct {
@@ -0,0 +1,376 @@
+package goarg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/major0/optargs"
+)
+
+// HelpFormat selects a machine-readable rendering of help output, as
+// requested on the command line with --help=FORMAT.
+type HelpFormat string
+
+const (
+	// HelpFormatText is the default human-readable help (same as WriteHelp).
+	HelpFormatText HelpFormat = "text"
+	// HelpFormatJSON renders the HelpDoc model as indented JSON.
+	HelpFormatJSON HelpFormat = "json"
+	// HelpFormatMan renders a roff man page in section 1.
+	HelpFormatMan HelpFormat = "man"
+	// HelpFormatMarkdown renders Markdown suitable for documentation sites.
+	HelpFormatMarkdown HelpFormat = "md"
+)
+
+// HelpRequest is returned by Parse when --help=FORMAT or --help-all is
+// given. It satisfies errors.Is(err, ErrHelp), so callers that only check
+// for ErrHelp keep working.
+type HelpRequest struct {
+	Format HelpFormat
+	All    bool // --help-all: text help without the Config.MaxHelpLines cap
+}
+
+func (e *HelpRequest) Error() string { return ErrHelp.Error() }
+
+// Is reports whether target is ErrHelp.
+func (e *HelpRequest) Is(target error) bool { return target == ErrHelp }
+
+// HelpDoc is the stable, machine-readable model of a command's help. The
+// JSON field names form the --help=json schema; fields are only ever added.
+type HelpDoc struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Version     string       `json:"version,omitempty"`
+	Usage       string       `json:"usage"`
+	Positionals []HelpArg    `json:"positionals,omitempty"`
+	Options     []HelpOption `json:"options,omitempty"`
+	Env         []HelpArg    `json:"env,omitempty"`
+	Commands    []HelpDoc    `json:"commands,omitempty"`
+	Epilogue    string       `json:"epilogue,omitempty"`
+}
+
+// HelpArg describes a positional argument or an environment-only variable.
+type HelpArg struct {
+	Name     string `json:"name"`
+	Help     string `json:"help,omitempty"`
+	Default  string `json:"default,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+// HelpOption describes a command-line option.
+type HelpOption struct {
+	Short    string   `json:"short,omitempty"`
+	Long     string   `json:"long,omitempty"`
+	Aliases  []string `json:"aliases,omitempty"` // prefix-pair and --no- spellings
+	ArgName  string   `json:"arg,omitempty"`
+	Help     string   `json:"help,omitempty"`
+	Default  string   `json:"default,omitempty"`
+	Env      string   `json:"env,omitempty"`
+	Group    string   `json:"group,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+// HelpDoc returns the help model for the program and all of its
+// subcommands.
+func (p *Parser) HelpDoc() *HelpDoc {
+	doc := buildHelpDoc(p.metadata, p.config, NewHelpGenerator(p.metadata, p.config).programName())
+	doc.Epilogue = p.config.Epilogue
+	return &doc
+}
+
+// buildHelpDoc converts metadata into a HelpDoc named name. Subcommands
+// are sorted by name so output is stable.
+func buildHelpDoc(meta *StructMetadata, config Config, name string) HelpDoc {
+	config.Program = name
+	var usage bytes.Buffer
+	NewHelpGenerator(meta, config).writeSynopsis(&usage)
+
+	doc := HelpDoc{
+		Name:        name,
+		Description: config.Description,
+		Version:     config.Version,
+		Usage:       strings.TrimSuffix(strings.TrimPrefix(usage.String(), "Usage: "), "\n"),
+	}
+	for i := range meta.Positionals {
+		field := &meta.Positionals[i]
+		doc.Positionals = append(doc.Positionals, HelpArg{
+			Name:     strings.ToUpper(field.Name),
+			Help:     field.Help,
+			Default:  formatDefault(field),
+			Required: field.Required,
+		})
+	}
+	for i := range meta.Options {
+		field := &meta.Options[i]
+		opt := HelpOption{
+			Short:    field.Short,
+			Long:     field.Long,
+			Help:     field.Help,
+			Default:  formatDefault(field),
+			Env:      field.Env,
+			Group:    field.Group,
+			Required: field.Required,
+		}
+		if field.ArgType != optargs.NoArgument {
+			opt.ArgName = strings.ToUpper(field.Name)
+		}
+		for _, pp := range field.Prefixes {
+			opt.Aliases = append(opt.Aliases, pp.True+"-"+field.Long, pp.False+"-"+field.Long)
+		}
+		if field.Negatable {
+			opt.Aliases = append(opt.Aliases, "no-"+field.Long)
+		}
+		doc.Options = append(doc.Options, opt)
+	}
+	for i := range meta.EnvOnly {
+		field := &meta.EnvOnly[i]
+		doc.Env = append(doc.Env, HelpArg{
+			Name:     field.Env,
+			Help:     field.Help,
+			Default:  formatDefault(field),
+			Required: field.Required,
+		})
+	}
+
+	cmds := make([]string, 0, len(meta.Subcommands))
+	for cmd := range meta.Subcommands {
+		cmds = append(cmds, cmd)
+	}
+	slices.Sort(cmds)
+	for _, cmd := range cmds {
+		sub := Config{Description: meta.SubcommandHelp[cmd], Version: config.Version}
+		if version, ok := meta.SubcommandVersion[cmd]; ok {
+			sub.Version = version
+		}
+		doc.Commands = append(doc.Commands, buildHelpDoc(meta.Subcommands[cmd], sub, name+" "+cmd))
+	}
+	return doc
+}
+
+// WriteHelpFormat writes help for the program in the given format.
+func (p *Parser) WriteHelpFormat(w io.Writer, format HelpFormat) error {
+	switch format {
+	case HelpFormatText, "":
+		p.WriteHelp(w)
+		return nil
+	case HelpFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p.HelpDoc())
+	case HelpFormatMan:
+		return writeMan(w, p.HelpDoc())
+	case HelpFormatMarkdown:
+		return writeMarkdown(w, p.HelpDoc(), 1)
+	}
+	return fmt.Errorf("unknown help format: %s", format)
+}
+
+// helpFormatRequest recognizes --help=FORMAT in err, the error of the
+// core parser matching the builtin --help, which takes no argument, with a
+// value attached. The format is thus only taken from --help itself, not
+// from another option's argument or an operand, and never from a
+// following word. It returns nil for any other error, or when the
+// command defines its own --help.
+func (p *Parser) helpFormatRequest(err error) error {
+	var unexpected *optargs.UnexpectedArgumentError
+	if !errors.As(err, &unexpected) || unexpected.Name != "help" {
+		return nil
+	}
+	for i := range p.metadata.Options {
+		if p.metadata.Options[i].Long == "help" {
+			return nil
+		}
+	}
+	format := HelpFormat(unexpected.Value)
+	if !validHelpFormat(format) {
+		return &ParseError{Message: "unknown help format: " + unexpected.Value, Flag: "--help", err: err}
+	}
+	return &HelpRequest{Format: format}
+}
+
+// validHelpFormat reports whether f is a format WriteHelpFormat accepts.
+func validHelpFormat(f HelpFormat) bool {
+	switch f {
+	case HelpFormatText, HelpFormatJSON, HelpFormatMan, HelpFormatMarkdown:
+		return true
+	}
+	return false
+}
+
+// optionLabel formats an option as "-s, --long ARG".
+func optionLabel(opt *HelpOption) string {
+	var names []string
+	if opt.Short != "" {
+		names = append(names, "-"+opt.Short)
+	}
+	if opt.Long != "" {
+		names = append(names, "--"+opt.Long)
+	}
+	for _, alias := range opt.Aliases {
+		names = append(names, "--"+alias)
+	}
+	label := strings.Join(names, ", ")
+	if opt.ArgName != "" {
+		label += " " + opt.ArgName
+	}
+	return label
+}
+
+// annotations returns the parenthesized suffixes shared by all renderers.
+func annotations(def, env string, required bool) string {
+	var parts []string
+	if required {
+		parts = append(parts, "required")
+	}
+	if def != "" {
+		parts = append(parts, "default: "+def)
+	}
+	if env != "" {
+		parts = append(parts, "env: "+env)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// roffEscape escapes text for use in a roff document.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// writeMan renders doc as a section 1 man page.
+func writeMan(w io.Writer, doc *HelpDoc) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %s 1 \"\" %q\n", strings.ToUpper(roffEscape(doc.Name)), doc.Version)
+	b.WriteString(".SH NAME\n")
+	b.WriteString(roffEscape(doc.Name))
+	if doc.Description != "" {
+		b.WriteString(` \- ` + roffEscape(strings.SplitN(doc.Description, "\n", 2)[0]))
+	}
+	b.WriteString("\n")
+	writeManBody(&b, doc)
+	if doc.Epilogue != "" {
+		b.WriteString(".SH NOTES\n" + roffEscape(doc.Epilogue) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeManBody(b *strings.Builder, doc *HelpDoc) {
+	b.WriteString(".SH SYNOPSIS\n" + roffEscape(doc.Usage) + "\n")
+	if doc.Description != "" {
+		b.WriteString(".SH DESCRIPTION\n" + roffEscape(doc.Description) + "\n")
+	}
+	writeManItems(b, ".SH ARGUMENTS\n", ".SH OPTIONS\n", doc)
+	if len(doc.Env) > 0 {
+		b.WriteString(".SH ENVIRONMENT\n")
+		for _, env := range doc.Env {
+			fmt.Fprintf(b, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(env.Name),
+				roffEscape(env.Help+annotations(env.Default, "", env.Required)))
+		}
+	}
+	if len(doc.Commands) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		writeManCommands(b, doc.Commands)
+	}
+}
+
+// writeManCommands renders each subcommand, recursively, as a .SS
+// subsection since roff has no deeper heading level.
+func writeManCommands(b *strings.Builder, cmds []HelpDoc) {
+	for i := range cmds {
+		cmd := &cmds[i]
+		fmt.Fprintf(b, ".SS %s\n%s\n", roffEscape(cmd.Name), roffEscape(cmd.Usage))
+		if cmd.Description != "" {
+			b.WriteString(".PP\n" + roffEscape(cmd.Description) + "\n")
+		}
+		writeManItems(b, "", "", cmd)
+		writeManCommands(b, cmd.Commands)
+	}
+}
+
+// writeManItems renders positionals and options as tagged paragraphs,
+// each list preceded by its heading when non-empty.
+func writeManItems(b *strings.Builder, argsHeading, optsHeading string, doc *HelpDoc) {
+	if len(doc.Positionals) > 0 {
+		b.WriteString(argsHeading)
+		for _, arg := range doc.Positionals {
+			fmt.Fprintf(b, ".TP\n\\fI%s\\fR\n%s\n", roffEscape(arg.Name),
+				roffEscape(arg.Help+annotations(arg.Default, "", arg.Required)))
+		}
+	}
+	if len(doc.Options) > 0 {
+		b.WriteString(optsHeading)
+		for i := range doc.Options {
+			opt := &doc.Options[i]
+			fmt.Fprintf(b, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(optionLabel(opt)),
+				roffEscape(opt.Help+annotations(opt.Default, opt.Env, opt.Required)))
+		}
+	}
+}
+
+// writeMarkdown renders doc with top-level heading depth level.
+func writeMarkdown(w io.Writer, doc *HelpDoc, level int) error {
+	var b strings.Builder
+	writeMarkdownDoc(&b, doc, level)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownDoc(b *strings.Builder, doc *HelpDoc, level int) {
+	h := strings.Repeat("#", level)
+	fmt.Fprintf(b, "%s %s\n\n", h, doc.Name)
+	if doc.Description != "" {
+		b.WriteString(doc.Description + "\n\n")
+	}
+	fmt.Fprintf(b, "```\n%s\n```\n\n", doc.Usage)
+	if len(doc.Positionals) > 0 {
+		fmt.Fprintf(b, "%s# Arguments\n\n", h)
+		for _, arg := range doc.Positionals {
+			fmt.Fprintf(b, "- `%s`%s%s\n", arg.Name, mdHelp(arg.Help), annotations(arg.Default, "", arg.Required))
+		}
+		b.WriteString("\n")
+	}
+	if len(doc.Options) > 0 {
+		fmt.Fprintf(b, "%s# Options\n\n", h)
+		for i := range doc.Options {
+			opt := &doc.Options[i]
+			fmt.Fprintf(b, "- `%s`%s%s\n", optionLabel(opt), mdHelp(opt.Help), annotations(opt.Default, opt.Env, opt.Required))
+		}
+		b.WriteString("\n")
+	}
+	if len(doc.Env) > 0 {
+		fmt.Fprintf(b, "%s# Environment\n\n", h)
+		for _, env := range doc.Env {
+			fmt.Fprintf(b, "- `%s`%s%s\n", env.Name, mdHelp(env.Help), annotations(env.Default, "", env.Required))
+		}
+		b.WriteString("\n")
+	}
+	if doc.Version != "" {
+		fmt.Fprintf(b, "Version: %s\n\n", doc.Version)
+	}
+	if doc.Epilogue != "" {
+		b.WriteString(doc.Epilogue + "\n\n")
+	}
+	for i := range doc.Commands {
+		writeMarkdownDoc(b, &doc.Commands[i], level+1)
+	}
+}
+
+func mdHelp(help string) string {
+	if help == "" {
+		return ""
+	}
+	return ": " + help
+}
@@ -0,0 +1,124 @@
+package goarg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type helpFormatArgs struct {
+	Verbose bool           `arg:"-v,--verbose" help:"verbose output"`
+	Level   int            `arg:"--level" default:"2" help:"log level"`
+	Token   string         `arg:"env:APP_TOKEN" help:"API token"`
+	Src     string         `arg:"positional,required" help:"source file"`
+	Build   *helpBuildArgs `arg:"subcommand:build" help:"build things"`
+}
+
+type helpBuildArgs struct {
+	Jobs int `arg:"-j,--jobs" help:"parallel jobs"`
+}
+
+func newHelpFormatParser(t *testing.T, out *bytes.Buffer) *Parser {
+	t.Helper()
+	p, err := NewParser(Config{Program: "prog", Description: "does things", Version: "1.2.3", Out: out, Exit: func(int) {}}, &helpFormatArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestHelpDoc(t *testing.T) {
+	p := newHelpFormatParser(t, nil)
+	doc := p.HelpDoc()
+	if doc.Name != "prog" || doc.Version != "1.2.3" || doc.Usage != "prog COMMAND [OPTIONS] SRC" {
+		t.Errorf("unexpected doc header: %+v", doc)
+	}
+	if len(doc.Options) != 2 || doc.Options[1].Long != "level" || doc.Options[1].ArgName != "LEVEL" || doc.Options[1].Default != "2" {
+		t.Errorf("unexpected options: %+v", doc.Options)
+	}
+	if len(doc.Env) != 1 || doc.Env[0].Name != "APP_TOKEN" {
+		t.Errorf("unexpected env: %+v", doc.Env)
+	}
+	if len(doc.Commands) != 1 || doc.Commands[0].Name != "prog build" || doc.Commands[0].Description != "build things" {
+		t.Errorf("unexpected commands: %+v", doc.Commands)
+	}
+}
+
+func TestHelpFormatRequest(t *testing.T) {
+	tests := []struct {
+		format HelpFormat
+		want   string
+	}{
+		{HelpFormatText, "Usage: prog COMMAND [OPTIONS] SRC\n"},
+		{HelpFormatJSON, `"name": "prog"`},
+		{HelpFormatMan, ".TH PROG 1 \"\" \"1.2.3\"\n"},
+		{HelpFormatMarkdown, "# prog\n\ndoes things\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			p := newHelpFormatParser(t, &buf)
+
+			err := p.Parse([]string{"--help=" + string(tt.format)})
+			if !errors.Is(err, ErrHelp) {
+				t.Fatalf("expected ErrHelp, got %v", err)
+			}
+			p.MustParse([]string{"--help=" + string(tt.format)})
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output missing %q:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestHelpFormatJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	p := newHelpFormatParser(t, &buf)
+	if err := p.WriteHelpFormat(&buf, HelpFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var doc HelpDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Commands[0].Options[0].Short != "j" {
+		t.Errorf("round-trip lost subcommand options: %+v", doc.Commands)
+	}
+}
+
+func TestHelpFormatErrors(t *testing.T) {
+	p := newHelpFormatParser(t, nil)
+	err := p.Parse([]string{"--help=xml"})
+	if err == nil || err.Error() != "unknown help format: xml" {
+		t.Errorf("err = %v", err)
+	}
+
+	// --help=FORMAT after -- is an operand.
+	var a helpFormatArgs
+	if err := ParseArgs(&a, []string{"--", "--help=json"}); err != nil {
+		t.Fatal(err)
+	}
+	if a.Src != "--help=json" {
+		t.Errorf("Src = %q", a.Src)
+	}
+
+	// --help=FORMAT as the argument of another option is its value.
+	var titled struct {
+		Title string `arg:"--title"`
+	}
+	if err := ParseArgs(&titled, []string{"--title", "--help=json"}); err != nil {
+		t.Fatal(err)
+	}
+	if titled.Title != "--help=json" {
+		t.Errorf("Title = %q", titled.Title)
+	}
+
+	// The format is only taken when attached with '='.
+	err = ParseArgs(&helpFormatArgs{}, []string{"--help", "json"})
+	var req *HelpRequest
+	if !errors.Is(err, ErrHelp) || errors.As(err, &req) {
+		t.Errorf("--help json: err = %#v, want plain ErrHelp", err)
+	}
+}
@@ -0,0 +1,125 @@
+package goarg
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// HelpSort selects the order in which help text lists options and
+// subcommands.
+type HelpSort int
+
+const (
+	// HelpSortDeclaration lists options in struct declaration order, as
+	// go-arg does. It is the default.
+	HelpSortDeclaration HelpSort = iota
+	// HelpSortAlphabetical lists options by long name, or short name for
+	// short-only options, and subcommands by name.
+	HelpSortAlphabetical
+	// HelpSortGrouped lists options outside any `xor` group first, then
+	// each mutual-exclusion group under its own heading, in declaration
+	// order.
+	HelpSortGrouped
+)
+
+// HelpColumns sets the layout of the two-column listings in help text.
+// Zero fields keep the go-arg layout.
+type HelpColumns struct {
+	// Name is the width of the name column, including its two-space
+	// indent, in every section. By default options and environment
+	// variables use 30 columns and positionals and commands 22.
+	Name int
+
+	// Wrap, when positive, wraps help text so lines end by this column;
+	// continuation lines are indented to the help column.
+	Wrap int
+}
+
+// helpSections returns the options of the help listing in Config.HelpSort
+// order, split into sections: the untitled "Options" section first, then,
+// for HelpSortGrouped, one titled section per xor group.
+func (hg *HelpGenerator) helpSections() (options []*FieldMetadata, groups []XorGroup) {
+	for i := range hg.metadata.Options {
+		field := &hg.metadata.Options[i]
+		if hg.config.HelpSort == HelpSortGrouped && field.Group != "" {
+			continue
+		}
+		options = append(options, field)
+	}
+	switch hg.config.HelpSort {
+	case HelpSortAlphabetical:
+		slices.SortStableFunc(options, func(a, b *FieldMetadata) int {
+			return strings.Compare(optionSortKey(a), optionSortKey(b))
+		})
+	case HelpSortGrouped:
+		groups = hg.metadata.XorGroups()
+	}
+	return options, groups
+}
+
+// optionSortKey returns the name an option is sorted by alphabetically.
+func optionSortKey(field *FieldMetadata) string {
+	if field.Long != "" {
+		return strings.ToLower(field.Long)
+	}
+	return strings.ToLower(field.Short)
+}
+
+// commandNames returns the subcommand names in the order help lists them:
+// by name for HelpSortAlphabetical, otherwise in declaration order.
+func (hg *HelpGenerator) commandNames() []string {
+	names := make([]string, 0, len(hg.metadata.Subcommands))
+	for name := range hg.metadata.Subcommands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	if hg.config.HelpSort != HelpSortAlphabetical {
+		idx := hg.metadata.SubcommandFieldIdx
+		slices.SortStableFunc(names, func(a, b string) int { return idx[a] - idx[b] })
+	}
+	return names
+}
+
+// writeRow writes one entry of a two-column listing: label padded to the
+// name column, which is width unless Config.HelpColumns sets it, followed
+// by help and suffix. Without help, the suffix follows the label directly.
+func (hg *HelpGenerator) writeRow(w io.Writer, label, help, suffix string, width int) {
+	if help == "" {
+		fmt.Fprintln(w, label+suffix)
+		return
+	}
+	cols := hg.config.HelpColumns
+	if cols.Name > 0 {
+		width = cols.Name
+	}
+	text := help + suffix
+	if cols.Wrap > 0 {
+		text = wrapText(text, cols.Wrap-max(width, len(label))-1, width+1)
+	}
+	fmt.Fprintf(w, "%-*s %s\n", width, label, text)
+}
+
+// wrapText breaks text between words into lines of at most avail
+// columns, never fewer than 20, and indents every line after the first by
+// indent spaces. A word longer than a line is kept whole.
+func wrapText(text string, avail, indent int) string {
+	avail = max(avail, 20)
+	var b strings.Builder
+	n := 0
+	for i, word := range strings.Fields(text) {
+		switch {
+		case i == 0:
+		case n+1+len(word) > avail:
+			b.WriteString("\n" + strings.Repeat(" ", indent))
+			n = 0
+		default:
+			b.WriteByte(' ')
+			n++
+		}
+		b.WriteString(word)
+		n += len(word)
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package goarg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type layoutArgs struct {
+	Zeta    bool      `arg:"-z,--zeta" help:"last letter"`
+	JSON    bool      `arg:"--json" xor:"format" help:"print JSON"`
+	Alpha   string    `arg:"--alpha" help:"first letter, with a longer description that will need wrapping"`
+	YAML    bool      `arg:"--yaml" xor:"format" help:"print YAML"`
+	Serve   *struct{} `arg:"subcommand:serve" help:"run the server"`
+	Archive *struct{} `arg:"subcommand:archive" help:"archive data"`
+}
+
+func layoutHelp(t *testing.T, config Config) string {
+	t.Helper()
+	config.Program = "prog"
+	p, err := NewParser(config, &layoutArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	p.WriteHelp(&b)
+	return b.String()
+}
+
+// order returns the position of each needle in the listings of help s,
+// or -1.
+func order(s string, needles ...string) []int {
+	s = s[strings.Index(s, "Options:"):]
+	pos := make([]int, len(needles))
+	for i, n := range needles {
+		pos[i] = strings.Index(s, n)
+	}
+	return pos
+}
+
+func increasing(pos []int) bool {
+	for i := range pos {
+		if pos[i] < 0 || i > 0 && pos[i] <= pos[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHelpSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		sort  HelpSort
+		order []string
+	}{
+		{"declaration", HelpSortDeclaration, []string{"--zeta", "--json", "--alpha", "--yaml", "--help", "serve", "archive"}},
+		{"alphabetical", HelpSortAlphabetical, []string{"--alpha", "--json", "--yaml", "--zeta", "--help", "archive", "serve"}},
+		{"grouped", HelpSortGrouped, []string{"--zeta", "--alpha", "--help", "format options (mutually exclusive):", "--json", "--yaml", "serve", "archive"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			help := layoutHelp(t, Config{HelpSort: tt.sort})
+			if pos := order(help, tt.order...); !increasing(pos) {
+				t.Errorf("help lists %q at %v, want increasing:\n%s", tt.order, pos, help)
+			}
+		})
+	}
+}
+
+func TestHelpColumns(t *testing.T) {
+	help := layoutHelp(t, Config{})
+	if !strings.Contains(help, "  -z, --zeta                   last letter\n") {
+		t.Errorf("default layout changed:\n%s", help)
+	}
+
+	help = layoutHelp(t, Config{HelpColumns: HelpColumns{Name: 16, Wrap: 60}})
+	for _, want := range []string{
+		"  -z, --zeta     last letter\n",
+		"  serve          run the server\n",
+		"      --alpha ALPHA first letter, with a longer description\n" +
+			"                 that will need wrapping\n",
+	} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help missing %q:\n%s", want, help)
+		}
+	}
+	for _, line := range strings.Split(help, "\n") {
+		if len(line) > 60 {
+			t.Errorf("line %q longer than 60 columns", line)
+		}
+	}
+}
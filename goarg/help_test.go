@@ -87,6 +87,61 @@ func TestUsageGeneration(t *testing.T) {
 	}
 }
 
+// TestMaxHelpLines verifies that long help is condensed to option names
+// and that --help-all restores the complete listing.
+func TestMaxHelpLines(t *testing.T) {
+	type args struct {
+		Verbose bool   `arg:"-v,--verbose" help:"enable verbose output"`
+		Output  string `arg:"-o,--output" help:"write results to FILE"`
+		Level   int    `arg:"--level" help:"compression level"`
+		Source  string `arg:"positional" help:"source file"`
+	}
+
+	tests := []struct {
+		name      string
+		max       int
+		condensed bool
+	}{
+		{"unlimited", 0, false},
+		{"fits", 20, false},
+		{"too long", 5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(Config{Program: "app", MaxHelpLines: tt.max}, &args{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			p.WriteHelp(&buf)
+			help := buf.String()
+			if got := strings.Contains(help, "Run with --help-all"); got != tt.condensed {
+				t.Errorf("condensed = %t, want %t:\n%s", got, tt.condensed, help)
+			}
+			if got := strings.Contains(help, "compression level"); got == tt.condensed {
+				t.Errorf("option help shown = %t, want %t:\n%s", got, !tt.condensed, help)
+			}
+			if tt.condensed && !strings.Contains(help, "  --verbose, --output OUTPUT, --level LEVEL, --help\n") {
+				t.Errorf("condensed option list missing:\n%s", help)
+			}
+			if !strings.HasPrefix(help, "Usage: app [OPTIONS] [SOURCE]\n") {
+				t.Errorf("help does not start with the synopsis:\n%s", help)
+			}
+		})
+	}
+
+	var out bytes.Buffer
+	var code int
+	p, err := NewParser(Config{Program: "app", MaxHelpLines: 5, Out: &out, Exit: func(c int) { code = c }}, &args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.MustParse([]string{"--help-all"})
+	if code != 0 || !strings.Contains(out.String(), "compression level") || !strings.Contains(out.String(), "--help-all") {
+		t.Errorf("--help-all: exit %d, output:\n%s", code, out.String())
+	}
+}
+
 func TestHelpWithSubcommands(t *testing.T) {
 	type ServerCmd struct {
 		Port int    `arg:"-p,--port" default:"8080" help:"server port"`
@@ -0,0 +1,118 @@
+package goarg
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ParseStats records where time was spent constructing and running a
+// Parser. It is intended for profiling startup of programs whose
+// destination structs carry thousands of fields.
+type ParseStats struct {
+	Metadata  time.Duration // struct tag reflection in NewParser (zero on a Precompile cache hit)
+	Build     time.Duration // flag building and subcommand registration
+	Iterate   time.Duration // option iteration, including subcommand dispatch
+	PostParse time.Duration // positionals, env vars, defaults, and validation
+	Options   int           // number of option fields in the top-level struct
+	Cached    bool          // metadata came from the Precompile cache
+}
+
+// Stats returns timing statistics for NewParser and the most recent Parse.
+func (p *Parser) Stats() ParseStats {
+	return p.stats
+}
+
+// metadataCache holds StructMetadata precompiled by Precompile, keyed by
+// the destination struct type and Config.MaxSubcommandDepth. Metadata
+// depends only on those, and is read-only once built, so it is safe to
+// share between parsers.
+var metadataCache sync.Map // metadataKey → *StructMetadata
+
+type metadataKey struct {
+	t     reflect.Type
+	depth int
+}
+
+// Precompile parses the struct tags of each destination type and caches
+// the resulting metadata, so later NewParser calls for the same types skip
+// reflection. Call it once during program initialization for large,
+// code-generated destinations.
+//
+// The cache lives in process memory. To skip tag parsing at startup as
+// well, write the metadata to disk once with WriteMetadata and load it
+// with LoadMetadata, which fills the same cache.
+func Precompile(dests ...any) error {
+	for _, dest := range dests {
+		if _, _, err := loadMetadata(dest, 0, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadMetadata returns metadata for dest, allowing subcommand recursion
+// up to depth, consulting the Precompile cache first. When store is true a
+// cache miss populates the cache.
+func loadMetadata(dest any, depth int, store bool) (*StructMetadata, bool, error) {
+	key := metadataKey{reflect.TypeOf(dest), depth}
+	if cached, ok := metadataCache.Load(key); ok {
+		return cached.(*StructMetadata), true, nil //nolint:errcheck // only *StructMetadata is stored
+	}
+	metadata, err := (&TagParser{MaxDepth: depth}).ParseStruct(dest)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse struct: %w", err)
+	}
+	if store {
+		metadataCache.Store(key, metadata)
+	}
+	return metadata, false, nil
+}
+
+// FlagSource tells where the value of a field came from; see
+// Config.OnFlagParsed.
+type FlagSource int
+
+const (
+	// SourceCommandLine is an option or positional argument, or a key
+	// given to Parser.ParseValues.
+	SourceCommandLine FlagSource = iota
+	// SourceEnv is the field's environment variable.
+	SourceEnv
+	// SourceDefault is the field's `default` tag.
+	SourceDefault
+)
+
+func (s FlagSource) String() string {
+	switch s {
+	case SourceEnv:
+		return "env"
+	case SourceDefault:
+		return "default"
+	}
+	return "command-line"
+}
+
+// fieldParsed records that field was set from the command line, invokes
+// Config.OnFieldParsed and Config.OnFlagParsed, and then the field's
+// `onset` method.
+func (fb *FlagBuilder) fieldParsed(destValue reflect.Value, field *FieldMetadata, value string) error {
+	fb.setFields[field.Name] = true
+	if fb.config.OnFieldParsed != nil {
+		reported := value
+		if field.Secret {
+			reported = ""
+		}
+		fb.config.OnFieldParsed(field, reported)
+	}
+	fb.config.flagParsed(field, SourceCommandLine)
+	return callOnSet(destValue, field, value)
+}
+
+// flagParsed invokes Config.OnFlagParsed for field.
+func (c *Config) flagParsed(field *FieldMetadata, source FlagSource) {
+	if c.OnFlagParsed != nil {
+		c.OnFlagParsed(diffFlag(field), source)
+	}
+}
@@ -0,0 +1,125 @@
+package goarg
+
+import (
+	"reflect"
+	"testing"
+)
+
+type instrumentArgs struct {
+	Name    string `arg:"-n,--name"`
+	Verbose bool   `arg:"-v,--verbose"`
+	Color   bool   `arg:"--color" prefix:"with,without"`
+	Count   int    `arg:"--count" default:"3"`
+}
+
+func TestOnFieldParsed(t *testing.T) {
+	var got []string
+	var a instrumentArgs
+	p, err := NewParser(Config{
+		OnFieldParsed: func(field *FieldMetadata, value string) {
+			got = append(got, field.Name+"="+value)
+		},
+	}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse([]string{"-v", "--name", "x", "--without-color"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Verbose=true", "Name=x", "Color=false"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OnFieldParsed calls = %q, want %q", got, want)
+	}
+}
+
+func TestOnFlagParsed(t *testing.T) {
+	t.Setenv("INSTRUMENT_REGION", "eu")
+	var a struct {
+		Name   string `arg:"-n,--name"`
+		Quiet  bool   `arg:"-q"`
+		Token  string `arg:"--token" secret:""`
+		Region string `arg:"env:INSTRUMENT_REGION"`
+		Count  int    `arg:"--count" default:"3"`
+		Dir    string `arg:"--dir" default:"${field:Name}/x"`
+		File   string `arg:"positional"`
+	}
+	var got []string
+	var values []string
+	p, err := NewParser(Config{
+		OnFlagParsed: func(name string, source FlagSource) {
+			got = append(got, name+" "+source.String())
+		},
+		OnFieldParsed: func(field *FieldMetadata, value string) {
+			values = append(values, field.Name+"="+value)
+		},
+	}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse([]string{"--token", "hunter2", "-q", "-n", "x", "in.txt"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"--token command-line", "-q command-line", "--name command-line",
+		"FILE command-line", "$INSTRUMENT_REGION env", "--count default", "--dir default",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OnFlagParsed calls = %q, want %q", got, want)
+	}
+	if want := []string{"Token=", "Quiet=true", "Name=x"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("OnFieldParsed calls = %q, want %q", values, want)
+	}
+	if a.Token != "hunter2" {
+		t.Errorf("Token = %q, want the parsed value", a.Token)
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	var a instrumentArgs
+	p, err := NewParser(Config{}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse([]string{"--name", "x"}); err != nil {
+		t.Fatal(err)
+	}
+	s := p.Stats()
+	if s.Options != 4 || s.Cached {
+		t.Errorf("stats = %+v", s)
+	}
+	if s.Metadata <= 0 {
+		t.Errorf("expected non-zero metadata duration: %+v", s)
+	}
+}
+
+func TestPrecompile(t *testing.T) {
+	type precompiledArgs struct {
+		Level int `arg:"-l,--level"`
+	}
+	t.Cleanup(func() { metadataCache.Delete(metadataKey{t: reflect.TypeFor[*precompiledArgs]()}) })
+
+	if err := Precompile(&precompiledArgs{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var a precompiledArgs
+	p, err := NewParser(Config{}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Stats().Cached || p.Stats().Metadata != 0 {
+		t.Errorf("expected cache hit, stats = %+v", p.Stats())
+	}
+	if err := p.Parse([]string{"-l", "4"}); err != nil {
+		t.Fatal(err)
+	}
+	if a.Level != 4 {
+		t.Errorf("Level = %d, want 4", a.Level)
+	}
+}
+
+func TestPrecompileInvalid(t *testing.T) {
+	if err := Precompile(42); err == nil {
+		t.Error("expected error for non-pointer destination")
+	}
+}
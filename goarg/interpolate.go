@@ -0,0 +1,123 @@
+package goarg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// fieldRefPrefix marks a ${...} reference to another field's value.
+const fieldRefPrefix = "field:"
+
+// errDefaultCycle reports ${field:...} references that form a loop.
+var errDefaultCycle = errors.New("cyclic ${field:...} reference")
+
+// isInterpolated reports whether a default tag contains ${...} references.
+// Such defaults are expanded when applied, so they are neither converted
+// nor validated against the field type at tag-parse time.
+func isInterpolated(defaultTag string) bool {
+	return strings.Contains(defaultTag, "${")
+}
+
+// expandDefault expands ${VAR} from the environment and ${field:Name}
+// through lookupField. An unset variable expands to "". "$${" yields a
+// literal "${"; any other '$' is kept as is.
+func expandDefault(s string, lookupField func(name string) (string, error)) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i])
+			b.WriteString("{")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", s[i:])
+		}
+		b.WriteString(s[:i])
+		key := s[i+2 : i+end]
+		if name, ok := strings.CutPrefix(key, fieldRefPrefix); ok {
+			value, err := lookupField(name)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+		} else {
+			b.WriteString(os.Getenv(key))
+		}
+		s = s[i+end+1:]
+	}
+}
+
+// defaultInterpolator applies interpolated defaults, resolving each
+// ${field:Name} reference before the field that uses it.
+type defaultInterpolator struct {
+	pp        *PostProcessor
+	destValue reflect.Value
+	state     map[string]int // field name → 1 while resolving, 2 when done
+}
+
+// apply sets field from its interpolated default tag unless the field was
+// already given a value.
+func (di *defaultInterpolator) apply(field *FieldMetadata) error {
+	switch di.state[field.Name] {
+	case 1:
+		return fmt.Errorf("default for field %s: %w", field.Name, errDefaultCycle)
+	case 2:
+		return nil
+	}
+	di.state[field.Name] = 1
+	defer func() { di.state[field.Name] = 2 }()
+
+	fieldValue := fieldByMeta(di.destValue, field)
+	if !fieldValue.IsValid() || !fieldValue.CanSet() ||
+		di.pp.setFields[field.Name] || !isZeroValue(fieldValue) {
+		return nil
+	}
+
+	value, err := expandDefault(field.DefaultTag, di.lookup)
+	if err != nil {
+		if errors.Is(err, errDefaultCycle) {
+			return err
+		}
+		return fmt.Errorf("default for field %s: %w", field.Name, err)
+	}
+	if err := setDefault(fieldValue, field, value); err != nil {
+		return err
+	}
+	di.pp.config.flagParsed(field, SourceDefault)
+	return callOnSet(di.destValue, field, value)
+}
+
+// lookup returns the string form of the named field's value, applying its
+// own interpolated default first.
+func (di *defaultInterpolator) lookup(name string) (string, error) {
+	for i := range di.pp.metadata.Fields {
+		field := &di.pp.metadata.Fields[i]
+		if field.Name != name {
+			continue
+		}
+		if field.HasDefault && isInterpolated(field.DefaultTag) {
+			if err := di.apply(field); err != nil {
+				return "", err
+			}
+		}
+		v := fieldByMeta(di.destValue, field)
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return "", nil
+			}
+			v = v.Elem()
+		}
+		return fmt.Sprint(v.Interface()), nil
+	}
+	return "", fmt.Errorf("unknown field %s in ${%s%s}", name, fieldRefPrefix, name)
+}
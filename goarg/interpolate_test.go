@@ -0,0 +1,147 @@
+package goarg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandDefault(t *testing.T) {
+	t.Setenv("GOARG_TEST_HOME", "/home/u")
+	fields := map[string]string{"Dir": "/etc/app"}
+	lookup := func(name string) (string, error) { return fields[name], nil }
+
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"${GOARG_TEST_HOME}/.config", "/home/u/.config", false},
+		{"${GOARG_TEST_UNSET}/x", "/x", false},
+		{"${field:Dir}/app.yaml", "/etc/app/app.yaml", false},
+		{"$${GOARG_TEST_HOME}", "${GOARG_TEST_HOME}", false},
+		{"cost: $5 ${field:Dir}", "cost: $5 /etc/app", false},
+		{"${GOARG_TEST_HOME", "", true},
+	}
+	for _, tt := range tests {
+		got, err := expandDefault(tt.in, lookup)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("expandDefault(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("expandDefault(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestInterpolatedDefaults(t *testing.T) {
+	t.Setenv("GOARG_TEST_HOME", "/home/u")
+	t.Setenv("GOARG_TEST_PORT", "9000")
+	type Args struct {
+		Config string `arg:"--config" default:"${field:Dir}/app.yaml"`
+		Dir    string `arg:"--dir" default:"${GOARG_TEST_HOME}/.config/app"`
+		Port   int    `arg:"--port" default:"${GOARG_TEST_PORT}"`
+		Cache  string `arg:"--cache" env:"GOARG_TEST_CACHE" default:"${field:Dir}/cache"`
+		Count  *int   `arg:"--count"`
+		Label  string `arg:"--label" default:"n=${field:Count}"`
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		want Args
+	}{
+		{"defaults", nil, "", Args{
+			Config: "/home/u/.config/app/app.yaml", Dir: "/home/u/.config/app", Port: 9000,
+			Cache: "/home/u/.config/app/cache", Label: "n=",
+		}},
+		{"reference sees command line", []string{"--dir", "/srv"}, "", Args{
+			Config: "/srv/app.yaml", Dir: "/srv", Port: 9000, Cache: "/srv/cache", Label: "n=",
+		}},
+		{"command line wins", []string{"--config", "x.yaml", "--port", "1"}, "", Args{
+			Config: "x.yaml", Dir: "/home/u/.config/app", Port: 1, Cache: "/home/u/.config/app/cache", Label: "n=",
+		}},
+		{"env wins", nil, "/tmp/c", Args{
+			Config: "/home/u/.config/app/app.yaml", Dir: "/home/u/.config/app", Port: 9000,
+			Cache: "/tmp/c", Label: "n=",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOARG_TEST_CACHE", tt.env)
+			var got Args
+			p, err := NewParser(Config{}, &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Parse(append([]string{}, tt.args...)); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("pointer reference", func(t *testing.T) {
+		var got Args
+		if err := ParseArgs(&got, []string{"--count", "3"}); err != nil {
+			t.Fatal(err)
+		}
+		if got.Label != "n=3" {
+			t.Errorf("Label = %q, want n=3", got.Label)
+		}
+	})
+
+	t.Run("help shows raw tag", func(t *testing.T) {
+		var a Args
+		p, err := NewParser(Config{Program: "app"}, &a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		p.WriteHelp(&b)
+		if !strings.Contains(b.String(), "(default: ${field:Dir}/app.yaml)") {
+			t.Errorf("help does not show the raw default:\n%s", b.String())
+		}
+	})
+}
+
+func TestInterpolatedDefaultErrors(t *testing.T) {
+	t.Run("unknown field", func(t *testing.T) {
+		var a struct {
+			Out string `arg:"--out" default:"${field:Missing}"`
+		}
+		err := ParseArgs(&a, []string{})
+		if err == nil || !strings.Contains(err.Error(), "unknown field Missing") {
+			t.Errorf("err = %v, want unknown field", err)
+		}
+	})
+	t.Run("cycle", func(t *testing.T) {
+		var a struct {
+			A string `arg:"--a" default:"${field:B}"`
+			B string `arg:"--b" default:"x${field:A}"`
+		}
+		err := ParseArgs(&a, []string{})
+		if err == nil || !strings.Contains(err.Error(), "cyclic") {
+			t.Errorf("err = %v, want cyclic reference", err)
+		}
+	})
+	t.Run("conversion", func(t *testing.T) {
+		t.Setenv("GOARG_TEST_PORT", "http")
+		var a struct {
+			Port int `arg:"--port" default:"${GOARG_TEST_PORT}"`
+		}
+		if err := ParseArgs(&a, []string{}); err == nil {
+			t.Error("expected conversion error")
+		}
+	})
+	t.Run("unterminated", func(t *testing.T) {
+		var a struct {
+			Dir string `arg:"--dir" default:"${HOME"`
+		}
+		if _, err := NewParser(Config{}, &a); err == nil || !strings.Contains(err.Error(), "unterminated") {
+			t.Errorf("NewParser err = %v, want unterminated", err)
+		}
+	})
+}
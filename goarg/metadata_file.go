@@ -0,0 +1,338 @@
+package goarg
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/major0/optargs"
+)
+
+// metadataFileVersion identifies the layout written by WriteMetadata.
+// LoadMetadata rejects any other version.
+const metadataFileVersion = 1
+
+// metadataFile is the serialized form of precompiled metadata: one entry
+// per destination type.
+type metadataFile struct {
+	Version int             `json:"version"`
+	Types   []metadataEntry `json:"types"`
+}
+
+// metadataEntry holds the metadata of one destination type. Fingerprint
+// identifies the field names, types, and tags it was built from, so a file
+// written for an older build of the type is detected.
+type metadataEntry struct {
+	Type        string       `json:"type"`
+	Fingerprint string       `json:"fingerprint"`
+	Metadata    structRecord `json:"metadata"`
+}
+
+// structRecord is the serialized form of StructMetadata.
+type structRecord struct {
+	Fields             []fieldRecord            `json:"fields,omitempty"`
+	Options            []fieldRecord            `json:"options,omitempty"`
+	Positionals        []fieldRecord            `json:"positionals,omitempty"`
+	EnvOnly            []fieldRecord            `json:"envOnly,omitempty"`
+	Subcommands        map[string]*structRecord `json:"subcommands,omitempty"`
+	SubcommandHelp     map[string]string        `json:"subcommandHelp,omitempty"`
+	SubcommandVersion  map[string]string        `json:"subcommandVersion,omitempty"`
+	SubcommandFields   map[string]string        `json:"subcommandFields,omitempty"`
+	SubcommandFieldIdx map[string]int           `json:"subcommandFieldIdx,omitempty"`
+}
+
+// fieldRecord is the serialized form of FieldMetadata. The field type,
+// the decoded default, and the core flag are rebuilt from the struct type
+// on load.
+type fieldRecord struct {
+	Name           string               `json:"name"`
+	FieldIndex     int                  `json:"index"`
+	Tag            string               `json:"tag,omitempty"`
+	Short          string               `json:"short,omitempty"`
+	Long           string               `json:"long,omitempty"`
+	Help           string               `json:"help,omitempty"`
+	Required       bool                 `json:"required,omitempty"`
+	Positional     bool                 `json:"positional,omitempty"`
+	Env            string               `json:"env,omitempty"`
+	DefaultTag     string               `json:"default,omitempty"`
+	HasDefault     bool                 `json:"hasDefault,omitempty"`
+	IsSubcommand   bool                 `json:"subcommand,omitempty"`
+	SubcommandName string               `json:"subcommandName,omitempty"`
+	Prefixes       []optargs.PrefixPair `json:"prefixes,omitempty"`
+	Negatable      bool                 `json:"negatable,omitempty"`
+	Secret         bool                 `json:"secret,omitempty"`
+	Stdin          bool                 `json:"stdin,omitempty"`
+	Group          string               `json:"group,omitempty"`
+	OnSet          string               `json:"onset,omitempty"`
+	ArgType        optargs.ArgType      `json:"argType"`
+}
+
+// WriteMetadata precompiles each destination, as Precompile does, and
+// writes the resulting metadata to w as JSON. A program that generates the
+// file at build time, or on first run, can pass it to LoadMetadata at
+// startup to skip struct tag parsing.
+func WriteMetadata(w io.Writer, dests ...any) error {
+	file := metadataFile{Version: metadataFileVersion}
+	for _, dest := range dests {
+		metadata, _, err := loadMetadata(dest, 0, true)
+		if err != nil {
+			return err
+		}
+		t := reflect.TypeOf(dest).Elem()
+		file.Types = append(file.Types, metadataEntry{
+			Type:        metadataTypeName(t),
+			Fingerprint: typeFingerprint(t),
+			Metadata:    *newStructRecord(metadata),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(file)
+}
+
+// LoadMetadata reads metadata written by WriteMetadata and places the
+// entry for each destination's type in the Precompile cache, so later
+// NewParser calls for those types skip struct tag parsing.
+//
+// It fails, caching nothing, when the file is malformed, has no entry for
+// a destination, or has an entry built from a different version of the
+// type (a changed field name, type, or tag). Such a file should be
+// rewritten with WriteMetadata; NewParser works without it either way.
+func LoadMetadata(r io.Reader, dests ...any) error {
+	var file metadataFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return fmt.Errorf("reading metadata: %w", err)
+	}
+	if file.Version != metadataFileVersion {
+		return fmt.Errorf("reading metadata: unsupported version %d", file.Version)
+	}
+	entries := make(map[string]*metadataEntry, len(file.Types))
+	for i := range file.Types {
+		entries[file.Types[i].Type] = &file.Types[i]
+	}
+
+	loaded := make(map[metadataKey]*StructMetadata, len(dests))
+	for _, dest := range dests {
+		pt := reflect.TypeOf(dest)
+		if pt == nil || pt.Kind() != reflect.Ptr || pt.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("destination must be a pointer to a struct, got %T", dest)
+		}
+		t := pt.Elem()
+		entry := entries[metadataTypeName(t)]
+		if entry == nil {
+			return fmt.Errorf("no metadata for %s", t)
+		}
+		if entry.Fingerprint != typeFingerprint(t) {
+			return fmt.Errorf("stale metadata for %s: the type has changed since it was written", t)
+		}
+		metadata, err := entry.Metadata.build(t)
+		if err != nil {
+			return fmt.Errorf("metadata for %s: %w", t, err)
+		}
+		loaded[metadataKey{t: pt}] = metadata
+	}
+	for key, metadata := range loaded {
+		metadataCache.Store(key, metadata)
+	}
+	return nil
+}
+
+// metadataTypeName returns the name a metadata file knows the struct type
+// t by.
+func metadataTypeName(t reflect.Type) string {
+	if t.Name() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// typeFingerprint hashes the names, types, and tags of the fields of t and
+// of every struct reachable from them, which together determine t's
+// metadata.
+func typeFingerprint(t reflect.Type) string {
+	h := fnv.New64a()
+	seen := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+		fmt.Fprintf(h, "%s{", t)
+		for i := range t.NumField() {
+			f := t.Field(i)
+			fmt.Fprintf(h, "%s %s %q;", f.Name, f.Type, f.Tag)
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				walk(ft)
+			}
+		}
+		fmt.Fprint(h, "}")
+	}
+	walk(t)
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// newStructRecord returns the serialized form of metadata.
+func newStructRecord(metadata *StructMetadata) *structRecord {
+	rec := &structRecord{
+		Fields:             newFieldRecords(metadata.Fields),
+		Options:            newFieldRecords(metadata.Options),
+		Positionals:        newFieldRecords(metadata.Positionals),
+		EnvOnly:            newFieldRecords(metadata.EnvOnly),
+		SubcommandHelp:     metadata.SubcommandHelp,
+		SubcommandVersion:  metadata.SubcommandVersion,
+		SubcommandFields:   metadata.SubcommandFields,
+		SubcommandFieldIdx: metadata.SubcommandFieldIdx,
+	}
+	if len(metadata.Subcommands) > 0 {
+		rec.Subcommands = make(map[string]*structRecord, len(metadata.Subcommands))
+		for name, sub := range metadata.Subcommands {
+			rec.Subcommands[name] = newStructRecord(sub)
+		}
+	}
+	return rec
+}
+
+func newFieldRecords(fields []FieldMetadata) []fieldRecord {
+	recs := make([]fieldRecord, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		recs[i] = fieldRecord{
+			Name:           f.Name,
+			FieldIndex:     f.FieldIndex,
+			Tag:            f.Tag,
+			Short:          f.Short,
+			Long:           f.Long,
+			Help:           f.Help,
+			Required:       f.Required,
+			Positional:     f.Positional,
+			Env:            f.Env,
+			DefaultTag:     f.DefaultTag,
+			HasDefault:     f.HasDefault,
+			IsSubcommand:   f.IsSubcommand,
+			SubcommandName: f.SubcommandName,
+			Prefixes:       f.Prefixes,
+			Negatable:      f.Negatable,
+			Secret:         f.Secret,
+			Stdin:          f.Stdin,
+			Group:          f.Group,
+			OnSet:          f.OnSet,
+			ArgType:        f.ArgType,
+		}
+	}
+	return recs
+}
+
+// build rebuilds the StructMetadata of the struct type t from rec.
+func (rec *structRecord) build(t reflect.Type) (*StructMetadata, error) {
+	metadata := &StructMetadata{
+		Subcommands:        make(map[string]*StructMetadata, len(rec.Subcommands)),
+		SubcommandHelp:     nonNilMap(rec.SubcommandHelp),
+		SubcommandVersion:  nonNilMap(rec.SubcommandVersion),
+		SubcommandFields:   nonNilMap(rec.SubcommandFields),
+		SubcommandFieldIdx: nonNilMap(rec.SubcommandFieldIdx),
+	}
+	var err error
+	for _, list := range []struct {
+		dst *[]FieldMetadata
+		src []fieldRecord
+	}{
+		{&metadata.Fields, rec.Fields},
+		{&metadata.Options, rec.Options},
+		{&metadata.Positionals, rec.Positionals},
+		{&metadata.EnvOnly, rec.EnvOnly},
+	} {
+		if *list.dst, err = buildFields(t, list.src); err != nil {
+			return nil, err
+		}
+	}
+	if len(metadata.EnvOnly) == 0 {
+		metadata.EnvOnly = nil // as ParseStruct leaves it
+	}
+	for name, sub := range rec.Subcommands {
+		field, ok := t.FieldByName(rec.SubcommandFields[name])
+		if !ok || field.Type.Kind() != reflect.Ptr {
+			return nil, fmt.Errorf("subcommand %s: no pointer field %q", name, rec.SubcommandFields[name])
+		}
+		if metadata.Subcommands[name], err = sub.build(field.Type.Elem()); err != nil {
+			return nil, fmt.Errorf("subcommand %s: %w", name, err)
+		}
+	}
+	return metadata, nil
+}
+
+// buildFields rebuilds the FieldMetadata of recs, fields of the struct
+// type t, restoring what the serialized form leaves out.
+func buildFields(t reflect.Type, recs []fieldRecord) ([]FieldMetadata, error) {
+	fields := make([]FieldMetadata, len(recs))
+	for i, rec := range recs {
+		var sf reflect.StructField
+		var ok bool
+		if rec.FieldIndex >= 0 && rec.FieldIndex < t.NumField() {
+			sf, ok = t.Field(rec.FieldIndex), true
+		} else if rec.FieldIndex < 0 {
+			sf, ok = t.FieldByName(rec.Name)
+		}
+		if !ok || sf.Name != rec.Name {
+			return nil, fmt.Errorf("no field %s", rec.Name)
+		}
+		field := FieldMetadata{
+			Name:           rec.Name,
+			FieldIndex:     rec.FieldIndex,
+			Type:           sf.Type,
+			Tag:            rec.Tag,
+			Short:          rec.Short,
+			Long:           rec.Long,
+			Help:           rec.Help,
+			Required:       rec.Required,
+			Positional:     rec.Positional,
+			Env:            rec.Env,
+			DefaultTag:     rec.DefaultTag,
+			HasDefault:     rec.HasDefault,
+			IsSubcommand:   rec.IsSubcommand,
+			SubcommandName: rec.SubcommandName,
+			Prefixes:       rec.Prefixes,
+			Negatable:      rec.Negatable,
+			Secret:         rec.Secret,
+			Stdin:          rec.Stdin,
+			Group:          rec.Group,
+			OnSet:          rec.OnSet,
+			ArgType:        rec.ArgType,
+		}
+		if field.HasDefault {
+			if isInterpolated(field.DefaultTag) || field.Type.Kind() == reflect.Interface {
+				field.Default = field.DefaultTag
+			} else {
+				v, err := (&TagParser{}).parseDefaultValue(field.DefaultTag, field.Type)
+				if err != nil {
+					return nil, fmt.Errorf("invalid default value for field %s: %w", field.Name, err)
+				}
+				field.Default = v
+			}
+		}
+		if name := field.Long; name != "" || field.Short != "" {
+			if name == "" {
+				name = field.Short
+			}
+			field.CoreFlag = &optargs.Flag{Name: name, HasArg: field.ArgType}
+		}
+		fields[i] = field
+	}
+	return fields, nil
+}
+
+// nonNilMap returns m, or an empty map when m is nil, matching the maps
+// ParseStruct allocates.
+func nonNilMap[V any](m map[string]V) map[string]V {
+	if m == nil {
+		return map[string]V{}
+	}
+	return m
+}
@@ -0,0 +1,111 @@
+package goarg
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type metadataFileServer struct {
+	Port int    `arg:"-p,--port" default:"8080"`
+	Host string `arg:"--host" default:"${HOST:-localhost}"`
+}
+
+func (*metadataFileServer) Version() string { return "server 2.0" }
+
+type metadataFileCommon struct {
+	Verbose bool `arg:"-v,--verbose" prefix:"enable,disable"`
+}
+
+type metadataFileArgs struct {
+	metadataFileCommon
+	Tags   []string            `arg:"--tag" default:"a,b"`
+	Token  string              `arg:"--token" secret:"" stdin:""`
+	Home   string              `arg:"env:HOME_DIR"`
+	Input  string              `arg:"positional,required" help:"input file"`
+	Server *metadataFileServer `arg:"subcommand:serve" help:"run the server"`
+}
+
+// forgetMetadata drops the Precompile cache entry for the type of dest.
+func forgetMetadata(t *testing.T, dest any) {
+	t.Helper()
+	key := metadataKey{t: reflect.TypeOf(dest)}
+	metadataCache.Delete(key)
+	t.Cleanup(func() { metadataCache.Delete(key) })
+}
+
+func TestMetadataFileRoundTrip(t *testing.T) {
+	forgetMetadata(t, &metadataFileArgs{})
+	var b bytes.Buffer
+	if err := WriteMetadata(&b, &metadataFileArgs{}); err != nil {
+		t.Fatal(err)
+	}
+	want, err := (&TagParser{}).ParseStruct(&metadataFileArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadataCache.Delete(metadataKey{t: reflect.TypeFor[*metadataFileArgs]()})
+	if err := LoadMetadata(&b, &metadataFileArgs{}); err != nil {
+		t.Fatal(err)
+	}
+	got, cached, err := loadMetadata(&metadataFileArgs{}, 0, false)
+	if err != nil || !cached {
+		t.Fatalf("loadMetadata: cached %t, err %v", cached, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded metadata differs from ParseStruct:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	var a metadataFileArgs
+	p, err := NewParser(Config{}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Stats().Cached {
+		t.Errorf("NewParser did not use the loaded metadata: %+v", p.Stats())
+	}
+	if err := p.Parse([]string{"--enable-verbose", "in.txt", "serve", "-p", "9"}); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Verbose || a.Input != "in.txt" || a.Server == nil || a.Server.Port != 9 || len(a.Tags) != 2 {
+		t.Errorf("parsed %+v", a)
+	}
+}
+
+func TestMetadataFileErrors(t *testing.T) {
+	forgetMetadata(t, &metadataFileArgs{})
+	forgetMetadata(t, &metadataFileServer{})
+	var b bytes.Buffer
+	if err := WriteMetadata(&b, &metadataFileArgs{}); err != nil {
+		t.Fatal(err)
+	}
+	file := b.String()
+	metadataCache.Delete(metadataKey{t: reflect.TypeFor[*metadataFileArgs]()})
+
+	fingerprint := typeFingerprint(reflect.TypeFor[metadataFileArgs]())
+	tests := []struct {
+		name string
+		file string
+		dest any
+		want string
+	}{
+		{"malformed", "{", &metadataFileArgs{}, "reading metadata"},
+		{"version", `{"version": 99}`, &metadataFileArgs{}, "unsupported version"},
+		{"missing type", file, &metadataFileServer{}, "no metadata"},
+		{"stale", strings.Replace(file, fingerprint, "0", 1), &metadataFileArgs{}, "stale metadata"},
+		{"not a struct", file, 42, "pointer to a struct"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LoadMetadata(strings.NewReader(tt.file), tt.dest)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadMetadata() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if _, cached, _ := loadMetadata(&metadataFileArgs{}, 0, false); cached {
+		t.Error("a failed LoadMetadata cached metadata")
+	}
+}
@@ -0,0 +1,38 @@
+package goarg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// errorType is the reflect.Type of the error interface.
+var errorType = reflect.TypeFor[error]()
+
+// validateOnSet checks that structPtr (a pointer-to-struct type) has a
+// method named by an `onset` tag with the signature func(string) error.
+func validateOnSet(structPtr reflect.Type, field *FieldMetadata) error {
+	m, ok := structPtr.MethodByName(field.OnSet)
+	if !ok {
+		return fmt.Errorf("onset method %s not found on %s", field.OnSet, structPtr)
+	}
+	// m.Type includes the receiver as its first input.
+	if m.Type.NumIn() != 2 || m.Type.In(1).Kind() != reflect.String ||
+		m.Type.NumOut() != 1 || m.Type.Out(0) != errorType {
+		return fmt.Errorf("onset method %s must have signature func(string) error, got %s", field.OnSet, m.Type)
+	}
+	return nil
+}
+
+// callOnSet invokes the field's `onset` method, if any, on the struct
+// holding the field. value is the raw string the field was set from: the
+// option argument, positional, environment variable, or default tag.
+func callOnSet(destValue reflect.Value, field *FieldMetadata, value string) error {
+	if field.OnSet == "" {
+		return nil
+	}
+	out := destValue.Addr().MethodByName(field.OnSet).Call([]reflect.Value{reflect.ValueOf(value)})
+	if err, _ := out[0].Interface().(error); err != nil {
+		return fmt.Errorf("%s: %w", field.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package goarg
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type onsetArgs struct {
+	Verbose bool   `arg:"-v" onset:"SetVerbose"`
+	Level   string `arg:"--level" default:"info" onset:"SetLevel"`
+	Token   string `arg:"--token" env:"ONSET_TOKEN" onset:"Record"`
+	Input   string `arg:"positional" onset:"Record"`
+
+	calls []string
+}
+
+func (a *onsetArgs) SetVerbose(v string) error {
+	// The field is already set when the hook runs.
+	a.calls = append(a.calls, "verbose="+v)
+	if a.Verbose {
+		a.Level = "debug"
+	}
+	return nil
+}
+
+func (a *onsetArgs) SetLevel(v string) error {
+	if v == "bogus" {
+		return errors.New("unknown level")
+	}
+	a.calls = append(a.calls, "level="+v)
+	return nil
+}
+
+func (a *onsetArgs) Record(v string) error {
+	a.calls = append(a.calls, "record="+v)
+	return nil
+}
+
+func TestOnSetSources(t *testing.T) {
+	t.Setenv("ONSET_TOKEN", "t0k")
+	var a onsetArgs
+	p, err := NewParser(Config{}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse([]string{"-v", "in"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"verbose=true", "record=in", "record=t0k"}
+	if !reflect.DeepEqual(a.calls, want) {
+		t.Errorf("calls = %q, want %q", a.calls, want)
+	}
+	// The default is skipped because the verbose hook already set Level.
+	if a.Level != "debug" {
+		t.Errorf("Level = %q, want debug", a.Level)
+	}
+}
+
+func TestOnSetDefault(t *testing.T) {
+	var a onsetArgs
+	p, err := NewParser(Config{IgnoreEnv: true}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"level=info"}; !reflect.DeepEqual(a.calls, want) {
+		t.Errorf("calls = %q, want %q", a.calls, want)
+	}
+}
+
+func TestOnSetError(t *testing.T) {
+	var a onsetArgs
+	p, err := NewParser(Config{}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Parse([]string{"--level", "bogus"})
+	if err == nil || !strings.Contains(err.Error(), "unknown level") {
+		t.Errorf("Parse() error = %v, want onset error", err)
+	}
+}
+
+type onsetMissing struct {
+	Name string `onset:"Nope"`
+}
+
+type onsetBadSig struct {
+	Name string `onset:"Hook"`
+}
+
+func (*onsetBadSig) Hook() {}
+
+func TestOnSetValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		dest any
+		want string
+	}{
+		{"missing method", &onsetMissing{}, "onset method Nope not found"},
+		{"bad signature", &onsetBadSig{}, "must have signature func(string) error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser(Config{}, tt.dest)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewParser() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
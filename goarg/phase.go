@@ -0,0 +1,35 @@
+package goarg
+
+// Phase is a set of the stages of Parser.Parse, combined with |, for
+// Config.Phases.
+type Phase uint
+
+const (
+	// PhaseOptions parses command-line options, including the builtin
+	// help and version flags. Without it Parse treats every argument as
+	// a positional.
+	PhaseOptions Phase = 1 << iota
+	// PhasePositionals assigns the remaining arguments to positional
+	// fields and reports missing required positionals.
+	PhasePositionals
+	// PhaseSubcommands dispatches to subcommands and clears the fields
+	// of those not invoked. Subcommand names are found while options
+	// are parsed, so it has no effect without PhaseOptions.
+	PhaseSubcommands
+	// PhaseEnv applies environment variable fallbacks, unless
+	// Config.IgnoreEnv is set.
+	PhaseEnv
+	// PhaseDefaults applies `default` tags, unless Config.IgnoreDefault
+	// is set.
+	PhaseDefaults
+	// PhaseValidation checks `xor` groups and `required` fields.
+	PhaseValidation
+
+	// PhaseAll runs every phase; it is what a zero Config.Phases means.
+	PhaseAll = PhaseOptions | PhasePositionals | PhaseSubcommands | PhaseEnv | PhaseDefaults | PhaseValidation
+)
+
+// runs reports whether the phases selected by c include ph.
+func (c *Config) runs(ph Phase) bool {
+	return c.Phases == 0 || c.Phases&ph != 0
+}
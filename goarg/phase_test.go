@@ -0,0 +1,104 @@
+package goarg
+
+import "testing"
+
+type phaseRun struct {
+	Force bool `arg:"-f"`
+}
+
+type phaseArgs struct {
+	Verbose bool      `arg:"-v"`
+	Region  string    `arg:"--region,env:PHASE_REGION"`
+	Count   int       `arg:"--count" default:"3"`
+	Name    string    `arg:"--name,required"`
+	File    string    `arg:"positional"`
+	Run     *phaseRun `arg:"subcommand:run"`
+}
+
+func TestPhases(t *testing.T) {
+	t.Setenv("PHASE_REGION", "eu")
+	tests := []struct {
+		name    string
+		phases  Phase
+		args    []string
+		preset  phaseArgs
+		want    phaseArgs
+		wantErr bool
+	}{
+		{
+			name: "all", args: []string{"-v", "--name", "n", "in.txt"},
+			want: phaseArgs{Verbose: true, Region: "eu", Count: 3, Name: "n", File: "in.txt"},
+		},
+		{
+			name: "no positionals", phases: PhaseAll &^ PhasePositionals,
+			args: []string{"-v", "--name", "n", "in.txt"}, preset: phaseArgs{File: "gui.txt"},
+			want: phaseArgs{Verbose: true, Region: "eu", Count: 3, Name: "n", File: "gui.txt"},
+		},
+		{
+			name: "options only", phases: PhaseOptions,
+			args: []string{"-v", "in.txt"},
+			want: phaseArgs{Verbose: true},
+		},
+		{
+			name: "no subcommands", phases: PhaseAll &^ PhaseSubcommands,
+			args: []string{"--name", "n", "run"}, preset: phaseArgs{Run: &phaseRun{Force: true}},
+			want: phaseArgs{Region: "eu", Count: 3, Name: "n", File: "run", Run: &phaseRun{Force: true}},
+		},
+		{
+			name: "positionals without options", phases: PhasePositionals,
+			args: []string{"-v"},
+			want: phaseArgs{File: "-v"},
+		},
+		{
+			name: "validation only", phases: PhaseValidation,
+			args: []string{"--name", "ignored"}, preset: phaseArgs{Name: "set"},
+			want: phaseArgs{Name: "set"},
+		},
+		{
+			name: "validation fails", phases: PhaseValidation,
+			args: []string{"--name", "ignored"}, wantErr: true,
+		},
+		{
+			name: "help skipped", phases: PhaseValidation,
+			args: []string{"--help"}, preset: phaseArgs{Name: "set"},
+			want: phaseArgs{Name: "set"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.preset
+			p, err := NewParser(Config{Phases: tt.phases}, &a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = p.Parse(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Parse() = nil, want a required error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Verbose != tt.want.Verbose || a.Region != tt.want.Region || a.Count != tt.want.Count ||
+				a.Name != tt.want.Name || a.File != tt.want.File || (a.Run == nil) != (tt.want.Run == nil) {
+				t.Errorf("got %+v, want %+v", a, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhasesParseValues(t *testing.T) {
+	var a phaseArgs
+	p, err := NewParser(Config{Phases: PhaseOptions}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.ParseValues(nil); err != nil {
+		t.Errorf("ParseValues() = %v, want no validation error", err)
+	}
+	if a.Count != 0 {
+		t.Errorf("Count = %d, want the default skipped", a.Count)
+	}
+}
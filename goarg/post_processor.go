@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 
 	"github.com/major0/optargs"
 )
@@ -14,7 +15,7 @@ import (
 type PostProcessor struct {
 	metadata    *StructMetadata
 	config      Config
-	setFields   map[int]bool // from FlagBuilder, read-only during post-processing
+	setFields   map[string]bool // from FlagBuilder, read-only during post-processing
 	positionals []PositionalArg
 }
 
@@ -38,26 +39,46 @@ func (pp *PostProcessor) buildPositionalArgs() {
 	}
 }
 
-// Process runs all post-parse steps in order:
+// Process runs the post-parse steps selected by Config.Phases in order:
 // 1. Assign positional arguments.
-// 2. Apply environment variable fallbacks.
-// 3. Apply default values.
-// 4. Validate required fields.
+// 2. Check mutual-exclusion groups against command-line input.
+// 3. Apply environment variable fallbacks.
+// 4. Apply default values.
+// 5. Validate required fields.
 func (pp *PostProcessor) Process(parser *optargs.Parser, destValue reflect.Value) error {
-	if err := pp.processPositionalArgs(parser, destValue); err != nil {
-		return err
+	if pp.config.runs(PhasePositionals) {
+		if err := pp.processPositionalArgs(parser, destValue); err != nil {
+			return err
+		}
+	}
+	return pp.finish(destValue)
+}
+
+// finish runs the post-parse steps that follow positional assignment.
+func (pp *PostProcessor) finish(destValue reflect.Value) error {
+	validate := pp.config.runs(PhaseValidation)
+	if validate {
+		if err := validateXorGroups(pp.setFields, pp.metadata); err != nil {
+			return err
+		}
 	}
-	if !pp.config.IgnoreEnv {
+	if !pp.config.IgnoreEnv && pp.config.runs(PhaseEnv) {
 		if err := pp.processEnvironmentVariables(destValue); err != nil {
 			return err
 		}
 	}
-	if !pp.config.IgnoreDefault {
+	if !pp.config.IgnoreDefault && pp.config.runs(PhaseDefaults) {
 		if err := pp.setDefaultValues(destValue); err != nil {
 			return err
 		}
 	}
-	return validateRequired(destValue.Addr().Interface(), pp.metadata)
+	if !validate {
+		return nil
+	}
+	if err := validateRequired(destValue.Addr().Interface(), pp.metadata); err != nil {
+		return err
+	}
+	return validateXorRequired(destValue, pp.setFields, pp.metadata)
 }
 
 // processPositionalArgs processes positional arguments from remaining args.
@@ -84,7 +105,11 @@ func (pp *PostProcessor) processPositionalArgs(parser *optargs.Parser, destValue
 			}
 			for argIndex < len(remainingArgs) {
 				if err := tv.Set(remainingArgs[argIndex]); err != nil {
-					return fmt.Errorf("failed to set positional argument %d: %w", argIndex, err)
+					return positionalError(argIndex, remainingArgs[argIndex], field, err)
+				}
+				pp.config.flagParsed(field, SourceCommandLine)
+				if err := callOnSet(destValue, field, remainingArgs[argIndex]); err != nil {
+					return err
 				}
 				argIndex++
 			}
@@ -97,7 +122,11 @@ func (pp *PostProcessor) processPositionalArgs(parser *optargs.Parser, destValue
 			}
 
 			if err := tv.Set(remainingArgs[argIndex]); err != nil {
-				return fmt.Errorf("failed to set positional argument %s: %w", field.Name, err)
+				return positionalError(argIndex, remainingArgs[argIndex], field, err)
+			}
+			pp.config.flagParsed(field, SourceCommandLine)
+			if err := callOnSet(destValue, field, remainingArgs[argIndex]); err != nil {
+				return err
 			}
 			argIndex++
 		}
@@ -106,6 +135,46 @@ func (pp *PostProcessor) processPositionalArgs(parser *optargs.Parser, destValue
 	return nil
 }
 
+// positionalError reports a positional value that failed to convert,
+// naming its 1-based position among the operands so the bad value can be
+// found in a long argument list:
+//
+//	argument 3 ("abc") for FILES: invalid integer
+func positionalError(index int, value string, field *FieldMetadata, err error) error {
+	return &ParseError{
+		Message: fmt.Sprintf("argument %d (%q) for %s: %s",
+			index+1, value, strings.ToUpper(field.Name), conversionReason(field.Type, err)),
+		err: err,
+	}
+}
+
+// conversionReason describes why a value failed to convert to t, or to the
+// element type when t is a slice. Types with their own parsing
+// (TextUnmarshaler, Duration) report the underlying error.
+func conversionReason(t reflect.Type, err error) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		if reflect.PointerTo(t).Implements(textUnmarshalerIface) {
+			break
+		}
+		t = t.Elem()
+	}
+	if t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerIface) {
+		return err.Error()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "invalid integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "invalid unsigned integer"
+	case reflect.Float32, reflect.Float64:
+		return "invalid number"
+	case reflect.Bool:
+		return "invalid boolean"
+	default:
+		return err.Error()
+	}
+}
+
 // processEnvironmentVariables processes environment variable fallbacks.
 func (pp *PostProcessor) processEnvironmentVariables(destValue reflect.Value) error {
 	for i := range pp.metadata.Fields {
@@ -140,18 +209,29 @@ func (pp *PostProcessor) processEnvironmentVariables(destValue reflect.Value) er
 		if err := tv.Set(envValue); err != nil {
 			return fmt.Errorf("failed to set environment variable %s for field %s: %w", field.Env, field.Name, err)
 		}
+		pp.config.flagParsed(field, SourceEnv)
+		if err := callOnSet(destValue, field, envValue); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 // setDefaultValues sets default values for unset fields via TypedValue.Set().
+// Plain defaults are applied first, then defaults with ${...} references,
+// so that a ${field:Name} reference sees Name's final value.
 func (pp *PostProcessor) setDefaultValues(destValue reflect.Value) error {
+	var interpolated []*FieldMetadata
 	for i := range pp.metadata.Fields {
 		field := &pp.metadata.Fields[i]
 		if !field.HasDefault {
 			continue
 		}
+		if isInterpolated(field.DefaultTag) {
+			interpolated = append(interpolated, field)
+			continue
+		}
 
 		fieldValue := fieldByMeta(destValue, field)
 		if !fieldValue.IsValid() || !fieldValue.CanSet() {
@@ -159,7 +239,7 @@ func (pp *PostProcessor) setDefaultValues(destValue reflect.Value) error {
 		}
 
 		// Skip fields explicitly set during parsing (including negatable zero-clear)
-		if pp.setFields[field.FieldIndex] {
+		if pp.setFields[field.Name] {
 			continue
 		}
 
@@ -167,18 +247,54 @@ func (pp *PostProcessor) setDefaultValues(destValue reflect.Value) error {
 			continue
 		}
 
-		tv, err := typedValueForField(fieldValue, field)
-		if err != nil {
-			return fmt.Errorf("default for field %s: %w", field.Name, err)
+		if err := setDefault(fieldValue, field, field.DefaultTag); err != nil {
+			return err
 		}
-		if err := tv.Set(field.DefaultTag); err != nil {
-			return fmt.Errorf("failed to set default value for field %s: %w", field.Name, err)
+		pp.config.flagParsed(field, SourceDefault)
+		if err := callOnSet(destValue, field, field.DefaultTag); err != nil {
+			return err
 		}
 	}
 
+	di := &defaultInterpolator{pp: pp, destValue: destValue, state: make(map[string]int)}
+	for _, field := range interpolated {
+		if err := di.apply(field); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// setDefault sets fieldValue, which has no value yet, from the default
+// tag value of field. A slice default is a list in the grammar of
+// splitTagList, so a quoted or escaped element keeps its commas, which
+// TypedValue.Set would split at.
+func setDefault(fieldValue reflect.Value, field *FieldMetadata, value string) error {
+	if isListDefault(field.Type) {
+		slice, err := convertTagList(value, field.Type)
+		if err != nil {
+			return fmt.Errorf("failed to set default value for field %s: %w", field.Name, err)
+		}
+		fieldValue.Set(reflect.ValueOf(slice))
+		return nil
+	}
+	tv, err := typedValueForField(fieldValue, field)
+	if err != nil {
+		return fmt.Errorf("default for field %s: %w", field.Name, err)
+	}
+	if err := tv.Set(value); err != nil {
+		return fmt.Errorf("failed to set default value for field %s: %w", field.Name, err)
+	}
+	return nil
+}
+
+// requiredError reports a required field that was not set.
+type requiredError struct {
+	Name string // option spelling ("--name", "-n") or field name
+}
+
+func (e *requiredError) Error() string { return e.Name + " is required" }
+
 // validateRequired validates that all required fields have been set.
 func validateRequired(dest any, metadata *StructMetadata) error {
 	destValue := reflect.ValueOf(dest)
@@ -193,7 +309,9 @@ func validateRequired(dest any, metadata *StructMetadata) error {
 
 	for i := range metadata.Fields {
 		field := &metadata.Fields[i]
-		if !field.Required {
+		// Required members of an xor group make the group required;
+		// validateXorRequired checks those.
+		if !field.Required || field.Group != "" {
 			continue
 		}
 
@@ -204,11 +322,11 @@ func validateRequired(dest any, metadata *StructMetadata) error {
 
 		if isZeroValue(fieldValue) {
 			if field.Long != "" {
-				return fmt.Errorf("--%s is required", field.Long)
+				return &requiredError{Name: "--" + field.Long}
 			} else if field.Short != "" {
-				return fmt.Errorf("-%s is required", field.Short)
+				return &requiredError{Name: "-" + field.Short}
 			}
-			return fmt.Errorf("%s is required", field.Name)
+			return &requiredError{Name: field.Name}
 		}
 	}
 
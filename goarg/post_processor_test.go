@@ -1,6 +1,7 @@
 package goarg
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -29,7 +30,7 @@ func TestPostProcessorDefaults(t *testing.T) {
 	pp := &PostProcessor{
 		metadata:  meta,
 		config:    Config{},
-		setFields: make(map[int]bool),
+		setFields: make(map[string]bool),
 	}
 	pp.buildPositionalArgs()
 
@@ -61,7 +62,7 @@ func TestPostProcessorSkipsSetFields(t *testing.T) {
 	}
 
 	// Mark the field as set
-	setFields := map[int]bool{0: true}
+	setFields := map[string]bool{"Port": true}
 	pp := &PostProcessor{
 		metadata:  meta,
 		config:    Config{},
@@ -101,7 +102,7 @@ func TestPostProcessorEnvFallback(t *testing.T) {
 	pp := &PostProcessor{
 		metadata:  meta,
 		config:    Config{},
-		setFields: make(map[int]bool),
+		setFields: make(map[string]bool),
 	}
 	pp.buildPositionalArgs()
 
@@ -113,3 +114,64 @@ func TestPostProcessorEnvFallback(t *testing.T) {
 		t.Errorf("Token = %q, want from-env", a.Token)
 	}
 }
+
+// TestPositionalConversionErrors verifies that a positional value that
+// fails to convert is reported with its 1-based operand position.
+func TestPositionalConversionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		dest any
+		args []string
+		want string
+	}{
+		{
+			name: "slice element",
+			dest: &struct {
+				Files []int `arg:"positional"`
+			}{},
+			args: []string{"1", "2", "abc"},
+			want: `argument 3 ("abc") for FILES: invalid integer`,
+		},
+		{
+			name: "after scalar",
+			dest: &struct {
+				Mode  string    `arg:"positional"`
+				Rates []float64 `arg:"positional"`
+			}{},
+			args: []string{"fast", "1.5", "x"},
+			want: `argument 3 ("x") for RATES: invalid number`,
+		},
+		{
+			name: "scalar",
+			dest: &struct {
+				Count uint `arg:"positional"`
+			}{},
+			args: []string{"many"},
+			want: `argument 1 ("many") for COUNT: invalid unsigned integer`,
+		},
+		{
+			name: "pointer",
+			dest: &struct {
+				Force *bool `arg:"positional"`
+			}{},
+			args: []string{"maybe"},
+			want: `argument 1 ("maybe") for FORCE: invalid boolean`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(Config{}, tt.dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = p.Parse(tt.args)
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("Parse(%q) error = %v, want %q", tt.args, err, tt.want)
+			}
+			var pe *ParseError
+			if !errors.As(err, &pe) || errors.Unwrap(pe) == nil {
+				t.Errorf("error %v is not a *ParseError wrapping the conversion error", err)
+			}
+		})
+	}
+}
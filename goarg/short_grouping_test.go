@@ -0,0 +1,98 @@
+package goarg
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestShortOptionGroupingMatrix publishes how grouped short flags and
+// attached values parse. Upstream go-arg treats every dash argument as a
+// whole option name split at the first '='; we follow getopt(3), which is
+// a superset: every spelling upstream accepts means the same thing here,
+// and spellings upstream rejects (clusters, attached values) follow GNU.
+func TestShortOptionGroupingMatrix(t *testing.T) {
+	type Args struct {
+		Verbose bool     `arg:"-v,--verbose"`
+		Quiet   bool     `arg:"-q"`
+		Out     string   `arg:"-o,--out"`
+		Count   int      `arg:"-c"`
+		Rest    []string `arg:"positional"`
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     Args
+		upstream string // upstream go-arg v1.6 behavior
+	}{
+		{"separate value", []string{"-o", "out.txt"}, Args{Out: "out.txt"}, "same"},
+		{"equals value", []string{"-o=out.txt"}, Args{Out: "out.txt"}, "same"},
+		{"equals int", []string{"-c=5"}, Args{Count: 5}, "same"},
+		{"empty equals value", []string{"-o="}, Args{Out: ""}, "same"},
+		{"attached value", []string{"-oout.txt"}, Args{Out: "out.txt"}, "unknown argument -oout.txt"},
+		{"attached int", []string{"-c5"}, Args{Count: 5}, "unknown argument -c5"},
+		{"attached double equals", []string{"-o==x"}, Args{Out: "=x"}, "same"},
+		{"cluster", []string{"-vq"}, Args{Verbose: true, Quiet: true}, "unknown argument -vq"},
+		{"cluster then separate value", []string{"-vvo", "out.txt"}, Args{Verbose: true, Out: "out.txt"}, "unknown argument -vvo"},
+		{"cluster then attached value", []string{"-vofile"}, Args{Verbose: true, Out: "file"}, "unknown argument -vofile"},
+		{"cluster then equals value", []string{"-vo=x"}, Args{Verbose: true, Out: "x"}, "unknown argument -vo"},
+		{"value ends cluster", []string{"-ovq"}, Args{Out: "vq"}, "unknown argument -ovq"},
+		{"value looks like option", []string{"-o", "-v"}, Args{Out: "-v"}, "missing value for -o"},
+		{"long value looks like option", []string{"--out", "-x"}, Args{Out: "-x"}, "missing value for --out"},
+		{"long equals dash value", []string{"--out=-x"}, Args{Out: "-x"}, "same"},
+		{"negative int", []string{"-c", "-3"}, Args{Count: -3}, "same"},
+		{"dash operand", []string{"-v", "-"}, Args{Verbose: true, Rest: []string{"-"}}, "same"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Args
+			p, err := NewParser(Config{}, &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Parse(tt.args); err != nil {
+				t.Fatalf("Parse(%q): %v (upstream: %s)", tt.args, err, tt.upstream)
+			}
+			if len(got.Rest) == 0 {
+				got.Rest = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v (upstream: %s)", tt.args, got, tt.want, tt.upstream)
+			}
+		})
+	}
+}
+
+// TestShortOptionGroupingErrors covers the matrix rows that fail in both
+// implementations; only the wording differs from upstream.
+func TestShortOptionGroupingErrors(t *testing.T) {
+	type Args struct {
+		Verbose bool   `arg:"-v"`
+		Out     string `arg:"-o"`
+		Count   int    `arg:"-c"`
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"missing value", []string{"-o"}},
+		{"cluster missing value", []string{"-vo"}},
+		{"unknown in cluster", []string{"-vx"}},
+		{"bad attached int", []string{"-cx"}},
+		{"bad equals int", []string{"-c=x"}},
+		{"equals on bool", []string{"-v=x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Args
+			p, err := NewParser(Config{}, &a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Parse(tt.args); err == nil {
+				t.Errorf("Parse(%q) succeeded, want error: %+v", tt.args, a)
+			}
+		})
+	}
+}
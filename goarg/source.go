@@ -0,0 +1,92 @@
+package goarg
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ParseFromMap populates dest from key/value pairs instead of command-line
+// arguments; see [Parser.ParseValues] for how keys are matched.
+func ParseFromMap(dest any, values map[string]string) error {
+	uv := make(url.Values, len(values))
+	for k, v := range values {
+		uv.Set(k, v)
+	}
+	return ParseFromValues(dest, uv)
+}
+
+// ParseFromValues populates dest from url.Values, such as a parsed query
+// string or form; see [Parser.ParseValues] for how keys are matched.
+func ParseFromValues(dest any, values url.Values) error {
+	parser, err := NewParser(Config{}, dest)
+	if err != nil {
+		return err
+	}
+	return parser.ParseValues(values)
+}
+
+// ParseValues populates the destination from values the way Parse does
+// from arguments: each value is converted and stored as if given on the
+// command line, then environment variables, defaults, mutual-exclusion
+// groups, and required fields are handled as usual, so HTTP handlers and
+// job payloads can fill the same structs as the CLI.
+//
+// An option's key is its long name, or its short name when it has no
+// long name; a positional field's key is its lowercased field name.
+// Every value of a key is applied in order, so slices collect them all
+// and scalars keep the last. An empty value sets a boolean to true.
+// Subcommands, help, and version are not reachable from values; an
+// unknown key is an error.
+func (p *Parser) ParseValues(values url.Values) error {
+	destValue := reflect.ValueOf(p.dest).Elem()
+	fb := &FlagBuilder{metadata: p.metadata, config: p.config, setFields: make(map[string]bool)}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		field := p.metadata.valueField(key)
+		if field == nil {
+			return &ParseError{Message: "unknown key: " + key, Flag: key}
+		}
+		set, err := fb.makeHandler(field, destValue)
+		if err != nil {
+			return err
+		}
+		for _, v := range values[key] {
+			if err := set(key, v); err != nil {
+				return &ParseError{
+					Message: fmt.Sprintf("invalid value %q for %s", v, key),
+					Flag:    key,
+					err:     err,
+				}
+			}
+		}
+	}
+
+	pp := &PostProcessor{metadata: p.metadata, config: p.config, setFields: fb.setFields}
+	return p.translateError(pp.finish(destValue), "")
+}
+
+// valueField returns the field a ParseValues key names, or nil.
+func (m *StructMetadata) valueField(key string) *FieldMetadata {
+	for i := range m.Options {
+		field := &m.Options[i]
+		if field.Long == key || field.Long == "" && field.Short == key {
+			return field
+		}
+	}
+	for i := range m.Positionals {
+		field := &m.Positionals[i]
+		if strings.ToLower(field.Name) == key {
+			return field
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package goarg
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type sourceArgs struct {
+	Host    string   `arg:"--host,required"`
+	Port    int      `arg:"-p,--port" default:"8080"`
+	Verbose bool     `arg:"-v"`
+	Tags    []string `arg:"--tag"`
+	Mode    string   `arg:"env:SOURCE_TEST_MODE"`
+	Files   []string `arg:"positional"`
+}
+
+func TestParseFromMap(t *testing.T) {
+	t.Setenv("SOURCE_TEST_MODE", "fast")
+	var a sourceArgs
+	err := ParseFromMap(&a, map[string]string{"host": "example.com", "v": "", "files": "a.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sourceArgs{Host: "example.com", Port: 8080, Verbose: true, Mode: "fast", Files: []string{"a.txt"}}
+	if !reflect.DeepEqual(a, want) {
+		t.Errorf("got %+v, want %+v", a, want)
+	}
+}
+
+func TestParseFromValues(t *testing.T) {
+	var a sourceArgs
+	err := ParseFromValues(&a, url.Values{
+		"host": {"a", "b"},
+		"port": {"9000"},
+		"v":    {"false"},
+		"tag":  {"x", "y"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Host != "b" || a.Port != 9000 || a.Verbose || !slices.Equal(a.Tags, []string{"x", "y"}) {
+		t.Errorf("got %+v", a)
+	}
+}
+
+func TestParseFromValuesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   url.Values
+		want     string
+		wantFlag string
+	}{
+		{"unknown key", url.Values{"host": {"h"}, "bogus": {"1"}}, "unknown key: bogus", "bogus"},
+		{"conversion", url.Values{"host": {"h"}, "port": {"abc"}}, `invalid value "abc" for port`, "port"},
+		{"required", url.Values{"port": {"1"}}, "required argument missing: host", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a sourceArgs
+			err := ParseFromValues(&a, tt.values)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want %q", err, tt.want)
+			}
+			var pe *ParseError
+			if tt.wantFlag != "" && (!errors.As(err, &pe) || pe.Flag != tt.wantFlag) {
+				t.Errorf("error %v: want *ParseError with Flag %q", err, tt.wantFlag)
+			}
+		})
+	}
+}
+
+// TestParseValuesOnSet verifies that values run the same hooks and
+// instrumentation as command-line options.
+func TestParseValuesOnSet(t *testing.T) {
+	var seen []string
+	var a sourceArgs
+	p, err := NewParser(Config{OnFieldParsed: func(field *FieldMetadata, value string) {
+		seen = append(seen, field.Name+"="+value)
+	}}, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.ParseValues(url.Values{"host": {"h"}, "tag": {"x"}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Host=h", "Tags=x"}; !slices.Equal(seen, want) {
+		t.Errorf("OnFieldParsed calls = %q, want %q", seen, want)
+	}
+}
@@ -0,0 +1,49 @@
+package goarg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinArg is the argument that makes a `stdin` field read its value from
+// standard input.
+const stdinArg = "-"
+
+// readStdin returns the value of a `stdin` field given as "-": everything
+// read from Config.Stdin, or os.Stdin when it is nil, with surrounding
+// whitespace trimmed. A terminal is refused rather than waited on, so a
+// user who forgot the pipe gets an error instead of a hung program.
+func (c *Config) readStdin(field *FieldMetadata) (string, error) {
+	in := c.Stdin
+	if in == nil {
+		in = os.Stdin
+	}
+	if f, ok := in.(interface{ Fd() uintptr }); ok && isTerminal(f.Fd()) {
+		return "", stdinError(field, errStdinTerminal)
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", stdinError(field, fmt.Errorf("reading standard input: %w", err))
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", stdinError(field, errEmptyStdin)
+	}
+	return value, nil
+}
+
+// stdinError reports err for the option of field given as "-". It is a
+// *ParseError so that the message reaches the user as written.
+func stdinError(field *FieldMetadata, err error) error {
+	name := diffFlag(field)
+	return &ParseError{Message: name + " " + stdinArg + ": " + err.Error(), Flag: name, err: err}
+}
+
+// Errors reading the value of a `stdin` field.
+var (
+	errEmptyStdin    = errors.New("no value on standard input")
+	errStdinTerminal = errors.New("standard input is a terminal, not a pipe or file")
+)
@@ -0,0 +1,88 @@
+package goarg
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+type stdinArgs struct {
+	Token string   `arg:"--token" stdin:"" secret:""`
+	Keys  []string `arg:"-k" stdin:""`
+	Name  string   `arg:"--name"`
+}
+
+func TestStdin(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		want    stdinArgs
+		wantErr string
+	}{
+		{name: "read", args: []string{"--token", "-"}, stdin: "  s3cret\n", want: stdinArgs{Token: "s3cret"}},
+		{name: "attached", args: []string{"--token=-", "--name", "-"}, stdin: "t\n", want: stdinArgs{Token: "t", Name: "-"}},
+		{name: "literal", args: []string{"--token", "abc"}, stdin: "unused", want: stdinArgs{Token: "abc"}},
+		{name: "slice", args: []string{"-k", "a", "-k", "-"}, stdin: "b\n", want: stdinArgs{Keys: []string{"a", "b"}}},
+		{name: "empty", args: []string{"--token", "-"}, stdin: " \n", wantErr: "--token -: no value on standard input"},
+		{name: "read once", args: []string{"--token", "-", "-k", "-"}, stdin: "x", wantErr: "-k -: no value on standard input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got stdinArgs
+			p, err := NewParser(Config{Stdin: strings.NewReader(tt.stdin)}, &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = p.Parse(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse(%q) error = %v, want %q", tt.args, err, tt.wantErr)
+				}
+				if !errors.Is(err, errEmptyStdin) {
+					t.Errorf("Parse(%q) error = %v, want errEmptyStdin", tt.args, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.args, err)
+			}
+			if got.Token != tt.want.Token || got.Name != tt.want.Name || strings.Join(got.Keys, ",") != strings.Join(tt.want.Keys, ",") {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdinDevice(t *testing.T) {
+	dev, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Skipf("no %s: %v", os.DevNull, err)
+	}
+	defer dev.Close()
+	var got stdinArgs
+	p, err := NewParser(Config{Stdin: dev}, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Parse([]string{"--token", "-"})
+	if err == nil || !errors.Is(err, errEmptyStdin) {
+		t.Errorf("Parse() error = %v, want a no-value error", err)
+	}
+}
+
+func TestStdinTagErrors(t *testing.T) {
+	for _, dest := range []any{
+		&struct {
+			Force bool `arg:"-f" stdin:""`
+		}{},
+		&struct {
+			File string `arg:"positional" stdin:""`
+		}{},
+	} {
+		if _, err := NewParser(Config{}, dest); err == nil || !strings.Contains(err.Error(), "stdin tag") {
+			t.Errorf("NewParser(%T) error = %v, want a stdin tag error", dest, err)
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package goarg
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
 	"strings"
+
+	"github.com/major0/optargs"
 )
 
 // Subcommand returns the active subcommand destination struct, or nil
@@ -28,56 +31,61 @@ func (p *Parser) FailSubcommand(msg string, subcommand ...string) error {
 		return nil
 	}
 
-	meta, err := p.lookupSubcommandMetadata(subcommand)
+	meta, config, err := p.lookupSubcommandMetadata(subcommand)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintln(p.output(), msg)
-	hg := NewHelpGenerator(meta, p.config)
-	hg.WriteUsage(p.output()) //nolint:errcheck,gosec // error handling not needed for usage output
+	hg := NewHelpGenerator(meta, config)
+	p.writeError(p.output(), errors.New(msg), func(w io.Writer) {
+		hg.WriteUsage(w) //nolint:errcheck,gosec // error handling not needed for usage output
+	})
 	p.config.Exit(1)
 	return nil
 }
 
 // WriteHelpForSubcommand writes help text for a specific subcommand path.
 func (p *Parser) WriteHelpForSubcommand(w io.Writer, subcommand ...string) error {
-	meta, err := p.lookupSubcommandMetadata(subcommand)
+	meta, config, err := p.lookupSubcommandMetadata(subcommand)
 	if err != nil {
 		return err
 	}
-	hg := NewHelpGenerator(meta, p.config)
+	hg := NewHelpGenerator(meta, config)
 	return hg.WriteHelp(w)
 }
 
 // WriteUsageForSubcommand writes usage text for a specific subcommand path.
 func (p *Parser) WriteUsageForSubcommand(w io.Writer, subcommand ...string) error {
-	meta, err := p.lookupSubcommandMetadata(subcommand)
+	meta, config, err := p.lookupSubcommandMetadata(subcommand)
 	if err != nil {
 		return err
 	}
-	hg := NewHelpGenerator(meta, p.config)
+	hg := NewHelpGenerator(meta, config)
 	return hg.WriteUsage(w)
 }
 
 // lookupSubcommandMetadata walks the metadata tree to find the metadata
-// for a subcommand path.
-func (p *Parser) lookupSubcommandMetadata(path []string) (*StructMetadata, error) {
-	meta := p.metadata
+// for a subcommand path, and returns it with the parser configuration
+// carrying the subcommand's version.
+func (p *Parser) lookupSubcommandMetadata(path []string) (*StructMetadata, Config, error) {
+	meta, config := p.metadata, p.config
 	for _, name := range path {
 		found := false
 		for cmdName, subMeta := range meta.Subcommands {
 			if strings.EqualFold(cmdName, name) {
+				if version, ok := meta.SubcommandVersion[cmdName]; ok {
+					config.Version = version
+				}
 				meta = subMeta
 				found = true
 				break
 			}
 		}
 		if !found {
-			return nil, fmt.Errorf("unknown subcommand: %s", name)
+			return nil, Config{}, fmt.Errorf("unknown subcommand: %s", name)
 		}
 	}
-	return meta, nil
+	return meta, config, nil
 }
 
 // recordSubcommandChain walks the core parser's ActiveCommand chain and
@@ -111,6 +119,44 @@ func (p *Parser) recordSubcommandChain(destValue reflect.Value, ci *CoreIntegrat
 	}
 }
 
+// requiredByCommand rewrites the error for a required option of the
+// command named name, parsed by level, when a subcommand below it was
+// invoked, so the message shows the full command path and the command
+// that declares the option:
+//
+//	prog db migrate: "--name" is required by "db"
+//
+// Other errors are returned unchanged.
+func (p *Parser) requiredByCommand(err error, level *optargs.Parser, name string) error {
+	var req *requiredError
+	if !errors.As(err, &req) {
+		return err
+	}
+	if sub, _ := level.ActiveCommand(); sub == "" {
+		return err
+	}
+	perr := &ParseError{Message: fmt.Sprintf("%s: %q is required by %q", p.commandPath(), req.Name, name), err: err}
+	if strings.HasPrefix(req.Name, "-") {
+		perr.Flag = req.Name
+	}
+	return perr
+}
+
+// commandPath returns the program name followed by the invoked
+// subcommand names.
+func (p *Parser) commandPath() string {
+	names := []string{NewHelpGenerator(p.metadata, p.config).programName()}
+	for cur := p.coreParser; cur != nil; {
+		name, child := cur.ActiveCommand()
+		if name == "" {
+			break
+		}
+		names = append(names, name)
+		cur = child
+	}
+	return strings.Join(names, " ")
+}
+
 // output returns the configured output writer, defaulting to os.Stderr.
 func (p *Parser) output() io.Writer {
 	if p.config.Out != nil {
@@ -59,11 +59,18 @@ func (ci *CoreIntegration) RegisterSubcommands(coreParser *optargs.Parser, destV
 			metadata: subMeta,
 			config:   ci.config,
 		}
+		if version, ok := ci.metadata.SubcommandVersion[name]; ok {
+			child.config.Version = version
+		}
 
 		childParser, err := child.CreateParserWithHandlers([]string{}, fieldValue)
 		if err != nil {
 			return fmt.Errorf("failed to create parser for subcommand %s: %w", name, err)
 		}
+		if ci.children == nil {
+			ci.children = make(map[*StructMetadata]*CoreIntegration)
+		}
+		ci.children[subMeta] = child
 
 		coreParser.AddCmd(name, childParser)
 
@@ -92,19 +99,42 @@ func (ci *CoreIntegration) dispatchSubcommand(childParser *optargs.Parser, invok
 	}
 
 	subDestValue := fieldValue.Elem()
-	childCI := &CoreIntegration{
-		metadata:  subMeta,
-		config:    ci.config,
-		setFields: make(map[int]bool),
+	childCI := ci.children[subMeta]
+	if childCI == nil {
+		childCI = &CoreIntegration{
+			metadata:  subMeta,
+			config:    ci.config,
+			setFields: make(map[string]bool),
+		}
 	}
 	if err := childCI.PostParse(childParser, subDestValue); err != nil {
-		return p.translateError(err, "")
+		return p.translateError(p.requiredByCommand(err, childParser, invokedName), "")
 	}
 
 	nestedName, nestedParser := childParser.ActiveCommand()
 	if nestedName != "" && nestedParser != nil {
-		return childCI.dispatchSubcommand(nestedParser, nestedName, subDestValue, p)
+		if err := childCI.dispatchSubcommand(nestedParser, nestedName, subDestValue, p); err != nil {
+			return err
+		}
 	}
-
+	childCI.clearSubcommands(subDestValue, nestedName)
 	return nil
 }
+
+// clearSubcommands nils out the subcommand fields of destValue other than
+// the invoked one, which RegisterSubcommands allocated, so callers can
+// detect which subcommand was selected.
+func (ci *CoreIntegration) clearSubcommands(destValue reflect.Value, invokedName string) {
+	for name := range ci.metadata.Subcommands {
+		if strings.EqualFold(name, invokedName) {
+			continue
+		}
+		fv, _, err := ci.findSubcommandField(destValue, name)
+		if err != nil {
+			continue
+		}
+		if fv.Kind() == reflect.Ptr {
+			fv.Set(reflect.Zero(fv.Type()))
+		}
+	}
+}
@@ -84,3 +84,67 @@ func TestRegisterSubcommandsDirect(t *testing.T) {
 		t.Error("serve subcommand should be registered")
 	}
 }
+
+type sharedConnFlags struct {
+	Host string `arg:"--host" default:"localhost" negatable:""`
+	Port int    `arg:"-p,--port" default:"22"`
+}
+
+type sharedPushCmd struct {
+	sharedConnFlags
+	Force bool `arg:"-f"`
+}
+
+type sharedPullCmd struct {
+	sharedConnFlags
+	All bool `arg:"-a"`
+}
+
+type sharedArgs struct {
+	sharedConnFlags
+	Push *sharedPushCmd `arg:"subcommand:push"`
+	Pull *sharedPullCmd `arg:"subcommand:pull"`
+}
+
+// TestSharedEmbeddedStruct verifies that one options struct embedded in
+// the root and in several subcommands is registered once per scope, and
+// that setting one of its fields leaves the defaults of the others to
+// apply in that scope. A field cleared with --no-host in a subcommand
+// keeps its zero value there.
+func TestSharedEmbeddedStruct(t *testing.T) {
+	tests := []struct {
+		args       []string
+		root, push sharedConnFlags
+		pull       *sharedConnFlags
+	}{
+		{
+			args: []string{"-p", "1", "push", "--no-host"},
+			root: sharedConnFlags{Host: "localhost", Port: 1}, push: sharedConnFlags{Port: 22},
+		},
+		{
+			args: []string{"--host", "r", "pull", "-p", "2", "-a"},
+			root: sharedConnFlags{Host: "r", Port: 22}, pull: &sharedConnFlags{Host: "localhost", Port: 2},
+		},
+	}
+	for _, tt := range tests {
+		var a sharedArgs
+		p, err := NewParser(Config{}, &a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.Parse(tt.args); err != nil {
+			t.Fatalf("Parse(%q): %v", tt.args, err)
+		}
+		if a.sharedConnFlags != tt.root {
+			t.Errorf("Parse(%q) root = %+v, want %+v", tt.args, a.sharedConnFlags, tt.root)
+		}
+		switch {
+		case tt.pull != nil:
+			if a.Pull == nil || a.Pull.sharedConnFlags != *tt.pull || !a.Pull.All {
+				t.Errorf("Parse(%q) pull = %+v, want %+v", tt.args, a.Pull, tt.pull)
+			}
+		case a.Push == nil || a.Push.sharedConnFlags != tt.push:
+			t.Errorf("Parse(%q) push = %+v, want %+v", tt.args, a.Push, tt.push)
+		}
+	}
+}
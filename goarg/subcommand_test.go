@@ -2,6 +2,7 @@ package goarg
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -179,3 +180,132 @@ func TestConfigOut(t *testing.T) {
 		t.Error("expected output to Config.Out, got nothing")
 	}
 }
+
+type versionDBCmd struct {
+	Name string `arg:"--name"`
+}
+
+func (versionDBCmd) Version() string { return "db 2.0" }
+
+type versionWebCmd struct {
+	Port int `arg:"--port"`
+}
+
+type versionRoot struct {
+	DB  *versionDBCmd  `arg:"subcommand:db"`
+	Web *versionWebCmd `arg:"subcommand:web"`
+}
+
+func (versionRoot) Version() string { return "app 1.0" }
+
+type versionlessRoot struct {
+	DB *versionDBCmd `arg:"subcommand:db"`
+}
+
+// TestSubcommandVersion verifies that --version prints a subcommand's own
+// version when it implements Versioned and the root's otherwise.
+func TestSubcommandVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		dest any
+		args []string
+		want string
+	}{
+		{"root", &versionRoot{}, []string{"--version"}, "app 1.0\n"},
+		{"own version", &versionRoot{}, []string{"db", "--version"}, "db 2.0\n"},
+		{"inherited", &versionRoot{}, []string{"web", "--version"}, "app 1.0\n"},
+		{"subcommand only", &versionlessRoot{}, []string{"db", "--version"}, "db 2.0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			code := -1
+			p, err := NewParser(Config{Out: &out, Exit: func(c int) { code = c }}, tt.dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.MustParse(tt.args)
+			if code != 0 || out.String() != tt.want {
+				t.Errorf("exit %d, output %q; want 0, %q", code, out.String(), tt.want)
+			}
+		})
+	}
+
+	p, err := NewParser(Config{}, &versionlessRoot{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse([]string{"--version"}); err == nil || !strings.Contains(err.Error(), "unrecognized argument: --version") {
+		t.Errorf("root --version without a root version: %v", err)
+	}
+}
+
+func TestSubcommandVersionDocs(t *testing.T) {
+	p, err := NewParser(Config{Program: "app"}, &versionRoot{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := p.HelpDoc()
+	got := map[string]string{doc.Name: doc.Version}
+	for _, cmd := range doc.Commands {
+		got[cmd.Name] = cmd.Version
+	}
+	want := map[string]string{"app": "app 1.0", "app db": "db 2.0", "app web": "app 1.0"}
+	for name, version := range want {
+		if got[name] != version {
+			t.Errorf("HelpDoc version of %q = %q, want %q", name, got[name], version)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteHelpForSubcommand(&buf, "db"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Version: db 2.0") {
+		t.Errorf("subcommand help missing its version:\n%s", buf.String())
+	}
+}
+
+type requiredMigrateCmd struct {
+	Steps int `arg:"--steps"`
+}
+
+type requiredDBCmd struct {
+	Name    string              `arg:"--name,required"`
+	Migrate *requiredMigrateCmd `arg:"subcommand:migrate"`
+}
+
+type requiredRoot struct {
+	Token string         `arg:"--token,required"`
+	DB    *requiredDBCmd `arg:"subcommand:db"`
+}
+
+func TestSubcommandRequiredParentOption(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantFlag string
+	}{
+		{"root option", []string{"db", "--name", "x"}, `app db: "--token" is required by "app"`, "--token"},
+		{"intermediate option", []string{"--token", "t", "db", "migrate"}, `app db migrate: "--name" is required by "db"`, "--name"},
+		{"no subcommand below", []string{"--token", "t", "db"}, "required argument missing: name", ""},
+		{"no subcommand", []string{}, "required argument missing: token", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(Config{Program: "app"}, &requiredRoot{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = p.Parse(tt.args)
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("Parse error = %v, want %q", err, tt.want)
+			}
+			var perr *ParseError
+			if tt.wantFlag != "" && (!errors.As(err, &perr) || perr.Flag != tt.wantFlag) {
+				t.Errorf("error %#v, want *ParseError with Flag %q", err, tt.wantFlag)
+			}
+		})
+	}
+}
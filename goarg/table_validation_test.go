@@ -9,6 +9,7 @@
 package goarg
 
 import (
+	"errors"
 	"os"
 	"testing"
 )
@@ -195,22 +196,23 @@ func TestTable_ErrHelpErrVersionSentinels(t *testing.T) {
 	}
 }
 
-func TestTable_BuiltinHelpVersionFlags(_ *testing.T) {
+func TestTable_BuiltinHelpVersionFlags(t *testing.T) {
 	type Args struct {
 		Name string `arg:"--name"`
 	}
 	var a Args
-	exitCalled := false
 	p, _ := NewParser(Config{
 		Program: "test",
 		Version: "1.0",
-		Exit:    func(int) { exitCalled = true },
+		Exit:    func(int) {},
 		Out:     os.Stderr,
 	}, &a)
-	_ = p.Parse([]string{"--version"})
-	// Our implementation may handle --version differently (sentinel vs exit).
-	// Either way, --version should be recognized.
-	_ = exitCalled
+	if err := p.Parse([]string{"--version"}); !errors.Is(err, ErrVersion) {
+		t.Errorf("--version: err = %v, want ErrVersion", err)
+	}
+	if err := p.Parse([]string{"-h"}); !errors.Is(err, ErrHelp) {
+		t.Errorf("-h: err = %v, want ErrHelp", err)
+	}
 }
 
 func TestTable_SubcommandQuery(t *testing.T) {
@@ -261,6 +263,41 @@ func TestTable_GNULongestMatch(t *testing.T) {
 	}
 }
 
+func TestTable_AbbreviationMatching(t *testing.T) {
+	type Args struct {
+		Verbose bool `arg:"--verbose"`
+		Version bool `arg:"--version-info"`
+	}
+	var a Args
+	p, _ := NewParser(Config{Program: "test"}, &a)
+	if err := p.Parse([]string{"--verb"}); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Verbose {
+		t.Error("--verb should set verbose")
+	}
+	p, _ = NewParser(Config{Program: "test"}, &a)
+	if err := p.Parse([]string{"--ver"}); err == nil {
+		t.Error("--ver should be ambiguous")
+	}
+}
+
+func TestTable_ArbitraryOptionNames(t *testing.T) {
+	type Args struct {
+		Key   string `arg:"--config[key]"`
+		Pair  bool   `arg:"--foo=bar"`
+		Scope bool   `arg:"--system7:verbose"`
+	}
+	var a Args
+	p, _ := NewParser(Config{Program: "test"}, &a)
+	if err := p.Parse([]string{"--config[key]=v", "--foo=bar", "--system7:verbose"}); err != nil {
+		t.Fatal(err)
+	}
+	if a.Key != "v" || !a.Pair || !a.Scope {
+		t.Errorf("got %+v", a)
+	}
+}
+
 func TestTable_BooleanNegation(t *testing.T) {
 	type Args struct {
 		Sysroot string `arg:"--sysroot" default:"/usr" negatable:""`
@@ -275,6 +312,26 @@ func TestTable_BooleanNegation(t *testing.T) {
 	}
 }
 
+func TestTable_BooleanPrefixPairs(t *testing.T) {
+	type Args struct {
+		Shared bool `arg:"--shared" prefix:"enable,disable" default:"true"`
+	}
+	var a Args
+	p, _ := NewParser(Config{Program: "test"}, &a)
+	if err := p.Parse([]string{"--disable-shared"}); err != nil {
+		t.Fatal(err)
+	}
+	if a.Shared {
+		t.Error("--disable-shared should clear shared")
+	}
+	if err := p.Parse([]string{"--enable-shared"}); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Shared {
+		t.Error("--enable-shared should set shared")
+	}
+}
+
 func TestTable_DoubleHyphenTermination(t *testing.T) {
 	type Args struct {
 		Name string   `arg:"--name"`
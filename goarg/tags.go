@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"maps"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/major0/optargs"
@@ -18,6 +19,7 @@ type StructMetadata struct {
 	EnvOnly            []FieldMetadata // env-only fields (no CLI flag)
 	Subcommands        map[string]*StructMetadata
 	SubcommandHelp     map[string]string // Maps subcommand name to help text
+	SubcommandVersion  map[string]string // Maps subcommand name to its Versioned version, if any
 	SubcommandFields   map[string]string // Maps subcommand name to struct field name
 	SubcommandFieldIdx map[string]int    // Maps subcommand name to struct field index
 }
@@ -46,13 +48,40 @@ type FieldMetadata struct {
 	Prefixes  []optargs.PrefixPair // boolean prefix pairs from `prefix` struct tag
 	Negatable bool                 // non-boolean field supports --no-<name>
 
+	// Secret marks a field, with the `secret` struct tag, whose value
+	// must not reach instrumentation: Config.OnFieldParsed receives an
+	// empty value for it.
+	Secret bool
+
+	// Stdin marks an option, with the `stdin` struct tag, whose value is
+	// read from standard input when it is given as "-", as in
+	// --token -; see Config.Stdin.
+	Stdin bool
+
+	// Group names a mutual-exclusion set from the `xor` struct tag. At most
+	// one field sharing a Group may be given; see StructMetadata.XorGroups.
+	Group string
+
+	// OnSet names a method on the struct holding the field, from the
+	// `onset` struct tag. It has the signature func(string) error and is
+	// called right after the field is set from the command line, a
+	// positional, an environment variable, or its default.
+	OnSet string
+
 	// Direct OptArgs Core mapping
 	CoreFlag *optargs.Flag
 	ArgType  optargs.ArgType
 }
 
 // TagParser processes struct tags - identical behavior to alexflint/go-arg.
-type TagParser struct{}
+type TagParser struct {
+	// MaxDepth is how many times a subcommand struct may recur beneath
+	// itself, directly or through other subcommands; see
+	// Config.MaxSubcommandDepth. Zero makes any such cycle an error.
+	MaxDepth int
+
+	path []reflect.Type // structs being parsed, outermost first
+}
 
 // ParseStruct parses a struct and returns its metadata.
 //
@@ -73,12 +102,16 @@ func (tp *TagParser) ParseStruct(dest any) (*StructMetadata, error) {
 	}
 
 	structType := destElem.Type()
+	tp.path = append(tp.path, structType)
+	defer func() { tp.path = tp.path[:len(tp.path)-1] }()
+
 	metadata := &StructMetadata{
 		Fields:             []FieldMetadata{},
 		Options:            []FieldMetadata{},
 		Positionals:        []FieldMetadata{},
 		Subcommands:        make(map[string]*StructMetadata),
 		SubcommandHelp:     make(map[string]string),
+		SubcommandVersion:  make(map[string]string),
 		SubcommandFields:   make(map[string]string),
 		SubcommandFieldIdx: make(map[string]int),
 	}
@@ -96,8 +129,9 @@ func (tp *TagParser) ParseStruct(dest any) (*StructMetadata, error) {
 
 		// Embedded (anonymous) struct: recurse into its fields.
 		if field.Anonymous && field.Type.Kind() == reflect.Struct {
-			embedded := destElem.Field(i).Addr().Interface()
-			subMeta, err := tp.ParseStruct(embedded)
+			// Parse a zero value: metadata depends only on the type,
+			// and the field itself may be unexported.
+			subMeta, err := tp.ParseStruct(reflect.New(field.Type).Interface())
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse embedded struct %s: %w", field.Name, err)
 			}
@@ -122,6 +156,7 @@ func (tp *TagParser) ParseStruct(dest any) (*StructMetadata, error) {
 			metadata.EnvOnly = append(metadata.EnvOnly, subMeta.EnvOnly...)
 			maps.Copy(metadata.Subcommands, subMeta.Subcommands)
 			maps.Copy(metadata.SubcommandHelp, subMeta.SubcommandHelp)
+			maps.Copy(metadata.SubcommandVersion, subMeta.SubcommandVersion)
 			maps.Copy(metadata.SubcommandFields, subMeta.SubcommandFields)
 			maps.Copy(metadata.SubcommandFieldIdx, subMeta.SubcommandFieldIdx)
 			continue
@@ -136,6 +171,11 @@ func (tp *TagParser) ParseStruct(dest any) (*StructMetadata, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse field %s: %w", field.Name, err)
 		}
+		if fieldMetadata.OnSet != "" {
+			if err := validateOnSet(destValue.Type(), fieldMetadata); err != nil {
+				return nil, fmt.Errorf("failed to parse field %s: %w", field.Name, err)
+			}
+		}
 
 		// Handle subcommands
 		if fieldMetadata.IsSubcommand { //nolint:nestif // subcommand registration requires conditional name derivation + recursive parse
@@ -144,6 +184,18 @@ func (tp *TagParser) ParseStruct(dest any) (*StructMetadata, error) {
 				subcommandName = strings.ToLower(field.Name)
 			}
 
+			// A subcommand struct already being parsed would recurse
+			// forever. Intentional recursion stops registering the
+			// subcommand once its struct has recurred MaxDepth times.
+			if n := tp.depth(field.Type); n > 0 {
+				if tp.MaxDepth == 0 {
+					return nil, tp.cycleError(subcommandName, field.Type)
+				}
+				if n > tp.MaxDepth {
+					continue
+				}
+			}
+
 			// Record the struct field name for O(1) lookup later.
 			metadata.SubcommandFields[subcommandName] = field.Name
 			metadata.SubcommandFieldIdx[subcommandName] = i
@@ -170,6 +222,9 @@ func (tp *TagParser) ParseStruct(dest any) (*StructMetadata, error) {
 
 				// Store the help text for this subcommand
 				metadata.SubcommandHelp[subcommandName] = fieldMetadata.Help
+				if v, ok := subInstance.(Versioned); ok && v.Version() != "" {
+					metadata.SubcommandVersion[subcommandName] = v.Version()
+				}
 
 				// If the field was originally nil, keep it nil (don't persist the temp instance)
 				// The subcommand will only be initialized when actually invoked
@@ -190,6 +245,34 @@ func (tp *TagParser) ParseStruct(dest any) (*StructMetadata, error) {
 	return metadata, nil
 }
 
+// depth returns how many times the struct a subcommand field of type t
+// points to is already being parsed.
+func (tp *TagParser) depth(t reflect.Type) int {
+	if t.Kind() != reflect.Ptr {
+		return 0
+	}
+	n := 0
+	for _, outer := range tp.path {
+		if outer == t.Elem() {
+			n++
+		}
+	}
+	return n
+}
+
+// cycleError reports subcommand name, of type t, as recursive, listing the
+// structs that lead back to it.
+func (tp *TagParser) cycleError(name string, t reflect.Type) error {
+	start := slices.Index(tp.path, t.Elem())
+	var chain []string
+	for _, outer := range tp.path[start:] {
+		chain = append(chain, outer.String())
+	}
+	chain = append(chain, t.Elem().String())
+	return fmt.Errorf("recursive subcommand %s: %s (set Config.MaxSubcommandDepth to allow it)",
+		name, strings.Join(chain, " → "))
+}
+
 // ParseField parses a single struct field and returns its metadata.
 func (tp *TagParser) ParseField(field reflect.StructField, fieldIndex int) (*FieldMetadata, error) {
 	metadata := &FieldMetadata{
@@ -199,6 +282,10 @@ func (tp *TagParser) ParseField(field reflect.StructField, fieldIndex int) (*Fie
 		Tag:        string(field.Tag),
 	}
 
+	// Parse the 'help' tag — before the arg tag, whose deprecated "help:"
+	// attribute takes precedence as it does upstream.
+	metadata.Help = field.Tag.Get("help")
+
 	// Parse the 'arg' tag
 	argTag := field.Tag.Get("arg")
 	if argTag != "" {
@@ -207,18 +294,27 @@ func (tp *TagParser) ParseField(field reflect.StructField, fieldIndex int) (*Fie
 		}
 	}
 
-	// Parse the 'help' tag
-	metadata.Help = field.Tag.Get("help")
-
 	// Parse the 'default' tag — use Lookup once to detect presence and value.
 	if defaultTag, exists := field.Tag.Lookup("default"); exists {
 		metadata.HasDefault = true
 		metadata.DefaultTag = defaultTag
-		defaultValue, err := tp.parseDefaultValue(defaultTag, field.Type)
-		if err != nil {
-			return nil, fmt.Errorf("invalid default value for field %s: %w", field.Name, err)
+		switch {
+		case isInterpolated(defaultTag):
+			// Expanded and converted when applied; help shows the raw tag.
+			if _, err := expandDefault(defaultTag, func(string) (string, error) { return "", nil }); err != nil {
+				return nil, fmt.Errorf("invalid default value for field %s: %w", field.Name, err)
+			}
+			metadata.Default = defaultTag
+		case field.Type.Kind() == reflect.Interface:
+			// Built by the registered factory when applied.
+			metadata.Default = defaultTag
+		default:
+			defaultValue, err := tp.parseDefaultValue(defaultTag, field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default value for field %s: %w", field.Name, err)
+			}
+			metadata.Default = defaultValue
 		}
-		metadata.Default = defaultValue
 	}
 
 	// Parse the 'env' tag — only if not already set from the arg tag
@@ -243,6 +339,31 @@ func (tp *TagParser) ParseField(field reflect.StructField, fieldIndex int) (*Fie
 		}
 	}
 
+	// Parse the 'xor' tag — mutual-exclusion group name
+	metadata.Group = strings.TrimSpace(field.Tag.Get("xor"))
+	if metadata.Group != "" && (metadata.Positional || metadata.IsSubcommand) {
+		return nil, fmt.Errorf("xor tag on non-option field %q", field.Name)
+	}
+
+	// Parse the 'onset' tag — method called after the field is set
+	metadata.OnSet = strings.TrimSpace(field.Tag.Get("onset"))
+	if metadata.OnSet != "" && metadata.IsSubcommand {
+		return nil, fmt.Errorf("onset tag on subcommand field %q", field.Name)
+	}
+
+	// Parse the 'secret' tag — presence only
+	if _, exists := field.Tag.Lookup("secret"); exists {
+		metadata.Secret = true
+	}
+
+	// Parse the 'stdin' tag — presence only, on options taking a value
+	if _, exists := field.Tag.Lookup("stdin"); exists {
+		if metadata.Positional || metadata.IsSubcommand || field.Type.Kind() == reflect.Bool {
+			return nil, fmt.Errorf("stdin tag on field %q, which takes no option value", field.Name)
+		}
+		metadata.Stdin = true
+	}
+
 	// Parse the 'negatable' tag — silently ignored on boolean fields
 	if _, exists := field.Tag.Lookup("negatable"); exists && field.Type.Kind() != reflect.Bool {
 		metadata.Negatable = true
@@ -272,16 +393,15 @@ func (tp *TagParser) parseArgTag(metadata *FieldMetadata, argTag string) error {
 	// 6. "subcommand:name" - subcommand
 	// 7. "subcommand" - subcommand with default name
 	// 8. "env:VAR_NAME" - environment variable (can be combined)
-
-	parts := strings.Split(argTag, ",")
-
+	// 9. "help:text" - help text (deprecated upstream; use the help tag)
+	//
+	// Attributes may appear in any order, separated by commas, whitespace,
+	// or both; see normalizeArgTag.
+	parts, err := normalizeArgTag(argTag)
+	if err != nil {
+		return err
+	}
 	for _, part := range parts {
-		part = strings.TrimSpace(part)
-
-		if part == "" {
-			continue
-		}
-
 		// Handle special keywords
 		switch {
 		case part == "positional":
@@ -300,12 +420,16 @@ func (tp *TagParser) parseArgTag(metadata *FieldMetadata, argTag string) error {
 		case part == "env":
 			// Bare "env" — auto-derive env var name from field name in SCREAMING_SNAKE_CASE.
 			metadata.Env = toScreamingSnake(metadata.Name)
+		case strings.HasPrefix(part, "help:"):
+			metadata.Help = strings.TrimPrefix(part, "help:")
 		case part == "separate":
 			// "separate" changes slice behavior from greedy multi-value to
 			// one-value-per-flag. Our POSIX-based parser already uses this
 			// semantics by default, so this is a no-op — accepted for
 			// upstream compatibility.
 			continue
+		case strings.HasPrefix(part, "---"):
+			return fmt.Errorf("invalid long option format: %s (too many hyphens)", part)
 		case strings.HasPrefix(part, "--"):
 			// Long option
 			metadata.Long = strings.TrimPrefix(part, "--")
@@ -324,11 +448,127 @@ func (tp *TagParser) parseArgTag(metadata *FieldMetadata, argTag string) error {
 	return nil
 }
 
+// normalizeArgTag splits an 'arg' tag into attributes. Upstream separates
+// attributes with commas only; users routinely also type spaces
+// ("-v, --verbose", "--name required"), so each comma-separated segment is
+// further split on whitespace. Whitespace around a key's colon is dropped,
+// so "env: NAME" and "env : NAME" both yield "env:NAME". A "help:" segment
+// is kept whole because its value is free text. Commas are split by
+// splitTagList, so a quoted or escaped comma stays in its segment.
+func normalizeArgTag(tag string) ([]string, error) {
+	segments, err := splitTagList(tag)
+	if err != nil {
+		return nil, err
+	}
+	var parts []string
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "help:") {
+			parts = append(parts, segment)
+			continue
+		}
+		start := len(parts)
+		for _, word := range strings.Fields(segment) {
+			// Join "key:" with its value, and ":value" with its key.
+			if n := len(parts); n > start && (strings.HasSuffix(parts[n-1], ":") && !strings.HasPrefix(word, "-") ||
+				strings.HasPrefix(word, ":")) {
+				parts[n-1] += word
+				continue
+			}
+			parts = append(parts, word)
+		}
+	}
+	return parts, nil
+}
+
+// splitTagList splits a comma-separated tag value, such as an 'arg' tag
+// or a slice default, into its trimmed elements. Two forms keep a comma,
+// or any other text, literal inside an element:
+//
+//   - a single-quoted span at the start of an element or right after a
+//     colon, as in 'a, b' or help:'one, two'; the quotes are removed
+//   - a backslash before a comma, a single quote, or a backslash, as in
+//     a\, b; other backslashes are kept as they are
+//
+// Because struct tag values are themselves Go string literals, the
+// backslash is written doubled in source: `default:"a\, b"`. A single
+// quote anywhere else, as in don't, is an ordinary character.
+func splitTagList(s string) ([]string, error) {
+	var parts []string
+	var b strings.Builder
+	keep := 0 // leading part of b that is exempt from trimming
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(`,'\\`, s[i+1]) >= 0:
+			i++
+			b.WriteByte(s[i])
+			keep = b.Len()
+		case c == '\'' && (quoted || b.Len() == 0 || strings.HasSuffix(b.String(), ":")):
+			quoted = !quoted
+			keep = b.Len()
+		case quoted:
+			b.WriteByte(c)
+			keep = b.Len()
+		case c == ',':
+			parts = append(parts, trimTagElement(b.String(), keep))
+			b.Reset()
+			keep = 0
+		case b.Len() == 0 && (c == ' ' || c == '\t'):
+			// leading whitespace
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	return append(parts, trimTagElement(b.String(), keep)), nil
+}
+
+// trimTagElement trims trailing whitespace from s, except within its
+// first keep bytes.
+func trimTagElement(s string, keep int) string {
+	return s[:keep] + strings.TrimRight(s[keep:], " \t")
+}
+
+// isListDefault reports whether a default for a field of type t is a
+// list: t is a slice that does not parse itself as text, as net.IP does.
+func isListDefault(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && !reflect.PointerTo(t).Implements(textUnmarshalerIface)
+}
+
+// convertTagList converts a list-valued tag, split by splitTagList, to a
+// slice of sliceType. Empty elements are skipped, as by
+// optargs.ConvertSlice.
+func convertTagList(value string, sliceType reflect.Type) (any, error) {
+	parts, err := splitTagList(value)
+	if err != nil {
+		return nil, err
+	}
+	slice := reflect.MakeSlice(sliceType, 0, len(parts))
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		converted, err := optargs.Convert(part, sliceType.Elem())
+		if err != nil {
+			return nil, err
+		}
+		slice = reflect.Append(slice, reflect.ValueOf(converted))
+	}
+	return slice.Interface(), nil
+}
+
 // parseDefaultValue parses a default value string into the appropriate type
-// using optargs.Convert and optargs.ConvertSlice.
+// using optargs.Convert; a slice default is a list in the grammar of
+// splitTagList.
 func (tp *TagParser) parseDefaultValue(defaultStr string, fieldType reflect.Type) (any, error) {
-	if fieldType.Kind() == reflect.Slice {
-		return optargs.ConvertSlice(defaultStr, fieldType)
+	if isListDefault(fieldType) {
+		return convertTagList(defaultStr, fieldType)
 	}
 	return optargs.Convert(defaultStr, fieldType)
 }
@@ -2,6 +2,7 @@ package goarg
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/major0/optargs"
@@ -845,6 +846,153 @@ func TestTagParser_ComplexTagFormats(t *testing.T) {
 	}
 }
 
+func TestNormalizeArgTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"", nil},
+		{"-v,--verbose", []string{"-v", "--verbose"}},
+		{"-v, --verbose", []string{"-v", "--verbose"}},
+		{"-v --verbose", []string{"-v", "--verbose"}},
+		{"  -v\t--verbose  ", []string{"-v", "--verbose"}},
+		{"--name, required", []string{"--name", "required"}},
+		{"required --name", []string{"required", "--name"}},
+		{"--name,,required,", []string{"--name", "required"}},
+		{"env:NAME", []string{"env:NAME"}},
+		{"env: NAME", []string{"env:NAME"}},
+		{"env :NAME", []string{"env:NAME"}},
+		{"env : NAME --name", []string{"env:NAME", "--name"}},
+		{"env: --name", []string{"env:", "--name"}},
+		{"--name, env:", []string{"--name", "env:"}},
+		{"subcommand: run", []string{"subcommand:run"}},
+		{"-n, help:the name, with a comma", []string{"-n", "help:the name", "with", "a", "comma"}},
+		{"--name, help:two  words ", []string{"--name", "help:two  words"}},
+		{"-n, help:'the name, with a comma'", []string{"-n", "help:the name, with a comma"}},
+		{`-n, help:the name\, with a comma`, []string{"-n", "help:the name, with a comma"}},
+		{"--name, help:don't panic, required", []string{"--name", "help:don't panic", "required"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got, err := normalizeArgTag(tt.tag); err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeArgTag(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitTagList(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"a, b ,c", []string{"a", "b", "c"}, false},
+		{"'a, b',c", []string{"a, b", "c"}, false},
+		{"' padded ', x", []string{" padded ", "x"}, false},
+		{`a\, b,c`, []string{"a, b", "c"}, false},
+		{`it\'s,'\'quoted\''`, []string{"it's", "'quoted'"}, false},
+		{`C:\dir,\\`, []string{`C:\dir`, `\`}, false},
+		{"help:'x, y', z", []string{"help:x, y", "z"}, false},
+		{"don't, won't", []string{"don't", "won't"}, false},
+		{"", []string{""}, false},
+		{"'open, b", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := splitTagList(tt.value)
+			if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitTagList(%q) = %q, %v; want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTagParser_QuotedTagValues(t *testing.T) {
+	var args struct {
+		Tags  []string `arg:"--tags" default:"'a, b',c"`
+		Names []string `arg:"--names" default:"x\\, y,z"`
+		Title string   `arg:"--title" default:"one, two"`
+		Name  string   `arg:"-n,help:'the name, as typed'"`
+	}
+	p, err := NewParser(Config{}, &args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse([]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a, b", "c"}; !reflect.DeepEqual(args.Tags, want) {
+		t.Errorf("Tags = %q, want %q", args.Tags, want)
+	}
+	if want := []string{"x, y", "z"}; !reflect.DeepEqual(args.Names, want) {
+		t.Errorf("Names = %q, want %q", args.Names, want)
+	}
+	if args.Title != "one, two" {
+		t.Errorf("Title = %q, want the default verbatim", args.Title)
+	}
+	var help strings.Builder
+	p.WriteHelp(&help)
+	if !strings.Contains(help.String(), "the name, as typed") {
+		t.Errorf("help lost the quoted comma:\n%s", help.String())
+	}
+
+	var bad struct {
+		Name string `arg:"-n,help:'unterminated"`
+	}
+	if _, err := NewParser(Config{}, &bad); err == nil || !strings.Contains(err.Error(), "unterminated quote") {
+		t.Errorf("unterminated quote: err = %v", err)
+	}
+}
+
+func TestTagParser_TagSyntaxVariants(t *testing.T) {
+	parser := &TagParser{}
+	want := FieldMetadata{Short: "n", Long: "name", Env: "APP_NAME", Required: true}
+
+	tags := []string{
+		`arg:"-n,--name,env:APP_NAME,required"`,
+		`arg:"-n, --name, env:APP_NAME, required"`,
+		`arg:"-n --name env:APP_NAME required"`,
+		`arg:"required, env: APP_NAME, --name, -n"`,
+		`arg:"--name -n,required env : APP_NAME"`,
+	}
+	for _, tag := range tags {
+		t.Run(tag, func(t *testing.T) {
+			field := reflect.StructField{Name: "Name", Type: reflect.TypeFor[string](), Tag: reflect.StructTag(tag)}
+			got, err := parser.ParseField(field, 0)
+			if err != nil {
+				t.Fatalf("ParseField() error: %v", err)
+			}
+			if got.Short != want.Short || got.Long != want.Long || got.Env != want.Env || got.Required != want.Required {
+				t.Errorf("got short=%q long=%q env=%q required=%v, want short=%q long=%q env=%q required=%v",
+					got.Short, got.Long, got.Env, got.Required, want.Short, want.Long, want.Env, want.Required)
+			}
+		})
+	}
+}
+
+func TestTagParser_DeprecatedHelpAttribute(t *testing.T) {
+	parser := &TagParser{}
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{`arg:"--name,help:the name"`, "the name"},
+		{`arg:"--name" help:"from help tag"`, "from help tag"},
+		{`arg:"--name,help:from arg" help:"from help tag"`, "from arg"},
+	}
+	for _, tt := range tests {
+		field := reflect.StructField{Name: "Name", Type: reflect.TypeFor[string](), Tag: reflect.StructTag(tt.tag)}
+		got, err := parser.ParseField(field, 0)
+		if err != nil {
+			t.Fatalf("ParseField(%s) error: %v", tt.tag, err)
+		}
+		if got.Help != tt.want {
+			t.Errorf("ParseField(%s).Help = %q, want %q", tt.tag, got.Help, tt.want)
+		}
+	}
+}
+
 func TestTagParser_ErrorCases(t *testing.T) {
 	parser := &TagParser{}
 
@@ -876,6 +1024,14 @@ func TestTagParser_ErrorCases(t *testing.T) {
 				Tag:  `arg:"subcommand:bad"`,
 			},
 		},
+		{
+			name: "too_many_hyphens",
+			field: reflect.StructField{
+				Name: "Test",
+				Type: reflect.TypeFor[string](),
+				Tag:  `arg:"---test"`,
+			},
+		},
 		{
 			name: "unknown_tag_format",
 			field: reflect.StructField{
@@ -933,3 +1089,96 @@ func TestTagParser_ParseStruct_ErrorCases(t *testing.T) {
 		})
 	}
 }
+
+type cycleSelf struct {
+	Again *cycleSelf `arg:"subcommand:again"`
+}
+
+type cycleA struct {
+	B *cycleB `arg:"subcommand:b"`
+}
+
+type cycleB struct {
+	Verbose bool    `arg:"-v"`
+	A       *cycleA `arg:"subcommand:a"`
+}
+
+type cycleCommon struct {
+	Root *cycleEmbedding `arg:"subcommand:root"`
+}
+
+type cycleEmbedding struct {
+	cycleCommon
+}
+
+func TestTagParser_SubcommandCycle(t *testing.T) {
+	tests := []struct {
+		name string
+		dest any
+		want string
+	}{
+		{"self", &cycleSelf{}, "recursive subcommand again: goarg.cycleSelf → goarg.cycleSelf"},
+		{"mutual", &cycleA{}, "recursive subcommand a: goarg.cycleA → goarg.cycleB → goarg.cycleA"},
+		{
+			"through embedded struct", &cycleEmbedding{},
+			"recursive subcommand root: goarg.cycleEmbedding → goarg.cycleCommon → goarg.cycleEmbedding",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser(Config{}, tt.dest)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("NewParser() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// calcExpr is an intentionally recursive command: "neg" negates another
+// expression.
+type calcExpr struct {
+	Value int       `arg:"positional"`
+	Neg   *calcExpr `arg:"subcommand:neg"`
+}
+
+func (e *calcExpr) eval() int {
+	if e.Neg != nil {
+		return -e.Neg.eval()
+	}
+	return e.Value
+}
+
+func TestTagParser_RecursiveSubcommandDepth(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{args: []string{"7"}, want: 7},
+		{args: []string{"neg", "7"}, want: -7},
+		{args: []string{"neg", "neg", "7"}, want: 7},
+		{args: []string{"neg", "neg", "neg", "7"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var expr calcExpr
+			p, err := NewParser(Config{MaxSubcommandDepth: 2}, &expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = p.Parse(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse() = nil, want an error past the depth limit (got %d)", expr.eval())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := expr.eval(); got != tt.want {
+				t.Errorf("eval() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+//go:build linux
+
+package goarg
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal reports whether fd refers to a terminal.
+func isTerminal(fd uintptr) bool {
+	var t syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&t)))
+	return errno == 0
+}
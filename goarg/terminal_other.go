@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package goarg
+
+// isTerminal reports whether fd refers to a terminal. Without a way to ask
+// on this platform it reports false, and standard input is simply read.
+func isTerminal(uintptr) bool {
+	return false
+}
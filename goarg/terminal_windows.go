@@ -0,0 +1,11 @@
+//go:build windows
+
+package goarg
+
+import "syscall"
+
+// isTerminal reports whether fd refers to a console.
+func isTerminal(fd uintptr) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
@@ -152,7 +152,7 @@ func TestPropertyNilHandleBackwardCompat(t *testing.T) {
 		args2 := make([]string, len(args))
 		copy(args2, args)
 		config := ParserConfig{
-			enableErrors:    true,
+			errorMode:       ErrorReport,
 			longCaseIgnore:  true,
 			shortCaseIgnore: false,
 			longOptsOnly:    false,
@@ -264,7 +264,7 @@ func TestHandlerSuppressesYield(t *testing.T) {
 				}
 			}
 
-			p, _ := NewParser(ParserConfig{enableErrors: true, longCaseIgnore: true}, tt.short, tt.long, tt.args)
+			p, _ := NewParser(ParserConfig{errorMode: ErrorReport, longCaseIgnore: true}, tt.short, tt.long, tt.args)
 			var yielded []string
 			for opt, err := range p.Options() {
 				if err != nil {
@@ -318,7 +318,7 @@ func TestHandlerErrorPropagation(t *testing.T) {
 				args = append(args, "-"+string(c))
 			}
 
-			p, _ := NewParser(ParserConfig{enableErrors: true}, shortMap, nil, args)
+			p, _ := NewParser(ParserConfig{errorMode: ErrorReport}, shortMap, nil, args)
 			opts, errs := collectOptions(p)
 			if len(errs) == 0 || errs[0] == nil {
 				t.Fatal("expected error on first yield, got nil")
@@ -396,7 +396,7 @@ func TestChildOverloadingWins(t *testing.T) {
 				parentLong[n] = &Flag{Name: n, HasArg: NoArgument, Handle: parentHandler}
 			}
 
-			cfg := ParserConfig{enableErrors: true, longCaseIgnore: true}
+			cfg := ParserConfig{errorMode: ErrorReport, longCaseIgnore: true}
 			parent, _ := NewParser(cfg, parentShort, parentLong, nil)
 
 			childShort := make(map[byte]*Flag)
@@ -488,7 +488,7 @@ func TestCompactionHandlerDispatchOrder(t *testing.T) {
 				shortMap[c] = f
 			}
 			compacted := "-" + string(tt.chars)
-			p, _ := NewParser(ParserConfig{enableErrors: true}, shortMap, nil, []string{compacted})
+			p, _ := NewParser(ParserConfig{errorMode: ErrorReport}, shortMap, nil, []string{compacted})
 			var yielded []string
 			for opt, err := range p.Options() {
 				if err != nil {
@@ -686,7 +686,7 @@ func TestSetHandler(t *testing.T) {
 
 	for _, tt := range noWalkTests {
 		t.Run(tt.name, func(t *testing.T) {
-			cfg := ParserConfig{enableErrors: true, longCaseIgnore: true}
+			cfg := ParserConfig{errorMode: ErrorReport, longCaseIgnore: true}
 			parentShort := map[byte]*Flag{'v': {Name: "v", HasArg: NoArgument}}
 			parentLong := map[string]*Flag{"verbose": {Name: "verbose", HasArg: NoArgument}}
 			parent, _ := NewParser(cfg, parentShort, parentLong, nil)
@@ -714,7 +714,7 @@ func TestHandlerLongOnly(t *testing.T) {
 		longOpts := map[string]*Flag{
 			"verbose": {Name: "verbose", HasArg: NoArgument, Handle: handler},
 		}
-		cfg := ParserConfig{enableErrors: true, longCaseIgnore: true, longOptsOnly: true}
+		cfg := ParserConfig{errorMode: ErrorReport, longCaseIgnore: true, longOptsOnly: true}
 		p, _ := NewParser(cfg, nil, longOpts, []string{"-verbose"})
 		opts, errs := collectOptions(p)
 		for _, e := range errs {
@@ -736,7 +736,7 @@ func TestHandlerLongOnly(t *testing.T) {
 		longOpts := map[string]*Flag{
 			"verbose": {Name: "verbose", HasArg: NoArgument, Handle: handler},
 		}
-		cfg := ParserConfig{enableErrors: true, longCaseIgnore: true, longOptsOnly: true}
+		cfg := ParserConfig{errorMode: ErrorReport, longCaseIgnore: true, longOptsOnly: true}
 		p, _ := NewParser(cfg, nil, longOpts, []string{"-verbose"})
 		var sawError bool
 		for opt, err := range p.Options() {
@@ -765,7 +765,7 @@ func TestHandlerLongOnly(t *testing.T) {
 		longOpts := map[string]*Flag{
 			"output": {Name: "output", HasArg: RequiredArgument, Handle: handler},
 		}
-		cfg := ParserConfig{enableErrors: true, longCaseIgnore: true, longOptsOnly: true}
+		cfg := ParserConfig{errorMode: ErrorReport, longCaseIgnore: true, longOptsOnly: true}
 		p, _ := NewParser(cfg, nil, longOpts, []string{"-output=file.txt"})
 		opts, errs := collectOptions(p)
 		for _, e := range errs {
@@ -801,7 +801,7 @@ func TestHandlerIteratorBreak(t *testing.T) {
 					"debug":   {Name: "debug", HasArg: NoArgument, Handle: func(string, string) error { return sentinel }},
 				}
 				p, _ := NewParser(
-					ParserConfig{enableErrors: true, longCaseIgnore: true},
+					ParserConfig{errorMode: ErrorReport, longCaseIgnore: true},
 					nil, longOpts, []string{"--verbose", "--debug"},
 				)
 				return p
@@ -815,7 +815,7 @@ func TestHandlerIteratorBreak(t *testing.T) {
 					"verbose": {Name: "verbose", HasArg: NoArgument, Handle: func(string, string) error { return sentinel }},
 					"debug":   {Name: "debug", HasArg: NoArgument, Handle: func(string, string) error { return sentinel }},
 				}
-				cfg := ParserConfig{enableErrors: true, longCaseIgnore: true, longOptsOnly: true}
+				cfg := ParserConfig{errorMode: ErrorReport, longCaseIgnore: true, longOptsOnly: true}
 				p, _ := NewParser(cfg, nil, longOpts, []string{"-verbose", "-debug"})
 				return p
 			},
@@ -828,7 +828,7 @@ func TestHandlerIteratorBreak(t *testing.T) {
 					'a': {Name: "a", HasArg: NoArgument, Handle: func(string, string) error { return sentinel }},
 					'b': {Name: "b", HasArg: NoArgument},
 				}
-				p, _ := NewParser(ParserConfig{enableErrors: true}, shortOpts, nil, []string{"-ab", "-ab"})
+				p, _ := NewParser(ParserConfig{errorMode: ErrorReport}, shortOpts, nil, []string{"-ab", "-ab"})
 				return p
 			},
 		},
@@ -856,7 +856,7 @@ func TestHandlerIteratorBreak(t *testing.T) {
 			"debug":   {Name: "debug", HasArg: NoArgument},
 		}
 		p, _ := NewParser(
-			ParserConfig{enableErrors: true, longCaseIgnore: true},
+			ParserConfig{errorMode: ErrorReport, longCaseIgnore: true},
 			nil, longOpts, []string{"--verbose", "--debug"},
 		)
 		count := 0
@@ -872,3 +872,115 @@ func TestHandlerIteratorBreak(t *testing.T) {
 		}
 	})
 }
+
+func TestHandleCtx(t *testing.T) {
+	type call struct {
+		parser *Parser
+		opt    Option
+		rest   []string
+	}
+	var calls []call
+	record := func(p *Parser, opt Option) error {
+		calls = append(calls, call{p, opt, append([]string(nil), p.Args...)})
+		return nil
+	}
+	root, err := GetOptLong([]string{"--level", "2", "run", "-v", "x", "--level=3"}, "v", []Flag{
+		{Name: "level", HasArg: RequiredArgument, HandleCtx: record,
+			Handle: func(string, string) error { t.Error("Handle called alongside HandleCtx"); return nil }},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := root.SetHandlerCtx("-v", record); err != nil {
+		t.Fatal(err)
+	}
+	run, err := GetOptLong(nil, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run.Name = "run"
+	root.AddCmd("run", run)
+
+	assertOptions(t, requireParsedOptions(t, root), nil)
+	assertOptions(t, requireParsedOptions(t, run), nil)
+	want := []call{
+		{root, Option{Name: "level", HasArg: true, Arg: "2", Index: 0}, []string{"run", "-v", "x", "--level=3"}},
+		{run, Option{Name: "v", Index: 3, Offset: 1}, []string{"x", "--level=3"}},
+		{run, Option{Name: "level", HasArg: true, Arg: "3", Index: 5}, []string{}},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("got %d calls, want %d", len(calls), len(want))
+	}
+	for i, w := range want {
+		c := calls[i]
+		if c.parser != w.parser || c.opt != w.opt || strings.Join(c.rest, " ") != strings.Join(w.rest, " ") {
+			t.Errorf("call %d: parser %q, %+v, Args %q; want %q, %+v, %q",
+				i, c.parser.Name, c.opt, c.rest, w.parser.Name, w.opt, w.rest)
+		}
+	}
+	assertArgs(t, run.Args, []string{"x"})
+}
+
+func TestHandleCtxErrorsAndMiddleware(t *testing.T) {
+	boom := errors.New("boom")
+	p, err := GetOptLong([]string{"--fail", "-q"}, "q", []Flag{{Name: "fail"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetHandlerCtx("--fail", func(*Parser, Option) error { return boom }); err != nil {
+		t.Fatal(err)
+	}
+	var wrapped []string
+	p.Use(func(next HandlerFunc) HandlerFunc {
+		return func(name, arg string) error {
+			wrapped = append(wrapped, name)
+			return next(name, arg)
+		}
+	})
+	_, errs := collectOptions(p)
+	if len(errs) != 2 || !errors.Is(errs[0], boom) || errs[1] != nil {
+		t.Errorf("errors = %v, want boom then a yielded -q", errs)
+	}
+	if fmt.Sprint(wrapped) != "[fail]" {
+		t.Errorf("middleware saw %q, want [fail]", wrapped)
+	}
+
+	for _, name := range []string{"fail", "--missing", "-z", "-"} {
+		if err := p.SetHandlerCtx(name, func(*Parser, Option) error { return nil }); err == nil {
+			t.Errorf("SetHandlerCtx(%q) = nil, want an error", name)
+		}
+	}
+}
+
+// TestHandlerSettersReplace verifies that each handler setter replaces a
+// handler of the other form, whichever was set first.
+func TestHandlerSettersReplace(t *testing.T) {
+	var got []string
+	plain := func(name, _ string) error { got = append(got, "plain "+name); return nil }
+	ctx := func(_ *Parser, opt Option) error { got = append(got, "ctx "+opt.Name); return nil }
+
+	p, err := GetOptLong([]string{"-v", "--level", "--quiet", "-q"}, "vq", []Flag{{Name: "level"}, {Name: "quiet"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []func() error{
+		func() error { return p.SetHandlerCtx("-v", ctx) },
+		func() error { return p.SetHandler("-v", plain) },
+		func() error { return p.SetHandlerCtx("--level", ctx) },
+		func() error { return p.SetLongHandler("level", plain) },
+		func() error { return p.SetHandler("--quiet", plain) },
+		func() error { return p.SetHandlerCtx("--quiet", ctx) },
+		func() error { return p.SetHandlerCtx("-q", ctx) },
+		func() error { return p.SetShortHandler('q', plain) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertOptions(t, requireParsedOptions(t, p), nil)
+	want := "[plain v plain level ctx quiet plain q]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("handlers called %q, want %s", got, want)
+	}
+}
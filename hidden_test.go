@@ -0,0 +1,63 @@
+package optargs
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newHiddenParser(t *testing.T, args []string) *Parser {
+	t.Helper()
+	p, err := NewParser(ParserConfig{},
+		map[byte]*Flag{'v': {Name: "v", Help: "verbose"}, 'D': {Name: "D", Hidden: true}},
+		map[string]*Flag{
+			"verbose":    {Name: "verbose", Help: "verbose"},
+			"trace-file": {Name: "trace-file", HasArg: RequiredArgument, Hidden: true},
+		}, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Name = "prog"
+	return p
+}
+
+func TestHiddenFlagsParse(t *testing.T) {
+	p := newHiddenParser(t, []string{"-D", "--trace-file", "out", "-v"})
+	assertOptions(t, requireParsedOptions(t, p), []Option{
+		{Name: "D"},
+		{Name: "trace-file", HasArg: true, Arg: "out"},
+		{Name: "v"},
+	})
+}
+
+func TestHiddenFlagsExcluded(t *testing.T) {
+	p := newHiddenParser(t, nil)
+
+	var b strings.Builder
+	if err := p.WriteUsage(&b); err != nil {
+		t.Fatal(err)
+	}
+	if usage := b.String(); strings.Contains(usage, "trace-file") || strings.Contains(usage, "-D") {
+		t.Errorf("usage lists hidden flags:\n%s", usage)
+	}
+
+	var visible []string
+	for _, opt := range p.VisibleOptions() {
+		visible = append(visible, opt.Name)
+	}
+	if want := []string{"--verbose", "-v"}; !slices.Equal(visible, want) {
+		t.Errorf("VisibleOptions = %q, want %q", visible, want)
+	}
+	if got := len(p.AllOptions()); got != 4 {
+		t.Errorf("AllOptions has %d options, want 4", got)
+	}
+
+	if got := p.Complete("prog -", 6); !slices.Equal(got, []string{"--verbose", "-v"}) {
+		t.Errorf("Complete = %q", got)
+	}
+	// The hidden option's argument is still skipped.
+	opts, argopts := p.completionOptions()
+	if slices.Contains(opts, "--trace-file") || !slices.Equal(argopts, []string{"--trace-file"}) {
+		t.Errorf("completionOptions = %q, %q", opts, argopts)
+	}
+}
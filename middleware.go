@@ -0,0 +1,31 @@
+package optargs
+
+// HandlerFunc is the signature of Flag.Handle: it receives the option
+// name, or OperandName for the operand handler, and the argument.
+type HandlerFunc func(name, arg string) error
+
+// Use adds middleware that wraps every handler call of an Options
+// iteration over p, for cross-cutting concerns such as logging, metrics,
+// or permission checks. It wraps each Flag.Handle and Flag.HandleCtx,
+// inherited handlers included, and the operand handler (see [Parser.SetOperandHandler]),
+// which it sees under the name [OperandName]. Options without a handler
+// are yielded as usual and do not pass through it.
+//
+// The first middleware added is the outermost. Subcommands dispatched
+// from p run their handlers through p's middleware as well, outside any
+// of their own. A middleware that returns without calling next skips the
+// handler; the error it returns is yielded as the handler's would be.
+func (p *Parser) Use(middleware ...func(next HandlerFunc) HandlerFunc) {
+	p.middleware = append(p.middleware, middleware...)
+}
+
+// wrapHandler returns h wrapped in the middleware of p and of each parser
+// that dispatched it, the root's outermost.
+func (p *Parser) wrapHandler(h HandlerFunc) HandlerFunc {
+	for cur := p; cur != nil; cur = cur.dispatchedBy {
+		for i := len(cur.middleware) - 1; i >= 0; i-- {
+			h = cur.middleware[i](h)
+		}
+	}
+	return h
+}
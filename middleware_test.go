@@ -0,0 +1,91 @@
+package optargs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestUse(t *testing.T) {
+	var log []string
+	record := func(tag string) func(HandlerFunc) HandlerFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(name, arg string) error {
+				log = append(log, tag+">"+name)
+				err := next(name, arg)
+				log = append(log, tag+"<"+name)
+				return err
+			}
+		}
+	}
+	handler := func(name, arg string) error {
+		log = append(log, "handle "+name+"="+arg)
+		return nil
+	}
+
+	root, err := GetOptLong([]string{"-v", "x", "run", "--out", "f", "-v", "y"}, "v", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.shortOpts['v'].Handle = handler
+	run, err := GetOptLong(nil, "", []Flag{{Name: "out", HasArg: RequiredArgument, Handle: handler}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("run", run)
+	root.Use(record("a"), record("b"))
+	run.Use(record("c"))
+	run.SetOperandHandler(func(arg string, _ int) error {
+		log = append(log, "operand "+arg)
+		return nil
+	})
+
+	assertOptions(t, requireParsedOptions(t, root), nil)
+	assertOptions(t, requireParsedOptions(t, run), nil)
+	want := []string{
+		"a>v", "b>v", "handle v=", "b<v", "a<v",
+		"a>out", "b>out", "c>out", "handle out=f", "c<out", "b<out", "a<out",
+		"a>v", "b>v", "c>v", "handle v=", "c<v", "b<v", "a<v",
+		"a>" + OperandName, "b>" + OperandName, "c>" + OperandName, "operand y",
+		"c<" + OperandName, "b<" + OperandName, "a<" + OperandName,
+	}
+	if !slices.Equal(log, want) {
+		t.Errorf("calls =\n%q\nwant\n%q", log, want)
+	}
+	assertArgs(t, root.Args, []string{"x"})
+}
+
+func TestUseSkipsHandler(t *testing.T) {
+	denied := errors.New("denied")
+	called := false
+	p, err := GetOptLong([]string{"--admin", "--user"}, "", []Flag{
+		{Name: "admin", Handle: func(string, string) error { called = true; return nil }},
+		{Name: "user"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Use(func(next HandlerFunc) HandlerFunc {
+		return func(name, arg string) error {
+			if name == "admin" {
+				return denied
+			}
+			return next(name, arg)
+		}
+	})
+	var errs []error
+	var names []string
+	for opt, err := range p.Options() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, opt.Name)
+	}
+	if called || len(errs) != 1 || !errors.Is(errs[0], denied) {
+		t.Errorf("handler called %v, errors %v; want skipped with %v", called, errs, denied)
+	}
+	if !slices.Equal(names, []string{"user"}) {
+		t.Errorf("yielded %q, want [user]: options without a handler bypass middleware", names)
+	}
+}
@@ -0,0 +1,367 @@
+package optargs
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// ---------------------------------------------------------------------------
+// Model-based tests: the Options() state machine is checked against a
+// deliberately naive reference implementation of getopt_long(3) and
+// getopt_long_only(3) over randomized argument vectors. Each step of the
+// model is a direct transcription of the documented rules, with no shared
+// code paths, so divergence points at a state-machine bug in one or the
+// other.
+// ---------------------------------------------------------------------------
+
+// modelOptstring and modelLongopts define the option surface used by every
+// model test: a flag, a required-argument option, and an optional-argument
+// option in both short and long form, plus "alpine" so that "al" is an
+// ambiguous abbreviation.
+const modelOptstring = "ab:c::"
+
+var modelLongopts = []Flag{
+	{Name: "alpha", HasArg: NoArgument},
+	{Name: "alpine", HasArg: NoArgument},
+	{Name: "beta", HasArg: RequiredArgument},
+	{Name: "gamma", HasArg: OptionalArgument},
+}
+
+var modelShorts = map[byte]ArgType{
+	'a': NoArgument,
+	'b': RequiredArgument,
+	'c': OptionalArgument,
+}
+
+// modelEvent is one step of iterator output. Error events carry no option
+// so that the model need not reproduce partially filled error options.
+type modelEvent struct {
+	Option
+	Err bool
+}
+
+// modelSpec selects the parse mode and long-only behavior under test.
+type modelSpec struct {
+	mode     ParseMode
+	longOnly bool
+}
+
+func (s modelSpec) String() string {
+	return fmt.Sprintf("mode=%d longOnly=%v", s.mode, s.longOnly)
+}
+
+var modelSpecs = []modelSpec{
+	{ParseDefault, false},
+	{ParseNonOpts, false},
+	{ParsePosixlyCorrect, false},
+	{ParseDefault, true},
+	{ParseNonOpts, true},
+	{ParsePosixlyCorrect, true},
+}
+
+// modelParse is the reference parser. It returns the iterator events and
+// the final Args.
+func modelParse(spec modelSpec, args []string) ([]modelEvent, []string) {
+	var events []modelEvent
+	var nonOpts []string
+	argc := len(args)
+
+	for len(args) > 0 {
+		arg := args[0]
+		index := argc - len(args)
+		switch {
+		case arg == "--":
+			return events, append(nonOpts, args[1:]...)
+
+		case strings.HasPrefix(arg, "--"):
+			var ev modelEvent
+			ev, args, _ = modelLong(arg[2:], args[1:])
+			if !ev.Err {
+				ev.Index = index
+			}
+			events = append(events, ev)
+
+		case len(arg) > 1 && arg[0] == '-':
+			word := arg[1:]
+			args = args[1:]
+			if spec.longOnly {
+				_, isShort := modelShorts[word[0]]
+				if len(word) != 1 || !isShort {
+					ev, rest, unknown := modelLong(word, args)
+					if !unknown {
+						if !ev.Err {
+							ev.Index = index
+						}
+						events = append(events, ev)
+						args = rest
+						continue
+					}
+				}
+			}
+			events, args = modelShortWord(events, index, word, args)
+
+		default:
+			switch spec.mode {
+			case ParseDefault:
+				nonOpts = append(nonOpts, arg)
+			case ParseNonOpts:
+				events = append(events, modelEvent{Option: Option{Name: "\x01", Arg: arg, Index: index}})
+			case ParsePosixlyCorrect:
+				return events, append(nonOpts, args...)
+			}
+			args = args[1:]
+		}
+	}
+	return events, nonOpts
+}
+
+// modelShortWord consumes the option characters of a single-dash word
+// found at index.
+func modelShortWord(events []modelEvent, index int, word string, args []string) ([]modelEvent, []string) {
+	offset := 1
+	for len(word) > 0 {
+		c := word[0]
+		word = word[1:]
+		hasArg, ok := modelShorts[c]
+		if !ok {
+			return append(events, modelEvent{Err: true}), args
+		}
+		opt := Option{Name: string(c), Index: index, Offset: offset}
+		offset++
+		switch hasArg {
+		case RequiredArgument:
+			switch {
+			case word != "":
+				opt.Arg, word = word, ""
+			case len(args) > 0:
+				opt.Arg, args = args[0], args[1:]
+			default:
+				return append(events, modelEvent{Err: true}), args
+			}
+			opt.HasArg = true
+		case OptionalArgument:
+			switch {
+			case word != "":
+				opt.Arg, word = word, ""
+				opt.HasArg = true
+			case len(args) > 0:
+				opt.Arg, args = args[0], args[1:]
+				opt.HasArg = true
+			}
+		}
+		events = append(events, modelEvent{Option: opt})
+	}
+	return events, args
+}
+
+// modelLong resolves a long option body (without leading dashes). The
+// unknown result reports that no split of name matched any long option.
+func modelLong(name string, args []string) (ev modelEvent, rest []string, unknown bool) {
+	errEvent := modelEvent{Err: true}
+
+	// Candidate splits: the whole word first, then at each '=' from the right.
+	type split struct {
+		input, inline string
+		hasInline     bool
+	}
+	splits := []split{{input: name}}
+	for i := len(name) - 1; i >= 0; i-- {
+		if name[i] == '=' {
+			splits = append(splits, split{name[:i], name[i+1:], true})
+		}
+	}
+
+	for _, s := range splits {
+		input := strings.ToLower(s.input)
+		var matches []Flag
+		for _, f := range modelLongopts {
+			if f.Name == input {
+				matches = []Flag{f}
+				break
+			}
+			if len(f.Name) > len(input) && strings.HasPrefix(f.Name, input) {
+				matches = append(matches, f)
+			}
+		}
+		switch {
+		case len(matches) > 1:
+			return errEvent, args, false
+		case len(matches) == 0:
+			continue
+		}
+
+		f := matches[0]
+		opt := Option{Name: f.Name}
+		switch {
+		case s.hasInline && f.HasArg == NoArgument:
+			return errEvent, args, false
+		case s.hasInline:
+			opt.Arg, opt.HasArg = s.inline, true
+		case f.HasArg == RequiredArgument:
+			if len(args) == 0 {
+				return errEvent, args, false
+			}
+			opt.Arg, opt.HasArg, args = args[0], true, args[1:]
+		case f.HasArg == OptionalArgument:
+			if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+				opt.Arg, opt.HasArg, args = args[0], true, args[1:]
+			}
+		}
+		return modelEvent{Option: opt}, args, false
+	}
+	return errEvent, args, true
+}
+
+// realParse runs the real parser configured per spec and records its
+// events and final Args in model form.
+func realParse(t *testing.T, spec modelSpec, args []string) ([]modelEvent, []string) {
+	t.Helper()
+	optstring := ":" + modelOptstring
+	switch spec.mode {
+	case ParseNonOpts:
+		optstring = "-" + optstring
+	case ParsePosixlyCorrect:
+		optstring = "+" + optstring
+	}
+
+	argv := append([]string(nil), args...)
+	var p *Parser
+	var err error
+	if spec.longOnly {
+		p, err = GetOptLongOnly(argv, optstring, modelLongopts)
+	} else {
+		p, err = GetOptLong(argv, optstring, modelLongopts)
+	}
+	if err != nil {
+		t.Fatalf("%v: %v", spec, err)
+	}
+
+	var events []modelEvent
+	for opt, err := range p.Options() {
+		if err != nil {
+			events = append(events, modelEvent{Err: true})
+			continue
+		}
+		events = append(events, modelEvent{Option: opt})
+	}
+	return events, p.Args
+}
+
+// modelTokens is the vocabulary from which random argument vectors are
+// drawn. It covers every transition of the state machine: terminators,
+// clustered shorts, attached and detached arguments, abbreviations,
+// ambiguity, inline '=' splitting, unknown options, and operands.
+var modelTokens = []string{
+	"--", "-", "", "op", "x", "-x",
+	"-a", "-b", "-c", "-ab", "-ac", "-abv", "-acv", "-bv", "-cv", "-ba",
+	"-z", "-az", "-a-", "-aza",
+	"--alpha", "--alp", "--al", "--alpine", "--ALPHA", "--alpha=v",
+	"--beta", "--b", "--beta=", "--beta=v", "--beta=v=w", "--be=v",
+	"--gamma", "--g", "--gamma=v", "--gamma=",
+	"--zeta", "--zeta=v", "--=v", "--a=b=c",
+	"-alpha", "-al", "-beta", "-beta=v", "-gam", "-gamma=v", "-zeta",
+}
+
+func randModelArgs(rng *rand.Rand) []string {
+	args := make([]string, rng.Intn(9))
+	for i := range args {
+		args[i] = modelTokens[rng.Intn(len(modelTokens))]
+	}
+	return args
+}
+
+// TestPropertyModel_OptionsMatchesReference verifies that, for every parse
+// mode, the real parser yields the same events and leaves the same Args
+// as the reference model on randomized argument vectors.
+func TestPropertyModel_OptionsMatchesReference(t *testing.T) {
+	t.Setenv("POSIXLY_CORRECT", "")
+	cfg := &quick.Config{MaxCount: 100}
+	for _, spec := range modelSpecs {
+		t.Run(spec.String(), func(t *testing.T) {
+			f := func(seed int64) bool {
+				rng := rand.New(rand.NewSource(seed)) //nolint:gosec // deterministic seed for reproducible property tests
+				args := randModelArgs(rng)
+
+				wantEvents, wantArgs := modelParse(spec, args)
+				gotEvents, gotArgs := realParse(t, spec, args)
+				if !reflect.DeepEqual(gotEvents, wantEvents) || !equalArgs(gotArgs, wantArgs) {
+					t.Logf("args=%q\n got events=%+v args=%q\nwant events=%+v args=%q",
+						args, gotEvents, gotArgs, wantEvents, wantArgs)
+					return false
+				}
+				return true
+			}
+			if err := quick.Check(f, cfg); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestModelRegressions pins argument vectors that once diverged from the
+// model.
+func TestModelRegressions(t *testing.T) {
+	t.Setenv("POSIXLY_CORRECT", "")
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"lone dash is an operand", []string{"-", "-a"}},
+		{"lone dash stops posix parsing", []string{"-a", "-", "-b", "v"}},
+		{"empty string after optional long", []string{"--gamma", ""}},
+		{"empty string after optional short", []string{"-c", ""}},
+	}
+	for _, tt := range tests {
+		for _, spec := range modelSpecs {
+			t.Run(tt.name+"/"+spec.String(), func(t *testing.T) {
+				wantEvents, wantArgs := modelParse(spec, tt.args)
+				gotEvents, gotArgs := realParse(t, spec, tt.args)
+				if !reflect.DeepEqual(gotEvents, wantEvents) {
+					t.Errorf("events = %+v, want %+v", gotEvents, wantEvents)
+				}
+				if !equalArgs(gotArgs, wantArgs) {
+					t.Errorf("Args = %q, want %q", gotArgs, wantArgs)
+				}
+			})
+		}
+	}
+}
+
+// FuzzOptionsMatchesReference feeds arbitrary argument vectors, including
+// embedded NULs and other control characters, through every parse mode and
+// checks the real parser against the reference model.
+func FuzzOptionsMatchesReference(f *testing.F) {
+	f.Setenv("POSIXLY_CORRECT", "")
+	for _, seed := range [][3]string{
+		{"-a", "op", "--beta=v"},
+		{"-b\x00", "--gamma=\n", "\x00"},
+		{"--al\x00pha", "-\x00", "--"},
+		{"-ac\r\n", "--beta", ""},
+		{"-alpha\x00", "-gam", "\x7f"},
+	} {
+		f.Add(seed[0], seed[1], seed[2])
+	}
+	f.Fuzz(func(t *testing.T, a, b, c string) {
+		args := []string{a, b, c}
+		for _, spec := range modelSpecs {
+			wantEvents, wantArgs := modelParse(spec, args)
+			gotEvents, gotArgs := realParse(t, spec, args)
+			if !reflect.DeepEqual(gotEvents, wantEvents) || !equalArgs(gotArgs, wantArgs) {
+				t.Fatalf("%v: args=%q\n got events=%+v args=%q\nwant events=%+v args=%q",
+					spec, args, gotEvents, gotArgs, wantEvents, wantArgs)
+			}
+		}
+	})
+}
+
+// equalArgs compares argument slices treating nil and empty as equal.
+func equalArgs(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
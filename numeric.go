@@ -0,0 +1,51 @@
+package optargs
+
+import (
+	"errors"
+	"strconv"
+)
+
+// isNumericOption reports whether arg is a dash followed only by ASCII
+// digits whose first digit is not a short option of p or an ancestor.
+func (p *Parser) isNumericOption(arg string) bool {
+	if len(arg) < 2 || !isDigits(arg[1:]) {
+		return false
+	}
+	return p.resolveShort(arg[1]) == nil
+}
+
+// isDigits reports whether s is non-empty and holds only ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isNegativeNumber reports whether negative numbers are enabled and arg
+// is a dash followed by a decimal number, such as -42, -3.14, or -.5e3,
+// whose first character is not a short option of p or an ancestor.
+func (p *Parser) isNegativeNumber(arg string) bool {
+	if !p.config.negativeNums || len(arg) < 2 {
+		return false
+	}
+	if p.config.numericOpts && isDigits(arg[1:]) {
+		return false
+	}
+	first := arg[1]
+	if first == '.' && len(arg) > 2 {
+		first = arg[2]
+	}
+	if first < '0' || first > '9' {
+		return false
+	}
+	if _, err := strconv.ParseFloat(arg, 64); err != nil && !errors.Is(err, strconv.ErrRange) {
+		return false
+	}
+	return p.resolveShort(arg[1]) == nil
+}
@@ -0,0 +1,121 @@
+package optargs
+
+import "testing"
+
+func TestNumericOptions(t *testing.T) {
+	shortOpts := map[byte]*Flag{
+		'n': {Name: "n", HasArg: RequiredArgument},
+		'q': {Name: "q", HasArg: NoArgument},
+		'1': {Name: "1", HasArg: NoArgument},
+	}
+	tests := []struct {
+		name     string
+		enabled  bool
+		args     []string
+		want     []Option
+		wantArgs []string
+		wantErr  bool
+	}{
+		{"head style", true, []string{"-20", "file"}, []Option{{Name: NumericName, HasArg: true, Arg: "20"}}, []string{"file"}, false},
+		{"among options", true, []string{"-q", "-5", "-n", "3"}, []Option{{Name: "q"}, {Name: NumericName, HasArg: true, Arg: "5"}, {Name: "n", HasArg: true, Arg: "3"}}, []string{}, false},
+		{"registered digit", true, []string{"-1"}, []Option{{Name: "1"}}, []string{}, false},
+		{"digits then letters", true, []string{"-5q"}, nil, []string{}, true},
+		{"operand after terminator", true, []string{"--", "-5"}, nil, []string{"-5"}, false},
+		{"disabled", false, []string{"-5"}, nil, []string{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg ParserConfig
+			cfg.SetNumericOptions(tt.enabled)
+			if cfg.NumericOptions() != tt.enabled {
+				t.Fatalf("NumericOptions() = %v, want %v", cfg.NumericOptions(), tt.enabled)
+			}
+			p, err := NewParser(cfg, shortOpts, nil, tt.args)
+			if err != nil {
+				t.Fatalf("NewParser: %v", err)
+			}
+			var got []Option
+			sawErr := false
+			for opt, err := range p.Options() {
+				if err != nil {
+					sawErr = true
+					continue
+				}
+				if opt.IsNumeric() != (opt.Name == NumericName) {
+					t.Errorf("IsNumeric() = %v for %q", opt.IsNumeric(), opt.Name)
+				}
+				got = append(got, opt)
+			}
+			if sawErr != tt.wantErr {
+				t.Errorf("error = %v, want %v", sawErr, tt.wantErr)
+			}
+			assertOptions(t, got, tt.want)
+			assertArgs(t, p.Args, tt.wantArgs)
+		})
+	}
+}
+
+func TestParserSetNumericOptions(t *testing.T) {
+	p, err := GetOpt([]string{"-v", "-20"}, "v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetNumericOptions(true)
+	opts := requireParsedOptions(t, p)
+	assertOptions(t, opts, []Option{{Name: "v"}, {Name: NumericName, HasArg: true, Arg: "20"}})
+	if n, err := opts[1].Int(); err != nil || n != 20 {
+		t.Errorf("Int() = %d, %v, want 20", n, err)
+	}
+}
+
+func TestNegativeNumbers(t *testing.T) {
+	shortOpts := map[byte]*Flag{
+		'n': {Name: "n", HasArg: RequiredArgument},
+		'q': {Name: "q", HasArg: NoArgument},
+		'1': {Name: "1", HasArg: NoArgument},
+	}
+	tests := []struct {
+		name     string
+		numeric  bool
+		args     []string
+		want     []Option
+		wantArgs []string
+		wantErr  bool
+	}{
+		{"integer", false, []string{"-42", "-q"}, []Option{{Name: "q"}}, []string{"-42"}, false},
+		{"decimals", false, []string{"-3.14", "-.5", "-2e-9", "7"}, nil, []string{"-3.14", "-.5", "-2e-9", "7"}, false},
+		{"option argument", false, []string{"-n", "-3"}, []Option{{Name: "n", HasArg: true, Arg: "-3"}}, []string{}, false},
+		{"registered digit", false, []string{"-1"}, []Option{{Name: "1"}}, []string{}, false},
+		{"not a number", false, []string{"-3x"}, nil, []string{}, true},
+		{"letters", false, []string{"-q5"}, []Option{{Name: "q"}}, []string{}, true},
+		{"numeric options win", true, []string{"-5", "-2.5"}, []Option{{Name: NumericName, HasArg: true, Arg: "5"}}, []string{"-2.5"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg ParserConfig
+			cfg.SetNegativeNumbers(true)
+			cfg.SetNumericOptions(tt.numeric)
+			if !cfg.NegativeNumbers() {
+				t.Fatal("NegativeNumbers() = false after SetNegativeNumbers(true)")
+			}
+			p, err := NewParser(cfg, shortOpts, nil, tt.args)
+			if err != nil {
+				t.Fatalf("NewParser: %v", err)
+			}
+			var got []Option
+			sawErr := false
+			for opt, err := range p.Options() {
+				if err != nil {
+					sawErr = true
+					continue
+				}
+				got = append(got, opt)
+			}
+			if sawErr != tt.wantErr {
+				t.Errorf("error = %v, want %v", sawErr, tt.wantErr)
+			}
+			assertOptions(t, got, tt.want)
+			assertArgs(t, p.Args, tt.wantArgs)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package optargs
+
+// SetOperandHandler sets a function called for each non-option argument
+// the Options iteration encounters, in order among the option handlers,
+// so a whole command line can be processed in one pass. index is the
+// argument's position in the original argument list, as for Option.Index.
+// A handled operand is consumed: it is neither yielded nor left in Args.
+// An error is yielded from the iterator with an Option carrying the
+// operand (see [Option.IsOperand]), and iteration continues.
+//
+// Subcommand names are dispatched, not handled. Arguments after "--",
+// and in POSIXLY_CORRECT mode the first operand and everything after it,
+// end option processing and stay in Args. Pass nil to restore the
+// default of collecting operands in Args.
+func (p *Parser) SetOperandHandler(fn func(arg string, index int) error) {
+	p.config.onOperand = fn
+}
+
+// handleOperand calls the operand handler for arg, tracing the call.
+func (p *Parser) handleOperand(arg string, index int) error {
+	if trace := p.tracer(); trace != nil {
+		trace(TraceEvent{Kind: TraceHandle, Parser: p, Owner: p, Option: Option{Name: OperandName, Arg: arg, Index: index}})
+	}
+	return p.wrapHandler(func(_, arg string) error {
+		return p.config.onOperand(arg, index)
+	})(OperandName, arg)
+}
+
+// SetOperandGrammar sets a function that parses regions of the command
+// line with a grammar of its own, such as find(1) predicates or ffmpeg
+// filter expressions, while the parser stays in charge of the rest.
+// Before each argument is classified, fn receives the remaining arguments
+// starting with it, and index, its position in the original argument
+// list. It returns how many arguments it consumed; option parsing resumes
+// after them. Returning 0 declines, and the argument is parsed as usual.
+// Consumed arguments are neither yielded nor left in Args.
+//
+// An error is yielded from the iterator with an Option carrying the first
+// argument of the region (see [Option.IsOperand]) and iteration
+// continues after the consumed arguments, or after that first argument
+// when none were consumed. The "--" terminator is never offered to fn.
+// Pass nil to remove the grammar.
+func (p *Parser) SetOperandGrammar(fn func(args []string, index int) (int, error)) {
+	p.config.onGrammar = fn
+}
+
+// handleGrammar offers the remaining arguments to the operand grammar,
+// tracing a call that consumes or fails, and returns how many arguments
+// to drop.
+func (p *Parser) handleGrammar(index int) (int, error) {
+	n, err := p.config.onGrammar(p.Args, index)
+	n = min(max(n, 0), len(p.Args))
+	if n == 0 && err == nil {
+		return 0, nil
+	}
+	if trace := p.tracer(); trace != nil {
+		trace(TraceEvent{Kind: TraceHandle, Parser: p, Owner: p, Option: Option{Name: OperandName, Arg: p.Args[0], Index: index}})
+	}
+	if n == 0 {
+		n = 1
+	}
+	return n, err
+}
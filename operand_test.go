@@ -0,0 +1,202 @@
+package optargs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOperandHandler(t *testing.T) {
+	errBad := errors.New("bad operand")
+	tests := []struct {
+		name      string
+		optstring string
+		args      []string
+		wantLog   []string
+		wantArgs  []string
+		wantErrs  int
+	}{
+		{
+			name:      "interleaved",
+			optstring: "ab:",
+			args:      []string{"x", "-a", "y", "-b", "z", "w"},
+			wantLog:   []string{"operand x@0", "-a", "operand y@2", "-bz", "operand w@5"},
+			wantArgs:  []string{},
+		},
+		{
+			name:      "terminator",
+			optstring: "a",
+			args:      []string{"x", "--", "-a", "y"},
+			wantLog:   []string{"operand x@0"},
+			wantArgs:  []string{"-a", "y"},
+		},
+		{
+			name:      "posixly correct",
+			optstring: "+a",
+			args:      []string{"-a", "x", "-a"},
+			wantLog:   []string{"-a"},
+			wantArgs:  []string{"x", "-a"},
+		},
+		{
+			name:      "in order mode",
+			optstring: "-a",
+			args:      []string{"x", "-a"},
+			wantLog:   []string{"operand x@0", "-a"},
+			wantArgs:  []string{},
+		},
+		{
+			name:      "handler error",
+			optstring: "a",
+			args:      []string{"bad", "-a", "ok"},
+			wantLog:   []string{"operand bad@0", "-a", "operand ok@2"},
+			wantArgs:  []string{},
+			wantErrs:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetOpt(tt.args, tt.optstring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var log []string
+			p.SetOperandHandler(func(arg string, index int) error {
+				log = append(log, fmt.Sprintf("operand %s@%d", arg, index))
+				if arg == "bad" {
+					return errBad
+				}
+				return nil
+			})
+			errs := 0
+			for opt, err := range p.Options() {
+				if err != nil {
+					if !errors.Is(err, errBad) || !opt.IsOperand() || opt.Arg != "bad" {
+						t.Errorf("unexpected error %v with %+v", err, opt)
+					}
+					errs++
+					continue
+				}
+				log = append(log, "-"+opt.Name+opt.Arg)
+			}
+			if !slices.Equal(log, tt.wantLog) {
+				t.Errorf("log = %q, want %q", log, tt.wantLog)
+			}
+			assertArgs(t, p.Args, tt.wantArgs)
+			if errs != tt.wantErrs {
+				t.Errorf("%d errors, want %d", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestOperandHandlerSubcommand(t *testing.T) {
+	root, err := GetOpt([]string{"x", "run", "y"}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := GetOpt(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("run", run)
+	var handled []string
+	root.SetOperandHandler(func(arg string, _ int) error {
+		handled = append(handled, arg)
+		return nil
+	})
+	requireParsedOptions(t, root)
+	for _, child := range root.Dispatches() {
+		requireParsedOptions(t, child)
+	}
+	if !slices.Equal(handled, []string{"x"}) {
+		t.Errorf("handled %q, want [x]", handled)
+	}
+	assertArgs(t, run.Args, []string{"y"})
+}
+
+// findPredicates maps find(1)-style predicates to their argument counts.
+var findPredicates = map[string]int{"-name": 1, "-type": 1, "-o": 0, "-print": 0, "!": 0}
+
+// findGrammar consumes a run of predicates, recording each one in expr.
+func findGrammar(expr *[]string) func(args []string, index int) (int, error) {
+	return func(args []string, _ int) (int, error) {
+		n := 0
+		for n < len(args) {
+			nargs, ok := findPredicates[args[n]]
+			if !ok {
+				break
+			}
+			if n+nargs >= len(args) {
+				return len(args), fmt.Errorf("missing argument to %s", args[n])
+			}
+			*expr = append(*expr, strings.Join(args[n:n+nargs+1], " "))
+			n += nargs + 1
+		}
+		return n, nil
+	}
+}
+
+func TestOperandGrammar(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantOpts []Option
+		wantExpr []string
+		wantArgs []string
+		wantErr  string
+	}{
+		{
+			name:     "region between options",
+			args:     []string{"-L", "src", "-name", "*.go", "-o", "!", "-type", "d", "-v", "lib"},
+			wantOpts: []Option{{Name: "L"}, {Name: "v"}},
+			wantExpr: []string{"-name *.go", "-o", "!", "-type d"},
+			wantArgs: []string{"src", "lib"},
+		},
+		{
+			name:     "declined",
+			args:     []string{"-v", "src"},
+			wantOpts: []Option{{Name: "v"}},
+			wantArgs: []string{"src"},
+		},
+		{
+			name:     "terminator",
+			args:     []string{"-print", "--", "-name", "x"},
+			wantExpr: []string{"-print"},
+			wantArgs: []string{"-name", "x"},
+		},
+		{
+			name:     "error",
+			args:     []string{"-v", "-name"},
+			wantOpts: []Option{{Name: "v"}},
+			wantArgs: []string{},
+			wantErr:  "missing argument to -name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetOpt(tt.args, "Lv")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var expr []string
+			p.SetOperandGrammar(findGrammar(&expr))
+			var opts []Option
+			for opt, err := range p.Options() {
+				if err != nil {
+					if err.Error() != tt.wantErr || !opt.IsOperand() || opt.Arg != "-name" {
+						t.Errorf("unexpected error %v with %+v", err, opt)
+					}
+					continue
+				}
+				opts = append(opts, opt)
+			}
+			assertOptions(t, opts, tt.wantOpts)
+			if !slices.Equal(expr, tt.wantExpr) {
+				t.Errorf("expression = %q, want %q", expr, tt.wantExpr)
+			}
+			assertArgs(t, p.Args, tt.wantArgs)
+		})
+	}
+}
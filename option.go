@@ -0,0 +1,83 @@
+package optargs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsOperand reports whether o stands for a non-option argument, which is
+// then held in Arg; see [OperandName].
+func (o Option) IsOperand() bool {
+	return o.Name == OperandName
+}
+
+// IsNumeric reports whether o is a numeric option such as -5, whose
+// digits are then held in Arg; see [NumericName].
+func (o Option) IsNumeric() bool {
+	return o.Name == NumericName
+}
+
+// Int returns the option argument as a base-10 int.
+func (o Option) Int() (int, error) {
+	v, err := strconv.Atoi(o.Arg)
+	if err != nil {
+		return 0, o.invalid("int", err)
+	}
+	return v, nil
+}
+
+// Bool returns the option argument as a boolean, accepting the same
+// spellings as [Convert]: true/t/1/yes/y/on and false/f/0/no/n/off,
+// case-insensitively. An option given without an argument is true.
+func (o Option) Bool() (bool, error) {
+	if !o.HasArg {
+		return true, nil
+	}
+	v, err := convertBool(o.Arg)
+	if err != nil {
+		return false, o.invalid("bool", err)
+	}
+	return v, nil
+}
+
+// Float returns the option argument as a float64.
+func (o Option) Float() (float64, error) {
+	v, err := strconv.ParseFloat(o.Arg, 64)
+	if err != nil {
+		return 0, o.invalid("float", err)
+	}
+	return v, nil
+}
+
+// Duration returns the option argument parsed by [time.ParseDuration].
+func (o Option) Duration() (time.Duration, error) {
+	v, err := time.ParseDuration(o.Arg)
+	if err != nil {
+		return 0, o.invalid("duration", err)
+	}
+	return v, nil
+}
+
+// KeyValue splits the option argument of the form key=value at its first
+// '='. The key must not be empty; the value may be.
+func (o Option) KeyValue() (key, value string, err error) {
+	key, value, ok := strings.Cut(o.Arg, "=")
+	switch {
+	case !ok:
+		return "", "", o.invalid("key=value", errMissingEquals)
+	case key == "":
+		return "", "", o.invalid("key=value", errEmptyKey)
+	}
+	return key, value, nil
+}
+
+var (
+	errMissingEquals = errors.New("missing '='")
+	errEmptyKey      = errors.New("empty key")
+)
+
+func (o Option) invalid(typ string, err error) error {
+	return &InvalidArgumentError{Name: o.Name, Arg: o.Arg, Type: typ, Err: err}
+}
@@ -0,0 +1,61 @@
+package optargs
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestOptionAccessors(t *testing.T) {
+	opt := func(arg string) Option { return Option{Name: "n", HasArg: true, Arg: arg} }
+
+	if v, err := opt("-42").Int(); v != -42 || err != nil {
+		t.Errorf("Int = %d, %v", v, err)
+	}
+	if v, err := opt("2.5").Float(); v != 2.5 || err != nil {
+		t.Errorf("Float = %g, %v", v, err)
+	}
+	if v, err := opt("1m30s").Duration(); v != 90*time.Second || err != nil {
+		t.Errorf("Duration = %v, %v", v, err)
+	}
+	for arg, want := range map[string]bool{"yes": true, "OFF": false, "1": true, "": false} {
+		if v, err := opt(arg).Bool(); v != want || err != nil {
+			t.Errorf("Bool(%q) = %t, %v; want %t", arg, v, err, want)
+		}
+	}
+	if v, err := (Option{Name: "verbose"}).Bool(); !v || err != nil {
+		t.Errorf("Bool without argument = %t, %v; want true", v, err)
+	}
+}
+
+func TestOptionAccessorErrors(t *testing.T) {
+	o := Option{Name: "port", HasArg: true, Arg: "http"}
+	tests := []struct {
+		name string
+		call func() error
+		typ  string
+	}{
+		{"int", func() error { _, err := o.Int(); return err }, "int"},
+		{"bool", func() error { _, err := o.Bool(); return err }, "bool"},
+		{"float", func() error { _, err := o.Float(); return err }, "float"},
+		{"duration", func() error { _, err := o.Duration(); return err }, "duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var invalid *InvalidArgumentError
+			if !errors.As(err, &invalid) || invalid.Type != tt.typ || invalid.Name != "port" || invalid.Arg != "http" {
+				t.Fatalf("error = %#v, want *InvalidArgumentError for %s", err, tt.typ)
+			}
+			if want := `invalid ` + tt.typ + ` argument for option port: "http"`; err.Error() != want {
+				t.Errorf("Error() = %q, want %q", err.Error(), want)
+			}
+		})
+	}
+
+	_, err := Option{Name: "n", HasArg: true, Arg: "99999999999999999999"}.Int()
+	if !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("Int overflow error = %v, want to wrap strconv.ErrRange", err)
+	}
+}
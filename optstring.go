@@ -0,0 +1,166 @@
+package optargs
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Behavior prefixes of an optstring. They must come before any option
+// character; see [GetOpt].
+const (
+	// OptStringSilent suppresses error reports, as [ErrorSilent] does.
+	OptStringSilent = ":"
+	// OptStringPosixlyCorrect stops option parsing at the first
+	// non-option, as if POSIXLY_CORRECT were set.
+	OptStringPosixlyCorrect = "+"
+	// OptStringNonOpts yields each non-option in order as the argument of
+	// an option with the name "\x01"; see [ParseNonOpts].
+	OptStringNonOpts = "-"
+)
+
+// OptStringGNUWords is the optstring entry that makes -W foo parse as
+// --foo, per the GNU getopt(3) extension.
+const OptStringGNUWords = "W;"
+
+// ShortSpec describes one short option of an optstring.
+type ShortSpec struct {
+	Name   rune    // option character, ASCII or a multibyte UTF-8 character
+	HasArg ArgType // argument requirement, written as "", ":" or "::"
+}
+
+// String returns the optstring entry for s, such as "a", "b:" or "c::".
+func (s ShortSpec) String() string {
+	switch s.HasArg {
+	case RequiredArgument:
+		return string(s.Name) + ":"
+	case OptionalArgument:
+		return string(s.Name) + "::"
+	}
+	return string(s.Name)
+}
+
+// validate reports whether s can appear in an optstring.
+func (s ShortSpec) validate() error {
+	switch r := s.Name; {
+	case r == ':' || r == '-' || r == ';':
+		return fmt.Errorf("prohibited short option: %c", r)
+	case r == utf8.RuneError || !utf8.ValidRune(r) || unicode.IsSpace(r) || !unicode.IsGraphic(r):
+		return fmt.Errorf("invalid short option: %q", r)
+	}
+	if s.HasArg < NoArgument || s.HasArg > OptionalArgument {
+		return fmt.Errorf("short option %c: invalid argument type %d", s.Name, s.HasArg)
+	}
+	return nil
+}
+
+// OptString builds the option characters of an optstring from specs, in
+// order, so that generated code need not assemble colons by hand. Behavior
+// prefixes and [OptStringGNUWords] are concatenated by the caller:
+//
+//	optargs.OptStringSilent + optargs.OptString(
+//		optargs.ShortSpec{Name: 'v'},
+//		optargs.ShortSpec{Name: 'o', HasArg: optargs.RequiredArgument},
+//	)
+//
+// OptString panics if a spec names a prohibited or non-graphic character,
+// has an unknown ArgType, or repeats an earlier option; the result always
+// passes [ValidateOptString].
+func OptString(specs ...ShortSpec) string {
+	var b strings.Builder
+	seen := make(map[rune]bool, len(specs))
+	for _, s := range specs {
+		if err := s.validate(); err != nil {
+			panic("optargs: OptString: " + err.Error())
+		}
+		if seen[s.Name] {
+			panic(fmt.Sprintf("optargs: OptString: duplicate short option: %c", s.Name))
+		}
+		seen[s.Name] = true
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
+
+// ValidateOptString reports the first error [GetOpt] would return for
+// optstring, and additionally rejects an option character given twice,
+// which GetOpt accepts by letting the later definition win.
+func ValidateOptString(optstring string) error {
+	specs, _, err := scanOptString(strings.TrimLeft(optstring, OptStringSilent+OptStringPosixlyCorrect+OptStringNonOpts))
+	if err != nil {
+		return err
+	}
+	seen := make(map[rune]bool, len(specs))
+	for _, s := range specs {
+		if seen[s.Name] {
+			return fmt.Errorf("duplicate short option: %c", s.Name)
+		}
+		seen[s.Name] = true
+	}
+	return nil
+}
+
+// scanOptString parses the option characters of an optstring, after its
+// behavior prefixes, according to the libc getopt() spec. It reports
+// whether the optstring contains "W;", which is returned as a W option
+// with a required argument.
+func scanOptString(optstring string) (specs []ShortSpec, gnuWords bool, err error) {
+	for len(optstring) > 0 {
+		if debug {
+			slog.Debug("GetOpt", "optstring", optstring, "len", len(optstring))
+		}
+
+		c := optstring[0]
+		r, n := utf8.DecodeRuneInString(optstring)
+		optstring = optstring[n:]
+		if n > 1 {
+			if unicode.IsSpace(r) || !unicode.IsGraphic(r) {
+				return nil, false, errors.New("invalid short option: " + string(r))
+			}
+		} else if r == utf8.RuneError || !isGraph(c) {
+			return nil, false, errors.New("invalid short option: " + byteString(c))
+		}
+
+		if debug {
+			slog.Debug("GetOpt", "c", byteString(c), "optstring", optstring, "len", len(optstring))
+		}
+		switch c {
+		case ':', '-', ';': // Disallowed by the spec
+			return nil, false, errors.New("prohibited short option: " + byteString(c))
+		}
+
+		// look ahead to see if c is followed by ":" or "::"
+		var hasArg ArgType
+		switch {
+		case len(optstring) > 1 && optstring[0] == ':' && optstring[1] == ':':
+			if debug {
+				slog.Debug("GetOpt", "c", byteString(c), "hasArg", "optional")
+			}
+			hasArg = OptionalArgument
+			optstring = optstring[2:]
+		case len(optstring) > 0 && optstring[0] == ':':
+			if debug {
+				slog.Debug("GetOpt", "c", byteString(c), "hasArg", "required")
+			}
+			hasArg = RequiredArgument
+			optstring = optstring[1:]
+		case c == 'W' && len(optstring) > 0 && optstring[0] == ';':
+			if debug {
+				slog.Debug("GetOpt", "c", byteString(c), "gnuWords", true)
+			}
+			gnuWords = true
+			hasArg = RequiredArgument
+			optstring = optstring[1:]
+		default:
+			if debug {
+				slog.Debug("GetOpt", "c", byteString(c), "hasArg", "none")
+			}
+			hasArg = NoArgument
+		}
+		specs = append(specs, ShortSpec{Name: r, HasArg: hasArg})
+	}
+	return specs, gnuWords, nil
+}
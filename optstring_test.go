@@ -0,0 +1,98 @@
+package optargs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOptString(t *testing.T) {
+	got := OptStringSilent + OptString(
+		ShortSpec{Name: 'v'},
+		ShortSpec{Name: 'o', HasArg: RequiredArgument},
+		ShortSpec{Name: 'c', HasArg: OptionalArgument},
+		ShortSpec{Name: 'ä', HasArg: RequiredArgument},
+		ShortSpec{Name: '='},
+	) + OptStringGNUWords
+	if want := ":vo:c::ä:=W;"; got != want {
+		t.Fatalf("OptString = %q, want %q", got, want)
+	}
+	if err := ValidateOptString(got); err != nil {
+		t.Fatalf("ValidateOptString(%q) = %v", got, err)
+	}
+
+	p, err := GetOpt([]string{"-vofile", "-ä", "x", "-c", "-="}, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, requireParsedOptions(t, p), []Option{
+		{Name: "v"},
+		{Name: "o", HasArg: true, Arg: "file"},
+		{Name: "ä", HasArg: true, Arg: "x"},
+		{Name: "c", HasArg: true, Arg: "-="},
+	})
+}
+
+func TestOptStringPanics(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []ShortSpec
+		want  string
+	}{
+		{"colon", []ShortSpec{{Name: ':'}}, "prohibited short option: :"},
+		{"dash", []ShortSpec{{Name: '-'}}, "prohibited short option: -"},
+		{"semicolon", []ShortSpec{{Name: ';'}}, "prohibited short option: ;"},
+		{"space", []ShortSpec{{Name: ' '}}, "invalid short option"},
+		{"control", []ShortSpec{{Name: '\x01'}}, "invalid short option"},
+		{"zero", []ShortSpec{{}}, "invalid short option"},
+		{"arg type", []ShortSpec{{Name: 'a', HasArg: 7}}, "invalid argument type 7"},
+		{"duplicate", []ShortSpec{{Name: 'a'}, {Name: 'a', HasArg: RequiredArgument}}, "duplicate short option: a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.HasPrefix(msg, "optargs: OptString: ") || !strings.Contains(msg, tt.want) {
+					t.Fatalf("panic = %q, want %q", msg, tt.want)
+				}
+			}()
+			OptString(tt.specs...)
+		})
+	}
+}
+
+func TestValidateOptString(t *testing.T) {
+	tests := []struct {
+		optstring string
+		wantErr   string
+	}{
+		{optstring: ""},
+		{optstring: ":+-"},
+		{optstring: "ab:c::"},
+		{optstring: "-:ab"},
+		{optstring: "W;vä::"},
+		{optstring: "a+="},
+		{optstring: "a:::", wantErr: "prohibited short option: :"},
+		{optstring: "ab-", wantErr: "prohibited short option: -"},
+		{optstring: "a;", wantErr: "prohibited short option: ;"},
+		{optstring: "a b", wantErr: "invalid short option:  "},
+		{optstring: "a\xc3", wantErr: "invalid short option"},
+		{optstring: "ab:a", wantErr: "duplicate short option: a"},
+		{optstring: "äbä::", wantErr: "duplicate short option: ä"},
+	}
+	for _, tt := range tests {
+		err := ValidateOptString(tt.optstring)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("ValidateOptString(%q) = %v", tt.optstring, err)
+		case tt.wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.wantErr)):
+			t.Errorf("ValidateOptString(%q) = %v, want %q", tt.optstring, err, tt.wantErr)
+		}
+		// Apart from duplicates, GetOpt must agree.
+		if strings.HasPrefix(tt.wantErr, "duplicate") {
+			continue
+		}
+		if _, gerr := GetOpt(nil, tt.optstring); (gerr == nil) != (err == nil) {
+			t.Errorf("GetOpt(%q) = %v, ValidateOptString = %v", tt.optstring, gerr, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package optargs
+
+import "strings"
+
+// OSArgs returns the program's command-line arguments, excluding the
+// program name, in a form suitable for passing to [GetOpt] and friends.
+//
+// On Windows the raw command line is re-read with GetCommandLineW and split
+// with [SplitCommandLine], so quoting behaves exactly as the Microsoft C
+// runtime's wmain would see it regardless of how the Go runtime split it.
+// On all other platforms it returns os.Args[1:]. With no arguments the
+// result is an empty, non-nil slice on every platform.
+func OSArgs() []string {
+	return osArgs()
+}
+
+// SplitCommandLine splits a Windows command line into an argv slice using
+// the rules of the Microsoft C runtime (UCRT, 2008 and later):
+//
+//   - The program name (argv[0]) ends at the first space or tab, or, when
+//     it begins with a double quote, at the next double quote. Backslashes
+//     in the program name are never escapes.
+//   - Arguments are separated by spaces and tabs outside double quotes.
+//   - 2n backslashes followed by a double quote produce n backslashes and
+//     the quote toggles quoting; 2n+1 backslashes followed by a double quote
+//     produce n backslashes and a literal double quote.
+//   - Backslashes not followed by a double quote are literal.
+//   - Inside a quoted region, "" produces a literal double quote and the
+//     region continues.
+//
+// SplitCommandLine is available on every platform so tools can reproduce
+// Windows argument semantics in tests.
+func SplitCommandLine(cmdline string) []string {
+	var argv []string
+
+	// argv[0]: no escape processing.
+	if cmdline != "" {
+		var name string
+		if cmdline[0] == '"' {
+			end := strings.IndexByte(cmdline[1:], '"')
+			if end < 0 {
+				name, cmdline = cmdline[1:], ""
+			} else {
+				name, cmdline = cmdline[1:end+1], cmdline[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(cmdline, " \t")
+			if end < 0 {
+				end = len(cmdline)
+			}
+			name, cmdline = cmdline[:end], cmdline[end:]
+		}
+		argv = append(argv, name)
+	}
+
+	var b strings.Builder
+	inArg, inQuote := false, false
+	for i := 0; i < len(cmdline); i++ {
+		c := cmdline[i]
+		switch {
+		case (c == ' ' || c == '\t') && !inQuote:
+			if inArg {
+				argv = append(argv, b.String())
+				b.Reset()
+				inArg = false
+			}
+
+		case c == '\\':
+			inArg = true
+			n := 1
+			for i+1 < len(cmdline) && cmdline[i+1] == '\\' {
+				n++
+				i++
+			}
+			if i+1 < len(cmdline) && cmdline[i+1] == '"' {
+				b.WriteString(strings.Repeat(`\`, n/2))
+				if n%2 == 1 {
+					b.WriteByte('"')
+					i++
+				}
+				continue
+			}
+			b.WriteString(strings.Repeat(`\`, n))
+
+		case c == '"':
+			inArg = true
+			if inQuote && i+1 < len(cmdline) && cmdline[i+1] == '"' {
+				b.WriteByte('"')
+				i++
+				continue
+			}
+			inQuote = !inQuote
+
+		default:
+			inArg = true
+			b.WriteByte(c)
+		}
+	}
+	if inArg {
+		argv = append(argv, b.String())
+	}
+	return argv
+}
@@ -0,0 +1,12 @@
+//go:build !windows
+
+package optargs
+
+import "os"
+
+func osArgs() []string {
+	if len(os.Args) < 2 {
+		return []string{}
+	}
+	return os.Args[1:]
+}
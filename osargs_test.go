@@ -0,0 +1,60 @@
+package optargs
+
+import (
+	"os"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// TestSplitCommandLine checks the Microsoft C runtime examples from
+// "Parsing C++ command-line arguments" plus program-name edge cases.
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmdline string
+		want    []string
+	}{
+		{"empty", ``, nil},
+		{"program only", `prog`, []string{"prog"}},
+		{"quoted program with spaces", `"C:\Program Files\x.exe" -a`, []string{`C:\Program Files\x.exe`, "-a"}},
+		{"program backslashes literal", `C:\dir\x.exe\" -a`, []string{`C:\dir\x.exe\"`, "-a"}},
+		{"quoted arg", `prog "a b c" d e`, []string{"prog", "a b c", "d", "e"}},
+		{"even backslashes then quote", `prog a\\b d"e f"g h`, []string{"prog", `a\\b`, "de fg", "h"}},
+		{"odd backslashes then quote", `prog a\\\"b c d`, []string{"prog", `a\"b`, "c", "d"}},
+		{"even backslashes open quote", `prog a\\\\"b c" d e`, []string{"prog", `a\\b c`, "d", "e"}},
+		{"doubled quote inside quotes", `prog a"b"" c d`, []string{"prog", `ab" c d`}},
+		{"empty quoted arg", `prog "" x`, []string{"prog", "", "x"}},
+		{"tabs and runs of spaces", "prog \t a   b\t", []string{"prog", "a", "b"}},
+		{"unterminated quote", `prog "a b`, []string{"prog", "a b"}},
+		{"trailing backslashes", `prog a\\`, []string{"prog", `a\\`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitCommandLine(tt.cmdline)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SplitCommandLine(%q) = %q, want %q", tt.cmdline, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOSArgs(t *testing.T) {
+	if got := OSArgs(); len(got) != len(os.Args)-1 {
+		t.Errorf("OSArgs() has %d entries, want %d", len(got), len(os.Args)-1)
+	}
+}
+
+func TestOSArgsEmpty(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows reads the command line, not os.Args")
+	}
+	saved := os.Args
+	defer func() { os.Args = saved }()
+	for _, args := range [][]string{nil, {"prog"}} {
+		os.Args = args
+		if got := OSArgs(); got == nil || len(got) != 0 {
+			t.Errorf("OSArgs() with os.Args %q = %#v, want an empty, non-nil slice", args, got)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package optargs
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// osArgs re-splits the raw UTF-16 command line with SplitCommandLine,
+// falling back to os.Args when it is unavailable.
+func osArgs() []string {
+	p := syscall.GetCommandLine()
+	if p == nil {
+		if len(os.Args) < 2 {
+			return []string{}
+		}
+		return os.Args[1:]
+	}
+	n := 0
+	for *(*uint16)(unsafe.Add(unsafe.Pointer(p), n*2)) != 0 {
+		n++
+	}
+	argv := SplitCommandLine(syscall.UTF16ToString(unsafe.Slice(p, n)))
+	if len(argv) == 0 {
+		return []string{}
+	}
+	return argv[1:]
+}
@@ -3,10 +3,13 @@ package optargs
 import (
 	"errors"
 	"fmt"
+	"io"
 	"iter"
 	"log/slog"
+	"slices"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // ParseMode controls how non-option arguments are handled during parsing.
@@ -16,21 +19,54 @@ const (
 	// ParseDefault permutes arguments so that non-options are moved to the end.
 	ParseDefault ParseMode = iota
 	// ParseNonOpts treats each non-option argument as an argument to a
-	// synthetic option with character code 1.
+	// synthetic option with character code 1, yielding operands in order
+	// among the options (GNU RETURN_IN_ORDER); see [Option.IsOperand].
 	ParseNonOpts
 	// ParsePosixlyCorrect stops option processing at the first non-option argument.
 	ParsePosixlyCorrect
 )
 
+// ErrorMode controls whether the parser reports errors as diagnostics in
+// addition to returning them through the iterator.
+type ErrorMode int
+
+const (
+	// ErrorSilent only returns errors; the caller decides what to print.
+	// It is the zero value, used by [NewParser] unless configured
+	// otherwise, and selected for getopt parsers by a leading ':' in the
+	// optstring.
+	ErrorSilent ErrorMode = iota
+	// ErrorReport also writes each error as a diagnostic line, like
+	// getopt(3) printing to stderr. Diagnostics go to the writer set with
+	// SetErrorWriter, or to the default slog logger when none is set.
+	// This is the default for [GetOpt], [GetOptLong], and [GetOptLongOnly].
+	ErrorReport
+)
+
 // ParserConfig holds configuration for a Parser instance.
 // All fields are unexported; configuration is set via optstring prefix
 // flags and constructor parameters, or via setter methods.
+//
+// The zero value is the configuration of a plain [NewParser]: permuting
+// arguments, case-sensitive options and commands, and silent errors.
+// Callers composing a parser directly start from it and call setters:
+//
+//	var config optargs.ParserConfig
+//	config.SetLongCaseIgnore(true)
+//	config.SetParseMode(optargs.ParsePosixlyCorrect)
+//	p, err := optargs.NewParser(config, shortOpts, longOpts, os.Args[1:])
 type ParserConfig struct {
-	enableErrors bool
-	parseMode    ParseMode
+	errorMode   ErrorMode
+	errorWriter io.Writer
+	parseMode   ParseMode
 
 	shortCaseIgnore bool
+	shortOptEquals  bool
 	gnuWords        bool
+	numericOpts     bool
+	negativeNums    bool
+	plusOpts        bool
+	slashOpts       bool
 
 	longCaseIgnore bool
 	longOptsOnly   bool
@@ -43,6 +79,26 @@ type ParserConfig struct {
 	// unknown options in a subcommand are not resolved by walking the
 	// parent chain. Automatically enabled when POSIXLY_CORRECT is set.
 	strictSubcommands bool
+
+	// commandFirst requires the first non-option argument to be a
+	// registered command; see SetCommandFirst.
+	commandFirst bool
+
+	// sanitizer, when non-nil, vets every argument before option
+	// processing begins; see Parser.SetSanitizer.
+	sanitizer func(arg string) error
+
+	// onDeprecated, when non-nil, replaces the default warning for
+	// deprecated options; see Parser.SetDeprecationHandler.
+	onDeprecated func(name string, flag *Flag)
+
+	// onOperand, when non-nil, consumes non-option arguments; see
+	// Parser.SetOperandHandler.
+	onOperand func(arg string, index int) error
+
+	// onGrammar, when non-nil, consumes regions of arguments before they
+	// are classified; see Parser.SetOperandGrammar.
+	onGrammar func(args []string, index int) (int, error)
 }
 
 // SetLongOnly enables or disables getopt_long_only(3) behavior.
@@ -73,16 +129,202 @@ func (c *ParserConfig) Interspersed() bool {
 	return c.parseMode == ParseDefault
 }
 
+// SetParseMode selects how non-option arguments are handled, as the '+'
+// and '-' optstring prefixes do. It covers ParseNonOpts, which
+// SetInterspersed cannot express.
+func (c *ParserConfig) SetParseMode(mode ParseMode) {
+	c.parseMode = mode
+}
+
+// ParseMode returns how non-option arguments are handled.
+func (c *ParserConfig) ParseMode() ParseMode {
+	return c.parseMode
+}
+
+// SetErrorMode selects whether errors are reported as diagnostics.
+func (c *ParserConfig) SetErrorMode(mode ErrorMode) {
+	c.errorMode = mode
+}
+
+// ErrorMode returns the error reporting mode.
+func (c *ParserConfig) ErrorMode() ErrorMode {
+	return c.errorMode
+}
+
+// SetErrorWriter directs ErrorReport diagnostics to w, one line per
+// error. A nil w restores the default slog logger.
+func (c *ParserConfig) SetErrorWriter(w io.Writer) {
+	c.errorWriter = w
+}
+
+// SetShortOptEquals makes an argument attached to a short option drop one
+// leading '=', so -o=file and -ofile both give "file". getopt(3) keeps the
+// '=' as part of the argument; this is for wrappers of libraries that
+// accept the -o=value spelling.
+func (c *ParserConfig) SetShortOptEquals(enabled bool) {
+	c.shortOptEquals = enabled
+}
+
+// ShortOptEquals returns whether attached short-option arguments drop a
+// leading '='.
+func (c *ParserConfig) ShortOptEquals() bool {
+	return c.shortOptEquals
+}
+
+// SetNumericOptions makes an argument of a dash followed only by digits,
+// such as -5 in head -5, a single numeric option: it is yielded with the
+// Name [NumericName] and the digits as Arg, like the classic -number
+// spelling of head, tail, and nice. A word whose first digit is a
+// registered short option is parsed as short options as usual.
+func (c *ParserConfig) SetNumericOptions(enabled bool) {
+	c.numericOpts = enabled
+}
+
+// NumericOptions returns whether -<digits> is parsed as a numeric option.
+func (c *ParserConfig) NumericOptions() bool {
+	return c.numericOpts
+}
+
+// SetNegativeNumbers makes an argument that reads as a negative number,
+// such as -42, -3.14, or -1e-9, an operand rather than a cluster of short
+// options, so numeric programs can take negative values. It applies only
+// when the first character after the dash is not a registered short
+// option, and with SetNumericOptions also enabled -<digits> remains a
+// numeric option.
+func (c *ParserConfig) SetNegativeNumbers(enabled bool) {
+	c.negativeNums = enabled
+}
+
+// NegativeNumbers returns whether negative numbers are parsed as operands.
+func (c *ParserConfig) NegativeNumbers() bool {
+	return c.negativeNums
+}
+
+// SetPlusOptions enables shell-style toggles, as in set -x and set +x:
+// short options may also be given with a '+' prefix, alone or grouped
+// (+ab), and every yielded Option carries Enabled, false for an option
+// given with '+' and true otherwise. A Flag.Handle receives the name of a
+// '+' option with its '+', as in "+x". A lone "+" stays an operand.
+func (c *ParserConfig) SetPlusOptions(enabled bool) {
+	c.plusOpts = enabled
+}
+
+// PlusOptions returns whether '+'-prefixed toggles are parsed.
+func (c *ParserConfig) PlusOptions() bool {
+	return c.plusOpts
+}
+
+// SetSlashOptions enables Windows-style options alongside the dash forms:
+// /x names the short option x when one is registered and /name a long
+// option otherwise, with unique prefixes accepted as for --name. A value
+// follows the first ':', as in /out:file or /x:3; without one, a required
+// argument is taken from the next argument and an optional one is left
+// unset. A lone "/" stays an operand, and so does any argument after
+// "--", which is how operands that are absolute paths are passed.
+func (c *ParserConfig) SetSlashOptions(enabled bool) {
+	c.slashOpts = enabled
+}
+
+// SlashOptions returns whether '/'-prefixed options are parsed.
+func (c *ParserConfig) SlashOptions() bool {
+	return c.slashOpts
+}
+
+// SetCommandFirst requires the first non-option argument to name a
+// registered command when the parser has any. Otherwise an operand ahead
+// of the command (prog file.txt db) is collected silently and the command
+// is never dispatched; with this set, Options yields an
+// [UnknownCommandError] for file.txt instead.
+func (c *ParserConfig) SetCommandFirst(enabled bool) {
+	c.commandFirst = enabled
+}
+
+// CommandFirst returns whether the first operand must be a command.
+func (c *ParserConfig) CommandFirst() bool {
+	return c.commandFirst
+}
+
 // SetCommandCaseIgnore enables or disables case-insensitive command matching.
 func (c *ParserConfig) SetCommandCaseIgnore(enabled bool) {
 	c.commandCaseIgnore = enabled
 }
 
+// CommandCaseIgnore returns whether command matching ignores case.
+func (c *ParserConfig) CommandCaseIgnore() bool {
+	return c.commandCaseIgnore
+}
+
+// SetShortCaseIgnore makes short options match regardless of case, so -V
+// finds a registered -v when no -V exists.
+func (c *ParserConfig) SetShortCaseIgnore(enabled bool) {
+	c.shortCaseIgnore = enabled
+}
+
+// ShortCaseIgnore returns whether short options match regardless of case.
+func (c *ParserConfig) ShortCaseIgnore() bool {
+	return c.shortCaseIgnore
+}
+
+// SetLongCaseIgnore makes long options match regardless of case, as the
+// getopt constructors do.
+func (c *ParserConfig) SetLongCaseIgnore(enabled bool) {
+	c.longCaseIgnore = enabled
+}
+
+// LongCaseIgnore returns whether long options match regardless of case.
+func (c *ParserConfig) LongCaseIgnore() bool {
+	return c.longCaseIgnore
+}
+
+// SetGNUWords enables the GNU -W extension, as "W;" in an optstring does:
+// the short option W, registered with a required argument, is yielded
+// under the name of its argument, so -W foo yields an option named foo.
+func (c *ParserConfig) SetGNUWords(enabled bool) {
+	c.gnuWords = enabled
+}
+
+// GNUWords returns whether the GNU -W extension is enabled.
+func (c *ParserConfig) GNUWords() bool {
+	return c.gnuWords
+}
+
+// SetStrictSubcommands stops subcommands registered with AddCmd from
+// inheriting parent options; see [Parser.SetStrictSubcommands].
+func (c *ParserConfig) SetStrictSubcommands(strict bool) {
+	c.strictSubcommands = strict
+}
+
+// StrictSubcommands returns whether subcommands are kept from inheriting
+// parent options.
+func (c *ParserConfig) StrictSubcommands() bool {
+	return c.strictSubcommands
+}
+
 // Parser is the core argument parser. It processes command-line arguments
 // according to POSIX getopt(3) and GNU getopt_long(3) conventions.
 //
-// Args holds the remaining unprocessed arguments. After iteration completes,
-// Args contains non-option arguments (and any arguments after "--").
+// Args holds the arguments still to be processed. It is only meaningful
+// between iterations: before [Parser.Options] is first ranged over it holds
+// the full input, and once iteration ends it holds the non-option arguments
+// (permuted operands first, then everything after "--" or the first operand
+// in POSIX mode). While iteration is in progress Args is an internal
+// cursor; use [Parser.Remaining] to obtain it with the contract enforced.
+//
+// A range loop may exit early, including right after an error. The
+// argument that produced the last yielded option or error has then been
+// consumed, and Args holds the collected operands followed by the
+// unprocessed arguments; the rest of a compacted short group such as
+// "-abc" stays pending. [Parser.Resume] or a later Options call continues
+// from there and yields exactly what the loop would have yielded next,
+// without examining the collected operands again. Counts, values, and
+// handler side effects of the options already parsed are kept. Two
+// errors consume nothing beyond what they report: a sanitizer rejection
+// (see [Parser.SetSanitizer]) is reported again on resumption, and a
+// subcommand whose preparation failed has still been dispatched.
+//
+// A nil or empty argument list is valid, so a parser can be built before
+// its arguments are known: Options yields nothing, and Args, Remaining,
+// and SplitArgs return an empty, non-nil slice.
 //
 // Commands holds registered subcommands. Use [Parser.AddCmd] to register
 // subcommands; do not manipulate Commands directly.
@@ -93,8 +335,21 @@ type Parser struct {
 	shortOptN int        // number of registered short options
 	longOpts  map[string]*Flag
 
+	// shortClasses holds the flags registered with AddShortClass, in
+	// registration order.
+	shortClasses []*Flag
+
+	// runeOpts holds the short options whose character is not ASCII,
+	// such as -ä; see NewParserRunes.
+	runeOpts map[rune]*Flag
+
+	// longAliases maps each Flag.Aliases entry to the long option name
+	// the flag is registered under.
+	longAliases map[string]string
+
 	// longOptsLower maps strings.ToLower(name) → *Flag for O(1)
-	// case-insensitive lookup. Only populated when longCaseIgnore is true.
+	// case-insensitive lookup, aliases included. Only populated when
+	// longCaseIgnore is true.
 	longOptsLower map[string]*Flag
 
 	config ParserConfig
@@ -110,6 +365,62 @@ type Parser struct {
 	// Active subcommand tracking — set during Options() when command dispatch succeeds
 	activeCmd       string  // name of dispatched subcommand
 	activeCmdParser *Parser // parser of dispatched subcommand
+
+	// Iteration state, consulted by Remaining.
+	iterating bool // an Options() range loop is in progress
+	iterDone  bool // the last Options() range loop ran to completion
+
+	// probing is set while tryLongOnly looks a word up as a long option
+	// it may yet parse as short options, so that the unknown option
+	// error it may discard is built without suggestions.
+	probing bool
+
+	// Resumption state after a range loop exited early: Args[:collected]
+	// are operands it already collected, when wordAt is non-zero the
+	// short-option group Args[0] continues at byte wordAt, and sawOperand
+	// records that an operand was reached.
+	collected  int
+	wordAt     int
+	sawOperand bool
+
+	// Terminator state, consulted by SplitArgs. When terminated is set,
+	// Args[restAt:] are the arguments that followed "--".
+	terminated bool
+	restAt     int
+
+	// counts tallies how often each flag was parsed; see Count.
+	counts map[*Flag]int
+
+	// occurrences lists each parsed option with its argument, in command
+	// line order; see Values.
+	occurrences []occurrence
+
+	// exclusive holds the groups declared with MutuallyExclusive, and
+	// dependencies the declarations made with Requires.
+	exclusive    [][]*Flag
+	dependencies []dependency
+
+	// trace receives iteration events; see SetTrace. dispatchedBy is the
+	// parser that last dispatched p as a subcommand.
+	trace        func(TraceEvent)
+	dispatchedBy *Parser
+
+	// middleware wraps handler calls; see Use.
+	middleware []func(next HandlerFunc) HandlerFunc
+
+	// onCommand holds the callbacks registered with OnCommand, keyed by
+	// the subcommand parser so that aliases share them.
+	onCommand map[*Parser]func(child *Parser) error
+
+	// defaultCmd names the subcommand dispatched when no command is
+	// given; see SetDefaultCmd.
+	defaultCmd string
+
+	// Argument positions, for Option.Index: argBase is the original index
+	// of Args[0] when iteration starts, at which point Args had argStart
+	// elements.
+	argBase  int
+	argStart int
 }
 
 // NewParser creates a Parser from pre-built configuration, short option map,
@@ -143,6 +454,9 @@ type Parser struct {
 // complementary: NewParser for construction-time setup, SetHandler variants
 // for post-construction attachment.
 func NewParser(config ParserConfig, shortOpts map[byte]*Flag, longOpts map[string]*Flag, args []string) (*Parser, error) {
+	if args == nil {
+		args = []string{}
+	}
 	parser := Parser{
 		Args:    args,
 		nonOpts: make([]string, 0, 8),
@@ -150,15 +464,9 @@ func NewParser(config ParserConfig, shortOpts map[byte]*Flag, longOpts map[strin
 	}
 
 	for c, flag := range shortOpts {
-		if !isGraph(c) {
-			return nil, parser.optErrorf("invalid short option: %c", c)
-		}
-		switch c {
-		case ':', ';', '-':
-			return nil, parser.optErrorf("prohibited short option: %c", c)
+		if err := parser.addShortOpt(c, flag); err != nil {
+			return nil, err
 		}
-		parser.shortOpts[c] = flag
-		parser.shortOptN++
 	}
 
 	for s := range longOpts {
@@ -170,12 +478,23 @@ func NewParser(config ParserConfig, shortOpts map[byte]*Flag, longOpts map[strin
 	}
 	parser.longOpts = longOpts
 
+	for name, flag := range longOpts {
+		for _, alias := range flag.Aliases {
+			if err := parser.addLongAlias(alias, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Build lowercased shadow map for O(1) case-insensitive lookup.
 	if config.longCaseIgnore && len(longOpts) > 0 {
-		parser.longOptsLower = make(map[string]*Flag, len(longOpts))
+		parser.longOptsLower = make(map[string]*Flag, len(longOpts)+len(parser.longAliases))
 		for name, flag := range longOpts {
 			parser.longOptsLower[strings.ToLower(name)] = flag
 		}
+		for alias, name := range parser.longAliases {
+			parser.longOptsLower[strings.ToLower(alias)] = longOpts[name]
+		}
 	}
 
 	// Initialize command registry
@@ -184,6 +503,42 @@ func NewParser(config ParserConfig, shortOpts map[byte]*Flag, longOpts map[strin
 	return &parser, nil
 }
 
+// addShortOpt registers flag as the short option c.
+func (p *Parser) addShortOpt(c byte, flag *Flag) error {
+	if !isGraph(c) {
+		return p.optErrorf("invalid short option: %c", c)
+	}
+	switch c {
+	case ':', ';', '-':
+		return p.optErrorf("prohibited short option: %c", c)
+	}
+	p.shortOpts[c] = flag
+	p.shortOptN++
+	return nil
+}
+
+// addLongAlias registers alias as another spelling of the long option
+// name. An alias may not collide with a long option or with an alias of a
+// different option.
+func (p *Parser) addLongAlias(alias, name string) error {
+	if alias == "" || strings.IndexFunc(alias, func(r rune) bool {
+		return unicode.IsSpace(r) || !unicode.IsGraphic(r)
+	}) >= 0 {
+		return p.optErrorf("invalid long option alias: %s", alias)
+	}
+	if _, ok := p.longOpts[alias]; ok {
+		return p.optErrorf("long option alias conflicts with option: %s", alias)
+	}
+	if other, ok := p.longAliases[alias]; ok && other != name {
+		return p.optErrorf("long option alias %s is shared by %s and %s", alias, other, name)
+	}
+	if p.longAliases == nil {
+		p.longAliases = make(map[string]string)
+	}
+	p.longAliases[alias] = name
+	return nil
+}
+
 // NewParserWithCaseInsensitiveCommands creates a new parser with case insensitive
 // command matching enabled.
 func NewParserWithCaseInsensitiveCommands(
@@ -195,11 +550,22 @@ func NewParserWithCaseInsensitiveCommands(
 	return NewParser(config, shortOpts, longOpts, args)
 }
 
-func (p *Parser) optError(msg string) error {
-	if p.config.enableErrors {
-		slog.Error(msg)
+// report writes err as a diagnostic when the parser is in ErrorReport mode.
+func (p *Parser) report(err error) {
+	if p.config.errorMode != ErrorReport {
+		return
+	}
+	if p.config.errorWriter != nil {
+		fmt.Fprintln(p.config.errorWriter, err)
+		return
 	}
-	return errors.New(msg)
+	slog.Error(err.Error())
+}
+
+func (p *Parser) optError(msg string) error {
+	err := errors.New(msg)
+	p.report(err)
+	return err
 }
 
 func (p *Parser) optErrorf(msg string, args ...any) error {
@@ -212,17 +578,38 @@ func (p *Parser) optErrorf(msg string, args ...any) error {
 
 func (p *Parser) unknownOptionError(name string, isShort bool) error {
 	err := &UnknownOptionError{Name: name, IsShort: isShort}
-	if p.config.enableErrors {
-		slog.Error(err.Error())
+	p.report(err)
+	return err
+}
+
+// unknownLongOptionError reports an unknown long option word, keeping a
+// value attached with '=' apart from the name.
+func (p *Parser) unknownLongOptionError(word string) error {
+	err := &UnknownOptionError{Name: word}
+	if name, value, ok := strings.Cut(word, "="); ok {
+		err.Name, err.Value, err.HasValue = name, value, true
+	}
+	if !p.probing {
+		err.Suggestions = p.SuggestOptions(err.Name)
 	}
+	p.report(err)
+	return err
+}
+
+func (p *Parser) unknownCommandError(name string) error {
+	names := make([]string, 0, len(p.Commands))
+	for cmd := range p.Commands {
+		names = append(names, cmd)
+	}
+	slices.Sort(names)
+	err := &UnknownCommandError{Name: name, Commands: names, Suggestions: p.SuggestCommands(name)}
+	p.report(err)
 	return err
 }
 
 func (p *Parser) missingArgumentError(name string, isShort bool) error {
 	err := &MissingArgumentError{Name: name, IsShort: isShort}
-	if p.config.enableErrors {
-		slog.Error(err.Error())
-	}
+	p.report(err)
 	return err
 }
 
@@ -232,35 +619,20 @@ func (p *Parser) findLongOpt(name string, args []string) ([]string, *Flag, Optio
 	var inlineArg string
 
 	for {
-		// Phase 1: exact match (walk self + ancestors).
-		if m := p.exactMatch(input); m.flag != nil {
-			return p.resolveMatch(m, splitCount > 0, inlineArg, args)
-		}
-
-		// Phase 2: prefix match (walk self + ancestors).
-		matches := p.prefixMatches(input)
-		switch len(matches) {
-		case 1:
-			return p.resolveMatch(matches[0], splitCount > 0, inlineArg, args)
-		case 0:
-			// fall through to rsplit
-		default: // >1
-			names := make([]string, len(matches))
-			for i, m := range matches {
-				names[i] = m.name
-			}
-			err := &AmbiguousOptionError{Name: name, Matches: names}
-			if p.config.enableErrors {
-				slog.Error(err.Error())
-			}
+		// Phases 1 and 2: exact, then prefix match.
+		m, err := p.matchLongOpt(input)
+		if err != nil {
 			return args, nil, Option{}, err
 		}
+		if m.flag != nil {
+			return p.resolveMatch(m, splitCount > 0, inlineArg, args)
+		}
 
 		// Phase 3: rsplit on next rightmost '='.
 		splitCount++
 		left, right, ok := rsplitNth(name, '=', splitCount)
 		if !ok {
-			return args, nil, Option{}, p.unknownOptionError(name, false)
+			return args, nil, Option{}, p.unknownLongOptionError(name)
 		}
 		input = left
 		inlineArg = right
@@ -269,6 +641,31 @@ func (p *Parser) findLongOpt(name string, args []string) ([]string, *Flag, Optio
 
 // Helpers for the two-phase matching algorithm used by findLongOpt.
 
+// matchLongOpt looks name up as a long option of p or an ancestor: an
+// exact match first, then a unique prefix. It returns a zero matchResult
+// when nothing matches and an [AmbiguousOptionError] when several
+// prefixes do.
+func (p *Parser) matchLongOpt(name string) (matchResult, error) {
+	if m := p.exactMatch(name); m.flag != nil {
+		return m, nil
+	}
+	matches := p.prefixMatches(name)
+	switch len(matches) {
+	case 0:
+		return matchResult{}, nil
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = m.name
+	}
+	slices.Sort(names)
+	err := &AmbiguousOptionError{Name: name, Matches: names}
+	p.report(err)
+	return matchResult{}, err
+}
+
 // matchResult pairs a registered option name with its Flag for prefix match collection.
 type matchResult struct {
 	name string
@@ -295,13 +692,27 @@ func rsplitNth(s string, sep byte, n int) (left, right string, ok bool) {
 // Returns the matched result or an empty matchResult with nil flag.
 func (p *Parser) exactMatch(opt string) matchResult {
 	for current := p; current != nil; current = current.parent {
-		if flag, ok := current.longOpts[opt]; ok {
-			return matchResult{name: opt, flag: flag}
-		}
-		if current.longOptsLower != nil {
-			if flag, ok := current.longOptsLower[strings.ToLower(opt)]; ok {
-				return matchResult{name: flag.Name, flag: flag}
+		if m := current.ownExactMatch(opt); m.flag != nil {
+			if m.flag.Disabled {
+				return matchResult{}
 			}
+			return m
+		}
+	}
+	return matchResult{}
+}
+
+// ownExactMatch is exactMatch for the long options registered on p alone.
+func (p *Parser) ownExactMatch(opt string) matchResult {
+	if flag, ok := p.longOpts[opt]; ok {
+		return matchResult{name: opt, flag: flag}
+	}
+	if name, ok := p.longAliases[opt]; ok {
+		return matchResult{name: name, flag: p.longOpts[name]}
+	}
+	if p.longOptsLower != nil {
+		if flag, ok := p.longOptsLower[strings.ToLower(opt)]; ok {
+			return matchResult{name: flag.Name, flag: flag}
 		}
 	}
 	return matchResult{}
@@ -310,14 +721,24 @@ func (p *Parser) exactMatch(opt string) matchResult {
 // prefixMatches walks self → parent collecting all registered long option names
 // that are proper prefix matches for opt (i.e., the registered name starts with
 // opt and is strictly longer). Deduplicates by flag pointer so the same flag
-// registered in both parent and child counts once.
+// registered in both parent and child counts once. A disabled flag
+// matches nothing and hides the ancestors' options of the same names.
 func (p *Parser) prefixMatches(opt string) []matchResult {
 	var results []matchResult
 	seen := make(map[*Flag]struct{})
+	disabled := make(map[string]bool)
 
 	for current := p; current != nil; current = current.parent {
 		for registeredName, flag := range current.longOpts {
-			if _, dup := seen[flag]; dup {
+			if flag.Disabled {
+				disabled[registeredName] = true
+				for _, alias := range flag.Aliases {
+					disabled[alias] = true
+				}
+			}
+		}
+		for registeredName, flag := range current.longOpts {
+			if _, dup := seen[flag]; dup || disabled[registeredName] {
 				continue
 			}
 			if len(registeredName) > len(opt) && hasPrefix(registeredName, opt, current.config.longCaseIgnore) {
@@ -325,6 +746,16 @@ func (p *Parser) prefixMatches(opt string) []matchResult {
 				seen[flag] = struct{}{}
 			}
 		}
+		for alias, registeredName := range current.longAliases {
+			flag := current.longOpts[registeredName]
+			if _, dup := seen[flag]; dup || disabled[alias] {
+				continue
+			}
+			if len(alias) > len(opt) && hasPrefix(alias, opt, current.config.longCaseIgnore) {
+				results = append(results, matchResult{name: registeredName, flag: flag})
+				seen[flag] = struct{}{}
+			}
+		}
 	}
 	return results
 }
@@ -341,7 +772,7 @@ func (p *Parser) resolveMatch(
 		// Inline arg present (from =value split).
 		switch m.flag.HasArg {
 		case NoArgument:
-			return args, nil, Option{}, &UnexpectedArgumentError{Name: m.name}
+			return args, nil, Option{}, &UnexpectedArgumentError{Name: m.name, Value: inlineArg}
 		default: // RequiredArgument, OptionalArgument
 			option.Arg = inlineArg
 			option.HasArg = true
@@ -365,7 +796,7 @@ func (p *Parser) resolveMatch(
 	default: // OptionalArgument
 		// OptionalArgument without inline = does not consume next arg
 		// unless it exists and doesn't start with '-'.
-		if len(args) > 0 && args[0][0] != '-' {
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
 			option.Arg = args[0]
 			option.HasArg = true
 			return args[1:], m.flag, option, nil
@@ -390,56 +821,78 @@ func (p *Parser) findShortOpt(c byte, word string, args []string) ([]string, str
 		if flag == nil {
 			continue
 		}
+		if flag.Disabled {
+			return args, word, nil, Option{}, p.unknownOptionError(byteString(c), true)
+		}
 
-		option := Option{Name: byteString(matched)}
+		return p.shortOptArg(flag, byteString(matched), byteString(c), word, args)
+	}
 
-		switch flag.HasArg {
-		case NoArgument:
-			if debug {
-				slog.Debug("findShortOpt", "hasArg", "none", "c", byteString(c))
-			}
+	if flag := p.matchShortClass(c); flag != nil {
+		return p.shortOptArg(flag, byteString(c), byteString(c), word, args)
+	}
 
-		case RequiredArgument:
-			if debug {
-				slog.Debug("findShortOpt", "hasArg", "required", "c", byteString(c))
-			}
-			switch {
-			case len(word) > 0:
-				option.Arg = word
-				word = ""
-			case len(args) == 0:
-				return args, word, nil, option, p.missingArgumentError(byteString(c), true)
-			default:
-				option.Arg = args[0]
-				args = args[1:]
-			}
-			option.HasArg = true
+	return args, word, nil, Option{}, p.unknownOptionError(byteString(c), true)
+}
 
-		case OptionalArgument:
-			if debug {
-				slog.Debug("findShortOpt", "hasArg", "optional", "c", byteString(c))
-			}
-			if len(word) > 0 {
-				option.Arg = word
-				word = ""
-				option.HasArg = true
-			} else if len(args) > 0 {
-				option.Arg = args[0]
-				args = args[1:]
-				option.HasArg = true
-			}
+// shortOptArg takes the argument of the short option flag, registered as
+// name and typed as typed, from the rest of its word or from args.
+func (p *Parser) shortOptArg(flag *Flag, name, typed, word string, args []string) ([]string, string, *Flag, Option, error) {
+	option := Option{Name: name}
 
+	switch flag.HasArg {
+	case NoArgument:
+		if debug {
+			slog.Debug("findShortOpt", "hasArg", "none", "c", typed)
+		}
+
+	case RequiredArgument:
+		if debug {
+			slog.Debug("findShortOpt", "hasArg", "required", "c", typed)
+		}
+		switch {
+		case len(word) > 0:
+			option.Arg = p.attachedArg(word)
+			word = ""
+		case len(args) == 0:
+			return args, word, nil, option, p.missingArgumentError(typed, true)
 		default:
-			return args, word, nil, option, p.optErrorf("unknown argument type: %d", flag.HasArg)
+			option.Arg = args[0]
+			args = args[1:]
 		}
+		option.HasArg = true
 
+	case OptionalArgument:
 		if debug {
-			slog.Debug("findShortOpt", "args", args, "word", word, "option", option, "err", "yield")
+			slog.Debug("findShortOpt", "hasArg", "optional", "c", typed)
+		}
+		if len(word) > 0 {
+			option.Arg = p.attachedArg(word)
+			word = ""
+			option.HasArg = true
+		} else if len(args) > 0 {
+			option.Arg = args[0]
+			args = args[1:]
+			option.HasArg = true
 		}
-		return args, word, flag, option, nil
+
+	default:
+		return args, word, nil, option, p.optErrorf("unknown argument type: %d", flag.HasArg)
 	}
 
-	return args, word, nil, Option{}, p.unknownOptionError(byteString(c), true)
+	if debug {
+		slog.Debug("findShortOpt", "args", args, "word", word, "option", option, "err", "yield")
+	}
+	return args, word, flag, option, nil
+}
+
+// attachedArg returns the argument attached to a short option, without
+// its leading '=' when ShortOptEquals is enabled.
+func (p *Parser) attachedArg(word string) string {
+	if p.config.shortOptEquals {
+		return strings.TrimPrefix(word, "=")
+	}
+	return word
 }
 
 // lookupShortOpt finds a short option in this parser's shortOpts array,
@@ -484,19 +937,17 @@ func (p *Parser) tryLongOnly(
 ) (matched bool, args []string, flag *Flag, option Option, err error) {
 	// Single-character input prefers the short option when one is
 	// registered, even if the character is a prefix of a long option.
-	if len(word) == 1 {
-		if _, f := p.lookupShortOpt(word[0]); f != nil {
-			restored := append([]string{"-" + word}, remaining...)
-			return false, restored, nil, Option{}, nil
-		}
+	if _, f := p.shortFlag(word); f != nil && !f.Disabled {
+		restored := append([]string{"-" + word}, remaining...)
+		return false, restored, nil, Option{}, nil
 	}
 
 	// Suppress error logging during the long option probe —
 	// we may fall back to short options.
-	savedErrors := p.config.enableErrors
-	p.config.enableErrors = false
+	savedMode := p.config.errorMode
+	p.config.errorMode, p.probing = ErrorSilent, true
 	args, flag, option, err = p.findLongOpt(word, remaining)
-	p.config.enableErrors = savedErrors
+	p.config.errorMode, p.probing = savedMode, false
 
 	if err == nil {
 		return true, args, flag, option, nil
@@ -509,18 +960,16 @@ func (p *Parser) tryLongOnly(
 	if !errors.As(err, &unkErr) {
 		// Non-unknown error (ambiguous, unexpected argument, etc.)
 		// — always return directly regardless of short opts.
-		if savedErrors {
-			slog.Error(err.Error())
-		}
+		p.report(err)
 		return true, remaining, nil, option, err
 	}
 
 	// UnknownOptionError — fall back to short options if available.
-	if p.shortOptN == 0 {
-		// No short options registered — re-log and return the error.
-		if savedErrors {
-			slog.Error(err.Error())
-		}
+	if p.shortOptN == 0 && len(p.shortClasses) == 0 {
+		// No short options registered — re-log and return the error,
+		// now with the suggestions the probe left out.
+		unkErr.Suggestions = p.SuggestOptions(unkErr.Name)
+		p.report(err)
 		return true, remaining, nil, option, err
 	}
 
@@ -533,34 +982,102 @@ func (p *Parser) tryLongOnly(
 // an [Option] and an error. When a subcommand is encountered, the iterator
 // dispatches to the child parser automatically.
 //
+// Handlers run and options are yielded in argument order, one option at a
+// time: each option is handled (Flag.Handle) or yielded, together with any
+// error it produces, before the next one is examined. The options of a
+// compacted short group such as "-abc" follow one another left to right.
+// An option inherited from a parent parser is handled at its position
+// through the parent's Flag, exactly like an option of p. Nothing is
+// deferred or reordered. A subcommand name ends the iteration after every
+// option before it has been processed; the subcommand's options are
+// produced only when its parser is iterated. [Parser.SetTrace] reports
+// the steps in this order.
+//
+// The first "--" not taken as an option argument ends option parsing in
+// every parse mode. Everything after it, including any later "--" and
+// any command name, is left in Args as an operand: it is not dispatched,
+// not passed to an operand handler, and not checked by
+// [ParserConfig.SetCommandFirst]. A dispatched subcommand applies the
+// same rule to its own arguments, so "prog cmd -- --" gives cmd the
+// single operand "--".
+//
 //nolint:gocognit,gocyclo,cyclop,funlen // main parser loop handles --, --long, -short, long-only, commands, and parse modes
 func (p *Parser) Options() iter.Seq2[Option, error] {
 	if debug {
 		slog.Debug("Iterator")
 	}
 	return func(yield func(Option, error) bool) {
+		if trace := p.tracer(); trace != nil {
+			yield = p.tracedYield(trace, yield)
+		}
 		var err error
 		cleanupDone := false
+		sawOperand := p.sawOperand
+		p.iterating, p.iterDone = true, false
+		p.terminated = false
 		defer func() {
 			if !cleanupDone {
+				// Early exit: fold collected operands back into Args so a
+				// later Options call resumes without losing them.
+				p.argBase = p.argIndex() - len(p.nonOpts)
 				p.Args = append(p.nonOpts, p.Args...)
+				p.collected, p.sawOperand = len(p.nonOpts), sawOperand
+			} else {
+				p.iterDone = true
+				p.collected, p.wordAt, p.sawOperand = 0, 0, false
 			}
+			// Args now owns the collected operands; start afresh so a
+			// further iteration does not duplicate them.
+			p.nonOpts = nil
+			p.iterating = false
 		}()
 
+		p.argStart = len(p.Args)
+		if n := min(p.collected, len(p.Args)); n > 0 {
+			// Resuming: the operands collected before the early exit are
+			// not examined again.
+			p.nonOpts = append(p.nonOpts, p.Args[:n]...)
+			p.Args = p.Args[n:]
+		}
+		p.collected = 0
 		if debug {
 			slog.Debug("Options", "args", p.Args)
 		}
+		if p.config.sanitizer != nil {
+			profileEnter(phaseSanitize)
+			for _, arg := range p.Args {
+				if err = p.config.sanitizer(arg); err != nil {
+					profileExit()
+					yield(Option{}, err)
+					return
+				}
+			}
+			profileExit()
+		}
 	out:
 		for len(p.Args) > 0 {
 			if debug {
 				slog.Debug("Options", "arg[0]", p.Args[0])
 			}
+			index := p.argIndex()
+			if p.config.onGrammar != nil && p.Args[0] != "--" && p.wordAt == 0 {
+				arg := p.Args[0]
+				if n, gerr := p.handleGrammar(index); n > 0 {
+					p.Args = p.Args[n:]
+					sawOperand = true
+					if gerr != nil && !yield(Option{Name: OperandName, Arg: arg, Index: index}, gerr) {
+						return
+					}
+					continue
+				}
+			}
 			option := Option{}
 			switch {
 			case p.Args[0] == "--": // Stop parsing options
 				if debug {
 					slog.Debug("Options", "break", true)
 				}
+				p.terminated, p.restAt = true, len(p.nonOpts)
 				p.Args = append(p.nonOpts, p.Args[1:]...)
 				cleanupDone = true
 				break out
@@ -570,15 +1087,29 @@ func (p *Parser) Options() iter.Seq2[Option, error] {
 					slog.Debug("Options", "prefix", "--")
 				}
 				var flag *Flag
+				profileEnter(phaseLong)
 				p.Args, flag, option, err = p.findLongOpt(p.Args[0][2:], p.Args[1:])
+				profileExit()
+				option.Index, option.Enabled = index, p.config.plusOpts
 				if err != nil {
 					if !yield(option, err) {
 						return
 					}
 					continue
 				}
-				if flag != nil && flag.Handle != nil {
-					if herr := flag.Handle(option.Name, option.Arg); herr != nil {
+				if p.skipDuplicate(flag) {
+					continue
+				}
+				if err := p.checkOption(flag, option); err != nil {
+					if !yield(option, err) {
+						return
+					}
+					continue
+				}
+				p.warnDeprecated("--"+option.Name, flag)
+				p.countOccurrence(flag, option)
+				if flag.handled() {
+					if herr := p.handle(flag, option); herr != nil {
 						if !yield(Option{}, herr) {
 							return
 						}
@@ -589,14 +1120,28 @@ func (p *Parser) Options() iter.Seq2[Option, error] {
 					return
 				}
 
-			case strings.HasPrefix(p.Args[0], "-"):
+			case (strings.HasPrefix(p.Args[0], "-") || p.isToggleOff(p.Args[0])) && p.Args[0] != "-" && !p.isNegativeNumber(p.Args[0]):
+				// A lone "-" is an operand (conventionally stdin), not an
+				// option, and so is a negative number when enabled.
 				if debug {
-					slog.Debug("Options", "prefix", "-")
+					slog.Debug("Options", "prefix", p.Args[0][:1])
 				}
-				if p.config.longOptsOnly { //nolint:nestif // long-only dispatch requires try-long then fall-through-to-short
+				plus := p.Args[0][0] == '+'
+				if !plus && p.wordAt == 0 && p.config.numericOpts && p.isNumericOption(p.Args[0]) {
+					option = Option{Name: NumericName, HasArg: true, Arg: p.Args[0][1:], Index: index}
+					p.Args = p.Args[1:]
+					if !yield(option, nil) {
+						return
+					}
+					continue
+				}
+				if p.config.longOptsOnly && !plus && p.wordAt == 0 { //nolint:nestif // long-only dispatch requires try-long then fall-through-to-short
 					var matched bool
 					var flag *Flag
+					profileEnter(phaseLong)
 					matched, p.Args, flag, option, err = p.tryLongOnly(p.Args[0][1:], p.Args[1:])
+					profileExit()
+					option.Index, option.Enabled = index, p.config.plusOpts
 					if matched {
 						if err != nil {
 							if !yield(option, err) {
@@ -604,8 +1149,19 @@ func (p *Parser) Options() iter.Seq2[Option, error] {
 							}
 							continue
 						}
-						if flag != nil && flag.Handle != nil {
-							if herr := flag.Handle(option.Name, option.Arg); herr != nil {
+						if p.skipDuplicate(flag) {
+							continue
+						}
+						if err := p.checkOption(flag, option); err != nil {
+							if !yield(option, err) {
+								return
+							}
+							continue
+						}
+						p.warnDeprecated("-"+option.Name, flag)
+						p.countOccurrence(flag, option)
+						if flag.handled() {
+							if herr := p.handle(flag, option); herr != nil {
 								if !yield(Option{}, herr) {
 									return
 								}
@@ -621,14 +1177,27 @@ func (p *Parser) Options() iter.Seq2[Option, error] {
 
 				// iterate over each character in the word looking
 				// for short options
-				word := p.Args[0][1:]
-				p.Args = p.Args[1:]
+				token := p.Args[0]
+				word := token[max(p.wordAt, 1):]
+				p.Args, p.wordAt = p.Args[1:], 0
 				for len(word) > 0 {
 					if debug {
 						slog.Debug("Options", "word", word)
 					}
 					var flag *Flag
-					p.Args, word, flag, option, err = p.findShortOpt(word[0], word[1:], p.Args)
+					c := word[0]
+					offset := len(token) - len(word)
+					typed := byteString(c)
+					profileEnter(phaseShort)
+					if r, n := utf8.DecodeRuneInString(word); n > 1 && p.resolveShort(c) == nil {
+						typed = word[:n]
+						p.Args, word, flag, option, err = p.findRuneOpt(r, word[n:], p.Args)
+					} else {
+						p.Args, word, flag, option, err = p.findShortOpt(c, word[1:], p.Args)
+					}
+					profileExit()
+					option.Index, option.Offset = index, offset
+					option.Enabled = p.config.plusOpts && !plus
 
 					// Transform usages such as `-W foo` into `--foo`
 					if option.Name == "W" && p.config.gnuWords {
@@ -641,8 +1210,19 @@ func (p *Parser) Options() iter.Seq2[Option, error] {
 						}
 						break
 					}
-					if flag != nil && flag.Handle != nil {
-						if herr := flag.Handle(option.Name, option.Arg); herr != nil {
+					if p.skipDuplicate(flag) {
+						continue
+					}
+					if err := p.checkOption(flag, option); err != nil {
+						if !yield(option, err) {
+							return
+						}
+						break
+					}
+					p.warnDeprecated(token[:1]+typed, flag)
+					p.countOccurrence(flag, option)
+					if flag.handled() {
+						if herr := p.handle(flag, option); herr != nil {
 							if !yield(Option{}, herr) {
 								return
 							}
@@ -651,26 +1231,89 @@ func (p *Parser) Options() iter.Seq2[Option, error] {
 						continue
 					}
 					if !yield(option, nil) {
+						if word != "" {
+							// Leave the rest of the group for resumption.
+							p.Args = append([]string{token}, p.Args...)
+							p.wordAt = len(token) - len(word)
+						}
 						return
 					}
 				}
 
-			default:
-				// Check if this is a registered command
-				if cmd, exists := p.GetCommand(p.Args[0]); exists {
-					cmdName := p.Args[0]
-					_, err := prepareCommand(cmdName, cmd, true, p.Args[1:])
-					if err != nil {
-						if !yield(Option{}, err) {
+			case p.isSlashOption(p.Args[0]):
+				var flag *Flag
+				profileEnter(phaseLong)
+				p.Args, flag, option, err = p.findSlashOpt(p.Args[0][1:], p.Args[1:])
+				profileExit()
+				option.Index, option.Enabled = index, p.config.plusOpts
+				if err != nil {
+					if !yield(option, err) {
+						return
+					}
+					continue
+				}
+				if p.skipDuplicate(flag) {
+					continue
+				}
+				if err := p.checkOption(flag, option); err != nil {
+					if !yield(option, err) {
+						return
+					}
+					continue
+				}
+				p.warnDeprecated("/"+option.Name, flag)
+				p.countOccurrence(flag, option)
+				if flag.handled() {
+					if herr := p.handle(flag, option); herr != nil {
+						if !yield(Option{}, herr) {
 							return
 						}
 					}
-					p.activeCmd = cmdName
-					p.activeCmdParser = cmd
+					continue
+				}
+				if !yield(option, nil) {
+					return
+				}
+
+			default:
+				// Check if this is a registered command
+				profileEnter(phaseCommand)
+				cmdName, cmdArgs := p.Args[0], p.Args[1:]
+				cmd, exists := p.GetCommand(cmdName)
+				if !exists && !sawOperand && p.defaultCmd != "" {
+					// The operand belongs to the default command.
+					cmdName, cmdArgs = p.defaultCmd, p.Args
+					cmd, exists = p.GetCommand(cmdName)
+				}
+				profileExit()
+				if exists {
+					err := p.dispatch(cmdName, cmd, cmdArgs, index)
 					p.Args = []string{}
+					if err != nil && !yield(Option{}, err) {
+						return
+					}
 					break out
 				}
 
+				if p.config.commandFirst && !sawOperand && len(p.Commands) > 0 {
+					sawOperand = true
+					if !yield(Option{}, p.unknownCommandError(p.Args[0])) {
+						return
+					}
+				}
+				sawOperand = true
+
+				if p.config.onOperand != nil && p.config.parseMode != ParsePosixlyCorrect {
+					arg := p.Args[0]
+					p.Args = p.Args[1:]
+					if herr := p.handleOperand(arg, index); herr != nil {
+						if !yield(Option{Name: OperandName, Arg: arg, Index: index}, herr) {
+							return
+						}
+					}
+					continue
+				}
+
 				// Handle as non-option
 				switch p.config.parseMode {
 				case ParseDefault:
@@ -678,12 +1321,15 @@ func (p *Parser) Options() iter.Seq2[Option, error] {
 
 				case ParseNonOpts:
 					option := Option{
-						Name: string(byte(1)),
-						Arg:  p.Args[0],
+						Name:  OperandName,
+						Arg:   p.Args[0],
+						Index: index,
 					}
+					p.Args = p.Args[1:]
 					if !yield(option, nil) {
 						return
 					}
+					continue
 
 				case ParsePosixlyCorrect:
 					break out
@@ -692,6 +1338,15 @@ func (p *Parser) Options() iter.Seq2[Option, error] {
 			}
 		}
 
+		if p.defaultCmd != "" && p.activeCmdParser == nil && !sawOperand && !p.terminated {
+			// No command and no operand: run the default command.
+			if cmd, exists := p.GetCommand(p.defaultCmd); exists {
+				if err := p.dispatch(p.defaultCmd, cmd, []string{}, p.argIndex()); err != nil && !yield(Option{}, err) {
+					return
+				}
+			}
+		}
+
 		if !cleanupDone {
 			cleanupDone = true
 			p.Args = append(p.nonOpts, p.Args...)
@@ -699,6 +1354,93 @@ func (p *Parser) Options() iter.Seq2[Option, error] {
 	}
 }
 
+// Collect runs an Options iteration to completion and returns the yielded
+// options in order, for callers that do not need the range loop. Handlers
+// run as usual. Parsing continues past errors, so err joins every error
+// encountered (see [errors.Join]) and is nil only if there were none; Args
+// holds the operands afterwards, as after a finished loop.
+//
+// Calling Collect from inside an Options range loop on the same parser
+// returns [ErrIterationInProgress].
+func (p *Parser) Collect() ([]Option, error) {
+	if p.iterating {
+		return nil, ErrIterationInProgress
+	}
+	var opts []Option
+	var errs []error
+	for opt, err := range p.Options() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		opts = append(opts, opt)
+	}
+	return opts, errors.Join(errs...)
+}
+
+// Resume continues an Options iteration that a range loop left early, from
+// the argument after the last option or error it yielded; see [Parser] for
+// the state in between. Before any iteration it behaves like Options. Once
+// an iteration has run to completion Resume yields nothing, whereas
+// Options would parse the operands left in Args afresh.
+func (p *Parser) Resume() iter.Seq2[Option, error] {
+	if p.iterDone {
+		return func(func(Option, error) bool) {}
+	}
+	return p.Options()
+}
+
+// Reset prepares p to parse args from the start, keeping its options,
+// handlers, subcommands, and configuration: the iteration, terminator,
+// occurrence-count, and dispatch state of the previous parse is cleared,
+// so [Parser.ActiveCommand] reports no subcommand until one is dispatched
+// again. A parser can thus be reused for each line of an interactive
+// shell. Reset must not be called while an Options range loop over p is
+// in progress.
+func (p *Parser) Reset(args []string) {
+	if args == nil {
+		args = []string{}
+	}
+	p.Args = args
+	p.nonOpts = []string{}
+	p.iterDone = false
+	p.collected, p.wordAt, p.sawOperand = 0, 0, false
+	p.terminated, p.restAt = false, 0
+	p.counts, p.occurrences = nil, nil
+	p.argBase, p.dispatchedBy = 0, nil
+	p.activeCmd, p.activeCmdParser = "", nil
+}
+
+// argIndex returns the original index of Args[0] during iteration.
+func (p *Parser) argIndex() int {
+	return p.argBase + p.argStart - len(p.Args)
+}
+
+// Remaining completes option processing and returns the non-option
+// arguments. If iteration has not started, or an earlier range loop exited
+// early, Remaining drains the rest of the iterator: handlers still run, but
+// options without a handler are discarded, and the first error encountered
+// is returned alongside the arguments. After a completed iteration it
+// simply returns Args.
+//
+// Calling Remaining from inside an Options range loop on the same parser
+// returns [ErrIterationInProgress].
+func (p *Parser) Remaining() ([]string, error) {
+	if p.iterating {
+		return nil, ErrIterationInProgress
+	}
+	if p.iterDone {
+		return p.Args, nil
+	}
+	var first error
+	for _, err := range p.Options() {
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return p.Args, first
+}
+
 // AddCmd registers a new subcommand with this parser.
 func (p *Parser) AddCmd(name string, parser *Parser) *Parser {
 	if parser != nil {
@@ -738,6 +1480,49 @@ func (p *Parser) SetStrictSubcommands(strict bool) {
 	p.config.strictSubcommands = strict
 }
 
+// SetCommandFirst requires the first operand to be a registered command;
+// see [ParserConfig.SetCommandFirst].
+func (p *Parser) SetCommandFirst(enabled bool) {
+	p.config.commandFirst = enabled
+}
+
+// SetNumericOptions enables or disables -<digits> numeric options; see
+// [ParserConfig.SetNumericOptions].
+func (p *Parser) SetNumericOptions(enabled bool) {
+	p.config.numericOpts = enabled
+}
+
+// SetNegativeNumbers enables or disables negative-number operands; see
+// [ParserConfig.SetNegativeNumbers].
+func (p *Parser) SetNegativeNumbers(enabled bool) {
+	p.config.negativeNums = enabled
+}
+
+// SetPlusOptions enables or disables '+'-prefixed toggles; see
+// [ParserConfig.SetPlusOptions].
+func (p *Parser) SetPlusOptions(enabled bool) {
+	p.config.plusOpts = enabled
+}
+
+// SetSlashOptions enables or disables '/'-prefixed options; see
+// [ParserConfig.SetSlashOptions].
+func (p *Parser) SetSlashOptions(enabled bool) {
+	p.config.slashOpts = enabled
+}
+
+// SetErrorMode selects whether errors are reported as diagnostics. It
+// overrides the ':' optstring prefix for parsers created by [GetOpt],
+// [GetOptLong], and [GetOptLongOnly].
+func (p *Parser) SetErrorMode(mode ErrorMode) {
+	p.config.errorMode = mode
+}
+
+// SetErrorWriter directs ErrorReport diagnostics to w; see
+// [ParserConfig.SetErrorWriter].
+func (p *Parser) SetErrorWriter(w io.Writer) {
+	p.config.errorWriter = w
+}
+
 // StrictSubcommands reports whether strict subcommand mode is enabled.
 func (p *Parser) StrictSubcommands() bool {
 	return p.config.strictSubcommands
@@ -749,7 +1534,8 @@ func (p *Parser) GetAliases(targetParser *Parser) []string {
 }
 
 // SetShortHandler attaches a handler to a short option registered on this
-// parser. Returns an error if no matching short option is found.
+// parser, replacing any HandleCtx set on it. Returns an error if no
+// matching short option is found.
 //
 // SetShortHandler only modifies options on this parser — it does not walk
 // the parent chain.
@@ -758,32 +1544,37 @@ func (p *Parser) SetShortHandler(c byte, handler func(string, string) error) err
 	if f == nil {
 		return fmt.Errorf("unknown option: -%c", c)
 	}
-	f.Handle = handler
+	f.Handle, f.HandleCtx = handler, nil
 	return nil
 }
 
 // SetLongHandler attaches a handler to a long option registered on this
-// parser. Returns an error if no matching long option is found.
+// parser, replacing any HandleCtx set on it. Returns an error if no
+// matching long option is found.
 //
 // Long option names may be single characters (e.g., "v" for --v). Use
 // SetLongHandler for long options and SetShortHandler for short options —
 // the two namespaces are independent.
 //
 // SetLongHandler only modifies options on this parser — it does not walk
-// the parent chain.
+// the parent chain. An alias (see Flag.Aliases) names its option.
 func (p *Parser) SetLongHandler(name string, handler func(string, string) error) error {
+	if registered, ok := p.longAliases[name]; ok {
+		name = registered
+	}
 	f, ok := p.longOpts[name]
 	if !ok {
 		return fmt.Errorf("unknown option: --%s", name)
 	}
-	f.Handle = handler
+	f.Handle, f.HandleCtx = handler, nil
 	return nil
 }
 
 // SetHandler is a convenience method that attaches a handler to a matching
 // option using command-line prefix syntax. Pass "--name" for long options
 // or "-c" for short options. Returns an error if the prefix is missing or
-// no matching option is found.
+// no matching option is found. Like SetHandlerCtx, it replaces the
+// handler of the other form.
 //
 // Examples:
 //
@@ -798,7 +1589,38 @@ func (p *Parser) SetHandler(name string, handler func(string, string) error) err
 		return p.SetLongHandler(name[2:], handler)
 	}
 	if strings.HasPrefix(name, "-") {
+		if r, n := utf8.DecodeRuneInString(name[1:]); n > 1 {
+			return p.SetShortRuneHandler(r, handler)
+		}
 		return p.SetShortHandler(name[1], handler)
 	}
 	return fmt.Errorf("invalid option name: %s", name)
 }
+
+// SetHandlerCtx is [Parser.SetHandler] for a handler of the
+// [Flag.HandleCtx] form, which receives the parser and the Option. It
+// replaces any Handle set on the option.
+func (p *Parser) SetHandlerCtx(name string, handler func(*Parser, Option) error) error {
+	var f *Flag
+	switch {
+	case strings.HasPrefix(name, "--"):
+		long := name[2:]
+		if registered, ok := p.longAliases[long]; ok {
+			long = registered
+		}
+		f = p.longOpts[long]
+	case strings.HasPrefix(name, "-"):
+		if r, n := utf8.DecodeRuneInString(name[1:]); n > 1 {
+			f = p.runeOpts[r]
+		} else if len(name) > 1 {
+			f = p.shortOpts[name[1]]
+		}
+	default:
+		return fmt.Errorf("invalid option name: %s", name)
+	}
+	if f == nil {
+		return fmt.Errorf("unknown option: %s", name)
+	}
+	f.Handle, f.HandleCtx = nil, handler
+	return nil
+}
@@ -2,6 +2,8 @@ package optargs
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"testing"
 	"testing/quick"
@@ -57,6 +59,49 @@ func TestParserInit(t *testing.T) {
 	}
 }
 
+// TestParserEmptyArgs verifies that nil and empty argument lists are
+// accepted by every constructor and produce an empty, non-nil result.
+func TestParserEmptyArgs(t *testing.T) {
+	constructors := []struct {
+		name string
+		new  func(args []string) (*Parser, error)
+	}{
+		{"GetOpt", func(args []string) (*Parser, error) { return GetOpt(args, "v") }},
+		{"GetOptLong", func(args []string) (*Parser, error) {
+			return GetOptLong(args, "v", []Flag{{Name: "verbose"}})
+		}},
+		{"GetOptLongOnly", func(args []string) (*Parser, error) {
+			return GetOptLongOnly(args, "v", []Flag{{Name: "verbose"}})
+		}},
+		{"NewParser", func(args []string) (*Parser, error) {
+			return NewParser(ParserConfig{}, nil, nil, args)
+		}},
+	}
+	for _, c := range constructors {
+		for _, args := range [][]string{nil, {}} {
+			t.Run(fmt.Sprintf("%s/nil=%t", c.name, args == nil), func(t *testing.T) {
+				p, err := c.new(args)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if p.Args == nil || len(p.Args) != 0 {
+					t.Errorf("Args before iteration = %#v, want empty", p.Args)
+				}
+				for opt, err := range p.Options() {
+					t.Errorf("Options yielded %+v, %v", opt, err)
+				}
+				rest, err := p.Remaining()
+				if err != nil || rest == nil || len(rest) != 0 {
+					t.Errorf("Remaining = %#v, %v; want empty", rest, err)
+				}
+				if operands, after := p.SplitArgs(); operands == nil || len(operands) != 0 || after != nil {
+					t.Errorf("SplitArgs = %#v, %#v; want empty, nil", operands, after)
+				}
+			})
+		}
+	}
+}
+
 func TestParserInitShortOpts(t *testing.T) {
 	shortOpts := make(map[byte]*Flag)
 	for _, c := range graphChars {
@@ -1759,3 +1804,479 @@ func TestLongOnlyMode(t *testing.T) {
 		}
 	})
 }
+
+func TestRemaining(t *testing.T) {
+	t.Run("drains unstarted iterator", func(t *testing.T) {
+		p, err := GetOpt([]string{"-a", "x", "-b", "y"}, "ab")
+		if err != nil {
+			t.Fatal(err)
+		}
+		args, err := p.Remaining()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertArgs(t, args, []string{"x", "y"})
+	})
+
+	t.Run("resumes after early break without duplication", func(t *testing.T) {
+		p, err := GetOpt([]string{"x", "-a", "y", "-b", "z"}, "ab")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for opt, err := range p.Options() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opt.Name == "a" {
+				break
+			}
+		}
+		args, err := p.Remaining()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertArgs(t, args, []string{"x", "y", "z"})
+	})
+
+	t.Run("returns first error", func(t *testing.T) {
+		p, err := GetOpt([]string{"-z", "x"}, ":a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		args, err := p.Remaining()
+		var unk *UnknownOptionError
+		if !errors.As(err, &unk) {
+			t.Errorf("err = %v, want UnknownOptionError", err)
+		}
+		assertArgs(t, args, []string{"x"})
+	})
+
+	t.Run("after completion returns Args", func(t *testing.T) {
+		p, err := GetOpt([]string{"-a", "x"}, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		requireParsedOptions(t, p)
+		args, err := p.Remaining()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertArgs(t, args, []string{"x"})
+	})
+
+	t.Run("mid-iteration is an error", func(t *testing.T) {
+		p, err := GetOpt([]string{"-a", "x"}, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range p.Options() {
+			if _, err := p.Remaining(); !errors.Is(err, ErrIterationInProgress) {
+				t.Errorf("err = %v, want ErrIterationInProgress", err)
+			}
+		}
+	})
+}
+
+// TestParserResume breaks out of the range loop after every yield and
+// resumes, which must produce the same options, errors, positions, and
+// operands as a single unbroken loop.
+func TestParserResume(t *testing.T) {
+	newParser := func(t *testing.T, args []string, optstring string, commandFirst bool) *Parser {
+		t.Helper()
+		p, err := GetOptLong(args, optstring, []Flag{{Name: "long", HasArg: RequiredArgument}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commandFirst {
+			sub, err := NewParser(ParserConfig{}, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.AddCmd("sub", sub)
+			p.SetCommandFirst(true)
+		}
+		return p
+	}
+	record := func(opt Option, err error) string {
+		return fmt.Sprintf("%s=%s@%d.%d err=%v", opt.Name, opt.Arg, opt.Index, opt.Offset, err)
+	}
+
+	tests := []struct {
+		name         string
+		optstring    string
+		args         []string
+		commandFirst bool
+	}{
+		{"compacted groups", ":abcf:", []string{"-abc", "x", "-bfval", "-ca", "y"}, false},
+		{"errors", ":abf:", []string{"x", "-azb", "--nope", "-b", "--long", "v", "-f"}, false},
+		{"terminator", "ab", []string{"x", "-ab", "--", "-a", "y"}, false},
+		{"operands in order", "-ab", []string{"x", "-ab", "y", "--long=v", "z"}, false},
+		{"command first", ":ab", []string{"x", "-ab", "y", "-a"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := newParser(t, slices.Clone(tt.args), tt.optstring, tt.commandFirst)
+			var wantSeq []string
+			for opt, err := range want.Options() {
+				wantSeq = append(wantSeq, record(opt, err))
+			}
+
+			p := newParser(t, slices.Clone(tt.args), tt.optstring, tt.commandFirst)
+			var seq []string
+			for yielded := true; yielded; {
+				yielded = false
+				for opt, err := range p.Resume() {
+					seq = append(seq, record(opt, err))
+					yielded = true
+					break
+				}
+			}
+			if !slices.Equal(seq, wantSeq) {
+				t.Errorf("resumed sequence:\n%q\nwant:\n%q", seq, wantSeq)
+			}
+			assertArgs(t, p.Args, want.Args)
+		})
+	}
+
+	t.Run("Args between iterations", func(t *testing.T) {
+		p := newParser(t, []string{"x", "-abc", "y"}, "abc", false)
+		for opt := range p.Options() {
+			if opt.Name == "a" {
+				break
+			}
+		}
+		assertArgs(t, p.Args, []string{"x", "-abc", "y"})
+		if _, err := p.Remaining(); err != nil {
+			t.Fatal(err)
+		}
+		if got := p.Count("b") + p.Count("c"); got != 2 {
+			t.Errorf("Count(b)+Count(c) = %d, want 2", got)
+		}
+		assertArgs(t, p.Args, []string{"x", "y"})
+	})
+
+	t.Run("after completion", func(t *testing.T) {
+		p := newParser(t, []string{"--", "-a"}, "a", false)
+		requireParsedOptions(t, p)
+		for opt, err := range p.Resume() {
+			t.Errorf("Resume after completion yielded %v, %v", opt, err)
+		}
+		assertOptions(t, requireParsedOptions(t, p), []Option{{Name: "a"}})
+	})
+
+	t.Run("sanitizer rejection", func(t *testing.T) {
+		p := newParser(t, []string{"-a", "x\x00"}, "a", false)
+		p.SetSanitizer(RejectControlChars)
+		for range 2 {
+			for _, err := range p.Resume() {
+				if err == nil {
+					t.Error("Resume yielded an option past the sanitizer")
+				}
+				break
+			}
+		}
+		assertArgs(t, p.Args, []string{"-a", "x\x00"})
+	})
+
+	t.Run("failed subcommand", func(t *testing.T) {
+		p := newParser(t, []string{"x", "sub", "-a"}, "a", false)
+		p.AddCmd("sub", nil)
+		for _, err := range p.Options() {
+			if err == nil {
+				t.Fatal("subcommand preparation did not fail")
+			}
+			break
+		}
+		if name, _ := p.ActiveCommand(); name != "sub" {
+			t.Errorf("ActiveCommand = %q, want sub", name)
+		}
+		for opt, err := range p.Resume() {
+			t.Errorf("Resume after dispatch yielded %v, %v", opt, err)
+		}
+		assertArgs(t, p.Args, []string{"x"})
+	})
+}
+
+func TestShortOptEquals(t *testing.T) {
+	shortOpts := map[byte]*Flag{
+		'v': {Name: "v", HasArg: NoArgument},
+		'o': {Name: "o", HasArg: RequiredArgument},
+		'd': {Name: "d", HasArg: OptionalArgument},
+	}
+	tests := []struct {
+		name    string
+		enabled bool
+		args    []string
+		want    []Option
+	}{
+		{"getopt keeps equals", false, []string{"-o=x"}, []Option{{Name: "o", HasArg: true, Arg: "=x"}}},
+		{"attached equals", true, []string{"-o=x"}, []Option{{Name: "o", HasArg: true, Arg: "x"}}},
+		{"attached without equals", true, []string{"-ox"}, []Option{{Name: "o", HasArg: true, Arg: "x"}}},
+		{"only one equals dropped", true, []string{"-o==x"}, []Option{{Name: "o", HasArg: true, Arg: "=x"}}},
+		{"separate argument kept", true, []string{"-o", "=x"}, []Option{{Name: "o", HasArg: true, Arg: "=x"}}},
+		{"cluster", true, []string{"-vo=x"}, []Option{{Name: "v"}, {Name: "o", HasArg: true, Arg: "x"}}},
+		{"optional argument", true, []string{"-d=3"}, []Option{{Name: "d", HasArg: true, Arg: "3"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg ParserConfig
+			cfg.SetShortOptEquals(tt.enabled)
+			if cfg.ShortOptEquals() != tt.enabled {
+				t.Fatalf("ShortOptEquals() = %v, want %v", cfg.ShortOptEquals(), tt.enabled)
+			}
+			p, err := NewParser(cfg, shortOpts, nil, tt.args)
+			if err != nil {
+				t.Fatalf("NewParser: %v", err)
+			}
+			assertOptions(t, requireParsedOptions(t, p), tt.want)
+		})
+	}
+}
+
+// TestParserConfigSetters verifies that a parser composed from a zero
+// ParserConfig and its setters behaves like the getopt constructors.
+func TestParserConfigSetters(t *testing.T) {
+	var cfg ParserConfig
+	cfg.SetParseMode(ParseNonOpts)
+	cfg.SetShortCaseIgnore(true)
+	cfg.SetLongCaseIgnore(true)
+	cfg.SetCommandCaseIgnore(true)
+	cfg.SetGNUWords(true)
+	cfg.SetStrictSubcommands(true)
+	if cfg.ParseMode() != ParseNonOpts || !cfg.ShortCaseIgnore() || !cfg.LongCaseIgnore() ||
+		!cfg.CommandCaseIgnore() || !cfg.GNUWords() || !cfg.StrictSubcommands() {
+		t.Fatalf("getters do not reflect setters: %+v", cfg)
+	}
+
+	shortOpts := map[byte]*Flag{
+		'v': {Name: "v"},
+		'W': {Name: "W", HasArg: RequiredArgument},
+	}
+	longOpts := map[string]*Flag{"verbose": {Name: "verbose"}}
+	p, err := NewParser(cfg, shortOpts, longOpts, []string{"-V", "x", "--VERBOSE", "-W", "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, requireParsedOptions(t, p), []Option{
+		{Name: "v"},
+		{Name: OperandName, Arg: "x"},
+		{Name: "verbose"},
+		{Name: "foo", HasArg: true, Arg: "foo"},
+	})
+
+	cfg.SetParseMode(ParseDefault)
+	p, err = NewParser(cfg, shortOpts, longOpts, []string{"run", "-v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := NewParser(ParserConfig{}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.AddCmd("Run", child)
+	for _, err := range p.Options() {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, cmd := p.ActiveCommand(); cmd != child {
+		t.Fatal("command matching is case-sensitive")
+	}
+	var errs []error
+	for _, err := range child.Options() {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || errs[0] == nil {
+		t.Errorf("strict subcommand parsed a parent option: %v", errs)
+	}
+}
+
+// TestOptionPositions verifies Option.Index and Option.Offset, including
+// across permuted operands, resumed iteration, and subcommand dispatch.
+func TestOptionPositions(t *testing.T) {
+	type pos struct {
+		name          string
+		index, offset int
+	}
+	positions := func(p *Parser, stopAfter int) []pos {
+		var got []pos
+		for opt, err := range p.Options() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, pos{opt.Name, opt.Index, opt.Offset})
+			if len(got) == stopAfter {
+				break
+			}
+		}
+		return got
+	}
+
+	p, err := GetOptLong([]string{"op1", "-ab", "--file", "x", "op2", "-cval", "--file=y"}, "abc:", []Flag{
+		{Name: "file", HasArg: RequiredArgument},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := positions(p, 2)
+	got = append(got, positions(p, 0)...)
+	want := []pos{{"a", 1, 1}, {"b", 1, 2}, {"file", 2, 0}, {"c", 5, 1}, {"file", 6, 0}}
+	if !slices.Equal(got, want) {
+		t.Errorf("positions = %v, want %v", got, want)
+	}
+
+	root, err := GetOpt([]string{"-a", "sub", "op", "-xy"}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := GetOpt(nil, "xy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("sub", sub)
+	got = positions(root, 0)
+	for _, child := range root.Dispatches() {
+		got = append(got, positions(child, 0)...)
+	}
+	want = []pos{{"a", 0, 1}, {"x", 3, 1}, {"y", 3, 2}}
+	if !slices.Equal(got, want) {
+		t.Errorf("subcommand positions = %v, want %v", got, want)
+	}
+}
+
+// TestParserReset verifies that a configured parser can be reused for a
+// new argument list, with handlers, counts, and dispatch state starting
+// afresh each time.
+func TestParserReset(t *testing.T) {
+	var handled []string
+	root, err := NewParser(ParserConfig{}, map[byte]*Flag{
+		'v': {Name: "v"},
+		'q': {Name: "q", Handle: func(name, _ string) error {
+			handled = append(handled, name)
+			return nil
+		}},
+	}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := NewParser(ParserConfig{}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("sub", sub)
+
+	parse := func(args ...string) []Option {
+		t.Helper()
+		root.Reset(args)
+		opts := requireParsedOptions(t, root)
+		for _, child := range root.Dispatches() {
+			opts = append(opts, requireParsedOptions(t, child)...)
+		}
+		return opts
+	}
+
+	assertOptions(t, parse("-vv", "-q", "sub", "-v", "a", "--", "-b"), []Option{{Name: "v"}, {Name: "v"}, {Name: "v"}})
+	if _, rest := sub.SplitArgs(); !slices.Equal(rest, []string{"-b"}) {
+		t.Errorf("first parse: sub rest = %q", rest)
+	}
+
+	assertOptions(t, parse("x", "-v"), []Option{{Name: "v"}})
+	assertArgs(t, root.Args, []string{"x"})
+	if name, child := root.ActiveCommand(); name != "" || child != nil {
+		t.Errorf("ActiveCommand after reset = %q, %v; want none", name, child)
+	}
+	if got := root.Count("v"); got != 1 {
+		t.Errorf("Count(v) after reset = %d, want 1", got)
+	}
+	if _, rest := root.SplitArgs(); rest != nil {
+		t.Errorf("SplitArgs rest after reset = %q, want nil", rest)
+	}
+	if !slices.Equal(handled, []string{"q"}) {
+		t.Errorf("handler calls = %q, want [q]", handled)
+	}
+
+	parse()
+	if len(root.Args) != 0 {
+		t.Errorf("Args after empty reset = %q", root.Args)
+	}
+}
+
+func TestParserCollect(t *testing.T) {
+	var handled int
+	p, err := GetOptLong([]string{"-a", "op", "-x", "--file", "f", "-y", "--quiet"}, ":ab", []Flag{
+		{Name: "file", HasArg: RequiredArgument},
+		{Name: "quiet", Handle: func(string, string) error { handled++; return nil }},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := p.Collect()
+	assertOptions(t, opts, []Option{{Name: "a"}, {Name: "file", HasArg: true, Arg: "f"}})
+	assertArgs(t, p.Args, []string{"op"})
+	if handled != 1 {
+		t.Errorf("handler called %d times, want 1", handled)
+	}
+	var unknown *UnknownOptionError
+	if !errors.As(err, &unknown) || !strings.Contains(err.Error(), "unknown option: x\nunknown option: y") {
+		t.Errorf("Collect error = %v, want both unknown options joined", err)
+	}
+
+	p, err = GetOpt([]string{"-a"}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Collect(); err != nil {
+		t.Errorf("Collect error = %v, want nil", err)
+	}
+	p.Reset([]string{"-a"})
+	for range p.Options() {
+		if _, err := p.Collect(); !errors.Is(err, ErrIterationInProgress) {
+			t.Errorf("Collect during iteration = %v, want ErrIterationInProgress", err)
+		}
+	}
+}
+
+// TestOperandName verifies that an in-order operand is named OperandName
+// and reports IsOperand, while options do not.
+func TestOperandName(t *testing.T) {
+	p, err := GetOpt([]string{"-a", "non-option"}, "-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := requireParsedOptions(t, p)
+	if len(options) != 2 {
+		t.Fatalf("got %d options, want 2", len(options))
+	}
+	if options[0].IsOperand() {
+		t.Errorf("-a reports IsOperand")
+	}
+	if op := options[1]; !op.IsOperand() || op.Name != OperandName || op.Arg != "non-option" {
+		t.Errorf("operand = %+v, want Name %q, Arg non-option, IsOperand", op, OperandName)
+	}
+}
+
+// TestReturnInOrder verifies the '-' optstring prefix: operands are
+// yielded where they appear, between the options, rather than permuted to
+// the end, and "--" still ends option processing.
+func TestReturnInOrder(t *testing.T) {
+	p, err := GetOpt([]string{"-a", "x.c", "-o", "x.o", "y.c", "-b", "--", "-z"}, "-abo:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for opt, err := range p.Options() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opt.IsOperand() {
+			got = append(got, fmt.Sprintf("operand %s@%d", opt.Arg, opt.Index))
+			continue
+		}
+		got = append(got, fmt.Sprintf("-%s%s@%d", opt.Name, opt.Arg, opt.Index))
+	}
+	want := []string{"-a@0", "operand x.c@1", "-ox.o@2", "operand y.c@4", "-b@5"}
+	if !slices.Equal(got, want) {
+		t.Errorf("yielded %q, want %q", got, want)
+	}
+	assertArgs(t, p.Args, []string{"-z"})
+}
@@ -0,0 +1,97 @@
+package pflag
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/major0/optargs"
+	"github.com/major0/optargs/goarg"
+)
+
+// BindStruct registers a flag for each option field of the struct ptr
+// points to, declared with goarg struct tags, so a command can keep the
+// FlagSet API while declaring its options in one place:
+//
+//	var opts struct {
+//		Verbose bool     `arg:"-v,--verbose" help:"verbose output"`
+//		Output  string   `arg:"-o" default:"out.txt"`
+//		Tags    []string `arg:"--tag" env:"APP_TAGS"`
+//	}
+//	fs.BindStruct(&opts)
+//
+// The binding is two-way. Each flag reads and writes its field, so after
+// Parse the struct holds the parsed values, Set updates it, and a value
+// stored in a field before BindStruct is the flag's default. A field
+// still zero starts from its `env` variable when that is set, otherwise
+// from its `default` tag; arguments on the command line override both.
+// Slice and map flags add to that starting value, as the FlagSet's own
+// slice flags do. Fields tagged `env` alone get no flag. The `prefix`
+// and `negatable` tags apply MarkBoolPrefix and MarkNegatable.
+//
+// Positional and subcommand fields, and the `required`, `xor`, and
+// `onset` tags, have no FlagSet equivalent; BindStruct returns an error
+// for them, as for invalid tags and unsupported field types, before
+// registering any flag. A flag name already in use panics, as it does
+// for the other registration methods.
+func (f *FlagSet) BindStruct(ptr any) error {
+	tp := &goarg.TagParser{}
+	metadata, err := tp.ParseStruct(ptr)
+	if err != nil {
+		return fmt.Errorf("bind struct: %w", err)
+	}
+	if len(metadata.Subcommands) > 0 {
+		return errors.New("bind struct: subcommands are not supported")
+	}
+	if len(metadata.Positionals) > 0 {
+		return fmt.Errorf("bind struct: field %s: positional arguments are not supported", metadata.Positionals[0].Name)
+	}
+
+	for i := range metadata.Fields {
+		field := &metadata.Fields[i]
+		switch {
+		case field.Required:
+			return fmt.Errorf("bind struct: field %s: required is not supported", field.Name)
+		case field.Group != "":
+			return fmt.Errorf("bind struct: field %s: xor is not supported", field.Name)
+		case field.OnSet != "":
+			return fmt.Errorf("bind struct: field %s: onset is not supported", field.Name)
+		}
+	}
+	for i := range metadata.Fields {
+		if err := goarg.SetFieldFallback(ptr, &metadata.Fields[i]); err != nil {
+			return fmt.Errorf("bind struct: %w", err)
+		}
+	}
+	values := make([]Value, len(metadata.Options))
+	for i := range metadata.Options {
+		field := &metadata.Options[i]
+		if values[i], err = goarg.FieldValue(ptr, field); err != nil {
+			return fmt.Errorf("bind struct: field %s: %w", field.Name, err)
+		}
+		if _, ok := optargs.ZeroString(values[i].Type()); field.Negatable && !ok {
+			return fmt.Errorf("bind struct: field %s: type %s cannot be negatable", field.Name, values[i].Type())
+		}
+	}
+
+	for i := range metadata.Options {
+		field := &metadata.Options[i]
+		name := field.Long
+		if name == "" {
+			f.ShortVar(values[i], field.Short, field.Help)
+			name = field.Short
+		} else {
+			f.VarP(values[i], name, field.Short, field.Help)
+		}
+		for _, pair := range field.Prefixes {
+			if err := f.MarkBoolPrefix(name, pair.True, pair.False); err != nil {
+				return fmt.Errorf("bind struct: %w", err)
+			}
+		}
+		if field.Negatable {
+			if err := f.MarkNegatable(name); err != nil {
+				return fmt.Errorf("bind struct: %w", err)
+			}
+		}
+	}
+	return nil
+}
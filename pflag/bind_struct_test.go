@@ -0,0 +1,119 @@
+package pflag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBindStruct(t *testing.T) {
+	t.Setenv("BIND_TEST_LEVEL", "4")
+	var opts struct {
+		Verbose bool     `arg:"-v,--verbose" help:"verbose output"`
+		Output  string   `arg:"-o" default:"out.txt"`
+		Level   int      `arg:"--level" env:"BIND_TEST_LEVEL" default:"1"`
+		Tags    []string `arg:"--tag" default:"a,'b,c'"`
+		Color   bool     `arg:"--color" prefix:"with,without"`
+		Name    string   `arg:"--name" negatable:""`
+		Preset  string   `arg:"--preset" default:"unused"`
+		Token   string   `arg:"env:BIND_TEST_TOKEN"`
+	}
+	opts.Preset = "kept"
+
+	fs := NewFlagSet("test", ContinueOnError)
+	if err := fs.BindStruct(&opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Output != "out.txt" || opts.Level != 4 || opts.Preset != "kept" || !reflect.DeepEqual(opts.Tags, []string{"a", "b,c"}) {
+		t.Fatalf("fallbacks = %+v", opts)
+	}
+	if flag := fs.Lookup("level"); flag == nil || flag.DefValue != "4" || flag.Usage != "" {
+		t.Errorf("level flag = %+v", flag)
+	}
+	if flag := fs.Lookup("verbose"); flag == nil || flag.Shorthand != "v" || flag.Usage != "verbose output" {
+		t.Errorf("verbose flag = %+v", flag)
+	}
+	if fs.Lookup("token") != nil {
+		t.Error("env-only field registered a flag")
+	}
+
+	err := fs.Parse([]string{"-vo", "log", "--level=9", "--tag", "d", "--without-color", "--name", "x", "--no-name", "arg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.Verbose || opts.Output != "log" || opts.Level != 9 || opts.Color || opts.Name != "" {
+		t.Errorf("parsed = %+v", opts)
+	}
+	if !reflect.DeepEqual(opts.Tags, []string{"a", "b,c", "d"}) {
+		t.Errorf("Tags = %q", opts.Tags)
+	}
+	if !reflect.DeepEqual(fs.Args(), []string{"arg"}) {
+		t.Errorf("Args() = %q", fs.Args())
+	}
+
+	// Setting a flag writes the field, and the getters read it back.
+	if err := fs.Set("level", "12"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := fs.GetInt("level"); opts.Level != 12 || v != 12 {
+		t.Errorf("after Set: field %d, GetInt %d", opts.Level, v)
+	}
+}
+
+func TestBindStructShortOnly(t *testing.T) {
+	var opts struct {
+		All bool `arg:"-a"`
+		N   int  `arg:"-n"`
+	}
+	fs := NewFlagSet("test", ContinueOnError)
+	if err := fs.BindStruct(&opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-an5"}); err != nil {
+		t.Fatal(err)
+	}
+	if !opts.All || opts.N != 5 {
+		t.Errorf("parsed = %+v", opts)
+	}
+}
+
+func TestBindStructErrors(t *testing.T) {
+	type sub struct{}
+	tests := []struct {
+		name string
+		ptr  any
+		want string
+	}{
+		{"not a pointer", struct{}{}, "must be a pointer to a struct"},
+		{"positional", &struct {
+			File string `arg:"positional"`
+		}{}, "field File: positional arguments are not supported"},
+		{"subcommand", &struct {
+			Run *sub `arg:"subcommand"`
+		}{}, "subcommands are not supported"},
+		{"required", &struct {
+			Name string `arg:"--name,required"`
+		}{}, "field Name: required is not supported"},
+		{"xor", &struct {
+			JSON bool `arg:"--json" xor:"format"`
+		}{}, "field JSON: xor is not supported"},
+		{"interpolated default", &struct {
+			Dir string `arg:"--dir" default:"${HOME}/x"`
+		}{}, "interpolation is only supported by Parse"},
+		{"unsupported type", &struct {
+			C chan int `arg:"--c"`
+		}{}, "unsupported type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("test", ContinueOnError)
+			err := fs.BindStruct(tt.ptr)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+			if fs.HasFlags() {
+				t.Error("flags registered despite the error")
+			}
+		})
+	}
+}
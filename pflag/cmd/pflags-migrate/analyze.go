@@ -0,0 +1,193 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"strconv"
+)
+
+const (
+	upstreamPath = "github.com/spf13/pflag"
+	compatPath   = "github.com/major0/optargs/pflag"
+)
+
+// Use is one reference to the upstream pflag API found in a source file.
+type Use struct {
+	Pos       token.Position
+	Name      string // pflag.String, FlagSet.VarP, Flag.Usage, ...
+	Supported bool
+}
+
+// analyzeFile returns every reference to the upstream pflag API in f.
+// Package-level identifiers are found through the import name; members of
+// FlagSet and Flag are found through variables, parameters, and fields
+// whose declared type or initializer makes their pflag type evident.
+// Nothing is reported for a file that does not import upstream pflag or
+// imports it with a blank or dot name.
+func analyzeFile(fset *token.FileSet, f *ast.File) []Use {
+	pkg := importName(f)
+	if pkg == "" || pkg == "_" || pkg == "." {
+		return nil
+	}
+
+	a := &analyzer{fset: fset, pkg: pkg, vars: make(map[string]string)}
+	ast.Inspect(f, a.collect)
+	ast.Inspect(f, a.check)
+	slices.SortFunc(a.uses, func(x, y Use) int { return x.Pos.Offset - y.Pos.Offset })
+	return a.uses
+}
+
+// importName returns the name upstream pflag is imported under in f, or ""
+// when f does not import it.
+func importName(f *ast.File) string {
+	for _, spec := range f.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err != nil || path != upstreamPath {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		return "pflag"
+	}
+	return ""
+}
+
+// rewriteImports points every upstream pflag import in f at the
+// compatibility package and reports whether anything changed. The package
+// name is the same, so unaliased imports keep working.
+func rewriteImports(f *ast.File) bool {
+	changed := false
+	for _, spec := range f.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil && path == upstreamPath {
+			spec.Path.Value = strconv.Quote(compatPath)
+			changed = true
+		}
+	}
+	return changed
+}
+
+type analyzer struct {
+	fset *token.FileSet
+	pkg  string
+	vars map[string]string // identifier → "FlagSet" or "Flag"
+	uses []Use
+}
+
+// collect records identifiers whose pflag type is evident from their
+// declaration. Scoping is ignored: a name bound to a FlagSet anywhere in
+// the file is treated as a FlagSet everywhere in it.
+func (a *analyzer) collect(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.Field:
+		if typ := a.typeOf(n.Type); typ != "" {
+			for _, name := range n.Names {
+				a.vars[name.Name] = typ
+			}
+		}
+	case *ast.ValueSpec:
+		typ := a.typeOf(n.Type)
+		for i, name := range n.Names {
+			switch {
+			case typ != "":
+				a.vars[name.Name] = typ
+			case i < len(n.Values) && len(n.Values) == len(n.Names):
+				if t := a.valueType(n.Values[i]); t != "" {
+					a.vars[name.Name] = t
+				}
+			}
+		}
+	case *ast.AssignStmt:
+		if len(n.Lhs) != len(n.Rhs) {
+			return true
+		}
+		for i, lhs := range n.Lhs {
+			if id, ok := lhs.(*ast.Ident); ok {
+				if t := a.valueType(n.Rhs[i]); t != "" {
+					a.vars[id.Name] = t
+				}
+			}
+		}
+	}
+	return true
+}
+
+// check records each selector that refers to the pflag API.
+func (a *analyzer) check(n ast.Node) bool {
+	sel, ok := n.(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+	if id, ok := sel.X.(*ast.Ident); ok && id.Name == a.pkg {
+		a.add(sel.Sel, a.pkg+"."+sel.Sel.Name, packageAPI[sel.Sel.Name])
+		return true
+	}
+	if typ := a.valueType(sel.X); typ != "" {
+		a.add(sel.Sel, typ+"."+sel.Sel.Name, memberAPI[typ][sel.Sel.Name])
+	}
+	return true
+}
+
+func (a *analyzer) add(id *ast.Ident, name string, supported bool) {
+	a.uses = append(a.uses, Use{Pos: a.fset.Position(id.Pos()), Name: name, Supported: supported})
+}
+
+// typeOf returns "FlagSet" or "Flag" when expr is that pflag type or a
+// pointer to it.
+func (a *analyzer) typeOf(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if id, ok := sel.X.(*ast.Ident); !ok || id.Name != a.pkg {
+		return ""
+	}
+	if _, ok := memberAPI[sel.Sel.Name]; ok {
+		return sel.Sel.Name
+	}
+	return ""
+}
+
+// valueType returns the pflag type of expr when it is evident: a tracked
+// identifier, pflag.CommandLine, a composite literal, or a call to a
+// constructor or lookup function.
+func (a *analyzer) valueType(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return a.vars[e.Name]
+	case *ast.ParenExpr:
+		return a.valueType(e.X)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return a.valueType(e.X)
+		}
+	case *ast.CompositeLit:
+		return a.typeOf(e.Type)
+	case *ast.SelectorExpr:
+		if id, ok := e.X.(*ast.Ident); ok && id.Name == a.pkg && e.Sel.Name == "CommandLine" {
+			return "FlagSet"
+		}
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return ""
+		}
+		id, isPkg := sel.X.(*ast.Ident)
+		isPkg = isPkg && id.Name == a.pkg
+		if !isPkg && a.valueType(sel.X) != "FlagSet" {
+			return ""
+		}
+		switch sel.Sel.Name {
+		case "NewFlagSet":
+			if isPkg {
+				return "FlagSet"
+			}
+		case "Lookup", "ShorthandLookup":
+			return "Flag"
+		}
+	}
+	return ""
+}
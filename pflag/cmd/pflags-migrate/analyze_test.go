@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sample = `package app
+
+import (
+	"fmt"
+
+	flags "github.com/spf13/pflag"
+)
+
+type config struct {
+	fs *flags.FlagSet
+}
+
+func setup(extra *flags.FlagSet) {
+	fs := flags.NewFlagSet("app", flags.ContinueOnError)
+	fs.StringP("name", "n", "", "name")
+	fs.IPSlice("peers", nil, "peers")
+	extra.Bool("x", false, "x")
+	if f := fs.Lookup("name"); f != nil {
+		fmt.Println(f.Usage)
+	}
+	flags.CommandLine.GetIPSlice("when")
+	fmt.Println(flags.Parsed())
+}
+`
+
+func TestAnalyzeFile(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "app.go", sample, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, use := range analyzeFile(fset, f) {
+		mark := "ok"
+		if !use.Supported {
+			mark = "unsupported"
+		}
+		got = append(got, use.Name+" "+mark)
+	}
+	want := []string{
+		"flags.FlagSet ok",
+		"flags.FlagSet ok",
+		"flags.NewFlagSet ok",
+		"flags.ContinueOnError ok",
+		"FlagSet.StringP ok",
+		"FlagSet.IPSlice unsupported",
+		"FlagSet.Bool ok",
+		"FlagSet.Lookup ok",
+		"Flag.Usage ok",
+		"flags.CommandLine ok",
+		"FlagSet.GetIPSlice unsupported",
+		"flags.Parsed ok",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("uses:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAnalyzeFileIgnoresOtherImports(t *testing.T) {
+	for _, src := range []string{
+		"package a\nimport \"flag\"\nvar _ = flag.String(\"a\", \"\", \"\")\n",
+		"package a\nimport _ \"github.com/spf13/pflag\"\n",
+		"package a\nimport . \"github.com/spf13/pflag\"\nvar _ = String(\"a\", \"\", \"\")\n",
+	} {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "a.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uses := analyzeFile(fset, f); len(uses) != 0 {
+			t.Errorf("analyzeFile(%q) = %v, want none", src, uses)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	supported := "package a\n\nimport \"github.com/spf13/pflag\"\n\nvar v = pflag.Bool(\"v\", false, \"verbose\")\n"
+	unsupported := "package a\n\nimport \"github.com/spf13/pflag\"\n\nvar t = pflag.IPSlice(\"t\", nil, \"peers\")\n"
+	writeFile(t, filepath.Join(dir, "ok.go"), supported)
+	writeFile(t, filepath.Join(dir, "bad.go"), unsupported)
+	writeFile(t, filepath.Join(dir, "vendor", "x.go"), unsupported)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-w", dir + "/..."}, &stdout, &stderr); code != 1 {
+		t.Errorf("exit = %d, want 1; stderr: %s", code, stderr.String())
+	}
+	if want := filepath.Join(dir, "bad.go") + ":5:15: pflag.IPSlice: unsupported\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if want := "pflags-migrate: 2 uses in 2 files, 1 unsupported, 1 files rewritten\n"; stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", stderr.String(), want)
+	}
+	if got := readFile(t, filepath.Join(dir, "ok.go")); got != strings.Replace(supported, upstreamPath, compatPath, 1) {
+		t.Errorf("ok.go not rewritten:\n%s", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "bad.go")); got != unsupported {
+		t.Errorf("bad.go rewritten despite unsupported use:\n%s", got)
+	}
+
+	// After the rewrite only the unsupported file still imports upstream.
+	stdout.Reset()
+	stderr.Reset()
+	if code := run([]string{"-v", filepath.Join(dir, "ok.go")}, &stdout, &stderr); code != 0 {
+		t.Errorf("exit = %d, want 0", code)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("stdout = %q, want empty", stdout.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-bogus"}, &stdout, &stderr); code != 2 {
+		t.Errorf("bad flag: exit = %d, want 2", code)
+	}
+	if code := run([]string{filepath.Join(t.TempDir(), "missing")}, &stdout, &stderr); code != 2 {
+		t.Errorf("missing path: exit = %d, want 2", code)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil { //nolint:gosec // test fixture
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,473 @@
+// Code generated by `go test -update`; DO NOT EDIT.
+
+package main
+
+// packageAPI holds the exported package-level identifiers of
+// github.com/major0/optargs/pflag.
+var packageAPI = map[string]bool{
+	"AddFlag":                 true,
+	"AddFlagSet":              true,
+	"AddGoFlag":               true,
+	"AddGoFlagSet":            true,
+	"Arg":                     true,
+	"Args":                    true,
+	"ArgsLenAtDash":           true,
+	"Bool":                    true,
+	"BoolFunc":                true,
+	"BoolFuncP":               true,
+	"BoolP":                   true,
+	"BoolSlice":               true,
+	"BoolSliceP":              true,
+	"BoolSliceVar":            true,
+	"BoolSliceVarP":           true,
+	"BoolVar":                 true,
+	"BoolVarP":                true,
+	"BytesBase64":             true,
+	"BytesBase64P":            true,
+	"BytesBase64Var":          true,
+	"BytesBase64VarP":         true,
+	"BytesHex":                true,
+	"BytesHexP":               true,
+	"BytesHexVar":             true,
+	"BytesHexVarP":            true,
+	"Changed":                 true,
+	"ChangedFlags":            true,
+	"CommandLine":             true,
+	"ContinueOnError":         true,
+	"CopyToGoFlagSet":         true,
+	"Count":                   true,
+	"CountP":                  true,
+	"CountVar":                true,
+	"CountVarP":               true,
+	"Duration":                true,
+	"DurationP":               true,
+	"DurationSlice":           true,
+	"DurationSliceP":          true,
+	"DurationSliceVar":        true,
+	"DurationSliceVarP":       true,
+	"DurationVar":             true,
+	"DurationVarP":            true,
+	"EnableNegations":         true,
+	"ErrHelp":                 true,
+	"ErrorHandling":           true,
+	"ExitOnError":             true,
+	"Flag":                    true,
+	"FlagSet":                 true,
+	"FlagUsages":              true,
+	"FlagUsagesWrapped":       true,
+	"Float32":                 true,
+	"Float32P":                true,
+	"Float32Slice":            true,
+	"Float32SliceP":           true,
+	"Float32SliceVar":         true,
+	"Float32SliceVarP":        true,
+	"Float32Var":              true,
+	"Float32VarP":             true,
+	"Float64":                 true,
+	"Float64P":                true,
+	"Float64Slice":            true,
+	"Float64SliceP":           true,
+	"Float64SliceVar":         true,
+	"Float64SliceVarP":        true,
+	"Float64Var":              true,
+	"Float64VarP":             true,
+	"Func":                    true,
+	"FuncP":                   true,
+	"Getter":                  true,
+	"HasAvailableFlags":       true,
+	"HasFlags":                true,
+	"IP":                      true,
+	"IPMask":                  true,
+	"IPMaskP":                 true,
+	"IPMaskVar":               true,
+	"IPMaskVarP":              true,
+	"IPNet":                   true,
+	"IPNetP":                  true,
+	"IPNetVar":                true,
+	"IPNetVarP":               true,
+	"IPP":                     true,
+	"IPVar":                   true,
+	"IPVarP":                  true,
+	"Int":                     true,
+	"Int16":                   true,
+	"Int16P":                  true,
+	"Int16Var":                true,
+	"Int16VarP":               true,
+	"Int32":                   true,
+	"Int32P":                  true,
+	"Int32Slice":              true,
+	"Int32SliceP":             true,
+	"Int32SliceVar":           true,
+	"Int32SliceVarP":          true,
+	"Int32Var":                true,
+	"Int32VarP":               true,
+	"Int64":                   true,
+	"Int64P":                  true,
+	"Int64Slice":              true,
+	"Int64SliceP":             true,
+	"Int64SliceVar":           true,
+	"Int64SliceVarP":          true,
+	"Int64Var":                true,
+	"Int64VarP":               true,
+	"Int8":                    true,
+	"Int8P":                   true,
+	"Int8Var":                 true,
+	"Int8VarP":                true,
+	"IntP":                    true,
+	"IntSlice":                true,
+	"IntSliceP":               true,
+	"IntSliceVar":             true,
+	"IntSliceVarP":            true,
+	"IntVar":                  true,
+	"IntVarP":                 true,
+	"InvalidSyntaxError":      true,
+	"InvalidValueError":       true,
+	"Lookup":                  true,
+	"MarkBoolPrefix":          true,
+	"MarkDeprecated":          true,
+	"MarkHidden":              true,
+	"MarkNegatable":           true,
+	"MarkShorthandDeprecated": true,
+	"NArg":                    true,
+	"NFlag":                   true,
+	"NewFlagSet":              true,
+	"NormalizedName":          true,
+	"NotExistError":           true,
+	"PFlagFromGoFlag":         true,
+	"PanicOnError":            true,
+	"Parse":                   true,
+	"ParseAll":                true,
+	"ParseErrorsAllowlist":    true,
+	"ParseErrorsWhitelist":    true,
+	"ParseIPv4Mask":           true,
+	"Parsed":                  true,
+	"PrintDefaults":           true,
+	"Set":                     true,
+	"SetAnnotation":           true,
+	"SetErrorPrefix":          true,
+	"SetInterspersed":         true,
+	"SetNormalizeFunc":        true,
+	"SetUsageTemplate":        true,
+	"ShorthandConflictError":  true,
+	"ShorthandLookup":         true,
+	"SliceValue":              true,
+	"String":                  true,
+	"StringArray":             true,
+	"StringArrayP":            true,
+	"StringArrayVar":          true,
+	"StringArrayVarP":         true,
+	"StringP":                 true,
+	"StringSlice":             true,
+	"StringSliceP":            true,
+	"StringSliceVar":          true,
+	"StringSliceVarP":         true,
+	"StringToInt":             true,
+	"StringToInt64":           true,
+	"StringToInt64P":          true,
+	"StringToInt64Var":        true,
+	"StringToInt64VarP":       true,
+	"StringToIntP":            true,
+	"StringToIntVar":          true,
+	"StringToIntVarP":         true,
+	"StringToString":          true,
+	"StringToStringP":         true,
+	"StringToStringVar":       true,
+	"StringToStringVarP":      true,
+	"StringVar":               true,
+	"StringVarP":              true,
+	"TextVar":                 true,
+	"TextVarP":                true,
+	"Time":                    true,
+	"TimeP":                   true,
+	"TimeVar":                 true,
+	"TimeVarP":                true,
+	"Uint":                    true,
+	"Uint16":                  true,
+	"Uint16P":                 true,
+	"Uint16Var":               true,
+	"Uint16VarP":              true,
+	"Uint32":                  true,
+	"Uint32P":                 true,
+	"Uint32Var":               true,
+	"Uint32VarP":              true,
+	"Uint64":                  true,
+	"Uint64P":                 true,
+	"Uint64Var":               true,
+	"Uint64VarP":              true,
+	"Uint8":                   true,
+	"Uint8P":                  true,
+	"Uint8Var":                true,
+	"Uint8VarP":               true,
+	"UintP":                   true,
+	"UintSlice":               true,
+	"UintSliceP":              true,
+	"UintSliceVar":            true,
+	"UintSliceVarP":           true,
+	"UintVar":                 true,
+	"UintVarP":                true,
+	"UnquoteUsage":            true,
+	"Usage":                   true,
+	"Value":                   true,
+	"ValueRequiredError":      true,
+	"Var":                     true,
+	"VarP":                    true,
+	"VarPF":                   true,
+	"Visit":                   true,
+	"VisitAll":                true,
+}
+
+// memberAPI holds the exported methods and fields of FlagSet and Flag.
+var memberAPI = map[string]map[string]bool{
+	"Flag": {
+		"Annotations":         true,
+		"Changed":             true,
+		"DefValue":            true,
+		"Deprecated":          true,
+		"Hidden":              true,
+		"Name":                true,
+		"Negatable":           true,
+		"NoOptDefVal":         true,
+		"Prefixes":            true,
+		"Shorthand":           true,
+		"ShorthandDeprecated": true,
+		"Usage":               true,
+		"Value":               true,
+	},
+	"FlagSet": {
+		"AddFlag":                 true,
+		"AddFlagSet":              true,
+		"AddGoFlag":               true,
+		"AddGoFlagSet":            true,
+		"AliasShortVar":           true,
+		"AliasVar":                true,
+		"AliasVarP":               true,
+		"Arg":                     true,
+		"Args":                    true,
+		"ArgsLenAtDash":           true,
+		"BindStruct":              true,
+		"Bool":                    true,
+		"BoolFunc":                true,
+		"BoolFuncP":               true,
+		"BoolP":                   true,
+		"BoolSlice":               true,
+		"BoolSliceP":              true,
+		"BoolSliceVar":            true,
+		"BoolSliceVarP":           true,
+		"BoolVar":                 true,
+		"BoolVarP":                true,
+		"BytesBase64":             true,
+		"BytesBase64P":            true,
+		"BytesBase64Var":          true,
+		"BytesBase64VarP":         true,
+		"BytesHex":                true,
+		"BytesHexP":               true,
+		"BytesHexVar":             true,
+		"BytesHexVarP":            true,
+		"Changed":                 true,
+		"ChangedFlags":            true,
+		"Count":                   true,
+		"CountP":                  true,
+		"CountVar":                true,
+		"CountVarP":               true,
+		"Duration":                true,
+		"DurationP":               true,
+		"DurationSlice":           true,
+		"DurationSliceP":          true,
+		"DurationSliceVar":        true,
+		"DurationSliceVarP":       true,
+		"DurationVar":             true,
+		"DurationVarP":            true,
+		"EnableNegations":         true,
+		"FlagUsages":              true,
+		"FlagUsagesWrapped":       true,
+		"Float32":                 true,
+		"Float32P":                true,
+		"Float32Slice":            true,
+		"Float32SliceP":           true,
+		"Float32SliceVar":         true,
+		"Float32SliceVarP":        true,
+		"Float32Var":              true,
+		"Float32VarP":             true,
+		"Float64":                 true,
+		"Float64P":                true,
+		"Float64Slice":            true,
+		"Float64SliceP":           true,
+		"Float64SliceVar":         true,
+		"Float64SliceVarP":        true,
+		"Float64Var":              true,
+		"Float64VarP":             true,
+		"Func":                    true,
+		"FuncP":                   true,
+		"GetBool":                 true,
+		"GetBoolSlice":            true,
+		"GetBytesBase64":          true,
+		"GetBytesHex":             true,
+		"GetCount":                true,
+		"GetDuration":             true,
+		"GetDurationSlice":        true,
+		"GetFloat32":              true,
+		"GetFloat32Slice":         true,
+		"GetFloat64":              true,
+		"GetFloat64Slice":         true,
+		"GetInt":                  true,
+		"GetInt16":                true,
+		"GetInt32":                true,
+		"GetInt32Slice":           true,
+		"GetInt64":                true,
+		"GetInt64Slice":           true,
+		"GetInt8":                 true,
+		"GetIntSlice":             true,
+		"GetInterspersed":         true,
+		"GetNormalizeFunc":        true,
+		"GetString":               true,
+		"GetStringSlice":          true,
+		"GetStringToInt":          true,
+		"GetStringToInt64":        true,
+		"GetStringToString":       true,
+		"GetTime":                 true,
+		"GetUint":                 true,
+		"GetUint16":               true,
+		"GetUint32":               true,
+		"GetUint64":               true,
+		"GetUint8":                true,
+		"GetUintSlice":            true,
+		"HasAvailableFlags":       true,
+		"HasFlags":                true,
+		"IP":                      true,
+		"IPMask":                  true,
+		"IPMaskP":                 true,
+		"IPMaskVar":               true,
+		"IPMaskVarP":              true,
+		"IPNet":                   true,
+		"IPNetP":                  true,
+		"IPNetVar":                true,
+		"IPNetVarP":               true,
+		"IPP":                     true,
+		"IPVar":                   true,
+		"IPVarP":                  true,
+		"Init":                    true,
+		"Int":                     true,
+		"Int16":                   true,
+		"Int16P":                  true,
+		"Int16Var":                true,
+		"Int16VarP":               true,
+		"Int32":                   true,
+		"Int32P":                  true,
+		"Int32Slice":              true,
+		"Int32SliceP":             true,
+		"Int32SliceVar":           true,
+		"Int32SliceVarP":          true,
+		"Int32Var":                true,
+		"Int32VarP":               true,
+		"Int64":                   true,
+		"Int64P":                  true,
+		"Int64Slice":              true,
+		"Int64SliceP":             true,
+		"Int64SliceVar":           true,
+		"Int64SliceVarP":          true,
+		"Int64Var":                true,
+		"Int64VarP":               true,
+		"Int8":                    true,
+		"Int8P":                   true,
+		"Int8Var":                 true,
+		"Int8VarP":                true,
+		"IntP":                    true,
+		"IntSlice":                true,
+		"IntSliceP":               true,
+		"IntSliceVar":             true,
+		"IntSliceVarP":            true,
+		"IntVar":                  true,
+		"IntVarP":                 true,
+		"LongOnly":                true,
+		"Lookup":                  true,
+		"MarkBoolPrefix":          true,
+		"MarkDeprecated":          true,
+		"MarkHidden":              true,
+		"MarkNegatable":           true,
+		"MarkShorthandDeprecated": true,
+		"NArg":                    true,
+		"NFlag":                   true,
+		"Name":                    true,
+		"NegationsEnabled":        true,
+		"Output":                  true,
+		"Parent":                  true,
+		"Parse":                   true,
+		"ParseAll":                true,
+		"ParseErrorsAllowlist":    true,
+		"ParseErrorsWhitelist":    true,
+		"Parsed":                  true,
+		"PrintDefaults":           true,
+		"Set":                     true,
+		"SetAnnotation":           true,
+		"SetErrorPrefix":          true,
+		"SetInterspersed":         true,
+		"SetLongOnly":             true,
+		"SetNormalizeFunc":        true,
+		"SetOutput":               true,
+		"SetParent":               true,
+		"SetUsageTemplate":        true,
+		"ShortVar":                true,
+		"ShorthandLookup":         true,
+		"SortFlags":               true,
+		"String":                  true,
+		"StringArray":             true,
+		"StringArrayP":            true,
+		"StringArrayVar":          true,
+		"StringArrayVarP":         true,
+		"StringP":                 true,
+		"StringSlice":             true,
+		"StringSliceP":            true,
+		"StringSliceVar":          true,
+		"StringSliceVarP":         true,
+		"StringToInt":             true,
+		"StringToInt64":           true,
+		"StringToInt64P":          true,
+		"StringToInt64Var":        true,
+		"StringToInt64VarP":       true,
+		"StringToIntP":            true,
+		"StringToIntVar":          true,
+		"StringToIntVarP":         true,
+		"StringToString":          true,
+		"StringToStringP":         true,
+		"StringToStringVar":       true,
+		"StringToStringVarP":      true,
+		"StringVar":               true,
+		"StringVarP":              true,
+		"TextVar":                 true,
+		"TextVarP":                true,
+		"Time":                    true,
+		"TimeP":                   true,
+		"TimeVar":                 true,
+		"TimeVarP":                true,
+		"Uint":                    true,
+		"Uint16":                  true,
+		"Uint16P":                 true,
+		"Uint16Var":               true,
+		"Uint16VarP":              true,
+		"Uint32":                  true,
+		"Uint32P":                 true,
+		"Uint32Var":               true,
+		"Uint32VarP":              true,
+		"Uint64":                  true,
+		"Uint64P":                 true,
+		"Uint64Var":               true,
+		"Uint64VarP":              true,
+		"Uint8":                   true,
+		"Uint8P":                  true,
+		"Uint8Var":                true,
+		"Uint8VarP":               true,
+		"UintP":                   true,
+		"UintSlice":               true,
+		"UintSliceP":              true,
+		"UintSliceVar":            true,
+		"UintSliceVarP":           true,
+		"UintVar":                 true,
+		"UintVarP":                true,
+		"Usage":                   true,
+		"Var":                     true,
+		"VarP":                    true,
+		"VarPF":                   true,
+		"Visit":                   true,
+		"VisitAll":                true,
+	},
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate api.go from the pflag package")
+
+// TestAPIUpToDate verifies api.go lists exactly the exported API of the
+// pflag package in the parent directory. Run `go test -update` after
+// changing that API.
+func TestAPIUpToDate(t *testing.T) {
+	pkg, members := scanAPI(t, "../..")
+	want := renderAPI(t, pkg, members)
+	if *update {
+		if err := os.WriteFile("api.go", want, 0o644); err != nil { //nolint:gosec // source file
+			t.Fatal(err)
+		}
+		return
+	}
+	got, err := os.ReadFile("api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("api.go is stale; run `go test -update` in pflag/cmd/pflags-migrate")
+	}
+}
+
+// scanAPI returns the exported package-level identifiers of the package in
+// dir, and the exported methods and fields of each type in memberAPI's
+// key set (FlagSet, Flag).
+func scanAPI(t *testing.T, dir string) ([]string, map[string][]string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := pkgs["pflag"]
+	if !ok {
+		t.Fatalf("no pflag package in %s", dir)
+	}
+
+	var pkg []string
+	members := map[string][]string{"FlagSet": nil, "Flag": nil}
+	for _, f := range p.Files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if !d.Name.IsExported() {
+					continue
+				}
+				if d.Recv == nil {
+					pkg = append(pkg, d.Name.Name)
+					continue
+				}
+				recv := d.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if id, ok := recv.(*ast.Ident); ok {
+					if _, ok := members[id.Name]; ok {
+						members[id.Name] = append(members[id.Name], d.Name.Name)
+					}
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						if !s.Name.IsExported() {
+							continue
+						}
+						pkg = append(pkg, s.Name.Name)
+						st, ok := s.Type.(*ast.StructType)
+						if _, tracked := members[s.Name.Name]; !ok || !tracked {
+							continue
+						}
+						for _, field := range st.Fields.List {
+							for _, name := range field.Names {
+								if name.IsExported() {
+									members[s.Name.Name] = append(members[s.Name.Name], name.Name)
+								}
+							}
+						}
+					case *ast.ValueSpec:
+						for _, name := range s.Names {
+							if name.IsExported() {
+								pkg = append(pkg, name.Name)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return pkg, members
+}
+
+func renderAPI(t *testing.T, pkg []string, members map[string][]string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	b.WriteString("// Code generated by `go test -update`; DO NOT EDIT.\n\npackage main\n\n")
+	b.WriteString("// packageAPI holds the exported package-level identifiers of\n// github.com/major0/optargs/pflag.\n")
+	b.WriteString("var packageAPI = map[string]bool{\n")
+	writeNames(&b, pkg)
+	b.WriteString("}\n\n")
+	b.WriteString("// memberAPI holds the exported methods and fields of FlagSet and Flag.\n")
+	b.WriteString("var memberAPI = map[string]map[string]bool{\n")
+	for _, typ := range []string{"Flag", "FlagSet"} {
+		fmt.Fprintf(&b, "%q: {\n", typ)
+		writeNames(&b, members[typ])
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n")
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+func writeNames(b *bytes.Buffer, names []string) {
+	names = slices.Clone(names)
+	slices.Sort(names)
+	for _, name := range slices.Compact(names) {
+		fmt.Fprintf(b, "%q: true,\n", name)
+	}
+}
@@ -0,0 +1,161 @@
+// Command pflags-migrate reports how a codebase uses github.com/spf13/pflag
+// and whether each use is provided by github.com/major0/optargs/pflag.
+//
+// Usage:
+//
+//	pflags-migrate [-v] [-w] [path ...]
+//
+// Each path is a Go file or a directory, which is searched recursively
+// (skipping vendor, testdata, and directories starting with "." or "_");
+// a trailing "/..." is accepted and ignored. The default is ".".
+//
+// Every unsupported use is printed as file:line:col: name. With -v,
+// supported uses are printed too. With -w, files whose uses are all
+// supported have their import rewritten to the compatibility package.
+// Remember to add github.com/major0/optargs/pflag to go.mod afterwards.
+//
+// The exit status is 1 when any unsupported use is found and 2 on error.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run is main without the process exit, for testing.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("pflags-migrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	verbose := flags.Bool("v", false, "also print supported uses")
+	write := flags.Bool("w", false, "rewrite imports in files with no unsupported uses")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	files, err := goFiles(paths)
+	if err != nil {
+		fmt.Fprintln(stderr, "pflags-migrate:", err)
+		return 2
+	}
+
+	var total, unsupported, rewritten, touched int
+	for _, path := range files {
+		uses, wrote, err := migrateFile(path, *write)
+		if err != nil {
+			fmt.Fprintln(stderr, "pflags-migrate:", err)
+			return 2
+		}
+		if len(uses) > 0 {
+			touched++
+		}
+		if wrote {
+			rewritten++
+		}
+		for _, use := range uses {
+			total++
+			if !use.Supported {
+				unsupported++
+				fmt.Fprintf(stdout, "%s: %s: unsupported\n", use.Pos, use.Name)
+			} else if *verbose {
+				fmt.Fprintf(stdout, "%s: %s\n", use.Pos, use.Name)
+			}
+		}
+	}
+
+	fmt.Fprintf(stderr, "pflags-migrate: %d uses in %d files, %d unsupported", total, touched, unsupported)
+	if *write {
+		fmt.Fprintf(stderr, ", %d files rewritten", rewritten)
+	}
+	fmt.Fprintln(stderr)
+	if unsupported > 0 {
+		return 1
+	}
+	return 0
+}
+
+// migrateFile analyzes one file and, when write is set and every use is
+// supported, rewrites its pflag import in place.
+func migrateFile(path string, write bool) ([]Use, bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, false, err
+	}
+	uses := analyzeFile(fset, f)
+	if !write || importName(f) == "" {
+		return uses, false, nil
+	}
+	for _, use := range uses {
+		if !use.Supported {
+			return uses, false, nil
+		}
+	}
+	if !rewriteImports(f) {
+		return uses, false, nil
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return nil, false, err
+	}
+	return uses, true, nil
+}
+
+// goFiles expands paths into the Go source files to analyze.
+func goFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		root = strings.TrimSuffix(root, "/...")
+		if root == "" {
+			root = "."
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != root && (name == "vendor" || name == "testdata" ||
+					strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
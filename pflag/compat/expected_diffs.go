@@ -35,7 +35,7 @@ var ExpectedDiffs = []ExpectedDiff{
 	{
 		Scenario:  "--no-<flag>=true and --no-<flag>=false (explicit values on negation)",
 		Upstream:  "Not supported; --no-verbose is not recognized at all. See https://github.com/spf13/pflag/issues/214, https://github.com/spf13/cobra/issues/958",
-		Ours:      "--no-verbose sets false; --no-verbose=true sets false; --no-verbose=false sets true (double negation)",
+		Ours:      "With EnableNegations(true), --no-verbose sets false; --no-verbose=true sets false; --no-verbose=false sets true (double negation). Off by default, as upstream",
 		Rationale: "GNU convention for boolean negation; explicit values allow scripted flag composition without conditional logic",
 	},
 	{
@@ -62,4 +62,10 @@ var ExpectedDiffs = []ExpectedDiff{
 		Ours:      "Types implement BoolTakesArg() to declare NoArgument vs OptionalArgument; Count and BoolFunc are strictly no-argument",
 		Rationale: "Prevents Count/BoolFunc flags from consuming the next positional argument as a value",
 	},
+	{
+		Scenario:  "Time flag defined with nil or empty formats",
+		Upstream:  "Every value is rejected: \"invalid time format `...` must be one of: \" with an empty list",
+		Ours:      "Values are parsed as time.RFC3339",
+		Rationale: "A flag that can never be set is a silent definition bug; RFC3339 is the layout GetTime and usage defaults are rendered in",
+	},
 }
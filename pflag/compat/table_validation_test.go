@@ -254,6 +254,18 @@ func TestTable_IPIPMaskIPNet(t *testing.T) {
 	}
 }
 
+func TestTable_Time(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	when := fs.Time("when", time.Time{}, []string{time.DateOnly}, "")
+	if err := fs.Parse([]string{"--when", "2024-05-01"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := fs.GetTime("when")
+	if err != nil || !got.Equal(*when) || when.Format(time.DateOnly) != "2024-05-01" {
+		t.Errorf("when = %v, GetTime = %v, %v", when, got, err)
+	}
+}
+
 func TestTable_TextVar(t *testing.T) {
 	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
 	var ip net.IP
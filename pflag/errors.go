@@ -30,6 +30,18 @@ func (e *NotExistError) GetSpecifiedName() string { return e.specifiedName }
 // (without dashes) that the flag appeared within. Empty if not in a group.
 func (e *NotExistError) GetSpecifiedShortnames() string { return e.specifiedShortnames }
 
+// ShorthandConflictError reports a flag added to a FlagSet with a
+// shorthand another flag of the set already uses; see [FlagSet.AddFlag].
+type ShorthandConflictError struct {
+	Shorthand string // the contested shorthand
+	Flag      string // the flag that requested it
+	Existing  string // the flag that already uses it
+}
+
+func (e *ShorthandConflictError) Error() string {
+	return fmt.Sprintf("shorthand %s of flag %s already used for flag %s", e.Shorthand, e.Flag, e.Existing)
+}
+
 // InvalidValueError is the error returned when an invalid value is used
 // for a flag.
 type InvalidValueError struct {
@@ -7,6 +7,8 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
+	"text/template"
 
 	"github.com/major0/optargs"
 )
@@ -67,6 +69,12 @@ type Flag struct {
 }
 
 // FlagSet represents a set of defined flags.
+//
+// Flags may be defined on a FlagSet from several goroutines or from the
+// init functions of many packages sharing one set; a name or shorthand
+// defined twice panics with the file and line of both definitions. A zero
+// FlagSet is ready for definitions. Parsing and lookups are not
+// synchronized with definitions and should follow them.
 type FlagSet struct {
 	// Usage is the function called when an error occurs while parsing flags.
 	// The field is a function (not a method) that may be changed to point to
@@ -82,10 +90,14 @@ type FlagSet struct {
 	args              []string // arguments after flags
 	argsLenAtDash     int      // len(args) when -- was encountered; -1 if no --
 	errorHandling     ErrorHandling
-	output            io.Writer // nil means stderr; use out() accessor
-	interspersed      bool      // allow interspersed option/non-option args
-	longOnly          bool      // getopt_long_only(3) mode
+	output            io.Writer          // nil means stderr; use out() accessor
+	usageTemplate     *template.Template // default Usage layout; nil for the built-in one
+	errorPrefix       string             // printed before parse errors by failf
+	interspersed      bool               // allow interspersed option/non-option args
+	longOnly          bool               // getopt_long_only(3) mode
+	negations         bool               // register automatic --no-<name> for booleans
 	normalizeNameFunc func(f *FlagSet, name string) NormalizedName
+	parent            *FlagSet // set whose flags this one inherits; see SetParent
 
 	// Flag storage and management
 	flags     map[string]*Flag  // flags by long name
@@ -93,8 +105,9 @@ type FlagSet struct {
 	shorthand map[string]string // shorthand to long name mapping
 	order     []string          // order of flag definition for help text
 
-	// parseAllFn is set by ParseAll to receive callbacks for each parsed flag.
-	parseAllFn func(flag *Flag, value string) error
+	// mu guards registration; sites records where each flag was defined.
+	mu    sync.Mutex
+	sites map[*Flag]string
 
 	// ParseErrorsAllowlist defines parsing errors that can be ignored.
 	ParseErrorsAllowlist ParseErrorsAllowlist
@@ -153,12 +166,30 @@ func (f *FlagSet) LongOnly() bool {
 	return f.longOnly
 }
 
+// EnableNegations sets whether boolean flags get an automatic --no-<name>
+// spelling. When enabled, --no-verbose sets verbose to false,
+// --no-verbose=false sets it to true, and either marks verbose as Changed.
+// The negation is not listed in usage text, and a flag explicitly
+// registered under the no- name takes precedence over it. It is disabled
+// by default, matching upstream pflag, where --no-<name> is an unknown
+// flag.
+func (f *FlagSet) EnableNegations(enabled bool) {
+	f.negations = enabled
+}
+
+// NegationsEnabled returns whether boolean flags get automatic --no-<name>
+// spellings.
+func (f *FlagSet) NegationsEnabled() bool {
+	return f.negations
+}
+
 // SetNormalizeFunc allows you to add a function which can translate flag names.
 // Flags added to the FlagSet will be translated and then when anything tries to
 // look up the flag that will also be translated. So it would be possible to create
 // a flag named "getURL" and have it translated to "geturl". A user could then pass
 // "--getUrl" which may also be translated to "geturl" and everything will work.
 func (f *FlagSet) SetNormalizeFunc(n func(f *FlagSet, name string) NormalizedName) {
+	defer f.lockRegistration()()
 	f.normalizeNameFunc = n
 	// Re-normalize existing flags under the new function.
 	newFlags := make(map[string]*Flag, len(f.flags))
@@ -178,7 +209,11 @@ func (f *FlagSet) GetNormalizeFunc() func(f *FlagSet, name string) NormalizedNam
 }
 
 // SetInterspersed sets whether to support interspersed option/non-option arguments.
-// When false, option processing stops at the first non-option argument (POSIX behavior).
+// When true (the default) the core parser permutes arguments so flags may
+// follow positionals (GNU behavior). When false, option processing stops at
+// the first non-option argument and everything from it onward, including any
+// later "--", is left in Args (POSIXLY_CORRECT behavior). Exec-style commands
+// use this to pass trailing flags through to a wrapped program.
 func (f *FlagSet) SetInterspersed(interspersed bool) {
 	f.interspersed = interspersed
 }
@@ -188,6 +223,35 @@ func (f *FlagSet) GetInterspersed() bool {
 	return f.interspersed
 }
 
+// SetParent makes the flags of parent, and of its own parent in turn,
+// usable when f is parsed, the way persistent flags of a command are
+// usable by its subcommands. A flag of f shadows an inherited flag of the
+// same name or shorthand. An inherited flag is set on the set that
+// defines it: its Changed field, its variable, and the getters of that
+// set report the value.
+//
+// For the local and persistent flags of a command framework, give each
+// command two sets: its local flags, whose parent is its persistent
+// flags, whose parent is the persistent flags of the parent command.
+// Local flags are then seen only by the command, persistent ones by it
+// and every command below it.
+//
+// A nil parent removes the link. SetParent panics if the link would make
+// f its own ancestor.
+func (f *FlagSet) SetParent(parent *FlagSet) {
+	for p := parent; p != nil; p = p.parent {
+		if p == f {
+			panic(fmt.Sprintf("flag set %q cannot be its own ancestor", f.name))
+		}
+	}
+	f.parent = parent
+}
+
+// Parent returns the set f inherits flags from, or nil.
+func (f *FlagSet) Parent() *FlagSet {
+	return f.parent
+}
+
 // Changed returns true if the named flag was set during Parse().
 func (f *FlagSet) Changed(name string) bool {
 	flag := f.Lookup(name)
@@ -208,6 +272,19 @@ func (f *FlagSet) NFlag() int {
 	return n
 }
 
+// ChangedFlags returns the names of the flags that have been set, sorted,
+// so applications can report which flags are actually used.
+func (f *FlagSet) ChangedFlags() []string {
+	var names []string
+	for _, name := range f.order {
+		if flag := f.flags[name]; flag.Changed {
+			names = append(names, flag.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // HasFlags returns true if the FlagSet has any flags defined.
 func (f *FlagSet) HasFlags() bool {
 	return len(f.flags) > 0 || len(f.shortOnly) > 0
@@ -369,24 +446,35 @@ func (f *FlagSet) MarkNegatable(name string) error {
 }
 
 // AddFlag adds the flag to the FlagSet. If a flag with the same name already
-// exists, the new flag is silently ignored (matching upstream pflag behavior).
+// exists, the new flag is silently ignored, so merging a set twice is
+// harmless.
+//
+// If the flag's shorthand is already used by another flag, a
+// *ShorthandConflictError naming both flags is reported when the flag is
+// added. Under ContinueOnError it is written to the output and the flag
+// is left out, as upstream does silently; under ExitOnError and
+// PanicOnError AddFlag panics with the error and both definition sites.
 func (f *FlagSet) AddFlag(flag *Flag) {
+	defer f.lockRegistration()()
 	normalName := f.normalizeFlagName(flag.Name)
 	if f.flags[normalName] != nil {
 		return // silently ignore duplicates
 	}
 	if len(flag.Shorthand) > 0 {
-		if _, exists := f.shorthand[flag.Shorthand]; exists {
-			return // silently ignore shorthand conflicts
+		if existing := f.shorthandOwner(flag.Shorthand); existing != nil {
+			f.shorthandConflict(flag, existing)
+			return
 		}
 		f.shorthand[flag.Shorthand] = flag.Name
 	}
 	f.flags[normalName] = flag
 	f.order = append(f.order, normalName)
+	f.recordSite(flag)
 }
 
 // AddFlagSet adds all flags from newSet to f. If a flag already exists in f,
-// the flag from newSet is silently ignored.
+// the flag from newSet is silently ignored; a shorthand conflict is handled
+// as by AddFlag.
 func (f *FlagSet) AddFlagSet(newSet *FlagSet) {
 	if newSet == nil {
 		return
@@ -395,11 +483,12 @@ func (f *FlagSet) AddFlagSet(newSet *FlagSet) {
 		f.AddFlag(flag)
 	})
 	// Also add short-only flags
+	defer f.lockRegistration()()
 	for _, flag := range newSet.shortOnly {
-		if _, exists := f.shortOnly[flag.Shorthand]; exists {
-			continue
-		}
-		if _, exists := f.shorthand[flag.Shorthand]; exists {
+		if existing := f.shorthandOwner(flag.Shorthand); existing != nil {
+			if existing != flag {
+				f.shorthandConflict(flag, existing)
+			}
 			continue
 		}
 		f.shortOnly[flag.Shorthand] = flag
@@ -435,8 +524,44 @@ func (f *FlagSet) Arg(i int) string {
 	return f.args[i]
 }
 
+// SetUsageTemplate replaces the layout of the default Usage function with
+// the text/template tmpl, executed with the FlagSet as its data, so
+// embedding frameworks can brand usage output without replacing Usage:
+//
+//	fs.SetUsageTemplate("Usage: {{.Name}} [flags]\n\nFlags:\n{{.FlagUsages}}")
+//
+// The template is read each time usage is printed, so it may be set after
+// Usage has been assigned elsewhere; it has no effect once Usage is
+// replaced. An empty tmpl restores the built-in layout. It returns the
+// error from parsing tmpl, leaving the layout unchanged.
+func (f *FlagSet) SetUsageTemplate(tmpl string) error {
+	if tmpl == "" {
+		f.usageTemplate = nil
+		return nil
+	}
+	t, err := template.New("usage").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	f.usageTemplate = t
+	return nil
+}
+
+// SetErrorPrefix sets the text printed before a parse error when the
+// ErrorHandling mode reports it (ExitOnError and PanicOnError), such as
+// "Error: " or "myctl: error: ". The returned error is unaffected.
+func (f *FlagSet) SetErrorPrefix(prefix string) {
+	f.errorPrefix = prefix
+}
+
 // defaultUsage is the default function to print a usage message.
 func (f *FlagSet) defaultUsage() {
+	if f.usageTemplate != nil {
+		if err := f.usageTemplate.Execute(f.out(), f); err != nil {
+			fmt.Fprintln(f.out(), err)
+		}
+		return
+	}
 	if f.name == "" {
 		fmt.Fprintf(f.out(), "Usage:\n")
 	} else {
@@ -479,7 +604,7 @@ func (f *FlagSet) printDefaultsTo(w io.Writer) {
 		typeName, usageText := UnquoteUsage(fl)
 
 		var prefix string
-		if len(fl.Shorthand) > 0 {
+		if len(fl.Shorthand) > 0 && fl.ShorthandDeprecated == "" {
 			prefix = fmt.Sprintf("  -%s, --%s", fl.Shorthand, fl.Name)
 		} else {
 			prefix = fmt.Sprintf("      --%s", fl.Name)
@@ -487,6 +612,7 @@ func (f *FlagSet) printDefaultsTo(w io.Writer) {
 		if len(typeName) > 0 {
 			prefix += " " + typeName
 		}
+		prefix += noOptDefSuffix(fl)
 
 		// Append prefix pair forms
 		var prefixSb477 strings.Builder
@@ -506,12 +632,10 @@ func (f *FlagSet) printDefaultsTo(w io.Writer) {
 	}
 
 	for _, line := range lines {
+		// Upstream pads every line to the usage column, even when the
+		// usage text is empty; snapshot tests depend on the trailing spaces.
 		padding := strings.Repeat(" ", maxLen-len(line.prefix))
-		if len(line.usage) > 0 {
-			fmt.Fprintf(w, "%s%s   %s", line.prefix, padding, line.usage)
-		} else {
-			fmt.Fprint(w, line.prefix)
-		}
+		fmt.Fprintf(w, "%s%s   %s", line.prefix, padding, line.usage)
 		if !isZeroValue(line.flag, line.flag.DefValue) {
 			if line.flag.Value.Type() == typeNameString {
 				fmt.Fprintf(w, " (default %q)", line.flag.DefValue)
@@ -583,19 +707,63 @@ func wrapLine(line string, cols int) string {
 // sortStrings sorts a slice of strings in place.
 func sortStrings(s []string) { sort.Strings(s) }
 
-// isZeroValue determines whether the string represents the zero
-// value for a flag. Delegates to optargs.ZeroString for O(1) lookup.
+// noOptDefSuffix returns the "[=value]" annotation upstream appends to
+// flags whose NoOptDefVal differs from the type's implicit one.
+func noOptDefSuffix(flag *Flag) string {
+	if flag.NoOptDefVal == "" {
+		return ""
+	}
+	switch flag.Value.Type() {
+	case typeNameString:
+		return "[=\"" + flag.NoOptDefVal + "\"]"
+	case "bool", "boolfunc", "boolFunc":
+		if flag.NoOptDefVal == "true" {
+			return ""
+		}
+	case "count":
+		if flag.NoOptDefVal == "+1" {
+			return ""
+		}
+	}
+	return "[=" + flag.NoOptDefVal + "]"
+}
+
+// isZeroValue reports whether value is the zero default for flag, i.e.
+// whether "(default …)" is omitted from usage. The per-type rules mirror
+// upstream pflag exactly; notably, empty slices other than int, string,
+// and string-array slices still print "(default [])".
 func isZeroValue(flag *Flag, value string) bool {
-	if z, ok := optargs.ZeroString(flag.Value.Type()); ok {
-		return value == z
+	switch flag.Value.Type() {
+	case "duration":
+		return value == "0" || value == "0s"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"count", "float32", "float64":
+		return value == "0"
+	case typeNameString:
+		return value == ""
+	case "ip", "ipMask", "ipNet":
+		return value == "<nil>"
+	case "intSlice", "stringSlice", "stringArray":
+		return value == "[]"
+	}
+	if isBoolFlag(flag.Value) {
+		return value == "false" || value == ""
+	}
+	switch value {
+	case "false", "<nil>", "", "0":
+		return true
 	}
 	return false
 }
 
-// typeDisplayNames maps Value type names to their display names in usage text.
+// typeDisplayNames maps Value type names to their display names in usage
+// text, matching upstream UnquoteUsage.
 var typeDisplayNames = map[string]string{
-	"bool": "", "float32": "float", "float64": "float",
-	"int64": "int", "uint64": "uint",
+	"bool": "", "boolfunc": "", "boolFunc": "", "func": "value",
+	"float64": "float", "int64": "int", "uint64": "uint",
+	"stringSlice": "strings", "intSlice": "ints",
+	"uintSlice": "uints", "boolSlice": "bools",
 }
 
 // UnquoteUsage extracts a back-quoted name from the usage
@@ -674,21 +842,24 @@ func (f *FlagSet) Visit(fn func(*Flag)) {
 
 // addFlag will add the flag to the FlagSet.
 func (f *FlagSet) addFlag(flag *Flag) {
+	defer f.lockRegistration()()
 	normalName := f.normalizeFlagName(flag.Name)
-	if f.flags[normalName] != nil {
-		panic(fmt.Sprintf("flag redefined: %s", flag.Name))
+	if existing := f.flags[normalName]; existing != nil {
+		panic(f.redefined("flag redefined: "+flag.Name, existing))
 	}
 
 	// Check for shorthand conflicts
 	if len(flag.Shorthand) > 0 {
-		if existingName, exists := f.shorthand[flag.Shorthand]; exists {
-			panic(fmt.Sprintf("shorthand %s already used for flag %s", flag.Shorthand, existingName))
+		if existing := f.shorthandOwner(flag.Shorthand); existing != nil {
+			err := &ShorthandConflictError{Shorthand: flag.Shorthand, Flag: flag.Name, Existing: existing.Name}
+			panic(f.redefined(err.Error(), existing))
 		}
 		f.shorthand[flag.Shorthand] = flag.Name
 	}
 
 	f.flags[normalName] = flag
 	f.order = append(f.order, normalName)
+	f.recordSite(flag)
 }
 
 // Var defines a flag with the specified name and usage string. The type and
@@ -716,6 +887,7 @@ func (f *FlagSet) VarP(value Value, name, shorthand, usage string) {
 // ShortVar registers a short-only flag (no long name). The flag is accessible
 // only via its single-character shorthand and participates in POSIX compaction.
 func (f *FlagSet) ShortVar(value Value, shorthand, usage string) {
+	defer f.lockRegistration()()
 	f.validateShorthand(shorthand)
 	flag := &Flag{
 		Name:      shorthand,
@@ -725,6 +897,7 @@ func (f *FlagSet) ShortVar(value Value, shorthand, usage string) {
 		DefValue:  value.String(),
 	}
 	f.shortOnly[shorthand] = flag
+	f.recordSite(flag)
 }
 
 // AliasVar registers an additional flag name that writes to the same Value
@@ -758,6 +931,7 @@ func (f *FlagSet) AliasVarP(value Value, name, shorthand, usage string) {
 // AliasShortVar registers a short-only alias that writes to the same Value.
 // The alias is hidden from help text by default.
 func (f *FlagSet) AliasShortVar(value Value, shorthand string) {
+	defer f.lockRegistration()()
 	f.validateShorthand(shorthand)
 	flag := &Flag{
 		Name:      shorthand,
@@ -767,17 +941,19 @@ func (f *FlagSet) AliasShortVar(value Value, shorthand string) {
 		Hidden:    true,
 	}
 	f.shortOnly[shorthand] = flag
+	f.recordSite(flag)
 }
 
 // validateShorthand panics if the shorthand is invalid or already in use.
+// The caller holds the registration lock.
 func (f *FlagSet) validateShorthand(shorthand string) {
 	if len(shorthand) != 1 {
 		panic("shorthand must be exactly one character")
 	}
-	if _, exists := f.shortOnly[shorthand]; exists {
-		panic(fmt.Sprintf("short-only flag redefined: %s", shorthand))
+	if existing, exists := f.shortOnly[shorthand]; exists {
+		panic(f.redefined("short-only flag redefined: "+shorthand, existing))
 	}
-	if _, exists := f.shorthand[shorthand]; exists {
-		panic(fmt.Sprintf("shorthand %s already in use", shorthand))
+	if longName, exists := f.shorthand[shorthand]; exists {
+		panic(f.redefined("shorthand "+shorthand+" already in use", f.flags[f.normalizeFlagName(longName)]))
 	}
 }
@@ -739,6 +739,26 @@ func (f *FlagSet) IPNetP(name, shorthand string, value net.IPNet, usage string)
 	return p
 }
 
+// -- Time. formats lists the accepted layouts for time.Parse, tried in
+// order; nil accepts time.RFC3339.
+
+func (f *FlagSet) TimeVar(p *time.Time, name string, value time.Time, formats []string, usage string) {
+	f.VarP(newTimeValue(value, p, formats), name, "", usage)
+}
+func (f *FlagSet) TimeVarP(p *time.Time, name, shorthand string, value time.Time, formats []string, usage string) {
+	f.VarP(newTimeValue(value, p, formats), name, shorthand, usage)
+}
+func (f *FlagSet) Time(name string, value time.Time, formats []string, usage string) *time.Time {
+	p := new(time.Time)
+	f.TimeVarP(p, name, "", value, formats, usage)
+	return p
+}
+func (f *FlagSet) TimeP(name, shorthand string, value time.Time, formats []string, usage string) *time.Time {
+	p := new(time.Time)
+	f.TimeVarP(p, name, shorthand, value, formats, usage)
+	return p
+}
+
 // -- BytesHex.
 
 func (f *FlagSet) BytesHexVar(p *[]byte, name string, value []byte, usage string) {
@@ -10,14 +10,15 @@ import (
 )
 
 // getFlagValue looks up a flag and returns its string value, or an error
-// if the flag doesn't exist or has the wrong type.
+// if the flag doesn't exist or has the wrong type. Error text matches
+// upstream pflag.
 func (f *FlagSet) getFlagValue(name, typeName string) (string, error) {
 	flag := f.Lookup(name)
 	if flag == nil {
-		return "", fmt.Errorf("flag %q not found", name)
+		return "", fmt.Errorf("flag accessed but not defined: %s", name)
 	}
 	if flag.Value.Type() != typeName {
-		return "", fmt.Errorf("flag %q is type %s, not %s", name, flag.Value.Type(), typeName)
+		return "", fmt.Errorf("trying to get %s value of flag of type %s", typeName, flag.Value.Type())
 	}
 	return flag.Value.String(), nil
 }
@@ -240,7 +241,8 @@ func (f *FlagSet) GetDurationSlice(name string) ([]time.Duration, error) {
 // --- Map getters ---.
 
 func parseStringMap(s string) map[string]string {
-	s = strings.TrimPrefix(s, "map[")
+	s = strings.TrimPrefix(s, "map")
+	s = strings.TrimPrefix(s, "[")
 	s = strings.TrimSuffix(s, "]")
 	if s == "" {
 		return nil
@@ -315,3 +317,21 @@ func (f *FlagSet) GetBytesBase64(name string) ([]byte, error) {
 	}
 	return base64.StdEncoding.DecodeString(s)
 }
+
+// --- Time getter ---.
+
+// GetTime returns the time.Time value of a flag with the given name. The
+// zero time is returned for a flag that was never set and has no default.
+func (f *FlagSet) GetTime(name string) (time.Time, error) {
+	s, err := f.getFlagValue(name, "time")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if v, ok := f.Lookup(name).Value.(*timeValue); ok {
+		return *v.p, nil
+	}
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
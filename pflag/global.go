@@ -394,6 +394,18 @@ func IPNet(name string, value net.IPNet, usage string) *net.IPNet {
 func IPNetP(name, sh string, value net.IPNet, usage string) *net.IPNet {
 	return CommandLine.IPNetP(name, sh, value, usage)
 }
+func TimeVar(p *time.Time, name string, value time.Time, formats []string, usage string) {
+	CommandLine.TimeVar(p, name, value, formats, usage)
+}
+func TimeVarP(p *time.Time, name, sh string, value time.Time, formats []string, usage string) {
+	CommandLine.TimeVarP(p, name, sh, value, formats, usage)
+}
+func Time(name string, value time.Time, formats []string, usage string) *time.Time {
+	return CommandLine.Time(name, value, formats, usage)
+}
+func TimeP(name, sh string, value time.Time, formats []string, usage string) *time.Time {
+	return CommandLine.TimeP(name, sh, value, formats, usage)
+}
 
 // --- Callback flags ---.
 
@@ -442,12 +454,16 @@ func Visit(fn func(*Flag))    { CommandLine.Visit(fn) }
 
 func Changed(name string) bool                                 { return CommandLine.Changed(name) }
 func NFlag() int                                               { return CommandLine.NFlag() }
+func ChangedFlags() []string                                   { return CommandLine.ChangedFlags() }
 func HasFlags() bool                                           { return CommandLine.HasFlags() }
 func HasAvailableFlags() bool                                  { return CommandLine.HasAvailableFlags() }
 func ShorthandLookup(name string) *Flag                        { return CommandLine.ShorthandLookup(name) }
 func ArgsLenAtDash() int                                       { return CommandLine.ArgsLenAtDash() }
 func SetNormalizeFunc(n func(*FlagSet, string) NormalizedName) { CommandLine.SetNormalizeFunc(n) }
 func SetInterspersed(interspersed bool)                        { CommandLine.SetInterspersed(interspersed) }
+func EnableNegations(enabled bool)                             { CommandLine.EnableNegations(enabled) }
+func SetUsageTemplate(tmpl string) error                       { return CommandLine.SetUsageTemplate(tmpl) }
+func SetErrorPrefix(prefix string)                             { CommandLine.SetErrorPrefix(prefix) }
 func MarkDeprecated(name, usageMessage string) error {
 	return CommandLine.MarkDeprecated(name, usageMessage)
 }
@@ -2,7 +2,13 @@ module github.com/major0/optargs/pflag
 
 go 1.23.4
 
-// Local development - replace with local optargs module
-replace github.com/major0/optargs => ../
+// Local development - replace with local optargs modules
+replace (
+	github.com/major0/optargs => ../
+	github.com/major0/optargs/goarg => ../goarg
+)
 
-require github.com/major0/optargs v0.4.2
+require (
+	github.com/major0/optargs v0.4.2
+	github.com/major0/optargs/goarg v0.0.0
+)
@@ -0,0 +1,143 @@
+package pflag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// newCommandSets returns the flag sets of a two-level command tree:
+// persistent and local flags of the root, and local flags of a child whose
+// parent is the root's persistent set.
+func newCommandSets() (persistent, rootLocal, child *FlagSet) {
+	persistent = NewFlagSet("app", ContinueOnError)
+	persistent.BoolP("verbose", "v", false, "verbose output")
+	persistent.String("config", "app.yaml", "config file")
+
+	rootLocal = NewFlagSet("app", ContinueOnError)
+	rootLocal.Bool("version", false, "print the version")
+	rootLocal.SetParent(persistent)
+
+	child = NewFlagSet("serve", ContinueOnError)
+	child.IntP("port", "p", 8080, "listen port")
+	child.SetParent(persistent)
+	return persistent, rootLocal, child
+}
+
+func TestSetParent(t *testing.T) {
+	persistent, rootLocal, child := newCommandSets()
+	if child.Parent() != persistent {
+		t.Fatalf("Parent() = %p, want %p", child.Parent(), persistent)
+	}
+
+	if err := child.Parse([]string{"-vp", "80", "--config=c.yaml", "dir"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if v, _ := persistent.GetBool("verbose"); !v || !persistent.Changed("verbose") {
+		t.Errorf("verbose = %v, changed %v; want true on the persistent set", v, persistent.Changed("verbose"))
+	}
+	if v, _ := persistent.GetString("config"); v != "c.yaml" {
+		t.Errorf("config = %q, want c.yaml", v)
+	}
+	if v, _ := child.GetInt("port"); v != 80 {
+		t.Errorf("port = %d, want 80", v)
+	}
+	if !slices.Equal(child.Args(), []string{"dir"}) {
+		t.Errorf("Args() = %q, want [dir]", child.Args())
+	}
+
+	// The root's local flags are not inherited.
+	err := child.Parse([]string{"--version"})
+	var notExist *NotExistError
+	if !errors.As(err, &notExist) {
+		t.Errorf("Parse(--version) error = %v, want *NotExistError", err)
+	}
+	persistent.EnableNegations(true)
+	if err := rootLocal.Parse([]string{"--version", "--no-verbose"}); err != nil {
+		t.Fatalf("root Parse: %v", err)
+	}
+	if v, _ := persistent.GetBool("verbose"); v {
+		t.Error("--no-verbose on the root did not clear verbose")
+	}
+}
+
+func TestSetParentShadowing(t *testing.T) {
+	persistent, _, child := newCommandSets()
+	child.StringP("config", "v", "", "the child's own config")
+	grandchild := NewFlagSet("worker", ContinueOnError)
+	grandchild.SetParent(child)
+
+	if err := grandchild.Parse([]string{"--config", "w.yaml", "-v", "x", "--verbose", "-p", "1"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if v, _ := child.GetString("config"); v != "x" {
+		t.Errorf("child config = %q, want x (set by -v, then --config)", v)
+	}
+	if persistent.Changed("config") {
+		t.Error("persistent config changed; the child's flag shadows it")
+	}
+	if v, _ := persistent.GetBool("verbose"); !v {
+		t.Error("--verbose did not reach the persistent set two levels up")
+	}
+	if v, _ := child.GetInt("port"); v != 1 {
+		t.Errorf("port = %d, want 1", v)
+	}
+}
+
+func TestSetParentParseAll(t *testing.T) {
+	_, _, child := newCommandSets()
+	var seen []string
+	err := child.ParseAll([]string{"-v", "--port=1"}, func(flag *Flag, value string) error {
+		seen = append(seen, flag.Name+"="+value)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"verbose=true", "port=1"}; !slices.Equal(seen, want) {
+		t.Errorf("ParseAll saw %q, want %q", seen, want)
+	}
+	seen = nil
+	if err := child.Parse([]string{"-v"}); err != nil {
+		t.Fatal(err)
+	}
+	if seen != nil {
+		t.Errorf("Parse after ParseAll reported %q", seen)
+	}
+}
+
+func TestSetParentParseAllConcurrent(t *testing.T) {
+	persistent := NewFlagSet("app", ContinueOnError)
+	persistent.Bool("verbose", false, "verbose output")
+	var wg sync.WaitGroup
+	for i := range 2 {
+		child := NewFlagSet(fmt.Sprintf("cmd%d", i), ContinueOnError)
+		child.Int("port", 0, "listen port")
+		child.SetParent(persistent)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var seen []string
+			err := child.ParseAll([]string{"--port=1"}, func(flag *Flag, value string) error {
+				seen = append(seen, flag.Name+"="+value)
+				return nil
+			})
+			if err != nil || !slices.Equal(seen, []string{"port=1"}) {
+				t.Errorf("%s: ParseAll saw %q, err %v", child.Name(), seen, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSetParentCycle(t *testing.T) {
+	persistent, _, child := newCommandSets()
+	defer func() {
+		if recover() == nil {
+			t.Error("SetParent did not panic on a cycle")
+		}
+	}()
+	persistent.SetParent(child)
+}
@@ -31,14 +31,15 @@ func shortOptArgType(v Value) optargs.ArgType {
 // buildShortOpts constructs the short option map for optargs.NewParser
 // from the FlagSet's registered flags and shorthand mappings.
 // Boolean short opts use NoArgument so they participate in POSIX compaction (-abc).
-func (f *FlagSet) buildShortOpts() map[byte]*optargs.Flag {
+// The handlers report each parsed flag to parseAll when it is non-nil.
+func (f *FlagSet) buildShortOpts(parseAll func(flag *Flag, value string) error) map[byte]*optargs.Flag {
 	shortOpts := make(map[byte]*optargs.Flag)
 
 	addShort := func(shortChar byte, flag *Flag) {
 		shortOpts[shortChar] = &optargs.Flag{
 			Name:   string(shortChar),
 			HasArg: shortOptArgType(flag.Value),
-			Handle: f.makeHandler(flag),
+			Handle: f.makeHandler(flag, parseAll),
 		}
 	}
 
@@ -101,11 +102,12 @@ func boolLongArgType(v Value) optargs.ArgType {
 
 // buildLongOpts constructs the long option map for optargs.NewParser
 // from the FlagSet's registered flags. Also registers --no-<name>
-// negation flags for boolean flags.
-func (f *FlagSet) buildLongOpts() map[string]*optargs.Flag {
+// negation flags for boolean flags when negations are enabled.
+// The handlers report each parsed flag to parseAll when it is non-nil.
+func (f *FlagSet) buildLongOpts(parseAll func(flag *Flag, value string) error) map[string]*optargs.Flag {
 	longOpts := make(map[string]*optargs.Flag)
 	for normalizedName, flag := range f.flags {
-		handler := f.makeHandler(flag)
+		handler := f.makeHandler(flag, parseAll)
 		isBool := isBoolFlag(flag.Value)
 		hasArg := optargs.RequiredArgument
 		if isBool {
@@ -118,13 +120,14 @@ func (f *FlagSet) buildLongOpts() map[string]*optargs.Flag {
 			Handle: handler,
 		}
 
-		// Register negation flag for booleans that accept an argument
-		if isBool && hasArg == optargs.OptionalArgument {
-			negName := "no-" + normalizedName
+		// Register negation flag for booleans that accept an argument,
+		// unless a flag is registered under that name.
+		negName := "no-" + normalizedName
+		if isBool && hasArg == optargs.OptionalArgument && f.negations && f.flags[negName] == nil {
 			longOpts[negName] = &optargs.Flag{
 				Name:   negName,
 				HasArg: optargs.OptionalArgument,
-				Handle: f.makeNegationHandler(flag),
+				Handle: f.makeNegationHandler(flag, parseAll),
 			}
 		}
 
@@ -135,12 +138,12 @@ func (f *FlagSet) buildLongOpts() map[string]*optargs.Flag {
 			longOpts[trueName] = &optargs.Flag{
 				Name:   trueName,
 				HasArg: optargs.NoArgument,
-				Handle: f.makeBoolPrefixHandler(flag, boolTrue),
+				Handle: f.makeBoolPrefixHandler(flag, boolTrue, parseAll),
 			}
 			longOpts[falseName] = &optargs.Flag{
 				Name:   falseName,
 				HasArg: optargs.NoArgument,
-				Handle: f.makeBoolPrefixHandler(flag, "false"),
+				Handle: f.makeBoolPrefixHandler(flag, "false", parseAll),
 			}
 		}
 
@@ -150,7 +153,7 @@ func (f *FlagSet) buildLongOpts() map[string]*optargs.Flag {
 			longOpts[negName] = &optargs.Flag{
 				Name:   negName,
 				HasArg: optargs.NoArgument,
-				Handle: f.makeNegatableHandler(flag),
+				Handle: f.makeNegatableHandler(flag, parseAll),
 			}
 		}
 	}
@@ -161,7 +164,7 @@ func (f *FlagSet) buildLongOpts() map[string]*optargs.Flag {
 // For boolean flags (type "bool" or IsBoolFlag()), no-arg sets "true" or
 // calls Set("") for custom bool flags. For all other types, the handler
 // calls Value.Set(arg) directly.
-func (f *FlagSet) makeHandler(flag *Flag) func(string, string) error {
+func (f *FlagSet) makeHandler(flag *Flag, parseAll func(flag *Flag, value string) error) func(string, string) error {
 	return func(_, arg string) error {
 		val := arg
 		if isBoolFlag(flag.Value) && val == "" {
@@ -175,8 +178,8 @@ func (f *FlagSet) makeHandler(flag *Flag) func(string, string) error {
 			return &InvalidValueError{flag: flag, value: val, err: err}
 		}
 		flag.Changed = true
-		if f.parseAllFn != nil {
-			if err := f.parseAllFn(flag, val); err != nil {
+		if parseAll != nil {
+			if err := parseAll(flag, val); err != nil {
 				return err
 			}
 		}
@@ -185,36 +188,42 @@ func (f *FlagSet) makeHandler(flag *Flag) func(string, string) error {
 }
 
 // makeNegationHandler returns a handler for --no-<name> boolean negation flags.
-// no-arg or =true → Set("false"), =false → Set("true").
-func (f *FlagSet) makeNegationHandler(flag *Flag) func(string, string) error {
+// no-arg or =true → Set("false"), =false → Set("true"). Changed and the
+// ParseAll callback report the original flag.
+func (f *FlagSet) makeNegationHandler(flag *Flag, parseAll func(flag *Flag, value string) error) func(string, string) error {
 	return func(_, arg string) error {
+		var val string
 		switch strings.ToLower(arg) {
 		case "", boolTrue, "1", "t":
-			if err := flag.Value.Set("false"); err != nil {
-				return err
-			}
+			val = "false"
 		case "false", "0", "f":
-			if err := flag.Value.Set(boolTrue); err != nil {
-				return err
-			}
+			val = boolTrue
 		default:
 			return fmt.Errorf("invalid boolean value '%s'", arg)
 		}
+		if err := flag.Value.Set(val); err != nil {
+			return err
+		}
 		flag.Changed = true
+		if parseAll != nil {
+			if err := parseAll(flag, val); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 }
 
 // makeBoolPrefixHandler returns a handler for a prefixed boolean option
 // (e.g. --enable-shared, --disable-shared). The val argument is "true" or "false".
-func (f *FlagSet) makeBoolPrefixHandler(flag *Flag, val string) func(string, string) error {
+func (f *FlagSet) makeBoolPrefixHandler(flag *Flag, val string, parseAll func(flag *Flag, value string) error) func(string, string) error {
 	return func(_, _ string) error {
 		if err := flag.Value.Set(val); err != nil {
 			return err
 		}
 		flag.Changed = true
-		if f.parseAllFn != nil {
-			if err := f.parseAllFn(flag, val); err != nil {
+		if parseAll != nil {
+			if err := parseAll(flag, val); err != nil {
 				return err
 			}
 		}
@@ -224,7 +233,7 @@ func (f *FlagSet) makeBoolPrefixHandler(flag *Flag, val string) func(string, str
 
 // makeNegatableHandler returns a handler for --no-<name> on a non-boolean flag.
 // Clears the value to its type's zero value: Reset() for collections, Set(zeroVal) for scalars.
-func (f *FlagSet) makeNegatableHandler(flag *Flag) func(string, string) error {
+func (f *FlagSet) makeNegatableHandler(flag *Flag, parseAll func(flag *Flag, value string) error) func(string, string) error {
 	zeroVal, _ := optargs.ZeroString(flag.Value.Type())
 	return func(_, _ string) error {
 		if r, ok := flag.Value.(optargs.Resetter); ok {
@@ -233,8 +242,8 @@ func (f *FlagSet) makeNegatableHandler(flag *Flag) func(string, string) error {
 			return err
 		}
 		flag.Changed = true
-		if f.parseAllFn != nil {
-			if err := f.parseAllFn(flag, zeroVal); err != nil {
+		if parseAll != nil {
+			if err := parseAll(flag, zeroVal); err != nil {
 				return err
 			}
 		}
@@ -295,32 +304,29 @@ func translateError(err error) error {
 //   - ExitOnError: print error + usage to output, call os.Exit(2)
 //   - PanicOnError: print error + usage to output, panic
 func (f *FlagSet) Parse(arguments []string) error {
-	// Detect -- position for ArgsLenAtDash
-	dashPos := -1
-	for i, arg := range arguments {
-		if arg == "--" {
-			dashPos = i
-			break
-		}
-	}
+	return f.parse(arguments, nil)
+}
 
+// parse implements Parse and ParseAll. The parseAll callback is handed to
+// the handlers of f and of its ancestors, so inherited flags are reported
+// too, without storing it on any FlagSet.
+func (f *FlagSet) parse(arguments []string, parseAll func(flag *Flag, value string) error) error {
 	// If a normalize func is set, normalize long option names in the
 	// arguments so the core parser can match them against registered flags.
 	if f.normalizeNameFunc != nil {
 		arguments = f.normalizeArgs(arguments)
 	}
 
-	shortOpts := f.buildShortOpts()
-	longOpts := f.buildLongOpts()
+	shortOpts := f.buildShortOpts(parseAll)
+	longOpts := f.buildLongOpts(parseAll)
 
-	config := optargs.ParserConfig{}
-	config.SetLongOnly(f.longOnly)
-	config.SetInterspersed(f.interspersed)
-
-	parser, err := optargs.NewParser(config, shortOpts, longOpts, arguments)
+	parser, err := optargs.NewParser(f.parserConfig(), shortOpts, longOpts, arguments)
 	if err != nil {
 		return f.failf(translateError(err))
 	}
+	if err := f.linkParents(parser, parseAll); err != nil {
+		return f.failf(translateError(err))
+	}
 
 	// Consume the iterator — handlers do the work, we only propagate errors.
 	for _, err := range parser.Options() {
@@ -340,22 +346,46 @@ func (f *FlagSet) Parse(arguments []string) error {
 	f.args = parser.Args
 	f.parsed = true
 
-	// Compute argsLenAtDash: if -- was present, count how many positional
-	// args appeared before it. The args after -- are at the tail of f.args.
-	if dashPos >= 0 {
-		argsAfterDash := len(arguments) - dashPos - 1 // args after the -- token
-		f.argsLenAtDash = max(len(f.args)-argsAfterDash, 0)
+	// ArgsLenAtDash counts the positionals before a -- the parser
+	// actually consumed as a terminator. A -- taken as a flag value does
+	// not count, nor does one left verbatim because non-interspersed
+	// parsing stopped at an earlier positional.
+	if operands, rest := parser.SplitArgs(); rest != nil {
+		f.argsLenAtDash = len(operands)
 	}
 
 	return nil
 }
 
+// parserConfig returns the core parser configuration for f's settings.
+func (f *FlagSet) parserConfig() optargs.ParserConfig {
+	config := optargs.ParserConfig{}
+	config.SetLongOnly(f.longOnly)
+	config.SetInterspersed(f.interspersed)
+	return config
+}
+
+// linkParents places a core parser for each ancestor set above parser, so
+// the core resolves options f does not define through the parent chain.
+// The ancestor parsers are never iterated; their handlers set the
+// ancestors' flags and report them to parseAll.
+func (f *FlagSet) linkParents(parser *optargs.Parser, parseAll func(flag *Flag, value string) error) error {
+	child, name := parser, f.name
+	for p := f.parent; p != nil; p = p.parent {
+		up, err := optargs.NewParser(p.parserConfig(), p.buildShortOpts(parseAll), p.buildLongOpts(parseAll), nil)
+		if err != nil {
+			return err
+		}
+		up.AddCmd(name, child)
+		child, name = up, p.name
+	}
+	return nil
+}
+
 // ParseAll parses flag definitions from the argument list and calls fn for
 // each flag that is set. The arguments for fn are the flag and its value.
 func (f *FlagSet) ParseAll(arguments []string, fn func(flag *Flag, value string) error) error {
-	f.parseAllFn = fn
-	defer func() { f.parseAllFn = nil }()
-	return f.Parse(arguments)
+	return f.parse(arguments, fn)
 }
 
 // failf handles a parse error according to the FlagSet's ErrorHandling mode.
@@ -366,11 +396,11 @@ func (f *FlagSet) failf(err error) error {
 	case ContinueOnError:
 		return err
 	case ExitOnError:
-		fmt.Fprintln(f.out(), err)
+		fmt.Fprintln(f.out(), f.errorPrefix+err.Error())
 		f.Usage()
 		os.Exit(2)
 	case PanicOnError:
-		fmt.Fprintln(f.out(), err)
+		fmt.Fprintln(f.out(), f.errorPrefix+err.Error())
 		f.Usage()
 		panic(err)
 	}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"net"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -85,17 +88,17 @@ func TestFlagCreationAllTypes(t *testing.T) {
 		// String collections and maps
 		{"StringArrayVar", func(fs *FlagSet) { fs.StringArrayVar(new([]string), "f", nil, "u") }, "f", "[]", "stringArray"},
 		{"StringArrayP", func(fs *FlagSet) { fs.StringArrayP("f", "a", nil, "u") }, "f", "[]", "stringArray"},
-		{"StringToStringVar", func(fs *FlagSet) { fs.StringToStringVar(new(map[string]string), "f", nil, "u") }, "f", "map[]", "stringToString"},
-		{"StringToStringP", func(fs *FlagSet) { fs.StringToStringP("f", "s", nil, "u") }, "f", "map[]", "stringToString"},
-		{"StringToIntVar", func(fs *FlagSet) { fs.StringToIntVar(new(map[string]int), "f", nil, "u") }, "f", "map[]", "stringToInt"},
-		{"StringToIntP", func(fs *FlagSet) { fs.StringToIntP("f", "i", nil, "u") }, "f", "map[]", "stringToInt"},
-		{"StringToInt64Var", func(fs *FlagSet) { fs.StringToInt64Var(new(map[string]int64), "f", nil, "u") }, "f", "map[]", "stringToInt64"},
-		{"StringToInt64P", func(fs *FlagSet) { fs.StringToInt64P("f", "l", nil, "u") }, "f", "map[]", "stringToInt64"},
+		{"StringToStringVar", func(fs *FlagSet) { fs.StringToStringVar(new(map[string]string), "f", nil, "u") }, "f", "[]", "stringToString"},
+		{"StringToStringP", func(fs *FlagSet) { fs.StringToStringP("f", "s", nil, "u") }, "f", "[]", "stringToString"},
+		{"StringToIntVar", func(fs *FlagSet) { fs.StringToIntVar(new(map[string]int), "f", nil, "u") }, "f", "[]", "stringToInt"},
+		{"StringToIntP", func(fs *FlagSet) { fs.StringToIntP("f", "i", nil, "u") }, "f", "[]", "stringToInt"},
+		{"StringToInt64Var", func(fs *FlagSet) { fs.StringToInt64Var(new(map[string]int64), "f", nil, "u") }, "f", "[]", "stringToInt64"},
+		{"StringToInt64P", func(fs *FlagSet) { fs.StringToInt64P("f", "l", nil, "u") }, "f", "[]", "stringToInt64"},
 		// Specialized types
 		{"CountVar", func(fs *FlagSet) { fs.CountVar(new(int), "f", "u") }, "f", "0", "count"},
 		{"CountP", func(fs *FlagSet) { fs.CountP("f", "c", "u") }, "f", "0", "count"},
-		{"IPVar", func(fs *FlagSet) { fs.IPVar(new(net.IP), "f", nil, "u") }, "f", "", "textUnmarshaler"},
-		{"IPP", func(fs *FlagSet) { fs.IPP("f", "i", nil, "u") }, "f", "", "textUnmarshaler"},
+		{"IPVar", func(fs *FlagSet) { fs.IPVar(new(net.IP), "f", nil, "u") }, "f", "<nil>", "ip"},
+		{"IPP", func(fs *FlagSet) { fs.IPP("f", "i", nil, "u") }, "f", "<nil>", "ip"},
 		{"IPMaskVar", func(fs *FlagSet) { fs.IPMaskVar(new(net.IPMask), "f", nil, "u") }, "f", "<nil>", "ipMask"},
 		{"IPMaskP", func(fs *FlagSet) { fs.IPMaskP("f", "m", nil, "u") }, "f", "<nil>", "ipMask"},
 		{"IPNetVar", func(fs *FlagSet) { fs.IPNetVar(new(net.IPNet), "f", net.IPNet{}, "u") }, "f", "<nil>", "ipNet"},
@@ -203,6 +206,7 @@ func TestBooleanFlagParsing(t *testing.T) {
 			fs := NewFlagSet("test", ContinueOnError)
 			var v bool
 			fs.BoolVarP(&v, "verbose", "v", tt.defaultVal, "")
+			fs.EnableNegations(true)
 			err := fs.Parse(tt.args)
 			if tt.shouldError {
 				if err == nil {
@@ -242,6 +246,7 @@ func TestNegationHandlerBranches(t *testing.T) {
 			fs := NewFlagSet("test", ContinueOnError)
 			var v bool
 			fs.BoolVar(&v, "flag", false, "")
+			fs.EnableNegations(true)
 			err := fs.Parse(tt.args)
 			if tt.wantErr {
 				if err == nil {
@@ -259,6 +264,76 @@ func TestNegationHandlerBranches(t *testing.T) {
 	}
 }
 
+// TestEnableNegations tests the automatic --no-<name> spelling for
+// boolean flags and the option that enables it.
+func TestEnableNegations(t *testing.T) {
+	tests := []struct {
+		name        string
+		enable      bool
+		explicit    bool // register a separate "no-cache" flag
+		args        []string
+		wantCache   bool
+		wantNoCache bool
+		wantChanged bool
+		wantErr     bool
+	}{
+		{"disabled by default", false, false, []string{"--no-cache"}, true, false, false, true},
+		{"enabled", true, false, []string{"--no-cache"}, false, false, true, false},
+		{"negation of negation", true, false, []string{"--no-cache=false"}, true, false, true, false},
+		{"unused", true, false, nil, true, false, false, false},
+		{"explicit flag wins", true, true, []string{"--no-cache"}, true, true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("test", ContinueOnError)
+			fs.SetOutput(io.Discard)
+			cache, noCache := true, false
+			fs.BoolVar(&cache, "cache", true, "")
+			if tt.explicit {
+				fs.BoolVar(&noCache, "no-cache", false, "")
+			}
+			if tt.enable {
+				fs.EnableNegations(true)
+			}
+			if got := fs.NegationsEnabled(); got != tt.enable {
+				t.Errorf("NegationsEnabled() = %t, want %t", got, tt.enable)
+			}
+			err := fs.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %t", tt.args, err, tt.wantErr)
+			}
+			if cache != tt.wantCache || noCache != tt.wantNoCache {
+				t.Errorf("cache, no-cache = %t, %t; want %t, %t", cache, noCache, tt.wantCache, tt.wantNoCache)
+			}
+			if got := fs.Changed("cache"); got != tt.wantChanged {
+				t.Errorf("Changed(cache) = %t, want %t", got, tt.wantChanged)
+			}
+		})
+	}
+}
+
+// TestNegationParseAll verifies that ParseAll reports a negation as the
+// original flag with the value it was set to, and that usage text does not
+// list the negation.
+func TestNegationParseAll(t *testing.T) {
+	fs := NewFlagSet("test", ContinueOnError)
+	fs.Bool("cache", true, "use the cache")
+	fs.EnableNegations(true)
+	var got []string
+	if err := fs.ParseAll([]string{"--no-cache"}, func(flag *Flag, value string) error {
+		got = append(got, flag.Name+"="+value)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cache=false"}; !slices.Equal(got, want) {
+		t.Errorf("ParseAll callbacks = %q, want %q", got, want)
+	}
+	if usage := fs.FlagUsages(); strings.Contains(usage, "no-cache") {
+		t.Errorf("usage lists the negation:\n%s", usage)
+	}
+}
+
 // TestParseStateAndArgs tests Parsed(), Args(), NArg(), Arg() after parsing.
 func TestParseStateAndArgs(t *testing.T) {
 	fs := NewFlagSet("test", ContinueOnError)
@@ -455,6 +530,10 @@ func TestIsZeroValue(t *testing.T) {
 		{"stringSlice/zero", &Flag{Value: newStringSliceValue([]string{}, new([]string))}, "[]", true},
 		{"intSlice/zero", &Flag{Value: newIntSliceValue([]int{}, new([]int))}, "[]", true},
 		{"custom/nonzero", &Flag{Value: &customValue{value: "x"}}, "x", false},
+		{"float64Slice/empty prints", &Flag{Value: newFloat64SliceValue(nil, new([]float64))}, "[]", false},
+		{"stringToString/empty prints", &Flag{Value: newStringToStringValue(nil, new(map[string]string))}, "[]", false},
+		{"ip/nil", &Flag{Value: newIPValue(nil, new(net.IP))}, "<nil>", true},
+		{"count/zero", &Flag{Value: newCountValue(0, new(int))}, "0", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -465,6 +544,41 @@ func TestIsZeroValue(t *testing.T) {
 	}
 }
 
+// TestFlagUsagesUpstreamParity compares usage output with text captured
+// from upstream spf13/pflag v1.0.10 for the same definitions.
+func TestFlagUsagesUpstreamParity(t *testing.T) {
+	fs := NewFlagSet("t", ContinueOnError)
+	fs.String("s0", "", "empty string")
+	fs.String("s2", "a \"q\" b", "quoted string")
+	fs.Bool("b1", true, "bool true")
+	fs.Int("i0", 0, "int zero")
+	fs.Float32("f32", 0.25, "f32")
+	fs.Float64Slice("fs", []float64{}, "floats empty")
+	fs.IntSlice("is0", []int{}, "intslice empty")
+	fs.StringToString("m1", map[string]string{"k": "v"}, "map")
+	fs.IP("ip0", nil, "ip nil")
+	fs.Int("ni", 0, "nooptdef int")
+	fs.Lookup("ni").NoOptDefVal = "5"
+	fs.String("ns", "", "nooptdef string")
+	fs.Lookup("ns").NoOptDefVal = "dflt"
+
+	want := `      --b1                   bool true (default true)
+      --f32 float32          f32 (default 0.25)
+      --fs float64Slice      floats empty (default [])
+      --i0 int               int zero
+      --ip0 ip               ip nil
+      --is0 ints             intslice empty
+      --m1 stringToString    map (default [k=v])
+      --ni int[=5]           nooptdef int
+      --ns string[="dflt"]   nooptdef string
+      --s0 string            empty string
+      --s2 string            quoted string (default "a \"q\" b")
+`
+	if got := fs.FlagUsages(); got != want {
+		t.Errorf("FlagUsages mismatch:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
 // TestTranslateError tests error translation from OptArgs Core to pflag format.
 func TestTranslateError(t *testing.T) {
 	tests := []struct {
@@ -895,6 +1009,23 @@ func TestNFlag(t *testing.T) {
 	}
 }
 
+// TestChangedFlags tests the ChangedFlags() method.
+func TestChangedFlags(t *testing.T) {
+	fs := NewFlagSet("test", ContinueOnError)
+	fs.StringVar(new(string), "zeta", "", "")
+	fs.StringVar(new(string), "alpha", "", "")
+	fs.BoolVarP(new(bool), "verbose", "v", false, "")
+	if got := fs.ChangedFlags(); len(got) != 0 {
+		t.Errorf("ChangedFlags before parse = %q, want none", got)
+	}
+	if err := fs.Parse([]string{"--zeta", "1", "-v", "--alpha=2", "--zeta", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fs.ChangedFlags(), []string{"alpha", "verbose", "zeta"}; !slices.Equal(got, want) {
+		t.Errorf("ChangedFlags = %q, want %q", got, want)
+	}
+}
+
 // TestHasFlags tests HasFlags() and HasAvailableFlags().
 func TestHasFlags(t *testing.T) {
 	fs := NewFlagSet("test", ContinueOnError)
@@ -1062,6 +1193,116 @@ func TestSetInterspersed(t *testing.T) {
 	}
 }
 
+// TestSetInterspersedExecStyle tests that a non-interspersed FlagSet passes
+// trailing flags and "--" through untouched, as exec-style commands expect.
+func TestSetInterspersedExecStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantArgs []string
+		wantDash int
+	}{
+		{"flags after command", []string{"--name", "v", "cmd", "--x", "--", "y"}, []string{"cmd", "--x", "--", "y"}, -1},
+		{"dash before command", []string{"--name", "v", "--", "cmd"}, []string{"cmd"}, 0},
+		{"dash after command", []string{"cmd", "--", "y"}, []string{"cmd", "--", "y"}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("test", ContinueOnError)
+			fs.SetInterspersed(false)
+			fs.String("name", "", "")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(fs.Args(), tt.wantArgs) {
+				t.Errorf("Args() = %q, want %q", fs.Args(), tt.wantArgs)
+			}
+			if fs.ArgsLenAtDash() != tt.wantDash {
+				t.Errorf("ArgsLenAtDash() = %d, want %d", fs.ArgsLenAtDash(), tt.wantDash)
+			}
+		})
+	}
+}
+
+// TestArgsLenAtDashFlagValue verifies that a -- consumed as a flag value
+// is not mistaken for the terminator.
+func TestArgsLenAtDashFlagValue(t *testing.T) {
+	tests := []struct {
+		args     []string
+		wantArgs []string
+		wantDash int
+	}{
+		{[]string{"--name", "--", "x"}, []string{"x"}, -1},
+		{[]string{"x", "--name=--", "--", "y"}, []string{"x", "y"}, 1},
+		{[]string{"-n", "--", "--", "y"}, []string{"y"}, 0},
+	}
+	for _, tt := range tests {
+		fs := NewFlagSet("test", ContinueOnError)
+		fs.StringP("name", "n", "", "")
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(fs.Args(), tt.wantArgs) || fs.ArgsLenAtDash() != tt.wantDash {
+			t.Errorf("Parse(%q): Args() = %q, ArgsLenAtDash() = %d, want %q, %d",
+				tt.args, fs.Args(), fs.ArgsLenAtDash(), tt.wantArgs, tt.wantDash)
+		}
+	}
+}
+
+// TestIntegerFlagsUpstreamParity checks integer parsing and error text
+// against output captured from upstream spf13/pflag v1.0.10.
+func TestIntegerFlagsUpstreamParity(t *testing.T) {
+	tests := []struct {
+		flag, arg string
+		wantErr   string
+		wantValue string
+	}{
+		{"u8", "300", `invalid argument "300" for "--u8" flag: strconv.ParseUint: parsing "300": value out of range`, "255"},
+		{"u8", "-1", `invalid argument "-1" for "--u8" flag: strconv.ParseUint: parsing "-1": invalid syntax`, "0"},
+		{"u32", "5000000000", `invalid argument "5000000000" for "--u32" flag: strconv.ParseUint: parsing "5000000000": value out of range`, "4294967295"},
+		{"i16", "-40000", `invalid argument "-40000" for "--i16" flag: strconv.ParseInt: parsing "-40000": value out of range`, "-32768"},
+		{"i64", "9223372036854775808", `invalid argument "9223372036854775808" for "--i64" flag: strconv.ParseInt: parsing "9223372036854775808": value out of range`, "9223372036854775807"},
+		{"i", "1.5", `invalid argument "1.5" for "--i" flag: strconv.ParseInt: parsing "1.5": invalid syntax`, "0"},
+		{"u8", "0x10", "", "16"},
+		{"i8", "-0x10", "", "-16"},
+		{"u", "0b11", "", "3"},
+		{"i32", "0o17", "", "15"},
+		{"i", "1_000", "", "1000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flag+"="+tt.arg, func(t *testing.T) {
+			fs := NewFlagSet("t", ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Uint8("u8", 0, "")
+			fs.Uint32("u32", 0, "")
+			fs.Uint("u", 0, "")
+			fs.Int8("i8", 0, "")
+			fs.Int16("i16", 0, "")
+			fs.Int32("i32", 0, "")
+			fs.Int64("i64", 0, "")
+			fs.Int("i", 0, "")
+			err := fs.Parse([]string{"--" + tt.flag + "=" + tt.arg})
+			if got := fmt.Sprint(err); (err != nil || tt.wantErr != "") && got != tt.wantErr {
+				t.Errorf("error = %q, want %q", got, tt.wantErr)
+			}
+			if got := fs.Lookup(tt.flag).Value.String(); got != tt.wantValue {
+				t.Errorf("value = %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestGetterErrorsUpstreamParity(t *testing.T) {
+	fs := NewFlagSet("t", ContinueOnError)
+	fs.String("s", "", "")
+	if _, err := fs.GetUint8("s"); fmt.Sprint(err) != "trying to get uint8 value of flag of type string" {
+		t.Errorf("GetUint8 type mismatch error = %v", err)
+	}
+	if _, err := fs.GetInt64("nope"); fmt.Sprint(err) != "flag accessed but not defined: nope" {
+		t.Errorf("GetInt64 undefined flag error = %v", err)
+	}
+}
+
 // TestMarkDeprecated tests the MarkDeprecated method.
 func TestMarkDeprecated(t *testing.T) {
 	fs := NewFlagSet("test", ContinueOnError)
@@ -1974,6 +2215,64 @@ func TestAddFlagShorthandConflict(t *testing.T) {
 	}
 }
 
+// TestAddFlagShorthandConflictContinue tests that under ContinueOnError
+// a shorthand conflict is reported, naming both flags, when the flag is
+// added, and does not affect later parses.
+func TestAddFlagShorthandConflictContinue(t *testing.T) {
+	var buf bytes.Buffer
+	fs := NewFlagSet("test", ContinueOnError)
+	fs.SetOutput(&buf)
+	fs.BoolP("verbose", "v", false, "")
+	fs.AddFlag(&Flag{Name: "version", Shorthand: "v", Value: newStringValue("", new(string))})
+
+	other := NewFlagSet("other", ContinueOnError)
+	other.ShortVar(newBoolValue(false, new(bool)), "v", "")
+	fs.AddFlagSet(other)
+
+	want := "shorthand v of flag version already used for flag verbose\n" +
+		"shorthand v of flag v already used for flag verbose\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+	for range 2 {
+		if err := fs.Parse([]string{"-v"}); err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+	}
+	if v, _ := fs.GetBool("verbose"); !v {
+		t.Error("-v did not set verbose")
+	}
+}
+
+// TestAddFlagSetShorthandConflictPanics tests that merging a conflicting
+// set panics, naming both flags, when the FlagSet does not continue on
+// error.
+func TestAddFlagSetShorthandConflictPanics(t *testing.T) {
+	tests := []struct {
+		name  string
+		other func(fs *FlagSet)
+		want  string
+	}{
+		{"long", func(fs *FlagSet) { fs.BoolP("version", "v", false, "") }, "shorthand v of flag version already used for flag verbose"},
+		{"short-only", func(fs *FlagSet) { fs.ShortVar(newBoolValue(false, new(bool)), "v", "") }, "shorthand v of flag v already used for flag verbose"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("test", PanicOnError)
+			fs.BoolP("verbose", "v", false, "")
+			other := NewFlagSet("other", ContinueOnError)
+			tt.other(other)
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.HasPrefix(msg, tt.want+" (first defined at ") {
+					t.Errorf("panic = %q, want prefix %q", msg, tt.want)
+				}
+			}()
+			fs.AddFlagSet(other)
+		})
+	}
+}
+
 // TestWrapLineHardBreak tests wrapLine when a word exceeds the column width
 // (no space found for soft break).
 func TestWrapLineHardBreak(t *testing.T) {
@@ -2121,7 +2420,7 @@ func TestBuildShortOptsNilFlag(t *testing.T) {
 	// Manually corrupt the shorthand map to point to a non-existent flag
 	fs.shorthand["z"] = "nonexistent"
 	// buildShortOpts should skip "z" without panicking
-	shortOpts := fs.buildShortOpts()
+	shortOpts := fs.buildShortOpts(nil)
 	if _, exists := shortOpts['z']; exists {
 		t.Error("shortOpts should not contain 'z' for non-existent flag")
 	}
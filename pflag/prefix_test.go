@@ -227,6 +227,7 @@ func TestBoolPrefixParsing(t *testing.T) {
 			fs := NewFlagSet("test", ContinueOnError)
 			fs.SetOutput(&strings.Builder{}) // suppress error output
 			shared := fs.Bool("shared", false, "shared library")
+			fs.EnableNegations(true)
 			if err := fs.MarkBoolPrefix("shared", "enable", "disable"); err != nil {
 				t.Fatal(err)
 			}
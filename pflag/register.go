@@ -0,0 +1,87 @@
+package pflag
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// pkgPrefix is the function name prefix of frames inside this package.
+const pkgPrefix = "github.com/major0/optargs/pflag."
+
+// lockRegistration serializes flag registration on f, so packages may
+// define flags on a shared set such as CommandLine from init functions or
+// goroutines, and allocates the flag maps of a zero FlagSet. It returns
+// the unlock function.
+func (f *FlagSet) lockRegistration() func() {
+	f.mu.Lock()
+	if f.flags == nil {
+		f.flags = make(map[string]*Flag)
+	}
+	if f.shortOnly == nil {
+		f.shortOnly = make(map[string]*Flag)
+	}
+	if f.shorthand == nil {
+		f.shorthand = make(map[string]string)
+	}
+	return f.mu.Unlock
+}
+
+// recordSite remembers where flag was registered, for the messages of
+// later duplicate registrations.
+func (f *FlagSet) recordSite(flag *Flag) {
+	if f.sites == nil {
+		f.sites = make(map[*Flag]string)
+	}
+	f.sites[flag] = registrationSite()
+}
+
+// redefined formats a duplicate registration message naming the site of
+// the existing flag and of the current caller.
+func (f *FlagSet) redefined(msg string, existing *Flag) string {
+	first := f.sites[existing]
+	if first == "" {
+		first = "unknown"
+	}
+	return fmt.Sprintf("%s (first defined at %s, redefined at %s)", msg, first, registrationSite())
+}
+
+// shorthandOwner returns the flag of f that uses shorthand, or nil.
+func (f *FlagSet) shorthandOwner(shorthand string) *Flag {
+	if flag := f.shortOnly[shorthand]; flag != nil {
+		return flag
+	}
+	if name, ok := f.shorthand[shorthand]; ok {
+		return f.flags[f.normalizeFlagName(name)]
+	}
+	return nil
+}
+
+// shorthandConflict reports that flag, added through AddFlag or
+// AddFlagSet, requests the shorthand of existing: it panics unless f
+// continues on error, in which case the error is written to the output.
+// The caller leaves flag out either way.
+func (f *FlagSet) shorthandConflict(flag, existing *Flag) {
+	err := &ShorthandConflictError{Shorthand: flag.Shorthand, Flag: flag.Name, Existing: existing.Name}
+	if f.errorHandling != ContinueOnError {
+		panic(f.redefined(err.Error(), existing))
+	}
+	fmt.Fprintln(f.out(), f.errorPrefix+err.Error())
+}
+
+// registrationSite returns the file:line of the nearest caller outside
+// this package, skipping the package's own wrappers such as the global
+// StringVar.
+func registrationSite() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkgPrefix) || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
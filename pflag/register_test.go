@@ -0,0 +1,86 @@
+package pflag
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentRegistration(t *testing.T) {
+	var fs FlagSet // zero value, as a package-level shared set would be
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fs.Int(fmt.Sprintf("flag%d", i), i, "")
+			fs.ShortVar(newBoolValue(false, new(bool)), string(rune('A'+i)), "")
+		}()
+	}
+	wg.Wait()
+
+	if n := len(fs.order); n != 50 {
+		t.Errorf("registered %d long flags, want 50", n)
+	}
+	if n := len(fs.shortOnly); n != 50 {
+		t.Errorf("registered %d short-only flags, want 50", n)
+	}
+	if err := fs.Parse([]string{"--flag7=70", "-A"}); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := fs.GetInt("flag7"); v != 70 {
+		t.Errorf("flag7 = %d, want 70", v)
+	}
+}
+
+func TestDuplicateRegistrationSites(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  func(fs *FlagSet)
+		second func(fs *FlagSet)
+		want   string
+	}{
+		{
+			name:   "long name",
+			first:  func(fs *FlagSet) { fs.String("output", "", "") },
+			second: func(fs *FlagSet) { fs.Bool("output", false, "") },
+			want:   "flag redefined: output",
+		},
+		{
+			name:   "shorthand",
+			first:  func(fs *FlagSet) { fs.StringP("output", "o", "", "") },
+			second: func(fs *FlagSet) { fs.BoolP("other", "o", false, "") },
+			want:   "shorthand o of flag other already used for flag output",
+		},
+		{
+			name:   "shorthand of short-only",
+			first:  func(fs *FlagSet) { fs.ShortVar(newBoolValue(false, new(bool)), "x", "") },
+			second: func(fs *FlagSet) { fs.BoolP("extra", "x", false, "") },
+			want:   "shorthand x of flag extra already used for flag x",
+		},
+		{
+			name:   "short-only",
+			first:  func(fs *FlagSet) { fs.ShortVar(newBoolValue(false, new(bool)), "x", "") },
+			second: func(fs *FlagSet) { fs.ShortVar(newBoolValue(false, new(bool)), "x", "") },
+			want:   "short-only flag redefined: x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("test", ContinueOnError)
+			tt.first(fs)
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.HasPrefix(msg, tt.want+" (first defined at ") {
+					t.Fatalf("panic = %q, want prefix %q", msg, tt.want)
+				}
+				// Both sites are in this file, on different lines.
+				if n := strings.Count(msg, "register_test.go:"); n != 2 {
+					t.Errorf("panic %q names %d sites in register_test.go, want 2", msg, n)
+				}
+			}()
+			tt.second(fs)
+		})
+	}
+}
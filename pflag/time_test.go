@@ -0,0 +1,109 @@
+package pflag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeFlagSetMethods(t *testing.T) {
+	fs := NewFlagSet("test", ContinueOnError)
+	var a time.Time
+	fs.TimeVar(&a, "a", time.Time{}, nil, "")
+	var b time.Time
+	fs.TimeVarP(&b, "b", "B", time.Time{}, []string{time.DateOnly}, "")
+	c := fs.Time("c", time.Time{}, []string{time.Kitchen}, "")
+	d := fs.TimeP("d", "D", time.Time{}, nil, "")
+
+	err := fs.Parse([]string{"--a=2024-05-01T10:00:00Z", "-B", "2024-05-02", "--c", "3:04PM", "-D", "2024-05-03T00:00:00+02:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := []struct {
+		got  time.Time
+		want string
+	}{
+		{a, "2024-05-01T10:00:00Z"},
+		{b, "2024-05-02T00:00:00Z"},
+		{*c, "0000-01-01T15:04:00Z"},
+		{*d, "2024-05-03T00:00:00+02:00"},
+	}
+	for i, c := range checks {
+		if got := c.got.Format(time.RFC3339); got != c.want {
+			t.Errorf("flag %d = %s, want %s", i, got, c.want)
+		}
+	}
+}
+
+func TestTimeLayouts(t *testing.T) {
+	layouts := []string{time.DateOnly, time.RFC3339}
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{"2024-05-01", "2024-05-01T00:00:00Z", false},
+		{" 2024-05-01T08:30:00Z ", "2024-05-01T08:30:00Z", false},
+		{"05/01/2024", "", true},
+	}
+	for _, tt := range tests {
+		fs := NewFlagSet("test", ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+		when := fs.Time("when", time.Time{}, layouts, "")
+		err := fs.Parse([]string{"--when", tt.arg})
+		if tt.wantErr {
+			if err == nil || !strings.Contains(err.Error(), "must be one of: `2006-01-02`, `2006-01-02T15:04:05Z07:00`") {
+				t.Errorf("Parse(%q) error = %v, want the accepted layouts", tt.arg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.arg, err)
+		}
+		if got := when.Format(time.RFC3339); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestGetTime(t *testing.T) {
+	def := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	fs := NewFlagSet("test", ContinueOnError)
+	fs.Time("start", def, nil, "")
+	fs.Time("end", time.Time{}, nil, "")
+	fs.String("name", "", "")
+
+	if got, err := fs.GetTime("start"); err != nil || !got.Equal(def) {
+		t.Errorf("GetTime(start) = %v, %v; want %v", got, err, def)
+	}
+	if got, err := fs.GetTime("end"); err != nil || !got.IsZero() {
+		t.Errorf("GetTime(end) = %v, %v; want the zero time", got, err)
+	}
+	if err := fs.Parse([]string{"--end", "2024-06-01T00:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := fs.GetTime("end"); err != nil || got.Format(time.RFC3339) != "2024-06-01T00:00:00Z" {
+		t.Errorf("GetTime(end) = %v, %v after parse", got, err)
+	}
+	if _, err := fs.GetTime("name"); err == nil || err.Error() != "trying to get time value of flag of type string" {
+		t.Errorf("GetTime(name) error = %v", err)
+	}
+	if _, err := fs.GetTime("missing"); err == nil || err.Error() != "flag accessed but not defined: missing" {
+		t.Errorf("GetTime(missing) error = %v", err)
+	}
+}
+
+func TestTimeUsage(t *testing.T) {
+	fs := NewFlagSet("test", ContinueOnError)
+	fs.Time("since", time.Time{}, nil, "start of the report")
+	fs.Time("until", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), nil, "end of the report")
+	for _, line := range strings.Split(fs.FlagUsages(), "\n") {
+		switch {
+		case strings.Contains(line, "--since time") && strings.Contains(line, "(default"):
+			t.Errorf("zero default shown: %q", line)
+		case strings.Contains(line, "--until time") && !strings.HasSuffix(line, "(default 2024-01-01T00:00:00Z)"):
+			t.Errorf("default missing: %q", line)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package pflag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetUsageTemplate(t *testing.T) {
+	fs := NewFlagSet("myctl", ContinueOnError)
+	var out bytes.Buffer
+	fs.SetOutput(&out)
+	fs.Bool("verbose", false, "be chatty")
+	if err := fs.SetUsageTemplate("Usage: {{.Name}} [flags]\nFlags:\n{{.FlagUsages}}"); err != nil {
+		t.Fatal(err)
+	}
+	fs.Usage()
+	want := "Usage: myctl [flags]\nFlags:\n" + fs.FlagUsages()
+	if out.String() != want {
+		t.Errorf("usage = %q, want %q", out.String(), want)
+	}
+
+	if err := fs.SetUsageTemplate("{{.Nope"); err == nil {
+		t.Error("SetUsageTemplate accepted a malformed template")
+	}
+	out.Reset()
+	fs.Usage()
+	if !strings.HasPrefix(out.String(), "Usage: myctl [flags]") {
+		t.Errorf("malformed template replaced the layout: %q", out.String())
+	}
+
+	if err := fs.SetUsageTemplate(""); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	fs.Usage()
+	if !strings.HasPrefix(out.String(), "Usage of myctl:\n") {
+		t.Errorf("built-in layout not restored: %q", out.String())
+	}
+}
+
+func TestSetErrorPrefix(t *testing.T) {
+	fs := NewFlagSet("myctl", PanicOnError)
+	var out bytes.Buffer
+	fs.SetOutput(&out)
+	fs.SetErrorPrefix("myctl: error: ")
+	if err := fs.SetUsageTemplate("Run 'myctl --help' for usage.\n"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err, _ := recover().(error)
+		if err == nil || strings.HasPrefix(err.Error(), "myctl:") {
+			t.Errorf("panic value %v, want the unprefixed parse error", err)
+		}
+		want := "myctl: error: " + err.Error() + "\nRun 'myctl --help' for usage.\n"
+		if out.String() != want {
+			t.Errorf("output = %q, want %q", out.String(), want)
+		}
+	}()
+	fs.Parse([]string{"--bogus"}) //nolint:errcheck // panics
+}
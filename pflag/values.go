@@ -4,27 +4,29 @@ import (
 	"encoding"
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/major0/optargs"
 )
 
-// All value types delegate to OptArgs Core TypedValue constructors.
+// Most value types delegate to OptArgs Core TypedValue constructors.
 // The pflag Value interface (String, Set, Type) is identical to
 // optargs.TypedValue, so core constructors satisfy it directly.
 
 func newStringValue(val string, p *string) Value    { return optargs.NewStringValue(val, p) }
 func newBoolValue(val bool, p *bool) Value          { return optargs.NewBoolValue(val, p) }
-func newIntValue(val int, p *int) Value             { return optargs.NewIntValue(val, p) }
-func newInt8Value(val int8, p *int8) Value          { return optargs.NewInt8Value(val, p) }
-func newInt16Value(val int16, p *int16) Value       { return optargs.NewInt16Value(val, p) }
-func newInt32Value(val int32, p *int32) Value       { return optargs.NewInt32Value(val, p) }
-func newInt64Value(val int64, p *int64) Value       { return optargs.NewInt64Value(val, p) }
-func newUintValue(val uint, p *uint) Value          { return optargs.NewUintValue(val, p) }
-func newUint8Value(val uint8, p *uint8) Value       { return optargs.NewUint8Value(val, p) }
-func newUint16Value(val uint16, p *uint16) Value    { return optargs.NewUint16Value(val, p) }
-func newUint32Value(val uint32, p *uint32) Value    { return optargs.NewUint32Value(val, p) }
-func newUint64Value(val uint64, p *uint64) Value    { return optargs.NewUint64Value(val, p) }
+func newIntValue(val int, p *int) Value             { return newSignedValue(val, p, 0, "int") }
+func newInt8Value(val int8, p *int8) Value          { return newSignedValue(val, p, 8, "int8") }
+func newInt16Value(val int16, p *int16) Value       { return newSignedValue(val, p, 16, "int16") }
+func newInt32Value(val int32, p *int32) Value       { return newSignedValue(val, p, 32, "int32") }
+func newInt64Value(val int64, p *int64) Value       { return newSignedValue(val, p, 64, "int64") }
+func newUintValue(val uint, p *uint) Value          { return newUnsignedValue(val, p, 0, "uint") }
+func newUint8Value(val uint8, p *uint8) Value       { return newUnsignedValue(val, p, 8, "uint8") }
+func newUint16Value(val uint16, p *uint16) Value    { return newUnsignedValue(val, p, 16, "uint16") }
+func newUint32Value(val uint32, p *uint32) Value    { return newUnsignedValue(val, p, 32, "uint32") }
+func newUint64Value(val uint64, p *uint64) Value    { return newUnsignedValue(val, p, 64, "uint64") }
 func newFloat32Value(val float32, p *float32) Value { return optargs.NewFloat32Value(val, p) }
 func newFloat64Value(val float64, p *float64) Value { return optargs.NewFloat64Value(val, p) }
 func newDurationValue(val time.Duration, p *time.Duration) Value {
@@ -47,18 +49,80 @@ func newDurationSliceValue(val []time.Duration, p *[]time.Duration) Value {
 	return optargs.NewDurationSliceValue(val, p)
 }
 
+// signedValue and unsignedValue parse integers the way upstream pflag
+// does rather than through the core converters: base prefixes (0x, 0o,
+// 0b) and digit underscores are accepted, strconv errors are returned
+// verbatim so "invalid argument" messages match upstream word for word,
+// and, as upstream, the clamped value strconv reports for an out-of-range
+// input is stored even though Set fails. A bits of 0 means the platform
+// int size.
+type signedValue[T ~int | ~int8 | ~int16 | ~int32 | ~int64] struct {
+	p     *T
+	bits  int
+	tname string
+}
+
+func newSignedValue[T ~int | ~int8 | ~int16 | ~int32 | ~int64](val T, p *T, bits int, tname string) Value {
+	if p == nil {
+		p = new(T)
+	}
+	*p = val
+	return &signedValue[T]{p: p, bits: bits, tname: tname}
+}
+
+func (v *signedValue[T]) Set(s string) error {
+	n, err := strconv.ParseInt(s, 0, v.bits)
+	*v.p = T(n)
+	return err
+}
+
+func (v *signedValue[T]) String() string { return strconv.FormatInt(int64(*v.p), 10) }
+func (v *signedValue[T]) Type() string   { return v.tname }
+
+type unsignedValue[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64] struct {
+	p     *T
+	bits  int
+	tname string
+}
+
+func newUnsignedValue[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64](val T, p *T, bits int, tname string) Value {
+	if p == nil {
+		p = new(T)
+	}
+	*p = val
+	return &unsignedValue[T]{p: p, bits: bits, tname: tname}
+}
+
+func (v *unsignedValue[T]) Set(s string) error {
+	n, err := strconv.ParseUint(s, 0, v.bits)
+	*v.p = T(n)
+	return err
+}
+
+func (v *unsignedValue[T]) String() string { return strconv.FormatUint(uint64(*v.p), 10) }
+func (v *unsignedValue[T]) Type() string   { return v.tname }
+
 // String collection and map types.
 func newStringArrayValue(val []string, p *[]string) Value { return optargs.NewStringArrayValue(val, p) }
 func newStringToStringValue(val map[string]string, p *map[string]string) Value {
-	return optargs.NewStringToStringValue(val, p)
+	return mapValue{optargs.NewStringToStringValue(val, p)}
 }
 func newStringToIntValue(val map[string]int, p *map[string]int) Value {
-	return optargs.NewStringToIntValue(val, p)
+	return mapValue{optargs.NewStringToIntValue(val, p)}
 }
 func newStringToInt64Value(val map[string]int64, p *map[string]int64) Value {
-	return optargs.NewStringToInt64Value(val, p)
+	return mapValue{optargs.NewStringToInt64Value(val, p)}
 }
 
+// mapValue adapts a core map value to upstream's "[k=v,...]" rendering;
+// the core renders maps as "map[k=v,...]".
+type mapValue struct{ optargs.TypedValue }
+
+func (v mapValue) String() string { return strings.TrimPrefix(v.TypedValue.String(), "map") }
+
+// Reset forwards to the core value so --no-<name> clears the map.
+func (v mapValue) Reset() { v.TypedValue.(optargs.Resetter).Reset() } //nolint:errcheck // core map values implement Resetter
+
 // Special types.
 func newCountValue(val int, p *int) Value          { return optargs.NewCountValue(val, p) }
 func newFuncValue(fn func(string) error) Value     { return optargs.NewFuncValue(fn) }
@@ -68,16 +132,30 @@ func newTextValue(val encoding.TextMarshaler, dest encoding.TextUnmarshaler) Val
 	return optargs.NewTextValue(val, dest)
 }
 
-// -- IP value (net.IP implements TextUnmarshaler).
+// -- IP value (custom: upstream reports type "ip" and renders nil as "<nil>").
+
+type ipValue net.IP
 
 func newIPValue(val net.IP, p *net.IP) Value {
 	if p == nil {
 		p = new(net.IP)
 	}
 	*p = val
-	return optargs.NewTextValue(&val, p)
+	return (*ipValue)(p)
 }
 
+func (v *ipValue) Set(s string) error {
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip == nil {
+		return fmt.Errorf("invalid IP address: %q", s)
+	}
+	*v = ipValue(ip)
+	return nil
+}
+
+func (v *ipValue) String() string { return net.IP(*v).String() }
+func (v *ipValue) Type() string   { return "ip" }
+
 // -- IPMask value (custom: net.IPMask does not implement TextUnmarshaler).
 
 type ipMaskValue net.IPMask
@@ -135,6 +213,49 @@ func (v *ipNetValue) String() string {
 
 func (v *ipNetValue) Type() string { return "ipNet" }
 
+// -- Time value (custom: tries each accepted layout in turn, as upstream).
+
+type timeValue struct {
+	p       *time.Time
+	formats []string
+}
+
+func newTimeValue(val time.Time, p *time.Time, formats []string) Value {
+	if p == nil {
+		p = new(time.Time)
+	}
+	*p = val
+	if len(formats) == 0 {
+		formats = []string{time.RFC3339}
+	}
+	return &timeValue{p: p, formats: formats}
+}
+
+func (v *timeValue) Set(s string) error {
+	s = strings.TrimSpace(s)
+	for _, layout := range v.formats {
+		if t, err := time.Parse(layout, s); err == nil {
+			*v.p = t
+			return nil
+		}
+	}
+	layouts := make([]string, len(v.formats))
+	for i, layout := range v.formats {
+		layouts[i] = "`" + layout + "`"
+	}
+	return fmt.Errorf("invalid time format `%s` must be one of: %s", s, strings.Join(layouts, ", "))
+}
+
+// String renders the zero time as "" so it is not shown as a default.
+func (v *timeValue) String() string {
+	if v.p.IsZero() {
+		return ""
+	}
+	return v.p.Format(time.RFC3339Nano)
+}
+
+func (v *timeValue) Type() string { return "time" }
+
 // -- BytesHex value (delegates to optargs core).
 
 func newBytesHexValue(val []byte, p *[]byte) Value { return optargs.NewBytesHexValue(val, p) }
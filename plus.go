@@ -0,0 +1,7 @@
+package optargs
+
+// isToggleOff reports whether '+'-prefixed toggles are enabled and arg is
+// a '+' followed by at least one character, as in +x or +abc.
+func (p *Parser) isToggleOff(arg string) bool {
+	return p.config.plusOpts && len(arg) > 1 && arg[0] == '+'
+}
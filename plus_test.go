@@ -0,0 +1,103 @@
+package optargs
+
+import "testing"
+
+func TestPlusOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		args     []string
+		want     []Option
+		wantOn   []bool
+		wantArgs []string
+		wantErr  bool
+	}{
+		{
+			name:     "toggles",
+			enabled:  true,
+			args:     []string{"-x", "+x", "+eu", "-o", "pipefail", "+o", "noclobber", "--trace"},
+			want:     []Option{{Name: "x"}, {Name: "x"}, {Name: "e"}, {Name: "u"}, {Name: "o", HasArg: true, Arg: "pipefail"}, {Name: "o", HasArg: true, Arg: "noclobber"}, {Name: "trace"}},
+			wantOn:   []bool{true, false, false, false, true, false, true},
+			wantArgs: []string{},
+		},
+		{
+			name:     "lone plus",
+			enabled:  true,
+			args:     []string{"+", "-x"},
+			want:     []Option{{Name: "x"}},
+			wantOn:   []bool{true},
+			wantArgs: []string{"+"},
+		},
+		{
+			name:    "unknown",
+			enabled: true,
+			args:    []string{"+z"},
+			wantErr: true,
+		},
+		{
+			name:     "disabled",
+			args:     []string{"+x", "-x"},
+			want:     []Option{{Name: "x"}},
+			wantOn:   []bool{false},
+			wantArgs: []string{"+x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetOptLong(tt.args, "xeuo:", []Flag{{Name: "trace"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.SetErrorMode(ErrorSilent)
+			p.SetPlusOptions(tt.enabled)
+			var got []Option
+			sawErr := false
+			for opt, err := range p.Options() {
+				if err != nil {
+					sawErr = true
+					continue
+				}
+				got = append(got, opt)
+			}
+			if sawErr != tt.wantErr {
+				t.Errorf("error = %v, want %v", sawErr, tt.wantErr)
+			}
+			assertOptions(t, got, tt.want)
+			for i := range min(len(got), len(tt.wantOn)) {
+				if got[i].Enabled != tt.wantOn[i] {
+					t.Errorf("option %d (%s) Enabled = %v, want %v", i, got[i].Name, got[i].Enabled, tt.wantOn[i])
+				}
+			}
+			if !tt.wantErr {
+				assertArgs(t, p.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestPlusOptionsHandler(t *testing.T) {
+	var cfg ParserConfig
+	cfg.SetPlusOptions(true)
+	if !cfg.PlusOptions() {
+		t.Fatal("PlusOptions() = false after SetPlusOptions(true)")
+	}
+	var names []string
+	var xtrace bool
+	p, err := NewParser(cfg, map[byte]*Flag{
+		'x': {Name: "x"},
+		'v': {Name: "v", Handle: func(name, _ string) error { names = append(names, name); return nil }},
+	}, nil, []string{"-x", "-v", "+v", "+x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Bind(p, map[string]any{"x": &xtrace}); err != nil {
+		t.Fatal(err)
+	}
+	requireParsedOptions(t, p)
+	if len(names) != 2 || names[0] != "v" || names[1] != "+v" {
+		t.Errorf("handler names = %q, want [v +v]", names)
+	}
+	if xtrace {
+		t.Error("bound xtrace = true after +x, want false")
+	}
+}
@@ -0,0 +1,13 @@
+package optargs
+
+// Parsing phases reported by [SetProfileLabels] under the "optargs.phase"
+// label.
+const (
+	phaseSanitize = iota // running the argument sanitizer
+	phaseLong            // resolving a long option, parent chain included
+	phaseShort           // resolving one option of a short cluster
+	phaseCommand         // looking up an operand as a subcommand name
+	numPhases
+)
+
+var phaseNames = [numPhases]string{"sanitize", "long", "short", "command"}
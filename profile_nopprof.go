@@ -0,0 +1,19 @@
+//go:build !optargs_pprof
+
+package optargs
+
+import "context"
+
+// SetProfileLabels makes the parser label its goroutine with
+// optargs.phase=<phase> while it resolves options, so CPU profiles can
+// attribute time to long lookups, short clusters, command lookups, or
+// sanitizing. Labels in ctx are kept, and the goroutine is returned to
+// exactly ctx's labels before handlers run and options are yielded, so
+// pass the context carrying the caller's own labels. A nil ctx disables
+// labeling. Labels are only applied in builds with the optargs_pprof tag;
+// otherwise this is a no-op and runtime/pprof is not linked.
+func SetProfileLabels(context.Context) {}
+
+func profileEnter(int) {}
+
+func profileExit() {}
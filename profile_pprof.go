@@ -0,0 +1,50 @@
+//go:build optargs_pprof
+
+package optargs
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// profileState holds the label sets installed by profileEnter and
+// profileExit, derived once from the context given to SetProfileLabels.
+type profileState struct {
+	base   context.Context
+	phases [numPhases]context.Context
+}
+
+// profileLabels is nil while labeling is disabled.
+var profileLabels *profileState
+
+// SetProfileLabels makes the parser label its goroutine with
+// optargs.phase=<phase> while it resolves options, so CPU profiles can
+// attribute time to long lookups, short clusters, command lookups, or
+// sanitizing. Labels in ctx are kept, and the goroutine is returned to
+// exactly ctx's labels before handlers run and options are yielded, so
+// pass the context carrying the caller's own labels. A nil ctx disables
+// labeling. Labels are only applied in builds with the optargs_pprof tag;
+// otherwise this is a no-op and runtime/pprof is not linked.
+func SetProfileLabels(ctx context.Context) {
+	if ctx == nil {
+		profileLabels = nil
+		return
+	}
+	l := &profileState{base: ctx}
+	for i, name := range phaseNames {
+		l.phases[i] = pprof.WithLabels(ctx, pprof.Labels("optargs.phase", name))
+	}
+	profileLabels = l
+}
+
+func profileEnter(phase int) {
+	if l := profileLabels; l != nil {
+		pprof.SetGoroutineLabels(l.phases[phase])
+	}
+}
+
+func profileExit() {
+	if l := profileLabels; l != nil {
+		pprof.SetGoroutineLabels(l.base)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build optargs_pprof
+
+package optargs
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestSetProfileLabels(t *testing.T) {
+	base := pprof.WithLabels(context.Background(), pprof.Labels("app", "test"))
+	SetProfileLabels(base)
+	defer SetProfileLabels(nil)
+
+	for i, name := range phaseNames {
+		if got, _ := pprof.Label(profileLabels.phases[i], "optargs.phase"); got != name {
+			t.Errorf("phase %d label = %q, want %q", i, got, name)
+		}
+		if got, _ := pprof.Label(profileLabels.phases[i], "app"); got != "test" {
+			t.Errorf("phase %s dropped the caller's label", name)
+		}
+	}
+
+	p, err := GetOptLong([]string{"-ab", "--long", "cmd"}, "ab", []Flag{{Name: "long"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, err := GetOpt(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.AddCmd("cmd", cmd)
+	if got := len(requireParsedOptions(t, p)); got != 3 {
+		t.Errorf("parsed %d options with labels enabled, want 3", got)
+	}
+}
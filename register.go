@@ -0,0 +1,155 @@
+package optargs
+
+import (
+	"maps"
+	"strings"
+	"unicode"
+)
+
+// AddShortFlag registers flag as the short option c of an existing
+// parser, such as one built by [GetOpt], so plugins and conditional
+// options need not rebuild it. The rules of an optstring apply: c must be
+// a graphic character other than ':', ';' and '-'. Registering a second
+// flag under c is an error.
+//
+// Options are looked up as arguments are parsed, so a flag added between
+// iterations, or from a handler, applies to the arguments not yet parsed.
+func (p *Parser) AddShortFlag(c byte, flag *Flag) error {
+	if flag == nil {
+		return p.optErrorf("nil flag for short option: %s", byteString(c))
+	}
+	if p.shortOpts[c] != nil {
+		return p.optErrorf("short option already registered: %s", byteString(c))
+	}
+	return p.addShortOpt(c, flag)
+}
+
+// AddLongFlag registers flag as the long option name of an existing
+// parser, together with its Flag.Aliases; see [Parser.AddShortFlag]. The
+// name must be non-empty and graphic, and neither it nor an alias may be
+// registered already; on error the parser is left unchanged.
+func (p *Parser) AddLongFlag(name string, flag *Flag) error {
+	if flag == nil {
+		return p.optErrorf("nil flag for long option: %s", name)
+	}
+	if name == "" || strings.IndexFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || !unicode.IsGraphic(r)
+	}) >= 0 {
+		return p.optErrorf("invalid long option: %s", name)
+	}
+	if _, ok := p.longOpts[name]; ok {
+		return p.optErrorf("long option already registered: %s", name)
+	}
+	if _, ok := p.longAliases[name]; ok {
+		return p.optErrorf("long option conflicts with alias: %s", name)
+	}
+
+	// The map may be the caller's own from NewParser; copy it rather
+	// than add to it.
+	prev := p.longOpts
+	p.longOpts = maps.Clone(prev)
+	if p.longOpts == nil {
+		p.longOpts = make(map[string]*Flag)
+	}
+	p.longOpts[name] = flag
+	for _, alias := range flag.Aliases {
+		if err := p.addLongAlias(alias, name); err != nil {
+			p.longOpts = prev
+			maps.DeleteFunc(p.longAliases, func(_, target string) bool { return target == name })
+			return err
+		}
+	}
+
+	if p.config.longCaseIgnore {
+		if p.longOptsLower == nil {
+			p.longOptsLower = make(map[string]*Flag)
+		}
+		p.longOptsLower[strings.ToLower(name)] = flag
+		for _, alias := range flag.Aliases {
+			p.longOptsLower[strings.ToLower(alias)] = flag
+		}
+	}
+	return nil
+}
+
+// RemoveFlag makes name, a long option, one of its aliases, or a short
+// option character, no longer an option of p, so a wrapper can suppress
+// an inherited or conflicting option in one subcommand. An option
+// registered on p is removed, aliases included; its Flag.Peer is not. An
+// option p inherits from a parent stays with the parent, and p registers
+// a disabled flag (see Flag.Disabled) under its names to hide it. Either
+// way the option is unknown to p and its subcommands afterwards. It is
+// an error if name is not an option of p.
+func (p *Parser) RemoveFlag(name string) error {
+	long := p.ownExactMatch(name)
+	matched, short := p.shortFlag(name)
+	switch {
+	case long.flag != nil && long.flag.Disabled, long.flag == nil && short != nil && short.Disabled:
+		return p.optErrorf("unknown option: %s", name)
+	case long.flag != nil:
+		p.removeLongFlag(long.name, long.flag)
+		if p.parent != nil {
+			return p.hideLong(p.parent.exactMatch(name))
+		}
+		return nil
+	case short != nil:
+		p.removeShortFlag(matched)
+		if p.parent != nil {
+			return p.hideShort(p.parent.resolveShortName(name))
+		}
+		return nil
+	case p.parent == nil:
+		return p.optErrorf("unknown option: %s", name)
+	}
+	if m := p.parent.exactMatch(name); m.flag != nil {
+		return p.hideLong(m)
+	}
+	if matched, flag := p.parent.resolveShortName(name); flag != nil {
+		return p.hideShort(matched, flag)
+	}
+	return p.optErrorf("unknown option: %s", name)
+}
+
+// hideLong registers a disabled flag on p under the names of the long
+// option m of an ancestor, if there is one.
+func (p *Parser) hideLong(m matchResult) error {
+	if m.flag == nil {
+		return nil
+	}
+	return p.AddLongFlag(m.name, &Flag{Name: m.name, HasArg: m.flag.HasArg, Aliases: m.flag.Aliases, Disabled: true})
+}
+
+// hideShort registers a disabled flag on p as the short option name of
+// an ancestor, if flag is non-nil.
+func (p *Parser) hideShort(name string, flag *Flag) error {
+	if flag == nil {
+		return nil
+	}
+	hidden := &Flag{Name: name, HasArg: flag.HasArg, Disabled: true}
+	if r, ok := runeName(name); ok {
+		return p.addShortRune(r, hidden)
+	}
+	return p.addShortOpt(name[0], hidden)
+}
+
+// removeLongFlag unregisters the long option name, which is flag, and its
+// aliases from p.
+func (p *Parser) removeLongFlag(name string, flag *Flag) {
+	// The map may be the caller's own from NewParser; copy it rather
+	// than delete from it.
+	p.longOpts = maps.Clone(p.longOpts)
+	delete(p.longOpts, name)
+	maps.DeleteFunc(p.longAliases, func(_, target string) bool { return target == name })
+	maps.DeleteFunc(p.longOptsLower, func(_ string, f *Flag) bool { return f == flag })
+}
+
+// removeShortFlag unregisters the short option name, a single byte or
+// multibyte character, from p.
+func (p *Parser) removeShortFlag(name string) {
+	if len(name) == 1 {
+		p.shortOpts[name[0]] = nil
+	} else if r, ok := runeName(name); ok {
+		delete(p.runeOpts, r)
+	}
+	p.shortOptN--
+}
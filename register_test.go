@@ -0,0 +1,270 @@
+package optargs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddFlags(t *testing.T) {
+	longOpts := []Flag{{Name: "verbose", HasArg: NoArgument}}
+	p, err := GetOptLong([]string{"-v", "-o", "out", "--plugin=x", "--PLUG-IN", "y", "--ext", "z"}, "v", longOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddShortFlag('o', &Flag{Name: "o", HasArg: RequiredArgument}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddLongFlag("plugin", &Flag{Name: "plugin", HasArg: RequiredArgument, Aliases: []string{"plug-in", "ext"}}); err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, requireParsedOptions(t, p), []Option{
+		{Name: "v"},
+		{Name: "o", HasArg: true, Arg: "out"},
+		{Name: "plugin", HasArg: true, Arg: "x"},
+		{Name: "plugin", HasArg: true, Arg: "y"},
+		{Name: "plugin", HasArg: true, Arg: "z"},
+	})
+}
+
+func TestAddFlagsBetweenIterations(t *testing.T) {
+	longOpts := map[string]*Flag{"mode": {Name: "mode", HasArg: RequiredArgument}}
+	p, err := NewParser(ParserConfig{}, nil, longOpts, []string{"--mode", "extra", "--extra", "-x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for opt, err := range p.Options() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opt.Name == "mode" && opt.Arg == "extra" {
+			if err := p.AddLongFlag("extra", &Flag{Name: "extra"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.AddShortFlag('x', &Flag{Name: "x"}); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+	assertOptions(t, requireParsedOptions(t, p), []Option{{Name: "extra"}, {Name: "x"}})
+	if _, ok := longOpts["extra"]; ok {
+		t.Error("AddLongFlag modified the map passed to NewParser")
+	}
+}
+
+func TestAddFlagsErrors(t *testing.T) {
+	p, err := NewParser(ParserConfig{}, map[byte]*Flag{'a': {Name: "a"}},
+		map[string]*Flag{"all": {Name: "all", Aliases: []string{"every"}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"short duplicate", p.AddShortFlag('a', &Flag{Name: "a"}), "short option already registered: a"},
+		{"short prohibited", p.AddShortFlag(':', &Flag{Name: ":"}), "prohibited short option: :"},
+		{"short invalid", p.AddShortFlag(' ', &Flag{Name: " "}), "invalid short option"},
+		{"short nil", p.AddShortFlag('b', nil), "nil flag for short option: b"},
+		{"long duplicate", p.AddLongFlag("all", &Flag{Name: "all"}), "long option already registered: all"},
+		{"long alias", p.AddLongFlag("every", &Flag{Name: "every"}), "long option conflicts with alias: every"},
+		{"long invalid", p.AddLongFlag("a b", &Flag{Name: "a b"}), "invalid long option: a b"},
+		{"long empty", p.AddLongFlag("", &Flag{}), "invalid long option"},
+		{"long nil", p.AddLongFlag("none", nil), "nil flag for long option: none"},
+		{"shared alias", p.AddLongFlag("each", &Flag{Name: "each", Aliases: []string{"any", "every"}}), "long option alias every is shared by all and each"},
+	}
+	for _, tt := range tests {
+		if tt.err == nil || !strings.Contains(tt.err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, tt.err, tt.want)
+		}
+	}
+
+	// A failed registration leaves nothing behind.
+	if _, ok := p.longOpts["each"]; ok {
+		t.Error("failed AddLongFlag registered the option")
+	}
+	if _, ok := p.longAliases["any"]; ok {
+		t.Error("failed AddLongFlag registered an alias")
+	}
+	if err := p.AddLongFlag("any", &Flag{Name: "any"}); err != nil {
+		t.Errorf("AddLongFlag after failure: %v", err)
+	}
+}
+
+// firstError parses every option of p and returns the first error.
+func firstError(p *Parser) error {
+	for _, err := range p.Options() {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRemoveFlag(t *testing.T) {
+	newParser := func(args ...string) *Parser {
+		t.Helper()
+		p, err := GetOptLong(args, "vo:", []Flag{
+			{Name: "verbose", Aliases: []string{"loud"}},
+			{Name: "output", HasArg: RequiredArgument},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		name    string
+		remove  string
+		args    []string
+		wantErr string
+	}{
+		{"long", "verbose", []string{"--verbose"}, "unknown option: verbose"},
+		{"long alias", "verbose", []string{"--loud"}, "unknown option: loud"},
+		{"by alias", "loud", []string{"--verb"}, "unknown option: verb"},
+		{"case folded", "VERBOSE", []string{"--Verbose"}, "unknown option: Verbose"},
+		{"short", "v", []string{"-v"}, "unknown option: v"},
+		{"short kept", "verbose", []string{"-v", "--output", "x"}, ""},
+		{"long kept", "v", []string{"--verbose", "-o", "x"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.args...)
+			if err := p.RemoveFlag(tt.remove); err != nil {
+				t.Fatal(err)
+			}
+			err := firstError(p)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	p := newParser()
+	if err := p.RemoveFlag("verbose"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.RemoveFlag("verbose"); err == nil || err.Error() != "unknown option: verbose" {
+		t.Errorf("second RemoveFlag: err = %v", err)
+	}
+	if err := p.RemoveFlag("nope"); err == nil {
+		t.Error("RemoveFlag of an unknown option succeeded")
+	}
+	if err := p.AddLongFlag("verbose", &Flag{Name: "verbose"}); err != nil {
+		t.Errorf("AddLongFlag after RemoveFlag: %v", err)
+	}
+}
+
+func TestRemoveFlagInherited(t *testing.T) {
+	parent, err := GetOptLong(nil, "vä", []Flag{
+		{Name: "verbose", Aliases: []string{"loud"}},
+		{Name: "version"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newChild := func(args ...string) *Parser {
+		t.Helper()
+		child, err := GetOptLong(args, "", []Flag{{Name: "dry-run"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		parent.AddCmd("run", child)
+		return child
+	}
+
+	tests := []struct {
+		name    string
+		remove  string
+		args    []string
+		want    []Option
+		wantErr string
+	}{
+		{name: "long", remove: "verbose", args: []string{"--verbose"}, wantErr: "unknown option: verbose"},
+		{name: "inherited alias", remove: "verbose", args: []string{"--loud"}, wantErr: "unknown option: loud"},
+		{name: "prefix", remove: "verbose", args: []string{"--ver"}, want: []Option{{Name: "version"}}},
+		{name: "short kept", remove: "verbose", args: []string{"-v"}, want: []Option{{Name: "v"}}},
+		{name: "short", remove: "v", args: []string{"-v"}, wantErr: "unknown option: v"},
+		{name: "rune", remove: "ä", args: []string{"-ä"}, wantErr: "unknown option: ä"},
+		{name: "own kept", remove: "verbose", args: []string{"--dry"}, want: []Option{{Name: "dry-run"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			child := newChild(tt.args...)
+			if err := child.RemoveFlag(tt.remove); err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" {
+				if err := firstError(child); err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			assertOptions(t, requireParsedOptions(t, child), tt.want)
+		})
+	}
+
+	// The parent and its other subcommands keep the option.
+	other := newChild()
+	child := newChild()
+	if err := child.RemoveFlag("verbose"); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*Parser{parent, other} {
+		if m := p.exactMatch("verbose"); m.flag == nil {
+			t.Error("RemoveFlag in a subcommand removed the option elsewhere")
+		}
+	}
+
+	// A subcommand option shadowing the parent's is removed along with
+	// the parent's.
+	shadow := newChild("--verbose")
+	if err := shadow.AddLongFlag("verbose", &Flag{Name: "verbose"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := shadow.RemoveFlag("verbose"); err != nil {
+		t.Fatal(err)
+	}
+	if err := firstError(shadow); err == nil || err.Error() != "unknown option: verbose" {
+		t.Errorf("err = %v, want unknown option", err)
+	}
+}
+
+func TestDisabledFlag(t *testing.T) {
+	shortOpts := map[byte]*Flag{'x': {Name: "x", Disabled: true, Required: true}}
+	longOpts := map[string]*Flag{
+		"trace":  {Name: "trace", Disabled: true},
+		"tracer": {Name: "tracer"},
+	}
+	p, err := NewParser(ParserConfig{}, shortOpts, longOpts, []string{"--trac"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, requireParsedOptions(t, p), []Option{{Name: "tracer"}})
+	if err := p.CheckRequired(); err != nil {
+		t.Errorf("CheckRequired: %v", err)
+	}
+	for _, opt := range p.VisibleOptions() {
+		if opt.Flag.Disabled {
+			t.Errorf("VisibleOptions lists disabled %s", opt.Name)
+		}
+	}
+
+	var usage strings.Builder
+	if err := p.WriteUsage(&usage); err != nil {
+		t.Fatal(err)
+	}
+	if out := usage.String(); strings.Contains(out, "-x") || strings.Contains(out, "--trace ") || strings.Contains(out, "--trace\n") {
+		t.Errorf("usage lists a disabled option:\n%s", out)
+	}
+
+	p, err = NewParser(ParserConfig{}, shortOpts, longOpts, []string{"-x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := firstError(p); err == nil || err.Error() != "unknown option: x" {
+		t.Errorf("err = %v, want unknown option: x", err)
+	}
+}
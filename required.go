@@ -0,0 +1,78 @@
+package optargs
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// CheckRequired reports the options marked Flag.Required that were not
+// parsed, as a *RequiredOptionError listing them, and each [Parser.Requires]
+// declaration that was violated, as a *DependencyError. Several errors are
+// joined (see [errors.Join]). It is meant to be called once
+// [Parser.Options] iteration has finished. When a subcommand was
+// dispatched, the subcommand's declarations are checked as well, and an
+// option of p given after the subcommand name counts as seen. A short and
+// a long option linked through Flag.Peer are one option: either spelling
+// satisfies it, and it is required when either flag is.
+func (p *Parser) CheckRequired() error {
+	var missing []string
+	var errs []error
+	for level := p; level != nil; level = level.activeCmdParser {
+		for _, opt := range level.requiredOptions() {
+			if !level.seenBelow(opt.Flag) {
+				missing = append(missing, opt.Name)
+			}
+		}
+		errs = append(errs, level.checkDependencies()...)
+	}
+	if len(missing) > 0 {
+		err := &RequiredOptionError{Names: missing}
+		p.report(err)
+		errs = append([]error{err}, errs...)
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// requiredOptions returns the required options registered on p, sorted by
+// name. A Peer pair is listed once, under its long spelling.
+func (p *Parser) requiredOptions() []VisibleOption {
+	var opts []VisibleOption
+	short := func(name string, flag *Flag) {
+		if !isRequired(flag) || flag.Peer != nil && p.longOpts[flag.Peer.Name] == flag.Peer {
+			return
+		}
+		opts = append(opts, VisibleOption{Name: "-" + name, Flag: flag, Owner: p})
+	}
+	for c, flag := range p.shortOpts {
+		short(byteString(byte(c)), flag)
+	}
+	for r, flag := range p.runeOpts {
+		short(string(r), flag)
+	}
+	for name, flag := range p.longOpts {
+		if isRequired(flag) {
+			opts = append(opts, VisibleOption{Name: "--" + name, Flag: flag, Owner: p})
+		}
+	}
+	slices.SortFunc(opts, func(a, b VisibleOption) int { return strings.Compare(a.Name, b.Name) })
+	return opts
+}
+
+func isRequired(flag *Flag) bool {
+	return flag != nil && !flag.Disabled && (flag.Required || flag.Peer != nil && flag.Peer.Required)
+}
+
+// seenBelow reports whether flag, or its Peer, was parsed by p or by a
+// subcommand dispatched from it.
+func (p *Parser) seenBelow(flag *Flag) bool {
+	for cur := p; cur != nil; cur = cur.activeCmdParser {
+		if cur.counts[flag] > 0 || flag.Peer != nil && cur.counts[flag.Peer] > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package optargs
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckRequired(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string // missing options; nil when CheckRequired succeeds
+	}{
+		{"all given", []string{"-o", "out", "-i", "in", "--token=t"}, nil},
+		{"peer spelling", []string{"--output", "out", "-i", "in", "--token", "t"}, nil},
+		{"one missing", []string{"-o", "out", "-i", "in"}, []string{"--token"}},
+		{"all missing", []string{"-v"}, []string{"--output", "--token", "-i"}},
+		{"after subcommand", []string{"-i", "in", "sub", "-o", "out", "--token=t", "--name", "n"}, nil},
+		{"subcommand missing", []string{"-i", "in", "sub", "-o", "out", "--token=t"}, []string{"--name"}},
+		{"both levels missing", []string{"sub"}, []string{"--output", "--token", "-i", "--name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &Flag{Name: "output", HasArg: RequiredArgument}
+			o := &Flag{Name: "o", HasArg: RequiredArgument, Required: true, Peer: output}
+			output.Peer = o
+			root, err := NewParser(ParserConfig{},
+				map[byte]*Flag{
+					'o': o,
+					'i': {Name: "i", HasArg: RequiredArgument, Required: true},
+					'v': {Name: "v"},
+				},
+				map[string]*Flag{
+					"output": output,
+					"token":  {Name: "token", HasArg: RequiredArgument, Required: true},
+				}, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sub, err := NewParser(ParserConfig{}, nil, map[string]*Flag{
+				"name": {Name: "name", HasArg: RequiredArgument, Required: true},
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.AddCmd("sub", sub)
+
+			requireParsedOptions(t, root)
+			for _, child := range root.Dispatches() {
+				requireParsedOptions(t, child)
+			}
+			err = root.CheckRequired()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("CheckRequired() = %v, want nil", err)
+				}
+				return
+			}
+			var rerr *RequiredOptionError
+			if !errors.As(err, &rerr) {
+				t.Fatalf("CheckRequired() = %v, want *RequiredOptionError", err)
+			}
+			if !slices.Equal(rerr.Names, tt.want) {
+				t.Errorf("Names = %q, want %q", rerr.Names, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredOptionErrorMessage(t *testing.T) {
+	if got := (&RequiredOptionError{Names: []string{"--output"}}).Error(); got != "missing required option: --output" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := (&RequiredOptionError{Names: []string{"--output", "-f"}}).Error(); got != "missing required options: --output, -f" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestRequiredUsage(t *testing.T) {
+	p, err := NewParser(ParserConfig{}, nil, map[string]*Flag{
+		"output": {Name: "output", HasArg: RequiredArgument, Help: "output file", Required: true},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := p.WriteUsage(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "--output=ARG  output file (required)") {
+		t.Errorf("usage does not mark --output as required:\n%s", b.String())
+	}
+}
@@ -0,0 +1,82 @@
+package optargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dependency is one Requires declaration.
+type dependency struct {
+	flag     *Flag
+	name     string // spelling for DependencyError
+	requires []*Flag
+	names    []string
+}
+
+// Requires declares that the option name may only be given together with
+// each of the options in requires, as in
+//
+//	p.Requires("--tls-cert", "--tls-key")
+//
+// Names are spelled with or without dashes and resolved like
+// [Parser.MutuallyExclusive]. The declaration is checked by
+// [Parser.CheckRequired] once iteration has finished, which reports
+// "option --tls-cert requires --tls-key" as a *DependencyError when
+// --tls-cert was given without --tls-key. Options given after a
+// subcommand name count, as for Flag.Required.
+//
+// It fails, declaring nothing, if a name is not an option of p.
+func (p *Parser) Requires(name string, requires ...string) error {
+	flag, spelled, err := p.dependencyFlag(name)
+	if err != nil {
+		return err
+	}
+	dep := dependency{flag: flag, name: spelled}
+	for _, req := range requires {
+		reqFlag, reqSpelled, err := p.dependencyFlag(req)
+		if err != nil {
+			return err
+		}
+		dep.requires = append(dep.requires, reqFlag)
+		dep.names = append(dep.names, reqSpelled)
+	}
+	p.dependencies = append(p.dependencies, dep)
+	return nil
+}
+
+// dependencyFlag resolves a name given to Requires and returns it spelled
+// with dashes.
+func (p *Parser) dependencyFlag(name string) (*Flag, string, error) {
+	bare := strings.TrimLeft(name, "-")
+	flag := p.resolveName(bare)
+	if flag == nil {
+		return nil, "", fmt.Errorf("requires: unknown option: %s", name)
+	}
+	if bare == name {
+		name = "--" + bare
+		if len(bare) == 1 {
+			name = "-" + bare
+		}
+	}
+	return flag, name, nil
+}
+
+// checkDependencies returns a *DependencyError for each Requires
+// declaration of p that was violated by the options parsed by p and the
+// subcommands dispatched from it.
+func (p *Parser) checkDependencies() []error {
+	var errs []error
+	for _, dep := range p.dependencies {
+		if !p.seenBelow(dep.flag) {
+			continue
+		}
+		for i, req := range dep.requires {
+			if !p.seenBelow(req) {
+				err := &DependencyError{Name: dep.name, Requires: dep.names[i]}
+				p.report(err)
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
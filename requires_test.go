@@ -0,0 +1,122 @@
+package optargs
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRequires(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string // DependencyError messages, in order
+	}{
+		{"neither", []string{"-v"}, nil},
+		{"both", []string{"--tls-cert=c", "--tls-key=k", "--tls-ca=a"}, nil},
+		{"dependent alone", []string{"--tls-cert=c"}, []string{
+			"option --tls-cert requires --tls-key",
+			"option --tls-cert requires --tls-ca",
+		}},
+		{"required alone", []string{"--tls-key=k"}, nil},
+		{"peer spelling", []string{"-c", "c", "--tls-ca=a"}, []string{"option --tls-cert requires --tls-key"}},
+		{"given after subcommand", []string{"--tls-cert=c", "serve", "--tls-key=k", "--tls-ca=a"}, nil},
+		{"subcommand declaration", []string{"serve", "-p", "80"}, []string{"option --port requires -v"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := &Flag{Name: "tls-cert", HasArg: RequiredArgument}
+			c := &Flag{Name: "c", HasArg: RequiredArgument, Peer: cert}
+			cert.Peer = c
+			root, err := NewParser(ParserConfig{},
+				map[byte]*Flag{'c': c, 'v': {Name: "v"}},
+				map[string]*Flag{
+					"tls-cert": cert,
+					"tls-key":  {Name: "tls-key", HasArg: RequiredArgument},
+					"tls-ca":   {Name: "tls-ca", HasArg: RequiredArgument},
+				}, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := root.Requires("--tls-cert", "--tls-key", "tls-ca"); err != nil {
+				t.Fatal(err)
+			}
+			port := &Flag{Name: "port", HasArg: RequiredArgument}
+			p := &Flag{Name: "p", HasArg: RequiredArgument, Peer: port}
+			port.Peer = p
+			serve, err := NewParser(ParserConfig{}, map[byte]*Flag{'p': p}, map[string]*Flag{"port": port}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.AddCmd("serve", serve)
+			if err := serve.Requires("port", "v"); err != nil {
+				t.Fatal(err)
+			}
+
+			requireParsedOptions(t, root)
+			for _, child := range root.Dispatches() {
+				requireParsedOptions(t, child)
+			}
+			err = root.CheckRequired()
+			var got []string
+			if err != nil {
+				for _, e := range errorsOf(err) {
+					var derr *DependencyError
+					if !errors.As(e, &derr) {
+						t.Fatalf("error %v is not a *DependencyError", e)
+					}
+					got = append(got, derr.Error())
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("CheckRequired() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// errorsOf returns the errors joined in err, or err itself.
+func errorsOf(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+func TestRequiresErrors(t *testing.T) {
+	p, err := GetOptLong(nil, "", []Flag{{Name: "tls-cert"}, {Name: "tls-key"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"--tls-crt", "--tls-key"}, {"--tls-cert", "--tls-kye"}} {
+		if err := p.Requires(args[0], args[1:]...); err == nil || !strings.Contains(err.Error(), "unknown option: ") {
+			t.Errorf("Requires(%q) error = %v", args, err)
+		}
+	}
+	if len(p.dependencies) != 0 {
+		t.Error("failed declaration left a dependency behind")
+	}
+}
+
+func TestCheckRequiredJoinsErrors(t *testing.T) {
+	p, err := GetOptLong([]string{"--tls-cert"}, "", []Flag{
+		{Name: "tls-cert"}, {Name: "tls-key"}, {Name: "user", HasArg: RequiredArgument, Required: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Requires("tls-cert", "tls-key"); err != nil {
+		t.Fatal(err)
+	}
+	requireParsedOptions(t, p)
+	err = p.CheckRequired()
+	var rerr *RequiredOptionError
+	var derr *DependencyError
+	if !errors.As(err, &rerr) || !errors.As(err, &derr) {
+		t.Fatalf("CheckRequired() = %v, want required and dependency errors", err)
+	}
+	if want := "missing required option: --user\noption --tls-cert requires --tls-key"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+}
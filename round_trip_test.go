@@ -55,7 +55,7 @@ func TestRoundTripShortOptions(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			parse := func(a []string) (*Parser, error) { return GetOpt(a, tt.optstring) }
-			roundTrip(t, parse, tt.args, slices.Equal)
+			roundTrip(t, parse, tt.args, optionsEqual)
 		})
 	}
 }
@@ -80,7 +80,7 @@ func TestRoundTripLongOptions(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			parse := func(a []string) (*Parser, error) { return GetOptLong(a, "", longOpts) }
-			roundTrip(t, parse, tt.args, slices.Equal)
+			roundTrip(t, parse, tt.args, optionsEqual)
 		})
 	}
 }
@@ -125,13 +125,14 @@ func generateArgsFromOptions(options []Option, remainingArgs []string) []string
 	return append(args, remainingArgs...)
 }
 
-// optionsEqual checks if two option slices are exactly equal (order-sensitive).
+// optionsEqual checks if two option slices are equal (order-sensitive).
+// Argument positions are ignored since reconstruction may regroup words.
 func optionsEqual(a, b []Option) bool {
 	if len(a) != len(b) {
 		return false
 	}
 	for i := range a {
-		if a[i] != b[i] {
+		if a[i].Name != b[i].Name || a[i].HasArg != b[i].HasArg || a[i].Arg != b[i].Arg {
 			return false
 		}
 	}
@@ -151,10 +152,10 @@ func optionsEquivalent(a, b []Option) bool {
 	}
 	counts := make(map[key]int, len(a))
 	for _, o := range a {
-		counts[key(o)]++
+		counts[key{o.Name, o.HasArg, o.Arg}]++
 	}
 	for _, o := range b {
-		k := key(o)
+		k := key{o.Name, o.HasArg, o.Arg}
 		counts[k]--
 		if counts[k] < 0 {
 			return false
@@ -0,0 +1,125 @@
+package optargs
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NewParserRunes is like [NewParser] but takes short options keyed by
+// rune, so that multibyte characters such as 'ä' or 'λ' can be short
+// options: -ä parses as one option, alone or in a compacted group such
+// as -vä, and is yielded with Name "ä". ASCII runes are registered
+// exactly as NewParser registers bytes.
+func NewParserRunes(config ParserConfig, shortOpts map[rune]*Flag, longOpts map[string]*Flag, args []string) (*Parser, error) {
+	p, err := NewParser(config, nil, longOpts, args)
+	if err != nil {
+		return nil, err
+	}
+	for r, flag := range shortOpts {
+		if err := p.addShortRune(r, flag); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// addShortRune registers flag as the short option r.
+func (p *Parser) addShortRune(r rune, flag *Flag) error {
+	if r < utf8.RuneSelf {
+		return p.addShortOpt(byte(r), flag)
+	}
+	if r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsGraphic(r) {
+		return p.optErrorf("invalid short option: %c", r)
+	}
+	if p.runeOpts == nil {
+		p.runeOpts = make(map[rune]*Flag)
+	}
+	p.runeOpts[r] = flag
+	p.shortOptN++
+	return nil
+}
+
+// runeName returns the character of name when name is exactly one
+// multibyte UTF-8 character, the form of a non-ASCII short option name.
+func runeName(name string) (rune, bool) {
+	r, n := utf8.DecodeRuneInString(name)
+	return r, n > 1 && n == len(name)
+}
+
+// lookupShortRune is lookupShortOpt for a non-ASCII short option. With
+// case-insensitive short options it also tries the other case forms of r.
+func (p *Parser) lookupShortRune(r rune) (rune, *Flag) {
+	if flag := p.runeOpts[r]; flag != nil {
+		return r, flag
+	}
+	if !p.config.shortCaseIgnore {
+		return 0, nil
+	}
+	for alt := unicode.SimpleFold(r); alt != r; alt = unicode.SimpleFold(alt) {
+		if flag := p.runeOpts[alt]; flag != nil {
+			return alt, flag
+		}
+	}
+	return 0, nil
+}
+
+// shortFlag returns the short option of p called name, which is a single
+// byte or a single multibyte character, together with the name it is
+// registered under; the two differ only in case. It returns nil when
+// there is no such option.
+func (p *Parser) shortFlag(name string) (string, *Flag) {
+	if len(name) == 1 {
+		if c, flag := p.lookupShortOpt(name[0]); flag != nil {
+			return byteString(c), flag
+		}
+	} else if r, ok := runeName(name); ok {
+		if m, flag := p.lookupShortRune(r); flag != nil {
+			return string(m), flag
+		}
+	}
+	return "", nil
+}
+
+// resolveShortName is shortFlag searching the parent chain like the
+// parser does.
+func (p *Parser) resolveShortName(name string) (string, *Flag) {
+	for cur := p; cur != nil; cur = cur.parent {
+		if matched, flag := cur.shortFlag(name); flag != nil {
+			if flag.Disabled {
+				return "", nil
+			}
+			return matched, flag
+		}
+	}
+	return "", nil
+}
+
+// findRuneOpt is findShortOpt for the non-ASCII short option character r;
+// word is the rest of the group after it.
+func (p *Parser) findRuneOpt(r rune, word string, args []string) ([]string, string, *Flag, Option, error) {
+	for cur := p; cur != nil; cur = cur.parent {
+		if matched, flag := cur.lookupShortRune(r); flag != nil {
+			if flag.Disabled {
+				break
+			}
+			return p.shortOptArg(flag, string(matched), string(r), word, args)
+		}
+	}
+	return args, word, nil, Option{}, p.unknownOptionError(string(r), true)
+}
+
+// SetShortRuneHandler is [Parser.SetShortHandler] for a short option
+// given as a rune, including the non-ASCII ones registered through
+// [NewParserRunes].
+func (p *Parser) SetShortRuneHandler(r rune, handler func(string, string) error) error {
+	if r < utf8.RuneSelf {
+		return p.SetShortHandler(byte(r), handler)
+	}
+	f := p.runeOpts[r]
+	if f == nil {
+		return fmt.Errorf("unknown option: -%c", r)
+	}
+	f.Handle, f.HandleCtx = handler, nil
+	return nil
+}
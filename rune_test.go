@@ -0,0 +1,132 @@
+package optargs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuneShortOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     []Option
+		wantArgs []string
+		wantErr  string
+	}{
+		{
+			name:     "alone",
+			args:     []string{"-ä", "x", "-λ", "-ä"},
+			want:     []Option{{Name: "ä"}, {Name: "λ"}, {Name: "ä"}},
+			wantArgs: []string{"x"},
+		},
+		{
+			name:     "compacted",
+			args:     []string{"-vä", "-λv", "-äλv"},
+			want:     []Option{{Name: "v"}, {Name: "ä"}, {Name: "λ"}, {Name: "v"}, {Name: "ä"}, {Name: "λ"}, {Name: "v"}},
+			wantArgs: []string{},
+		},
+		{
+			name:     "arguments",
+			args:     []string{"-ö", "file", "-vöλ", "-ßx", "y", "-ß"},
+			want:     []Option{{Name: "ö", HasArg: true, Arg: "file"}, {Name: "v"}, {Name: "ö", HasArg: true, Arg: "λ"}, {Name: "ß", HasArg: true, Arg: "x"}, {Name: "ß"}},
+			wantArgs: []string{"y"},
+		},
+		{
+			name:    "unknown",
+			args:    []string{"-vé"},
+			wantErr: "unknown option: é",
+		},
+		{
+			name:    "missing argument",
+			args:    []string{"-vö"},
+			wantErr: "option requires an argument: ö",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetOpt(tt.args, ":väλö:ß::")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.SetSlashOptions(true)
+			opts, err := p.Collect()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertOptions(t, opts, tt.want)
+			assertArgs(t, p.Args, tt.wantArgs)
+		})
+	}
+}
+
+func TestRuneShortOptionPositions(t *testing.T) {
+	p, err := GetOpt([]string{"-väλ"}, "väλ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := requireParsedOptions(t, p)
+	for i, want := range []int{1, 2, 4} {
+		if opts[i].Offset != want {
+			t.Errorf("option %s offset = %d, want %d", opts[i].Name, opts[i].Offset, want)
+		}
+	}
+}
+
+func TestNewParserRunes(t *testing.T) {
+	if _, err := NewParserRunes(ParserConfig{}, map[rune]*Flag{' ': {Name: "nbsp"}}, nil, nil); err == nil {
+		t.Error("NewParserRunes accepted a space character")
+	}
+	if _, err := NewParserRunes(ParserConfig{}, map[rune]*Flag{'-': {Name: "-"}}, nil, nil); err == nil {
+		t.Error("NewParserRunes accepted a prohibited ASCII option")
+	}
+
+	var handled []string
+	umlaut := &Flag{Name: "ä", Help: "umlaut"}
+	long := &Flag{Name: "umlaut", Peer: umlaut}
+	umlaut.Peer = long
+	p, err := NewParserRunes(ParserConfig{shortCaseIgnore: true}, map[rune]*Flag{
+		'v': {Name: "v"},
+		'ä': umlaut,
+		'λ': {Name: "λ"},
+	}, map[string]*Flag{"umlaut": long}, []string{"-Ä", "-vΛ", "--umlaut", "/ä"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetSlashOptions(true)
+	if err := p.SetHandler("-λ", func(name, _ string) error {
+		handled = append(handled, name)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, requireParsedOptions(t, p), []Option{{Name: "ä"}, {Name: "v"}, {Name: "umlaut"}, {Name: "ä"}})
+	if len(handled) != 1 || handled[0] != "λ" {
+		t.Errorf("handler calls = %q, want [λ]", handled)
+	}
+	if got := p.Count("ä"); got != 3 {
+		t.Errorf(`Count("ä") = %d, want 3`, got)
+	}
+	if err := p.SetShortRuneHandler('ö', nil); err == nil {
+		t.Error("SetShortRuneHandler accepted an unregistered rune")
+	}
+
+	var usage strings.Builder
+	if err := p.WriteUsage(&usage); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(usage.String(), "-ä, --umlaut  umlaut\n") || !strings.Contains(usage.String(), "  -λ\n") {
+		t.Errorf("usage missing rune options:\n%s", usage.String())
+	}
+
+	restored, err := NewParserFromSpec(p.Spec(), []string{"-λä"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, requireParsedOptions(t, restored), []Option{{Name: "λ"}, {Name: "ä"}})
+}
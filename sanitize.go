@@ -0,0 +1,30 @@
+package optargs
+
+import "unicode"
+
+// SetSanitizer installs a function that vets every argument before option
+// processing begins. If it returns an error for any argument, Options
+// yields that error once and stops without consuming anything, leaving
+// Args untouched. Pass nil to remove the sanitizer.
+//
+// The parser itself treats arguments as opaque byte strings: embedded
+// NULs, newlines, and other control characters are never truncated or
+// used to split tokens, so a sanitizer is only needed by hosts that wish
+// to reject such input outright, for example before forwarding operands
+// to a shell or a C API. Arguments are vetted once per Options call,
+// including those after "--" and those destined for subcommands.
+func (p *Parser) SetSanitizer(fn func(arg string) error) {
+	p.config.sanitizer = fn
+}
+
+// RejectControlChars is a sanitizer for [Parser.SetSanitizer] that rejects
+// arguments containing NUL or any other Unicode control character,
+// reporting the first offender as a [*ControlCharError].
+func RejectControlChars(arg string) error {
+	for i, r := range arg {
+		if unicode.IsControl(r) {
+			return &ControlCharError{Arg: arg, Offset: i, Char: r}
+		}
+	}
+	return nil
+}
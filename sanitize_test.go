@@ -0,0 +1,105 @@
+package optargs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRejectControlChars(t *testing.T) {
+	tests := []struct {
+		arg    string
+		offset int
+		char   rune
+	}{
+		{"plain", -1, 0},
+		{"", -1, 0},
+		{"café — ok", -1, 0},
+		{"a\x00b", 1, 0},
+		{"line\nbreak", 4, '\n'},
+		{"\ttab", 0, '\t'},
+		{"esc\x1b[0m", 3, 0x1b},
+		{"del\x7f", 3, 0x7f},
+		{"c1\u0085", 2, 0x85},
+	}
+	for _, tt := range tests {
+		err := RejectControlChars(tt.arg)
+		if tt.offset < 0 {
+			if err != nil {
+				t.Errorf("RejectControlChars(%q) = %v, want nil", tt.arg, err)
+			}
+			continue
+		}
+		var cerr *ControlCharError
+		if !errors.As(err, &cerr) {
+			t.Errorf("RejectControlChars(%q) = %v, want *ControlCharError", tt.arg, err)
+			continue
+		}
+		if cerr.Offset != tt.offset || cerr.Char != tt.char || cerr.Arg != tt.arg {
+			t.Errorf("RejectControlChars(%q) = %+v, want offset %d char %U", tt.arg, cerr, tt.offset, tt.char)
+		}
+	}
+}
+
+func TestSetSanitizer(t *testing.T) {
+	args := []string{"-a", "op", "--", "x\x00y"}
+	p, err := GetOptLong(slices.Clone(args), "ab:", []Flag{{Name: "alpha", HasArg: NoArgument}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetSanitizer(RejectControlChars)
+
+	var opts []Option
+	var errs []error
+	for opt, err := range p.Options() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		opts = append(opts, opt)
+	}
+	var cerr *ControlCharError
+	if len(errs) != 1 || !errors.As(errs[0], &cerr) || cerr.Arg != "x\x00y" {
+		t.Fatalf("errors = %v, want one *ControlCharError for %q", errs, "x\x00y")
+	}
+	if len(opts) != 0 {
+		t.Errorf("options = %+v, want none after rejection", opts)
+	}
+	if !slices.Equal(p.Args, args) {
+		t.Errorf("Args = %q, want untouched %q", p.Args, args)
+	}
+
+	p.SetSanitizer(nil)
+	rest, err := p.Remaining()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"op", "x\x00y"}; !slices.Equal(rest, want) {
+		t.Errorf("Remaining() = %q, want %q", rest, want)
+	}
+}
+
+// TestControlCharsOpaque verifies that control characters pass through
+// option arguments and operands byte for byte.
+func TestControlCharsOpaque(t *testing.T) {
+	t.Setenv("POSIXLY_CORRECT", "")
+	p, err := GetOptLong([]string{
+		"-b\x00v", "--beta=a\nb", "--beta", "\x00", "op\x00\r\n", "",
+	}, "b:", []Flag{{Name: "beta", HasArg: RequiredArgument}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for opt, err := range p.Options() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, opt.Arg)
+	}
+	if want := []string{"\x00v", "a\nb", "\x00"}; !slices.Equal(got, want) {
+		t.Errorf("option args = %q, want %q", got, want)
+	}
+	if want := []string{"op\x00\r\n", ""}; !slices.Equal(p.Args, want) {
+		t.Errorf("Args = %q, want %q", p.Args, want)
+	}
+}
@@ -0,0 +1,50 @@
+package optargs
+
+import "strings"
+
+// isSlashOption reports whether '/'-prefixed options are enabled and arg
+// is a '/' followed by at least one character, as in /v or /out:file.
+func (p *Parser) isSlashOption(arg string) bool {
+	return p.config.slashOpts && len(arg) > 1 && arg[0] == '/'
+}
+
+// findSlashOpt resolves a Windows-style option word without its '/':
+// "x" names the short option x when p or an ancestor registers one, and
+// any other name a long option. A value follows the first ':'.
+func (p *Parser) findSlashOpt(word string, args []string) ([]string, *Flag, Option, error) {
+	name, value, hasValue := strings.Cut(word, ":")
+
+	matched, flag := p.resolveShortName(name)
+	isShort := flag != nil
+	if !isShort {
+		m, err := p.matchLongOpt(name)
+		if err != nil {
+			return args, nil, Option{}, err
+		}
+		if m.flag == nil {
+			err := &UnknownOptionError{
+				Name: name, Value: value, HasValue: hasValue, Suggestions: p.SuggestOptions(name),
+			}
+			p.report(err)
+			return args, nil, Option{}, err
+		}
+		matched, flag = m.name, m.flag
+	}
+	option := Option{Name: matched}
+
+	switch {
+	case hasValue && flag.HasArg == NoArgument:
+		err := &UnexpectedArgumentError{Name: option.Name, Value: value}
+		p.report(err)
+		return args, nil, Option{}, err
+	case hasValue:
+		option.HasArg, option.Arg = true, value
+	case flag.HasArg == RequiredArgument:
+		if len(args) == 0 {
+			return args, nil, option, p.missingArgumentError(option.Name, isShort)
+		}
+		option.HasArg, option.Arg = true, args[0]
+		args = args[1:]
+	}
+	return args, flag, option, nil
+}
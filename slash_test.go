@@ -0,0 +1,106 @@
+package optargs
+
+import "testing"
+
+func TestSlashOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		args     []string
+		want     []Option
+		wantArgs []string
+		wantErr  string
+	}{
+		{
+			name:     "short and long",
+			enabled:  true,
+			args:     []string{"/v", "/out:a.txt", "/n:3", "/n", "4", "/level", "x", "/ver", "/?"},
+			want:     []Option{{Name: "v"}, {Name: "out", HasArg: true, Arg: "a.txt"}, {Name: "n", HasArg: true, Arg: "3"}, {Name: "n", HasArg: true, Arg: "4"}, {Name: "level"}, {Name: "verbose"}, {Name: "?"}},
+			wantArgs: []string{"x"},
+		},
+		{
+			name:     "value keeps later colons",
+			enabled:  true,
+			args:     []string{"/out:C:\\temp\\a.txt", "/level:"},
+			want:     []Option{{Name: "out", HasArg: true, Arg: "C:\\temp\\a.txt"}, {Name: "level", HasArg: true}},
+			wantArgs: []string{},
+		},
+		{
+			name:     "operands",
+			enabled:  true,
+			args:     []string{"/", "-v", "--", "/usr/bin"},
+			want:     []Option{{Name: "v"}},
+			wantArgs: []string{"/", "/usr/bin"},
+		},
+		{
+			name:    "unknown",
+			enabled: true,
+			args:    []string{"/usr/bin"},
+			wantErr: "unknown option: usr/bin",
+		},
+		{
+			name:    "unexpected value",
+			enabled: true,
+			args:    []string{"/verbose:yes"},
+			wantErr: "option does not take an argument: verbose",
+		},
+		{
+			name:    "missing argument",
+			enabled: true,
+			args:    []string{"/out"},
+			wantErr: "option requires an argument: out",
+		},
+		{
+			name:     "disabled",
+			args:     []string{"/v", "-v"},
+			want:     []Option{{Name: "v"}},
+			wantArgs: []string{"/v"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetOptLong(tt.args, ":vn:?", []Flag{
+				{Name: "out", HasArg: RequiredArgument},
+				{Name: "level", HasArg: OptionalArgument},
+				{Name: "verbose"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.SetSlashOptions(tt.enabled)
+			opts, err := p.Collect()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertOptions(t, opts, tt.want)
+			assertArgs(t, p.Args, tt.wantArgs)
+		})
+	}
+}
+
+func TestSlashOptionsInherited(t *testing.T) {
+	var cfg ParserConfig
+	cfg.SetSlashOptions(true)
+	if !cfg.SlashOptions() {
+		t.Fatal("SlashOptions() = false after SetSlashOptions(true)")
+	}
+	root, err := NewParser(cfg, map[byte]*Flag{'v': {Name: "v"}}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := NewParser(cfg, nil, map[string]*Flag{"force": {Name: "force"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("push", sub)
+
+	root.Reset([]string{"/v", "push", "/f", "/v"})
+	assertOptions(t, requireParsedOptions(t, root), []Option{{Name: "v"}})
+	assertOptions(t, requireParsedOptions(t, sub), []Option{{Name: "force"}, {Name: "v"}})
+}
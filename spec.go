@@ -0,0 +1,343 @@
+package optargs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Spec is the static definition of a parser tree: its options, argument
+// types, help metadata, parsing configuration, and subcommands, without
+// handlers. It marshals to compact JSON with encoding/json, so shells,
+// daemons, and completion helpers can load a program's grammar without
+// importing its code:
+//
+//	data, err := json.Marshal(root.Spec())
+//	...
+//	var spec optargs.Spec
+//	err = json.Unmarshal(data, &spec)
+//	p, err := optargs.NewParserFromSpec(&spec, args)
+type Spec struct {
+	Name        string        `json:"name,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Config      SpecConfig    `json:"config"`
+	Short       []FlagSpec    `json:"short,omitempty"`
+	Long        []FlagSpec    `json:"long,omitempty"`
+	Commands    []CommandSpec `json:"commands,omitempty"`
+}
+
+// SpecConfig is the part of [ParserConfig] that affects the grammar.
+// Error reporting, the error writer, and the sanitizer are not included.
+type SpecConfig struct {
+	ParseMode         ParseMode `json:"parseMode,omitempty"`
+	LongOnly          bool      `json:"longOnly,omitempty"`
+	ShortCaseIgnore   bool      `json:"shortCaseIgnore,omitempty"`
+	LongCaseIgnore    bool      `json:"longCaseIgnore,omitempty"`
+	CommandCaseIgnore bool      `json:"commandCaseIgnore,omitempty"`
+	GNUWords          bool      `json:"gnuWords,omitempty"`
+	ShortOptEquals    bool      `json:"shortOptEquals,omitempty"`
+	StrictSubcommands bool      `json:"strictSubcommands,omitempty"`
+	CommandFirst      bool      `json:"commandFirst,omitempty"`
+	NumericOptions    bool      `json:"numericOptions,omitempty"`
+	NegativeNumbers   bool      `json:"negativeNumbers,omitempty"`
+	PlusOptions       bool      `json:"plusOptions,omitempty"`
+	SlashOptions      bool      `json:"slashOptions,omitempty"`
+}
+
+// FlagSpec describes one option. Peer names the linked option of the
+// other kind with its dashes ("-v" or "--verbose").
+type FlagSpec struct {
+	Name       string          `json:"name"`
+	HasArg     ArgType         `json:"hasArg,omitempty"`
+	Help       string          `json:"help,omitempty"`
+	ArgName    string          `json:"argName,omitempty"`
+	Default    string          `json:"default,omitempty"`
+	Peer       string          `json:"peer,omitempty"`
+	Aliases    []string        `json:"aliases,omitempty"`
+	Deprecated string          `json:"deprecated,omitempty"`
+	Hidden     bool            `json:"hidden,omitempty"`
+	Disabled   bool            `json:"disabled,omitempty"`
+	Required   bool            `json:"required,omitempty"`
+	KeyValue   bool            `json:"keyValue,omitempty"`
+	Duplicates DuplicatePolicy `json:"duplicates,omitempty"`
+}
+
+// CommandSpec describes a registered subcommand. Names holds the command
+// name followed by its aliases. Inherit records whether the subcommand
+// resolves unknown options through its parent.
+type CommandSpec struct {
+	Names   []string `json:"names"`
+	Inherit bool     `json:"inherit,omitempty"`
+	Spec    *Spec    `json:"spec"`
+}
+
+// Spec returns the static definition of p and its subcommands.
+func (p *Parser) Spec() *Spec {
+	c := p.config
+	s := &Spec{
+		Name:        p.Name,
+		Description: p.Description,
+		Config: SpecConfig{
+			ParseMode:         c.parseMode,
+			LongOnly:          c.longOptsOnly,
+			ShortCaseIgnore:   c.shortCaseIgnore,
+			LongCaseIgnore:    c.longCaseIgnore,
+			CommandCaseIgnore: c.commandCaseIgnore,
+			GNUWords:          c.gnuWords,
+			ShortOptEquals:    c.shortOptEquals,
+			StrictSubcommands: c.strictSubcommands,
+			CommandFirst:      c.commandFirst,
+			NumericOptions:    c.numericOpts,
+			NegativeNumbers:   c.negativeNums,
+			PlusOptions:       c.plusOpts,
+			SlashOptions:      c.slashOpts,
+		},
+	}
+	short := func(name string, flag *Flag) {
+		fs := flagSpec(flag)
+		fs.Name = name
+		if flag.Peer != nil {
+			fs.Peer = "--" + flag.Peer.Name
+		}
+		s.Short = append(s.Short, fs)
+	}
+	for ch, flag := range p.shortOpts {
+		if flag != nil {
+			short(byteString(byte(ch)), flag)
+		}
+	}
+	for _, r := range slices.Sorted(maps.Keys(p.runeOpts)) {
+		short(string(r), p.runeOpts[r])
+	}
+	for name, flag := range p.longOpts {
+		fs := flagSpec(flag)
+		fs.Name = name
+		if flag.Peer != nil {
+			fs.Peer = "-" + flag.Peer.Name
+		}
+		s.Long = append(s.Long, fs)
+	}
+	slices.SortFunc(s.Long, func(a, b FlagSpec) int { return strings.Compare(a.Name, b.Name) })
+
+	_, parsers := p.commandRows()
+	for _, cmd := range parsers {
+		s.Commands = append(s.Commands, CommandSpec{
+			Names:   commandNames(p, cmd),
+			Inherit: cmd.parent == p,
+			Spec:    cmd.Spec(),
+		})
+	}
+	return s
+}
+
+// MarshalSpec returns the JSON encoding of [Parser.Spec], for tools that
+// document, complete, or audit a program's command-line surface. The
+// output is stable: short options are listed by character, long options
+// and subcommands by name, so the same parser tree always encodes to the
+// same bytes and the result can be checked into a repository and diffed.
+func (p *Parser) MarshalSpec() ([]byte, error) {
+	return json.Marshal(p.Spec())
+}
+
+func flagSpec(flag *Flag) FlagSpec {
+	return FlagSpec{
+		HasArg: flag.HasArg, Help: flag.Help, ArgName: flag.ArgName,
+		Default: flag.DefaultValue, Aliases: flag.Aliases,
+		Deprecated: flag.Deprecated, Hidden: flag.Hidden, Disabled: flag.Disabled, Required: flag.Required,
+		KeyValue: flag.KeyValue, Duplicates: flag.DuplicatePolicy,
+	}
+}
+
+// ReadSpec decodes a JSON spec document, such as one written by
+// [Parser.MarshalSpec] or by hand, for CLIs whose options are data-driven
+// or generated:
+//
+//	f, err := os.Open("cli.json")
+//	...
+//	spec, err := optargs.ReadSpec(f)
+//	p, err := optargs.NewParserFromSpec(spec, os.Args[1:])
+//
+// Unknown fields and trailing data are errors, so a misspelled key in a
+// hand-written file is reported rather than ignored. The core has no YAML
+// decoder; a YAML library that honors json tags can fill a [Spec]
+// directly.
+func ReadSpec(r io.Reader) (*Spec, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var s Spec
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("read spec: %w", err)
+	}
+	if dec.More() {
+		return nil, errors.New("read spec: unexpected data after the spec")
+	}
+	return &s, nil
+}
+
+// NewParserFromSpec builds a parser tree from s with args as its input.
+// The flags have no handlers, so every option is yielded by
+// [Parser.Options]; attach handlers with [Parser.SetHandler] if needed.
+func NewParserFromSpec(s *Spec, args []string) (*Parser, error) {
+	c := s.Config
+	config := ParserConfig{
+		parseMode:         c.ParseMode,
+		longOptsOnly:      c.LongOnly,
+		shortCaseIgnore:   c.ShortCaseIgnore,
+		longCaseIgnore:    c.LongCaseIgnore,
+		commandCaseIgnore: c.CommandCaseIgnore,
+		gnuWords:          c.GNUWords,
+		shortOptEquals:    c.ShortOptEquals,
+		strictSubcommands: c.StrictSubcommands,
+		commandFirst:      c.CommandFirst,
+		numericOpts:       c.NumericOptions,
+		negativeNums:      c.NegativeNumbers,
+		plusOpts:          c.PlusOptions,
+		slashOpts:         c.SlashOptions,
+	}
+
+	shortOpts := make(map[rune]*Flag, len(s.Short))
+	for _, fs := range s.Short {
+		r, ok := specShortRune(fs.Name)
+		if !ok {
+			return nil, fmt.Errorf("invalid short option in spec: %q", fs.Name)
+		}
+		shortOpts[r] = specFlag(fs)
+	}
+	longOpts := make(map[string]*Flag, len(s.Long))
+	for _, fs := range s.Long {
+		longOpts[fs.Name] = specFlag(fs)
+	}
+	if err := linkSpecPeers(s, shortOpts, longOpts); err != nil {
+		return nil, err
+	}
+
+	p, err := NewParserRunes(config, shortOpts, longOpts, args)
+	if err != nil {
+		return nil, err
+	}
+	p.Name = s.Name
+	p.Description = s.Description
+
+	for _, cs := range s.Commands {
+		if len(cs.Names) == 0 || cs.Spec == nil {
+			return nil, fmt.Errorf("invalid command in spec for %q", s.Name)
+		}
+		child, err := NewParserFromSpec(cs.Spec, []string{})
+		if err != nil {
+			return nil, err
+		}
+		// AddCmd links the parent unless strict; honor the recorded
+		// inheritance for this command instead.
+		p.config.strictSubcommands = !cs.Inherit
+		p.AddCmd(cs.Names[0], child)
+		p.config.strictSubcommands = c.StrictSubcommands
+		for _, alias := range cs.Names[1:] {
+			if err := p.AddAlias(alias, cs.Names[0]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+func specFlag(fs FlagSpec) *Flag {
+	return &Flag{
+		Name: fs.Name, HasArg: fs.HasArg, Help: fs.Help, ArgName: fs.ArgName,
+		DefaultValue: fs.Default, Aliases: fs.Aliases,
+		Deprecated: fs.Deprecated, Hidden: fs.Hidden, Disabled: fs.Disabled, Required: fs.Required,
+		KeyValue: fs.KeyValue, DuplicatePolicy: fs.Duplicates,
+	}
+}
+
+// specShortRune returns the character a short option of a spec is keyed
+// by: a single byte, or a single multibyte character.
+func specShortRune(name string) (rune, bool) {
+	if len(name) == 1 {
+		return rune(name[0]), true
+	}
+	return runeName(name)
+}
+
+// linkSpecPeers restores the Peer links recorded in s.
+func linkSpecPeers(s *Spec, shortOpts map[rune]*Flag, longOpts map[string]*Flag) error {
+	for _, fs := range s.Short {
+		if fs.Peer == "" {
+			continue
+		}
+		long, ok := longOpts[trimDashes(fs.Peer)]
+		if !ok {
+			return fmt.Errorf("spec peer %s of -%s is not defined", fs.Peer, fs.Name)
+		}
+		r, _ := specShortRune(fs.Name)
+		short := shortOpts[r]
+		short.Peer, long.Peer = long, short
+	}
+	return nil
+}
+
+func trimDashes(s string) string {
+	for len(s) > 0 && s[0] == '-' {
+		s = s[1:]
+	}
+	return s
+}
+
+// MarshalText encodes an ArgType as "none", "required", or "optional".
+func (a ArgType) MarshalText() ([]byte, error) {
+	switch a {
+	case NoArgument:
+		return []byte("none"), nil
+	case RequiredArgument:
+		return []byte("required"), nil
+	case OptionalArgument:
+		return []byte("optional"), nil
+	}
+	return nil, fmt.Errorf("unknown argument type: %d", int(a))
+}
+
+// UnmarshalText decodes the form written by MarshalText.
+func (a *ArgType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "none":
+		*a = NoArgument
+	case "required":
+		*a = RequiredArgument
+	case "optional":
+		*a = OptionalArgument
+	default:
+		return fmt.Errorf("unknown argument type: %q", text)
+	}
+	return nil
+}
+
+// MarshalText encodes a ParseMode as "default", "nonopts", or "posix".
+func (m ParseMode) MarshalText() ([]byte, error) {
+	switch m {
+	case ParseDefault:
+		return []byte("default"), nil
+	case ParseNonOpts:
+		return []byte("nonopts"), nil
+	case ParsePosixlyCorrect:
+		return []byte("posix"), nil
+	}
+	return nil, fmt.Errorf("unknown parse mode: %d", int(m))
+}
+
+// UnmarshalText decodes the form written by MarshalText.
+func (m *ParseMode) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "default":
+		*m = ParseDefault
+	case "nonopts":
+		*m = ParseNonOpts
+	case "posix":
+		*m = ParsePosixlyCorrect
+	default:
+		return fmt.Errorf("unknown parse mode: %q", text)
+	}
+	return nil
+}
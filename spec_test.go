@@ -0,0 +1,204 @@
+package optargs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSpecRoundTrip(t *testing.T) {
+	root := newCompletionTree(t)
+	root.Description = "Example program"
+	file := root.longOpts["file"]
+	f := root.shortOpts['f']
+	file.Peer, f.Peer = f, file
+	file.Help, file.ArgName, file.DefaultValue = "input file", "FILE", "-"
+	file.Required = true
+	file.DuplicatePolicy = DuplicateReject
+
+	data, err := json.Marshal(root.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := NewParserFromSpec(&spec, []string{"-v", "s", "--port", "80", "--file", "x", "status", "--json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := json.Marshal(decoded.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("re-encoded spec differs:\n%s\nwant:\n%s", again, data)
+	}
+
+	var want, got strings.Builder
+	if err := root.WriteUsage(&want); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.WriteUsage(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("decoded usage:\n%s\nwant:\n%s", got.String(), want.String())
+	}
+
+	// The decoded tree parses: root options, alias dispatch, inherited
+	// options in the child, and a nested command.
+	var names []string
+	for opt, err := range decoded.Options() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, opt.Name)
+	}
+	for name, child := range decoded.Dispatches() {
+		names = append(names, name)
+		for opt, err := range child.Options() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, opt.Name+"="+opt.Arg)
+		}
+	}
+	if got, want := strings.Join(names, " "), "v s port=80 file=x status json="; got != want {
+		t.Errorf("parsed %q, want %q", got, want)
+	}
+
+	// The strict "version" command does not inherit root's options.
+	if v, _ := decoded.GetCommand("version"); v.parent != nil {
+		t.Error("version command inherits options after round trip")
+	}
+}
+
+func TestSpecJSONForm(t *testing.T) {
+	p, err := GetOptLong(nil, "+v", []Flag{{Name: "out", HasArg: OptionalArgument}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(p.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"config":{"parseMode":"posix","longCaseIgnore":true,"strictSubcommands":true},` +
+		`"short":[{"name":"v"}],"long":[{"name":"out","hasArg":"optional"}]}`
+	if string(data) != want {
+		t.Errorf("JSON = %s\nwant   %s", data, want)
+	}
+}
+
+func TestMarshalSpec(t *testing.T) {
+	build := func() *Parser {
+		root := newCompletionTree(t)
+		for _, name := range []string{"zeta", "alpha", "mid"} {
+			if err := root.AddLongFlag(name, &Flag{Name: name, HasArg: RequiredArgument}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return root
+	}
+	first, err := build().MarshalSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := json.Marshal(build().Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != string(want) {
+		t.Errorf("MarshalSpec() = %s\nwant %s", first, want)
+	}
+	for range 20 {
+		again, err := build().MarshalSpec()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again) != string(first) {
+			t.Fatalf("MarshalSpec() is not stable:\n%s\n%s", first, again)
+		}
+	}
+	var spec Spec
+	if err := json.Unmarshal(first, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Commands) == 0 || len(spec.Long) < 3 {
+		t.Errorf("decoded spec = %+v, want long options and commands", spec)
+	}
+}
+
+func TestReadSpec(t *testing.T) {
+	doc := `{
+		"name": "tool",
+		"config": {"longCaseIgnore": true},
+		"short": [{"name": "v", "peer": "--verbose"}],
+		"long": [{"name": "verbose", "peer": "-v"}],
+		"commands": [{
+			"names": ["build", "b"],
+			"inherit": true,
+			"spec": {"name": "build", "long": [{"name": "output", "hasArg": "required"}]}
+		}]
+	}`
+	spec, err := ReadSpec(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewParserFromSpec(spec, []string{"-v", "b", "--output", "out", "--verbose"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOptions(t, requireParsedOptions(t, p), []Option{{Name: "v"}})
+	name, build := p.ActiveCommand()
+	if name != "b" || build == nil {
+		t.Fatalf("ActiveCommand() = %q, %v", name, build)
+	}
+	assertOptions(t, requireParsedOptions(t, build), []Option{
+		{Name: "output", HasArg: true, Arg: "out"},
+		{Name: "verbose"},
+	})
+
+	for _, bad := range []string{
+		`{"shrot": []}`,
+		`{"name": "tool"} {"name": "again"}`,
+		`{"long": [{"name": "x", "hasArg": "sometimes"}]}`,
+		``,
+	} {
+		if _, err := ReadSpec(strings.NewReader(bad)); err == nil || !strings.HasPrefix(err.Error(), "read spec: ") {
+			t.Errorf("ReadSpec(%q) = %v, want a read spec error", bad, err)
+		}
+	}
+}
+
+func TestNewParserFromSpecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"long short name", `{"short":[{"name":"vv"}]}`},
+		{"missing peer", `{"short":[{"name":"v","peer":"--verbose"}]}`},
+		{"empty command", `{"commands":[{"names":[],"spec":{}}]}`},
+		{"bad command spec", `{"commands":[{"names":["x"],"spec":{"short":[{"name":""}]}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spec Spec
+			if err := json.Unmarshal([]byte(tt.json), &spec); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewParserFromSpec(&spec, nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	for _, bad := range []string{`{"long":[{"name":"x","hasArg":"maybe"}]}`, `{"config":{"parseMode":"gnu"}}`} {
+		var spec Spec
+		if err := json.Unmarshal([]byte(bad), &spec); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded", bad)
+		}
+	}
+}
@@ -11,7 +11,7 @@ func TestStrictSubcommands_ParentOptionRejected(t *testing.T) {
 	rootShort := map[byte]*Flag{'v': {Name: "v", HasArg: NoArgument}}
 	rootLong := map[string]*Flag{"verbose": {Name: "verbose", HasArg: NoArgument}}
 	root, err := NewParser(ParserConfig{
-		enableErrors:      true,
+		errorMode:         ErrorReport,
 		strictSubcommands: true,
 		commandCaseIgnore: true,
 	}, rootShort, rootLong, []string{"serve", "--verbose"})
@@ -21,7 +21,7 @@ func TestStrictSubcommands_ParentOptionRejected(t *testing.T) {
 
 	childShort := map[byte]*Flag{'p': {Name: "p", HasArg: RequiredArgument}}
 	childLong := map[string]*Flag{"port": {Name: "port", HasArg: RequiredArgument}}
-	child, err := NewParser(ParserConfig{enableErrors: true}, childShort, childLong, []string{})
+	child, err := NewParser(ParserConfig{errorMode: ErrorReport}, childShort, childLong, []string{})
 	if err != nil {
 		t.Fatalf("child parser: %v", err)
 	}
@@ -54,7 +54,7 @@ func TestStrictSubcommands_ParentOptionRejected(t *testing.T) {
 func TestStrictSubcommands_ChildOwnOptionsWork(t *testing.T) {
 	rootShort := map[byte]*Flag{'v': {Name: "v", HasArg: NoArgument}}
 	root, err := NewParser(ParserConfig{
-		enableErrors:      true,
+		errorMode:         ErrorReport,
 		strictSubcommands: true,
 		commandCaseIgnore: true,
 	}, rootShort, nil, []string{"serve", "--port", "8080"})
@@ -63,7 +63,7 @@ func TestStrictSubcommands_ChildOwnOptionsWork(t *testing.T) {
 	}
 
 	childLong := map[string]*Flag{"port": {Name: "port", HasArg: RequiredArgument}}
-	child, err := NewParser(ParserConfig{enableErrors: true}, nil, childLong, []string{})
+	child, err := NewParser(ParserConfig{errorMode: ErrorReport}, nil, childLong, []string{})
 	if err != nil {
 		t.Fatalf("child parser: %v", err)
 	}
@@ -94,14 +94,14 @@ func TestStrictSubcommands_DefaultAllowsInheritance(t *testing.T) {
 	// Without StrictSubcommands, parent options should be inherited.
 	rootShort := map[byte]*Flag{'v': {Name: "v", HasArg: NoArgument}}
 	root, err := NewParser(ParserConfig{
-		enableErrors:      true,
+		errorMode:         ErrorReport,
 		commandCaseIgnore: true,
 	}, rootShort, nil, []string{"serve", "-v"})
 	if err != nil {
 		t.Fatalf("root parser: %v", err)
 	}
 
-	child, err := NewParser(ParserConfig{enableErrors: true}, nil, nil, []string{})
+	child, err := NewParser(ParserConfig{errorMode: ErrorReport}, nil, nil, []string{})
 	if err != nil {
 		t.Fatalf("child parser: %v", err)
 	}
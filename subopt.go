@@ -0,0 +1,42 @@
+package optargs
+
+import (
+	"iter"
+	"slices"
+	"strings"
+)
+
+// SubOpt is one suboption parsed by [SubOpts].
+type SubOpt struct {
+	Key      string
+	Value    string
+	HasValue bool // a '=' followed the key, even with an empty value
+	Token    int  // index of Key in the token table; -1 when unknown
+}
+
+// SubOpts parses arg as a comma-separated list of key[=value]
+// suboptions, the getsubopt(3) syntax of "mount -o rw,uid=1000", and
+// yields them in order. Each key is looked up in tokens: a key that is
+// not listed is yielded with Token -1 and an *UnknownSubOptionError, and
+// iteration continues. A nil tokens accepts every key. Empty elements,
+// as in "rw,,ro", are skipped. A value ends at the next comma; it cannot
+// contain one.
+func SubOpts(arg string, tokens ...string) iter.Seq2[SubOpt, error] {
+	return func(yield func(SubOpt, error) bool) {
+		for _, item := range strings.Split(arg, ",") {
+			if item == "" {
+				continue
+			}
+			var sub SubOpt
+			sub.Key, sub.Value, sub.HasValue = strings.Cut(item, "=")
+			sub.Token = slices.Index(tokens, sub.Key)
+			var err error
+			if sub.Token < 0 && tokens != nil {
+				err = &UnknownSubOptionError{Key: sub.Key, Tokens: tokens}
+			}
+			if !yield(sub, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package optargs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSubOpts(t *testing.T) {
+	tokens := []string{"ro", "rw", "uid", "gid"}
+	tests := []struct {
+		name   string
+		arg    string
+		tokens []string
+		want   []SubOpt
+		errs   []string // unknown keys, in order
+	}{
+		{
+			name:   "mount options",
+			arg:    "rw,uid=1000,gid=",
+			tokens: tokens,
+			want: []SubOpt{
+				{Key: "rw", Token: 1},
+				{Key: "uid", Value: "1000", HasValue: true, Token: 2},
+				{Key: "gid", HasValue: true, Token: 3},
+			},
+		},
+		{
+			name:   "empty elements",
+			arg:    ",ro,,",
+			tokens: tokens,
+			want:   []SubOpt{{Key: "ro", Token: 0}},
+		},
+		{
+			name:   "unknown key",
+			arg:    "ro,noexec,uid=0",
+			tokens: tokens,
+			want: []SubOpt{
+				{Key: "ro", Token: 0},
+				{Key: "noexec", Token: -1},
+				{Key: "uid", Value: "0", HasValue: true, Token: 2},
+			},
+			errs: []string{"noexec"},
+		},
+		{
+			name: "any key",
+			arg:  "a=1=2,b",
+			want: []SubOpt{{Key: "a", Value: "1=2", HasValue: true, Token: -1}, {Key: "b", Token: -1}},
+		},
+		{name: "empty", arg: "", tokens: tokens},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []SubOpt
+			var errs []string
+			for sub, err := range SubOpts(tt.arg, tt.tokens...) {
+				got = append(got, sub)
+				var uerr *UnknownSubOptionError
+				if errors.As(err, &uerr) {
+					errs = append(errs, uerr.Key)
+				} else if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("suboption %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+			if strings.Join(errs, ",") != strings.Join(tt.errs, ",") {
+				t.Errorf("unknown keys %q, want %q", errs, tt.errs)
+			}
+		})
+	}
+
+	err := &UnknownSubOptionError{Key: "noexec", Tokens: []string{"ro", "rw"}}
+	if got := err.Error(); got != "unknown suboption: noexec (expected one of: ro, rw)" {
+		t.Errorf("Error() = %q", got)
+	}
+}
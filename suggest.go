@@ -0,0 +1,108 @@
+package optargs
+
+import (
+	"slices"
+	"strings"
+)
+
+// SuggestCommands returns the registered command names, aliases included,
+// that name is likely a misspelling of: those within one edit of it per
+// three bytes typed (at least one), counting a swapped pair of adjacent
+// letters as one edit, and those it is a prefix of. Case is ignored, so a
+// name differing only in case is the closest of all. The closest come
+// first, ties in name order; the result is nil when nothing is close.
+//
+// The [UnknownCommandError] of a command-first parser carries these
+// suggestions. A program whose operands may be mistyped commands can call
+// SuggestCommands on p.Args[0] itself.
+func (p *Parser) SuggestCommands(name string) []string {
+	var names []string
+	for cmd, parser := range p.Commands {
+		if parser != nil {
+			names = append(names, cmd)
+		}
+	}
+	return suggest(name, names)
+}
+
+// SuggestOptions is like [Parser.SuggestCommands] for long option names:
+// it returns the visible long options and aliases p accepts, inherited
+// ones included, that name is likely a misspelling of. Names carry no
+// dashes.
+//
+// The [UnknownOptionError] for an unknown long option carries these
+// suggestions.
+func (p *Parser) SuggestOptions(name string) []string {
+	var names []string
+	for _, opt := range p.VisibleOptions() {
+		if long, ok := strings.CutPrefix(opt.Name, "--"); ok {
+			names = append(names, long)
+		}
+	}
+	return suggest(name, names)
+}
+
+// suggest implements SuggestCommands and SuggestOptions over the
+// candidate names.
+func suggest(name string, candidates []string) []string {
+	typed := strings.ToLower(name)
+	if typed == "" {
+		return nil
+	}
+	limit := max(1, len(typed)/3)
+	type candidate struct {
+		name string
+		dist int
+	}
+	var found []candidate
+	for _, n := range candidates {
+		lower := strings.ToLower(n)
+		dist := editDistance(typed, lower)
+		if strings.HasPrefix(lower, typed) {
+			dist = min(dist, 1)
+		}
+		if dist <= limit && n != name {
+			found = append(found, candidate{n, dist})
+		}
+	}
+	slices.SortFunc(found, func(a, b candidate) int {
+		if a.dist != b.dist {
+			return a.dist - b.dist
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	var names []string
+	for _, c := range found {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// editDistance returns the optimal string alignment distance between a
+// and b: the number of single-byte insertions, deletions, substitutions,
+// and transpositions of adjacent bytes that turn one into the other.
+func editDistance(a, b string) int {
+	// Three rows of the dynamic programming table: two back, previous,
+	// and current.
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,135 @@
+package optargs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"serve", "serve", 0},
+		{"srve", "serve", 1},
+		{"sevre", "serve", 1},
+		{"sever", "serve", 2},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestCommands(t *testing.T) {
+	root := newMinimalParser(t)
+	for _, name := range []string{"serve", "server", "status", "stash", "rm"} {
+		root.AddCmd(name, newMinimalParser(t))
+	}
+	if err := root.AddAlias("remove", "rm"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"srve", []string{"serve"}},
+		{"SERV", []string{"serve", "server"}},
+		{"stats", []string{"status"}},
+		{"remvoe", []string{"remove"}},
+		{"st", []string{"stash", "status"}},
+		{"serve", []string{"server"}},
+		{"mv", nil},
+		{"deploy", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := root.SuggestCommands(tt.name); !slices.Equal(got, tt.want) {
+			t.Errorf("SuggestCommands(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnknownCommandSuggestions(t *testing.T) {
+	root := newMinimalParser(t)
+	root.AddCmd("serve", newMinimalParser(t))
+	root.AddCmd("client", newMinimalParser(t))
+	root.SetCommandFirst(true)
+	root.Reset([]string{"clinet"})
+	_, err := root.Collect()
+	var unknown *UnknownCommandError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Collect() error = %v, want *UnknownCommandError", err)
+	}
+	if !slices.Equal(unknown.Suggestions, []string{"client"}) {
+		t.Errorf("Suggestions = %q, want [client]", unknown.Suggestions)
+	}
+	if got, want := err.Error(), "unknown command: clinet (did you mean client?)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestSuggestOptions(t *testing.T) {
+	root, err := GetOptLong(nil, "v", []Flag{
+		{Name: "verbose"},
+		{Name: "config", HasArg: RequiredArgument},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := GetOptLong(nil, "", []Flag{
+		{Name: "colour", Aliases: []string{"color"}},
+		{Name: "debug-internal", Hidden: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("show", child)
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"colr", []string{"color"}},
+		{"COLOUR", []string{"colour", "color"}},
+		{"conf", []string{"config"}},
+		{"verbsoe", []string{"verbose"}},
+		{"c", []string{"color", "colour", "config"}},
+		{"debug-internl", nil},
+		{"quiet", nil},
+	}
+	for _, tt := range tests {
+		if got := child.SuggestOptions(tt.name); !slices.Equal(got, tt.want) {
+			t.Errorf("SuggestOptions(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := root.SuggestOptions("colr"); got != nil {
+		t.Errorf("root SuggestOptions(colr) = %q, want none from the child", got)
+	}
+}
+
+func TestUnknownOptionSuggestions(t *testing.T) {
+	p, err := GetOptLong([]string{"--verbsoe"}, ":", []Flag{{Name: "verbose"}, {Name: "version"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.Collect()
+	var unknown *UnknownOptionError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Collect() error = %v, want *UnknownOptionError", err)
+	}
+	if !slices.Equal(unknown.Suggestions, []string{"verbose"}) {
+		t.Errorf("Suggestions = %q, want [verbose]", unknown.Suggestions)
+	}
+	if got, want := err.Error(), "unknown option: verbsoe"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
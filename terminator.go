@@ -0,0 +1,42 @@
+package optargs
+
+// SplitAtTerminator splits args at the first "--" into the arguments
+// before it and those after it, dropping the "--" itself. rest is nil if
+// args contains no "--", and non-nil (possibly empty) if it does, so a
+// wrapper can distinguish "tool --" from "tool".
+//
+// The split is lexical and uses the same test as the parser: only an
+// argument exactly equal to "--" is a terminator. A "--" that the parser
+// would consume as the argument of a preceding option, as in "-o --",
+// is not distinguished; parse with [Parser.Options] and use
+// [Parser.SplitArgs] when that matters.
+func SplitAtTerminator(args []string) (own, rest []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return args[:i:i], args[i+1:]
+		}
+	}
+	return args, nil
+}
+
+// SplitArgs reports how a completed [Parser.Options] iteration divided
+// the non-option arguments: operands holds those collected before a "--"
+// terminator, and rest those that followed it. rest is nil if the parser
+// never consumed a terminator, including when POSIX-mode parsing stopped
+// at the first operand, and when "--" was taken as an option argument.
+//
+// This is the split a wrapper of the form "tool [options] -- command
+// [args]" should use to forward arguments to a child process, since it
+// reflects exactly what the parser did:
+//
+//	for opt, err := range p.Options() { ... }
+//	_, rest := p.SplitArgs()
+//	cmd := exec.Command(rest[0], rest[1:]...)
+//
+// Before iteration completes SplitArgs returns Args and nil.
+func (p *Parser) SplitArgs() (operands, rest []string) {
+	if !p.iterDone || !p.terminated {
+		return p.Args, nil
+	}
+	return p.Args[:p.restAt:p.restAt], p.Args[p.restAt:]
+}
@@ -0,0 +1,206 @@
+package optargs
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSplitAtTerminator(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		own, rest []string
+		hasRest   bool
+	}{
+		{"none", []string{"-a", "x"}, []string{"-a", "x"}, nil, false},
+		{"empty", nil, nil, nil, false},
+		{"trailing", []string{"-a", "--"}, []string{"-a"}, []string{}, true},
+		{"leading", []string{"--", "-a"}, []string{}, []string{"-a"}, true},
+		{"first wins", []string{"x", "--", "y", "--", "z"}, []string{"x"}, []string{"y", "--", "z"}, true},
+		{"not a terminator", []string{"--=x", "---", "-", "—"}, []string{"--=x", "---", "-", "—"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			own, rest := SplitAtTerminator(tt.args)
+			if !slices.Equal(own, tt.own) || !slices.Equal(rest, tt.rest) || (rest != nil) != tt.hasRest {
+				t.Errorf("SplitAtTerminator(%q) = %q, %q (nil rest %v)", tt.args, own, rest, rest == nil)
+			}
+			if tt.hasRest && len(own) > 0 {
+				// Appending to own must not clobber the terminator or rest.
+				_ = append(own, "clobber")
+				if _, again := SplitAtTerminator(tt.args); !slices.Equal(again, tt.rest) {
+					t.Errorf("append to own modified rest: %q", again)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	t.Setenv("POSIXLY_CORRECT", "")
+	tests := []struct {
+		name           string
+		optstring      string
+		args           []string
+		operands, rest []string
+		hasRest        bool
+	}{
+		{"no terminator", "ab:", []string{"-a", "x", "y"}, []string{"x", "y"}, nil, false},
+		{"permuted operands", "ab:", []string{"x", "-a", "--", "cmd", "-a"}, []string{"x"}, []string{"cmd", "-a"}, true},
+		{"terminator only", "ab:", []string{"-a", "--"}, []string{}, []string{}, true},
+		{"option argument", "ab:", []string{"-b", "--", "x"}, []string{"x"}, nil, false},
+		{"attached option argument", "ab:", []string{"-b--", "--", "x"}, []string{}, []string{"x"}, true},
+		{"posix stops at operand", "+ab:", []string{"x", "--", "y"}, []string{"x", "--", "y"}, nil, false},
+		{"posix terminator first", "+ab:", []string{"-a", "--", "x", "--"}, []string{}, []string{"x", "--"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetOpt(slices.Clone(tt.args), tt.optstring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ops, rest := p.SplitArgs(); !slices.Equal(ops, tt.args) || rest != nil {
+				t.Errorf("before iteration SplitArgs() = %q, %q", ops, rest)
+			}
+			for _, err := range p.Options() {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			operands, rest := p.SplitArgs()
+			if !slices.Equal(operands, tt.operands) || !slices.Equal(rest, tt.rest) || (rest != nil) != tt.hasRest {
+				t.Errorf("SplitArgs() = %q, %q (nil rest %v), want %q, %q", operands, rest, rest == nil, tt.operands, tt.rest)
+			}
+		})
+	}
+}
+
+// TestRepeatedTerminator documents that only the first "--" ends option
+// parsing: every later "--" is an ordinary operand, and a command name
+// after the terminator is an operand rather than a dispatch.
+func TestRepeatedTerminator(t *testing.T) {
+	t.Setenv("POSIXLY_CORRECT", "")
+	tests := []struct {
+		name      string
+		optstring string
+		args      []string
+		options   string // short options seen, in order
+		operands  []string
+		args2     []string // Args after iteration
+		rest      []string // SplitArgs rest
+	}{
+		{
+			name: "default", optstring: "a", args: []string{"x", "--", "-a", "--", "y"},
+			args2: []string{"x", "-a", "--", "y"}, rest: []string{"-a", "--", "y"},
+		},
+		{
+			name: "consecutive", optstring: "a", args: []string{"-a", "--", "--", "--"},
+			options: "a", args2: []string{"--", "--"}, rest: []string{"--", "--"},
+		},
+		{
+			name: "posix", optstring: "+a", args: []string{"-a", "--", "--", "x"},
+			options: "a", args2: []string{"--", "x"}, rest: []string{"--", "x"},
+		},
+		{
+			name: "posix operand first", optstring: "+a", args: []string{"x", "--", "-a"},
+			args2: []string{"x", "--", "-a"},
+		},
+		{
+			name: "nonopts", optstring: "-a", args: []string{"x", "--", "y", "--"},
+			operands: []string{"x"}, args2: []string{"y", "--"}, rest: []string{"y", "--"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetOpt(slices.Clone(tt.args), tt.optstring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var options string
+			var operands []string
+			for opt, err := range p.Options() {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if opt.Name == OperandName {
+					operands = append(operands, opt.Arg)
+				} else {
+					options += opt.Name
+				}
+			}
+			if options != tt.options || !slices.Equal(operands, tt.operands) || !slices.Equal(p.Args, tt.args2) {
+				t.Errorf("options %q, operands %q, Args %q; want %q, %q, %q",
+					options, operands, p.Args, tt.options, tt.operands, tt.args2)
+			}
+			if _, rest := p.SplitArgs(); !slices.Equal(rest, tt.rest) {
+				t.Errorf("SplitArgs() rest = %q, want %q", rest, tt.rest)
+			}
+		})
+	}
+}
+
+// TestTerminatorBeforeCommand documents that a command name after "--" is
+// not dispatched, in any parse mode, and that a dispatched command applies
+// the same rule to its own arguments.
+func TestTerminatorBeforeCommand(t *testing.T) {
+	t.Setenv("POSIXLY_CORRECT", "")
+	for _, tt := range []struct {
+		optstring    string
+		commandFirst bool
+	}{{"a", false}, {"+a", false}, {"-a", false}, {"a", true}} {
+		t.Run(fmt.Sprintf("%s/%v", tt.optstring, tt.commandFirst), func(t *testing.T) {
+			root, err := GetOpt([]string{"--", "run", "-a"}, tt.optstring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.SetCommandFirst(tt.commandFirst)
+			run, err := GetOpt(nil, "a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.AddCmd("run", run)
+			for _, err := range root.Options() {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if name, _ := root.ActiveCommand(); name != "" {
+				t.Errorf("ActiveCommand() = %q after terminator", name)
+			}
+			if want := []string{"run", "-a"}; !slices.Equal(root.Args, want) {
+				t.Errorf("Args = %q, want %q", root.Args, want)
+			}
+		})
+	}
+
+	root, err := GetOpt([]string{"-a", "run", "-a", "--", "run", "--", "-a"}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := GetOpt(nil, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("run", run)
+	for _, err := range root.Options() {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if name, _ := root.ActiveCommand(); name != "run" {
+		t.Fatalf("ActiveCommand() = %q, want run", name)
+	}
+	var seen int
+	for opt, err := range run.Options() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opt.Name == "a" {
+			seen++
+		}
+	}
+	if want := []string{"run", "--", "-a"}; seen != 1 || !slices.Equal(run.Args, want) {
+		t.Errorf("run saw -a %d times, Args %q; want 1, %q", seen, run.Args, want)
+	}
+}
@@ -0,0 +1,110 @@
+package optargs
+
+// TraceKind identifies the step a [TraceEvent] records.
+type TraceKind int
+
+const (
+	// TraceHandle: a Flag.Handle is about to be called for Option, or
+	// the operand handler for an Option named OperandName.
+	TraceHandle TraceKind = iota
+	// TraceYield: Option is about to be yielded without an error.
+	TraceYield
+	// TraceError: Err is about to be yielded, with Option.
+	TraceError
+	// TraceDispatch: the subcommand named Option.Name is being
+	// dispatched; Option.Index is the position of its name.
+	TraceDispatch
+)
+
+// TraceEvent is one step of an Options iteration, as reported to the
+// function set with [Parser.SetTrace].
+type TraceEvent struct {
+	Kind   TraceKind
+	Parser *Parser // parser being iterated
+	Owner  *Parser // TraceHandle: parser the handled flag is registered on
+	Option Option
+	Err    error
+}
+
+// SetTrace sets a function called with each step of an Options iteration
+// over p, immediately before the step takes effect: every handler call,
+// every yield, and every subcommand dispatch. Iterations of subcommands
+// dispatched from p are traced as well, unless they have a trace function
+// of their own. Pass nil to stop tracing.
+//
+// The events of one iteration arrive in the order of the guarantee
+// documented on [Parser.Options], so a trace can be used to verify it or
+// to debug handler side effects.
+func (p *Parser) SetTrace(fn func(TraceEvent)) {
+	p.trace = fn
+}
+
+// tracer returns the trace function for iterations of p: its own, or that
+// of the nearest parser that dispatched it.
+func (p *Parser) tracer() func(TraceEvent) {
+	for cur := p; cur != nil; cur = cur.dispatchedBy {
+		if cur.trace != nil {
+			return cur.trace
+		}
+	}
+	return nil
+}
+
+// tracedYield wraps yield so that each call is reported to trace first.
+func (p *Parser) tracedYield(trace func(TraceEvent), yield func(Option, error) bool) func(Option, error) bool {
+	return func(option Option, err error) bool {
+		ev := TraceEvent{Kind: TraceYield, Parser: p, Option: option, Err: err}
+		if err != nil {
+			ev.Kind = TraceError
+		}
+		trace(ev)
+		return yield(option, err)
+	}
+}
+
+// handle calls the handler of flag for option through the middleware,
+// tracing the call.
+func (p *Parser) handle(flag *Flag, option Option) error {
+	if trace := p.tracer(); trace != nil {
+		trace(TraceEvent{Kind: TraceHandle, Parser: p, Owner: p.flagOwner(flag), Option: option})
+	}
+	name := option.Name
+	if p.config.plusOpts && !option.Enabled {
+		name = "+" + name
+	}
+	if flag.HandleCtx != nil {
+		return p.wrapHandler(func(string, string) error {
+			return flag.HandleCtx(p, option)
+		})(name, option.Arg)
+	}
+	return p.wrapHandler(flag.Handle)(name, option.Arg)
+}
+
+// handled reports whether flag has a handler, so its options are handled
+// rather than yielded.
+func (f *Flag) handled() bool {
+	return f != nil && (f.Handle != nil || f.HandleCtx != nil)
+}
+
+// flagOwner returns the parser on p's parent chain that flag is
+// registered on.
+func (p *Parser) flagOwner(flag *Flag) *Parser {
+	for cur := p; cur != nil; cur = cur.parent {
+		for _, f := range cur.shortOpts {
+			if f == flag {
+				return cur
+			}
+		}
+		for _, f := range cur.runeOpts {
+			if f == flag {
+				return cur
+			}
+		}
+		for _, f := range cur.longOpts {
+			if f == flag {
+				return cur
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package optargs
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+// traceTree builds a root parser with handled and unhandled short and long
+// options and a "sub" subcommand that inherits them. Handler calls and
+// trace events are appended to log.
+func traceTree(t *testing.T, args []string, log *[]string) (root, sub *Parser) {
+	t.Helper()
+	handler := func(name, arg string) error {
+		*log = append(*log, "call "+name+"="+arg)
+		return nil
+	}
+	root, err := NewParser(ParserConfig{},
+		map[byte]*Flag{
+			'a': {Name: "a", Handle: handler},
+			'b': {Name: "b"},
+			'c': {Name: "c", HasArg: RequiredArgument, Handle: handler},
+		},
+		map[string]*Flag{
+			"alpha": {Name: "alpha", Handle: handler},
+			"beta":  {Name: "beta", HasArg: OptionalArgument},
+		}, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err = NewParser(ParserConfig{},
+		map[byte]*Flag{'x': {Name: "x", Handle: handler}},
+		map[string]*Flag{"gamma": {Name: "gamma"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("sub", sub)
+	root.SetTrace(func(ev TraceEvent) {
+		switch ev.Kind {
+		case TraceHandle:
+			*log = append(*log, fmt.Sprintf("handle %s=%s", ev.Option.Name, ev.Option.Arg))
+		case TraceDispatch:
+			*log = append(*log, "dispatch "+ev.Option.Name)
+		}
+	})
+	return root, sub
+}
+
+func TestTraceOrder(t *testing.T) {
+	var log []string
+	root, sub := traceTree(t, []string{"-abcV", "op", "--alpha", "--beta=1", "-ba", "sub", "-xa", "--gamma", "--alpha"}, &log)
+	var events []TraceEvent
+	root.SetTrace(func(ev TraceEvent) { events = append(events, ev) })
+	requireParsedOptions(t, root)
+	for _, child := range root.Dispatches() {
+		requireParsedOptions(t, child)
+	}
+
+	type step struct {
+		kind   TraceKind
+		parser *Parser
+		owner  *Parser
+		name   string
+		index  int
+		offset int
+	}
+	want := []step{
+		{TraceHandle, root, root, "a", 0, 1},
+		{TraceYield, root, nil, "b", 0, 2},
+		{TraceHandle, root, root, "c", 0, 3},
+		{TraceHandle, root, root, "alpha", 2, 0},
+		{TraceYield, root, nil, "beta", 3, 0},
+		{TraceYield, root, nil, "b", 4, 1},
+		{TraceHandle, root, root, "a", 4, 2},
+		{TraceDispatch, root, nil, "sub", 5, 0},
+		{TraceHandle, sub, sub, "x", 6, 1},
+		{TraceHandle, sub, root, "a", 6, 2},
+		{TraceYield, sub, nil, "gamma", 7, 0},
+		{TraceHandle, sub, root, "alpha", 8, 0},
+	}
+	got := make([]step, len(events))
+	for i, ev := range events {
+		got[i] = step{ev.Kind, ev.Parser, ev.Owner, ev.Option.Name, ev.Option.Index, ev.Option.Offset}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("trace:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+// Feature: handler ordering, Property: trace order is argument order
+//
+// Invariant: for any argument list mixing compacted short options,
+// inherited parent options, errors, and a subcommand, (1) each handler
+// call is immediately preceded by its TraceHandle event, (2) the events
+// of each iteration advance strictly through the argument list by
+// (Index, Offset), (3) a dispatch is the last event of the parent's
+// iteration, and (4) handlers of inherited options report the parent as
+// their owner.
+//
+// Why randomized inputs: the guarantee covers every interleaving of the
+// token kinds below, which a handful of fixed examples cannot enumerate.
+func TestPropertyTraceOrder(t *testing.T) {
+	rootTokens := []string{"-a", "-ab", "-abc", "-bcV", "-c", "--alpha", "--beta", "--beta=v", "--zeta", "-q", "-aqb", "op", "V"}
+	subTokens := []string{"-x", "-xa", "-axb", "--gamma", "--alpha", "-cV", "--zeta", "op"}
+
+	property := func(seed int64) bool {
+		rng := rand.New(rand.NewSource(seed)) //nolint:gosec // deterministic seed for reproducible property tests
+		var args []string
+		for range rng.Intn(6) {
+			args = append(args, rootTokens[rng.Intn(len(rootTokens))])
+		}
+		if rng.Intn(2) == 0 {
+			args = append(args, "sub")
+			for range rng.Intn(6) {
+				args = append(args, subTokens[rng.Intn(len(subTokens))])
+			}
+		}
+
+		var log []string
+		root, sub := traceTree(t, args, &log)
+		var events []TraceEvent
+		trace := root.trace
+		root.SetTrace(func(ev TraceEvent) {
+			trace(ev)
+			events = append(events, ev)
+		})
+		for range root.Options() { //nolint:revive // drain; errors are part of the trace
+		}
+		for _, child := range root.Dispatches() {
+			for range child.Options() { //nolint:revive // drain; errors are part of the trace
+			}
+		}
+
+		// (1) Every handler call directly follows its trace event.
+		for i, entry := range log {
+			if len(entry) > 5 && entry[:5] == "call " && (i == 0 || log[i-1] != "handle "+entry[5:]) {
+				t.Logf("args %q: handler call %q not preceded by its trace event: %q", args, entry, log)
+				return false
+			}
+		}
+
+		var last *TraceEvent
+		for i := range events {
+			ev := &events[i]
+			if ev.Kind == TraceHandle {
+				want := ev.Parser
+				if ev.Option.Name == "a" || ev.Option.Name == "c" || ev.Option.Name == "alpha" {
+					want = root
+				}
+				// (4) Inherited handlers are owned by the parent.
+				if ev.Owner != want {
+					t.Logf("args %q: %s handled with owner %p, want %p", args, ev.Option.Name, ev.Owner, want)
+					return false
+				}
+			}
+			if last != nil && last.Parser == ev.Parser {
+				// (3) Nothing follows a dispatch in the same iteration.
+				if last.Kind == TraceDispatch {
+					t.Logf("args %q: event after dispatch: %+v", args, ev)
+					return false
+				}
+				// (2) Strictly increasing argument positions.
+				if ev.Option.Index < last.Option.Index ||
+					ev.Option.Index == last.Option.Index && ev.Option.Offset <= last.Option.Offset {
+					t.Logf("args %q: %+v does not follow %+v", args, ev.Option, last.Option)
+					return false
+				}
+			}
+			if last != nil && last.Parser != ev.Parser && (last.Kind != TraceDispatch || ev.Parser != sub ||
+				ev.Option.Index <= last.Option.Index) {
+				t.Logf("args %q: %+v in %p does not follow dispatch %+v", args, ev.Option, ev.Parser, last)
+				return false
+			}
+			last = ev
+		}
+		return true
+	}
+
+	config := &quick.Config{MaxCount: 200}
+	if err := quick.Check(property, config); err != nil {
+		t.Errorf("Property (trace order is argument order) failed: %v", err)
+	}
+}
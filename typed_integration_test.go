@@ -36,7 +36,7 @@ func TestTypedValueInFlagHandle(t *testing.T) {
 	}
 
 	args := []string{"-n", "test", "-c", "42", "-v", "--timeout=5s"}
-	p, err := NewParser(ParserConfig{enableErrors: true}, short, long, args)
+	p, err := NewParser(ParserConfig{errorMode: ErrorReport}, short, long, args)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -74,7 +74,7 @@ func TestTypedValueHandleError(t *testing.T) {
 	}
 
 	args := []string{"-c", "not-a-number"}
-	p, err := NewParser(ParserConfig{enableErrors: true}, short, nil, args)
+	p, err := NewParser(ParserConfig{errorMode: ErrorReport}, short, nil, args)
 	if err != nil {
 		t.Fatal(err)
 	}
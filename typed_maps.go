@@ -3,6 +3,7 @@ package optargs
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -56,6 +57,7 @@ func (v *mapValue) String() string {
 	for iter.Next() {
 		parts = append(parts, fmt.Sprintf("%v=%v", iter.Key().Interface(), iter.Value().Interface()))
 	}
+	slices.Sort(parts) // stable output for help text and snapshots
 	return "map[" + strings.Join(parts, ",") + "]"
 }
 
@@ -0,0 +1,242 @@
+package optargs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// usageHelpColumn caps the width of the option column; longer labels put
+// their help text on the following line.
+const usageHelpColumn = 30
+
+// usageRow is one line of the options or commands table.
+type usageRow struct {
+	key   string // sort key
+	label string
+	help  string
+}
+
+// WriteUsage writes an options summary for p and, after it, for each
+// registered subcommand, built from the Help, ArgName, DefaultValue,
+// Required, and Deprecated metadata on the registered flags; hidden flags
+// are left out. A short and a long option linked through Flag.Peer share
+// a row. Aliases share their command's row.
+//
+// The program name is p.Name, or the base name of os.Args[0] when p.Name
+// is empty; subcommand sections are headed by the full command path.
+func (p *Parser) WriteUsage(w io.Writer) error {
+	var b strings.Builder
+	p.writeUsage(&b, p.commandPath())
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// commandPath returns the space-separated names from the root parser down
+// to p.
+func (p *Parser) commandPath() string {
+	var names []string
+	for cur := p; cur != nil; cur = cur.parent {
+		name := cur.Name
+		if cur.parent == nil && name == "" {
+			name = filepath.Base(os.Args[0])
+		}
+		names = append(names, name)
+	}
+	slices.Reverse(names)
+	return strings.Join(names, " ")
+}
+
+func (p *Parser) writeUsage(b *strings.Builder, path string) {
+	options := p.optionRows()
+	commands, parsers := p.commandRows()
+
+	b.WriteString("Usage: " + path)
+	if len(options) > 0 {
+		b.WriteString(" [OPTIONS]")
+	}
+	if len(commands) > 0 {
+		b.WriteString(" [COMMAND]")
+	}
+	b.WriteString("\n")
+
+	if p.Description != "" {
+		b.WriteString("\n" + p.Description + "\n")
+	}
+	writeUsageRows(b, "Options", options)
+	writeUsageRows(b, "Commands", commands)
+
+	for _, cmd := range parsers {
+		b.WriteString("\n")
+		cmd.writeUsage(b, path+" "+commandNames(p, cmd)[0])
+	}
+}
+
+// optionRows builds one row per option, pairing short and long forms that
+// are linked through Peer.
+func (p *Parser) optionRows() []usageRow {
+	var rows []usageRow
+	paired := make(map[*Flag]bool)
+	shortRow := func(name string, flag *Flag) {
+		if flag == nil || flag.Hidden || flag.Disabled {
+			return
+		}
+		short := "-" + name
+		row := usageRow{key: strings.ToLower(name), help: flagHelp(flag)}
+		if long := flag.Peer; long != nil && !long.Hidden && !long.Disabled && p.longOpts[long.Name] == long {
+			paired[long] = true
+			row.label = short + ", " + longSpellings(long.Name, long) + longArgSuffix(long)
+			if row.help == "" {
+				row.help = flagHelp(long)
+			}
+		} else {
+			row.label = short + shortArgSuffix(flag)
+		}
+		rows = append(rows, row)
+	}
+	for c, flag := range p.shortOpts {
+		shortRow(byteString(byte(c)), flag)
+	}
+	for r, flag := range p.runeOpts {
+		shortRow(string(r), flag)
+	}
+	for name, flag := range p.longOpts {
+		if paired[flag] || flag.Hidden || flag.Disabled {
+			continue
+		}
+		rows = append(rows, usageRow{
+			key:   strings.ToLower(name),
+			label: "    " + longSpellings(name, flag) + longArgSuffix(flag),
+			help:  flagHelp(flag),
+		})
+	}
+	slices.SortFunc(rows, func(a, b usageRow) int { return strings.Compare(a.key, b.key) })
+	return rows
+}
+
+// commandRows builds one row per registered subcommand, listing aliases
+// alongside the command and noting the default command, and returns the
+// distinct subcommand parsers in row order.
+func (p *Parser) commandRows() ([]usageRow, []*Parser) {
+	var rows []usageRow
+	var parsers []*Parser
+	var defaultCmd *Parser
+	if p.defaultCmd != "" {
+		defaultCmd, _ = p.GetCommand(p.defaultCmd)
+	}
+	for _, cmd := range p.Commands {
+		if cmd == nil || slices.Contains(parsers, cmd) {
+			continue
+		}
+		names := commandNames(p, cmd)
+		parsers = append(parsers, cmd)
+		help := cmd.Description
+		if cmd == defaultCmd {
+			help = strings.TrimSpace(help + " (default)")
+		}
+		rows = append(rows, usageRow{key: names[0], label: strings.Join(names, ", "), help: help})
+	}
+	slices.SortFunc(rows, func(a, b usageRow) int { return strings.Compare(a.key, b.key) })
+	slices.SortFunc(parsers, func(a, b *Parser) int {
+		return strings.Compare(commandNames(p, a)[0], commandNames(p, b)[0])
+	})
+	return rows, parsers
+}
+
+// commandNames returns the names cmd is registered under in p: its own
+// name first, then aliases in sorted order.
+func commandNames(p *Parser, cmd *Parser) []string {
+	names := p.Commands.GetAliases(cmd)
+	slices.Sort(names)
+	if i := slices.Index(names, cmd.Name); i > 0 {
+		names = append([]string{cmd.Name}, slices.Delete(names, i, i+1)...)
+	}
+	return names
+}
+
+// flagHelp returns the help column for flag: its Help text followed by
+// its required, default, and deprecation notes.
+func flagHelp(flag *Flag) string {
+	var notes []string
+	if flag.Help != "" {
+		notes = append(notes, flag.Help)
+	}
+	if isRequired(flag) {
+		notes = append(notes, "(required)")
+	}
+	if flag.DefaultValue != "" {
+		notes = append(notes, "(default: "+flag.DefaultValue+")")
+	}
+	if flag.Deprecated != "" {
+		notes = append(notes, "(deprecated: "+flag.Deprecated+")")
+	}
+	return strings.Join(notes, " ")
+}
+
+func argName(flag *Flag) string {
+	switch {
+	case flag.ArgName != "":
+		return flag.ArgName
+	case isKeyValue(flag):
+		return "KEY=VALUE"
+	}
+	return "ARG"
+}
+
+func shortArgSuffix(flag *Flag) string {
+	switch flag.HasArg {
+	case RequiredArgument:
+		return " " + argName(flag)
+	case OptionalArgument:
+		return "[" + argName(flag) + "]"
+	}
+	return ""
+}
+
+// longSpellings lists the long option name followed by its aliases, each
+// with its dashes.
+func longSpellings(name string, flag *Flag) string {
+	spellings := "--" + name
+	for _, alias := range flag.Aliases {
+		spellings += ", --" + alias
+	}
+	return spellings
+}
+
+func longArgSuffix(flag *Flag) string {
+	switch flag.HasArg {
+	case RequiredArgument:
+		return "=" + argName(flag)
+	case OptionalArgument:
+		return "[=" + argName(flag) + "]"
+	}
+	return ""
+}
+
+// writeUsageRows writes a titled two-column table, aligning help text to
+// the widest label up to usageHelpColumn.
+func writeUsageRows(b *strings.Builder, title string, rows []usageRow) {
+	if len(rows) == 0 {
+		return
+	}
+	width := 0
+	for _, row := range rows {
+		if n := len(row.label); n > width && n <= usageHelpColumn {
+			width = n
+		}
+	}
+	b.WriteString("\n" + title + ":\n")
+	for _, row := range rows {
+		b.WriteString("  " + row.label)
+		switch {
+		case row.help == "":
+		case len(row.label) > width:
+			b.WriteString("\n  " + strings.Repeat(" ", width+2) + row.help)
+		default:
+			b.WriteString(strings.Repeat(" ", width-len(row.label)+2) + row.help)
+		}
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,104 @@
+package optargs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteUsage(t *testing.T) {
+	file := &Flag{Name: "file", HasArg: RequiredArgument, ArgName: "FILE", Help: "input file"}
+	f := &Flag{Name: "f", HasArg: RequiredArgument, Peer: file}
+	file.Peer = f
+	root, err := NewParser(ParserConfig{},
+		map[byte]*Flag{
+			'f': f,
+			'v': {Name: "v", Help: "verbose output"},
+			'o': {Name: "o", HasArg: OptionalArgument, ArgName: "N"},
+		},
+		map[string]*Flag{
+			"file":                      file,
+			"color":                     {Name: "color", HasArg: OptionalArgument, ArgName: "WHEN", DefaultValue: "auto"},
+			"a-really-long-option-name": {Name: "a-really-long-option-name", HasArg: RequiredArgument, Help: "wraps"},
+		}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.Name = "prog"
+	root.Description = "Does things."
+
+	serve, err := NewParser(ParserConfig{}, nil, map[string]*Flag{
+		"port": {Name: "port", HasArg: RequiredArgument, ArgName: "PORT", Help: "listen port", DefaultValue: "8080"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serve.Description = "Run the server"
+	status, err := NewParser(ParserConfig{}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AddCmd("serve", serve)
+	root.AddCmd("status", status)
+	if err := root.AddAlias("s", "serve"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Usage: prog [OPTIONS] [COMMAND]
+
+Does things.
+
+Options:
+      --a-really-long-option-name=ARG
+                      wraps
+      --color[=WHEN]  (default: auto)
+  -f, --file=FILE     input file
+  -o[N]
+  -v                  verbose output
+
+Commands:
+  serve, s  Run the server
+  status
+
+Usage: prog serve [OPTIONS]
+
+Run the server
+
+Options:
+      --port=PORT  listen port (default: 8080)
+
+Usage: prog status
+`
+	var b strings.Builder
+	if err := root.WriteUsage(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != want {
+		t.Errorf("WriteUsage:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	// A subcommand section on its own is headed by the full command path.
+	b.Reset()
+	if err := serve.WriteUsage(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.SplitN(b.String(), "\n", 2)[0]; got != "Usage: prog serve [OPTIONS]" {
+		t.Errorf("subcommand usage line = %q", got)
+	}
+}
+
+func TestWriteUsageWriterError(t *testing.T) {
+	p, err := GetOpt(nil, "v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.WriteUsage(failWriter{}); !errors.Is(err, errWriteFailed) {
+		t.Errorf("WriteUsage error = %v, want %v", err, errWriteFailed)
+	}
+}
+
+var errWriteFailed = errors.New("write failed")
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, errWriteFailed }
@@ -0,0 +1,56 @@
+package optargs
+
+// checkOption runs the parse-time checks on an option before it is
+// handled or yielded: its duplicate policy, its key=value syntax, its
+// Validate hook, then its exclusive groups.
+func (p *Parser) checkOption(flag *Flag, option Option) error {
+	if err := p.checkDuplicate(flag, option); err != nil {
+		return err
+	}
+	if err := p.checkKeyValue(flag, option); err != nil {
+		return err
+	}
+	if err := p.validateArg(flag, option); err != nil {
+		return err
+	}
+	return p.checkExclusive(flag, option)
+}
+
+// validateArg runs the Validate hook of flag, or of its Peer when flag
+// has none, on the argument of option. Options parsed without an argument
+// are not validated.
+func (p *Parser) validateArg(flag *Flag, option Option) error {
+	if flag == nil || !option.HasArg {
+		return nil
+	}
+	validate := flag.Validate
+	if validate == nil && flag.Peer != nil {
+		validate = flag.Peer.Validate
+	}
+	if validate == nil {
+		return nil
+	}
+	if err := validate(option.Arg); err != nil {
+		verr := &ValidationError{Name: option.Name, Arg: option.Arg, Err: err}
+		p.report(verr)
+		return verr
+	}
+	return nil
+}
+
+// checkKeyValue reports an *InvalidArgumentError when flag, or its Peer,
+// is a KeyValue option and the argument of option is not key=value.
+func (p *Parser) checkKeyValue(flag *Flag, option Option) error {
+	if !isKeyValue(flag) || !option.HasArg {
+		return nil
+	}
+	if _, _, err := option.KeyValue(); err != nil {
+		p.report(err)
+		return err
+	}
+	return nil
+}
+
+func isKeyValue(flag *Flag) bool {
+	return flag != nil && (flag.KeyValue || flag.Peer != nil && flag.Peer.KeyValue)
+}
@@ -0,0 +1,166 @@
+package optargs
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+var errNotPort = errors.New("must be a port number")
+
+func validatePort(arg string) error {
+	if n, err := strconv.Atoi(arg); err != nil || n < 1 || n > 65535 {
+		return errNotPort
+	}
+	return nil
+}
+
+func TestFlagValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantOpts []Option
+		wantErrs []string // names of rejected options, in order
+	}{
+		{
+			name:     "valid",
+			args:     []string{"--port=80", "-p", "443", "-vp8080"},
+			wantOpts: []Option{{Name: "port", HasArg: true, Arg: "80"}, {Name: "p", HasArg: true, Arg: "443"}, {Name: "v"}, {Name: "p", HasArg: true, Arg: "8080"}},
+		},
+		{
+			name:     "invalid long",
+			args:     []string{"--port", "http", "-v"},
+			wantOpts: []Option{{Name: "v"}},
+			wantErrs: []string{"port"},
+		},
+		{
+			name:     "invalid short through peer",
+			args:     []string{"-vp0", "-v"},
+			wantOpts: []Option{{Name: "v"}, {Name: "v"}},
+			wantErrs: []string{"p"},
+		},
+		{
+			name:     "optional argument absent",
+			args:     []string{"--level", "--level=x"},
+			wantOpts: []Option{{Name: "level"}},
+			wantErrs: []string{"level"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := &Flag{Name: "port", HasArg: RequiredArgument, Validate: validatePort}
+			short := &Flag{Name: "p", HasArg: RequiredArgument, Peer: port}
+			port.Peer = short
+			level := &Flag{Name: "level", HasArg: OptionalArgument, Validate: func(arg string) error {
+				if arg != "debug" {
+					return errors.New("unknown level")
+				}
+				return nil
+			}}
+			p, err := NewParser(ParserConfig{},
+				map[byte]*Flag{'p': short, 'v': {Name: "v"}},
+				map[string]*Flag{"port": port, "level": level},
+				tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var opts []Option
+			var errs []string
+			for opt, err := range p.Options() {
+				if err != nil {
+					var verr *ValidationError
+					if !errors.As(err, &verr) {
+						t.Fatalf("error %v is not a *ValidationError", err)
+					}
+					if verr.Name != opt.Name || verr.Arg != opt.Arg {
+						t.Errorf("ValidationError %+v does not match option %+v", verr, opt)
+					}
+					errs = append(errs, verr.Name)
+					continue
+				}
+				opts = append(opts, opt)
+			}
+			assertOptions(t, opts, tt.wantOpts)
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("rejected %v, want %v", errs, tt.wantErrs)
+			}
+			for i := range errs {
+				if errs[i] != tt.wantErrs[i] {
+					t.Errorf("rejected %v, want %v", errs, tt.wantErrs)
+				}
+			}
+		})
+	}
+}
+
+func TestFlagValidateHandler(t *testing.T) {
+	handled := false
+	p, err := NewParser(ParserConfig{}, nil, map[string]*Flag{
+		"port": {
+			Name: "port", HasArg: RequiredArgument, Validate: validatePort,
+			Handle: func(string, string) error { handled = true; return nil },
+		},
+	}, []string{"--port=99999"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.Collect()
+	if !errors.Is(err, errNotPort) {
+		t.Errorf("Collect error = %v, want %v", err, errNotPort)
+	}
+	if err.Error() != `invalid argument for option port: "99999": must be a port number` {
+		t.Errorf("Error() = %q", err)
+	}
+	if handled {
+		t.Error("handler ran for a rejected argument")
+	}
+	if n := p.Count("port"); n != 0 {
+		t.Errorf("Count(port) = %d, want 0", n)
+	}
+}
+
+func TestFlagKeyValue(t *testing.T) {
+	label := &Flag{Name: "label", HasArg: RequiredArgument, KeyValue: true}
+	short := &Flag{Name: "l", HasArg: RequiredArgument, Peer: label}
+	label.Peer = short
+	p, err := NewParser(ParserConfig{},
+		map[byte]*Flag{'l': short},
+		map[string]*Flag{"label": label},
+		[]string{"--label=env=prod", "-l", "tier=", "--label", "noequals", "-l=x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type pair struct{ key, value string }
+	var got []pair
+	var rejected []string
+	for opt, err := range p.Options() {
+		if err != nil {
+			var ierr *InvalidArgumentError
+			if !errors.As(err, &ierr) {
+				t.Fatalf("error %v is not an *InvalidArgumentError", err)
+			}
+			rejected = append(rejected, ierr.Arg)
+			continue
+		}
+		key, value, err := opt.KeyValue()
+		if err != nil {
+			t.Fatalf("KeyValue(%q): %v", opt.Arg, err)
+		}
+		got = append(got, pair{key, value})
+	}
+	want := []pair{{"env", "prod"}, {"tier", ""}}
+	if !slices.Equal(got, want) {
+		t.Errorf("pairs = %v, want %v", got, want)
+	}
+	if !slices.Equal(rejected, []string{"noequals", "=x"}) {
+		t.Errorf("rejected = %q, want [noequals =x]", rejected)
+	}
+}
+
+func TestOptionKeyValueError(t *testing.T) {
+	_, _, err := Option{Name: "label", HasArg: true, Arg: "x"}.KeyValue()
+	if !errors.Is(err, errMissingEquals) || err.Error() != `invalid key=value argument for option label: "x"` {
+		t.Errorf("KeyValue error = %v", err)
+	}
+}
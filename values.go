@@ -0,0 +1,30 @@
+package optargs
+
+// occurrence is one parse of an option, recorded for Values.
+type occurrence struct {
+	flag *Flag
+	arg  string
+}
+
+// Values returns the argument of every occurrence of the option name
+// parsed by p, in command-line order, whether it was yielded or
+// dispatched to a handler, so a repeated --include can be read back
+// without collecting it by hand. An occurrence without an argument
+// contributes an empty string. name is resolved as for [Parser.Count],
+// and a short and a long option linked through Flag.Peer share one list.
+// It is meant to be called once [Parser.Options] iteration has finished;
+// the list restarts when p is dispatched as a subcommand again. Values
+// returns nil when the option was not parsed.
+func (p *Parser) Values(name string) []string {
+	flag := p.resolveName(name)
+	if flag == nil {
+		return nil
+	}
+	var values []string
+	for _, o := range p.occurrences {
+		if sameOption(o.flag, flag) {
+			values = append(values, o.arg)
+		}
+	}
+	return values
+}
@@ -0,0 +1,57 @@
+package optargs
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	include := &Flag{Name: "I", HasArg: RequiredArgument}
+	includeLong := &Flag{Name: "include", HasArg: RequiredArgument, Peer: include, Aliases: []string{"inc"}}
+	include.Peer = includeLong
+	level := &Flag{Name: "level", HasArg: OptionalArgument}
+	define := &Flag{Name: "define", HasArg: RequiredArgument, Handle: func(string, string) error { return nil }}
+
+	tests := []struct {
+		name  string
+		args  []string
+		query string
+		want  []string
+	}{
+		{"repeated", []string{"--include=a", "--include", "b"}, "include", []string{"a", "b"}},
+		{"peer order", []string{"-Ia", "--include=b", "-I", "c"}, "I", []string{"a", "b", "c"}},
+		{"alias", []string{"--inc=a", "-Ib"}, "inc", []string{"a", "b"}},
+		{"optional absent", []string{"--level", "--level=2"}, "level", []string{"", "2"}},
+		{"handler", []string{"--define=x=1", "--define=y"}, "define", []string{"x=1", "y"}},
+		{"absent", []string{"file"}, "include", nil},
+		{"unknown", []string{"-Ia"}, "nope", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(ParserConfig{},
+				map[byte]*Flag{'I': include},
+				map[string]*Flag{"include": includeLong, "level": level, "define": define},
+				tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requireParsedOptions(t, p)
+			if got := p.Values(tt.query); !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("Values(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValuesReset(t *testing.T) {
+	p, err := GetOpt([]string{"-Ia", "-Ib"}, "I:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireParsedOptions(t, p)
+	p.Reset([]string{"-Ic"})
+	requireParsedOptions(t, p)
+	if got := p.Values("I"); !slices.Equal(got, []string{"c"}) {
+		t.Errorf("Values(I) after Reset = %q, want [c]", got)
+	}
+}
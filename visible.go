@@ -0,0 +1,88 @@
+package optargs
+
+import (
+	"slices"
+	"strings"
+)
+
+// VisibleOption is one option spelling accepted by a parser.
+type VisibleOption struct {
+	Name  string  // spelling with dashes: "-v" or "--verbose"
+	Flag  *Flag   // definition the spelling resolves to
+	Owner *Parser // parser the flag is registered on: p or an ancestor
+}
+
+// VisibleOptions returns every option spelling p accepts, its own and
+// those inherited through the parent chain, aliases included, sorted by
+// name. A spelling is listed only when parsing it on p reaches the listed
+// flag, so an inherited option overridden by p (exactly or through case
+// folding) is omitted in favor of p's definition. Hidden flags are left
+// out; see [Parser.AllOptions].
+//
+// The result is a snapshot: it does not change when options are added or
+// removed later, and may be kept or modified by the caller.
+func (p *Parser) VisibleOptions() []VisibleOption {
+	return p.resolvedOptions(false)
+}
+
+// AllOptions is like [Parser.VisibleOptions] but includes hidden flags.
+func (p *Parser) AllOptions() []VisibleOption {
+	return p.resolvedOptions(true)
+}
+
+// resolvedOptions implements VisibleOptions and AllOptions.
+func (p *Parser) resolvedOptions(hidden bool) []VisibleOption {
+	var opts []VisibleOption
+	seen := make(map[string]bool)
+	for owner := p; owner != nil; owner = owner.parent {
+		for c, flag := range owner.shortOpts {
+			name := "-" + byteString(byte(c))
+			if flag == nil || flag.Hidden && !hidden || seen[name] || p.resolveShort(byte(c)) != flag {
+				continue
+			}
+			seen[name] = true
+			opts = append(opts, VisibleOption{Name: name, Flag: flag, Owner: owner})
+		}
+		for r, flag := range owner.runeOpts {
+			name := "-" + string(r)
+			if _, f := p.resolveShortName(string(r)); flag.Hidden && !hidden || seen[name] || f != flag {
+				continue
+			}
+			seen[name] = true
+			opts = append(opts, VisibleOption{Name: name, Flag: flag, Owner: owner})
+		}
+		for longName, flag := range owner.longOpts {
+			name := "--" + longName
+			if flag.Hidden && !hidden || seen[name] || p.exactMatch(longName).flag != flag {
+				continue
+			}
+			seen[name] = true
+			opts = append(opts, VisibleOption{Name: name, Flag: flag, Owner: owner})
+		}
+		for alias, longName := range owner.longAliases {
+			name, flag := "--"+alias, owner.longOpts[longName]
+			if flag.Hidden && !hidden || seen[name] || p.exactMatch(alias).flag != flag {
+				continue
+			}
+			seen[name] = true
+			opts = append(opts, VisibleOption{Name: name, Flag: flag, Owner: owner})
+		}
+	}
+	slices.SortFunc(opts, func(a, b VisibleOption) int { return strings.Compare(a.Name, b.Name) })
+	return opts
+}
+
+// resolveShort returns the flag a short option character resolves to
+// when parsed on p, searching the parent chain like the parser does:
+// exact registrations first, then classes.
+func (p *Parser) resolveShort(c byte) *Flag {
+	for cur := p; cur != nil; cur = cur.parent {
+		if _, flag := cur.lookupShortOpt(c); flag != nil {
+			if flag.Disabled {
+				return nil
+			}
+			return flag
+		}
+	}
+	return p.matchShortClass(c)
+}
@@ -0,0 +1,127 @@
+package optargs
+
+import (
+	"slices"
+	"testing"
+)
+
+// visibleNames returns the spellings and owner names of opts.
+func visibleNames(opts []VisibleOption) (names, owners []string) {
+	for _, opt := range opts {
+		names = append(names, opt.Name)
+		owners = append(owners, opt.Owner.Name)
+	}
+	return names, owners
+}
+
+func TestVisibleOptions(t *testing.T) {
+	root, err := GetOptLong(nil, "vf:", []Flag{
+		{Name: "verbose"},
+		{Name: "file", HasArg: RequiredArgument},
+		{Name: "Color"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.Name = "root"
+
+	// child overrides -f and --file, and folds case so it also shadows
+	// the parent's --Color.
+	child, err := NewParser(ParserConfig{longCaseIgnore: true},
+		map[byte]*Flag{'f': {Name: "f"}},
+		map[string]*Flag{
+			"file":  {Name: "file"},
+			"color": {Name: "color", HasArg: OptionalArgument},
+		}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child.Name = "child"
+	root.AddCmd("child", child)
+
+	strict, err := GetOptLong(nil, "", []Flag{{Name: "only"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict.Name = "strict"
+	root.SetStrictSubcommands(true)
+	root.AddCmd("strict", strict)
+
+	tests := []struct {
+		name       string
+		parser     *Parser
+		wantNames  []string
+		wantOwners []string
+	}{
+		{
+			name:       "root",
+			parser:     root,
+			wantNames:  []string{"--Color", "--file", "--verbose", "-f", "-v"},
+			wantOwners: []string{"root", "root", "root", "root", "root"},
+		},
+		{
+			name:       "child overrides",
+			parser:     child,
+			wantNames:  []string{"--color", "--file", "--verbose", "-f", "-v"},
+			wantOwners: []string{"child", "child", "root", "child", "root"},
+		},
+		{
+			name:       "strict",
+			parser:     strict,
+			wantNames:  []string{"--only"},
+			wantOwners: []string{"strict"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, owners := visibleNames(tt.parser.VisibleOptions())
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("names = %q, want %q", names, tt.wantNames)
+			}
+			if !slices.Equal(owners, tt.wantOwners) {
+				t.Errorf("owners = %q, want %q", owners, tt.wantOwners)
+			}
+		})
+	}
+}
+
+// TestVisibleOptionsMatchParsing verifies that each listed spelling
+// resolves, when parsed on the subcommand, to the listed flag.
+func TestVisibleOptionsMatchParsing(t *testing.T) {
+	root := newCompletionTree(t)
+	parsers := []*Parser{root}
+	for i := 0; i < len(parsers); i++ {
+		cmd := parsers[i]
+		_, children := cmd.commandRows()
+		parsers = append(parsers, children...)
+		for _, opt := range cmd.VisibleOptions() {
+			var got *Flag
+			if len(opt.Name) == 2 {
+				got = cmd.resolveShort(opt.Name[1])
+			} else {
+				got = cmd.exactMatch(opt.Name[2:]).flag
+			}
+			if got != opt.Flag {
+				t.Errorf("%s %s resolves to %v, want %v", cmd.Name, opt.Name, got, opt.Flag)
+			}
+		}
+	}
+}
+
+func TestVisibleOptionsSnapshot(t *testing.T) {
+	p, err := GetOptLong(nil, "a", []Flag{{Name: "alpha"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := p.VisibleOptions()
+	p.longOpts["beta"] = &Flag{Name: "beta"}
+	opts[0].Name = "changed"
+
+	names, _ := visibleNames(p.VisibleOptions())
+	if want := []string{"--alpha", "--beta", "-a"}; !slices.Equal(names, want) {
+		t.Errorf("names = %q, want %q", names, want)
+	}
+	if len(opts) != 2 {
+		t.Errorf("snapshot has %d options after registration, want 2", len(opts))
+	}
+}